@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/kube-openapi/pkg/namer"
+)
+
+// runBundle merges a spec and the files it references into a single spec,
+// see loader.Document.Bundle. With --flatten, the inline object schemas are
+// moved to definitions too, see namer.Flatten.
+func runBundle(args []string) error {
+	return bundle("bundle", args, false)
+}
+
+// runFlatten bundles a spec like runBundle and moves its inline object
+// schemas to definitions, printing the names of the new definitions to
+// stderr.
+func runFlatten(args []string) error {
+	return bundle("flatten", args, true)
+}
+
+func bundle(name string, args []string, flatten bool) error {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	output := fs.StringP("output", "o", "", "file to write the spec to, instead of stdout")
+	format := fs.String("format", "", formatUsage)
+	if !flatten {
+		fs.BoolVar(&flatten, "flatten", false, "move the inline object schemas to definitions")
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: swaggerctl %s [flags] <spec>\n\nflags:\n%s", name, fs.FlagUsages())
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a single spec")
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	doc, err := loadDocument(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	s, err := doc.Bundle()
+	if err != nil {
+		return err
+	}
+	if flatten {
+		for _, def := range namer.Flatten(s) {
+			fmt.Fprintf(os.Stderr, "added definition %s\n", def)
+		}
+	}
+	return writeSpec(s, *output, *format)
+}
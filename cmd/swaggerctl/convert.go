@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/kube-openapi/pkg/openapiconv"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// runConvert converts a spec between Swagger 2.0, OpenAPI 3.0 and OpenAPI
+// 3.1, and prints what couldn't be converted exactly to stderr.
+func runConvert(args []string) error {
+	fs := pflag.NewFlagSet("convert", pflag.ContinueOnError)
+	to := fs.String("to", "", "version to convert to: 2.0, 3.0 or 3.1")
+	output := fs.StringP("output", "o", "", "file to write the converted spec to, instead of stdout")
+	format := fs.String("format", "", formatUsage)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: swaggerctl convert --to <version> [flags] <spec>\n\nflags:\n%s", fs.FlagUsages())
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || *to == "" {
+		fs.Usage()
+		return fmt.Errorf("expected --to and a single spec to convert")
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	// YAML is a superset of JSON, so this handles both formats.
+	if data, err = yaml.YAMLToJSON(data); err != nil {
+		return fmt.Errorf("failed to read %s: %v", fs.Arg(0), err)
+	}
+	converted, losses, err := convert(data, *to)
+	if err != nil {
+		return err
+	}
+	for _, l := range losses {
+		fmt.Fprintf(os.Stderr, "lossy: %s\n", l)
+	}
+
+	return writeSpec(converted, *output, *format)
+}
+
+// convert converts a JSON spec of any version to the version to, going
+// through OpenAPI 3.0 between Swagger 2.0 and OpenAPI 3.1.
+func convert(data []byte, to string) (interface{}, []openapiconv.Loss, error) {
+	var version struct {
+		Swagger string `json:"swagger"`
+		OpenAPI string `json:"openapi"`
+	}
+	if err := json.Unmarshal(data, &version); err != nil {
+		return nil, nil, err
+	}
+	if to != "2.0" && to != "3.0" && to != "3.1" {
+		return nil, nil, fmt.Errorf("unknown version %q, expected 2.0, 3.0 or 3.1", to)
+	}
+
+	var losses, more []openapiconv.Loss
+	var doc *spec3.OpenAPI
+	if version.Swagger != "" {
+		s := &spec.Swagger{}
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, nil, err
+		}
+		if to == "2.0" {
+			return s, nil, nil
+		}
+		var err error
+		if doc, losses, err = openapiconv.ConvertToV3WithReport(s); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		if !strings.HasPrefix(version.OpenAPI, "3.") {
+			return nil, nil, fmt.Errorf("unknown spec version %q", version.OpenAPI)
+		}
+		doc = &spec3.OpenAPI{}
+		if err := json.Unmarshal(data, doc); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var err error
+	switch {
+	case to == "3.1" && !doc.Is31():
+		doc, more, err = openapiconv.ConvertToV31WithReport(doc)
+	case to != "3.1" && doc.Is31():
+		doc, more, err = openapiconv.ConvertToV30WithReport(doc)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	losses = append(losses, more...)
+	if to != "2.0" {
+		return doc, losses, nil
+	}
+	s, more, err := openapiconv.ConvertToV2WithReport(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, append(losses, more...), nil
+}
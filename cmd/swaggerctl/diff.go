@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/kube-openapi/pkg/diff"
+)
+
+// Exit codes of diff when the specs differ. 1 is left for errors.
+const (
+	exitNonBreaking = 3
+	exitBreaking    = 4
+)
+
+// runDiff prints the changes between two versions of a spec as text, JSON or
+// a Markdown changelog.
+// It exits with 0 when nothing changed, exitNonBreaking when no change is
+// breaking and exitBreaking otherwise, so that pipelines can tell them apart
+// from failures.
+func runDiff(args []string) error {
+	var opts diffOptions
+	fs := diffFlags(&opts)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: swaggerctl diff [flags] <old spec> <new spec>\n\nflags:\n%s", fs.FlagUsages())
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected two specs to compare")
+	}
+	old, err := loadSpec(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	new, err := loadSpec(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	r := diff.Diff(old, new)
+	switch opts.format {
+	case "text":
+		for _, c := range r.Changes {
+			fmt.Println(c)
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	case "markdown":
+		if _, err := os.Stdout.Write(r.Markdown(opts.title)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown output format %q", opts.format)
+	}
+	return diffStatus(r)
+}
+
+type diffOptions struct {
+	format string
+	title  string
+}
+
+func diffFlags(opts *diffOptions) *pflag.FlagSet {
+	fs := pflag.NewFlagSet("diff", pflag.ContinueOnError)
+	fs.StringVarP(&opts.format, "output", "o", "text", "output format: text, json or markdown (alias --format)")
+	fs.StringVar(&opts.title, "title", "API changes", "title of the markdown changelog")
+	// --format is an alias of --output.
+	fs.SetNormalizeFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "format" {
+			name = "output"
+		}
+		return pflag.NormalizedName(name)
+	})
+	return fs
+}
+
+// diffStatus returns the error diff ends with for the report, if any.
+func diffStatus(r *diff.Report) error {
+	switch {
+	case r.HasBreaking():
+		return &exitError{code: exitBreaking, err: fmt.Errorf("%d breaking changes", len(r.Breaking()))}
+	case len(r.Changes) > 0:
+		return &exitError{code: exitNonBreaking}
+	}
+	return nil
+}
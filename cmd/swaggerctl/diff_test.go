@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/diff"
+)
+
+func TestDiffStatus(t *testing.T) {
+	nonBreaking := diff.Change{Kind: diff.PathAdded, Path: "/paths/~1b", Message: "path /b was added"}
+	breaking := diff.Change{Kind: diff.PathRemoved, Breaking: true, Path: "/paths/~1a", Message: "path /a was removed"}
+	for _, tc := range []struct {
+		name    string
+		changes []diff.Change
+		code    int
+		message string
+	}{
+		{name: "no change"},
+		{name: "non-breaking", changes: []diff.Change{nonBreaking}, code: exitNonBreaking},
+		{name: "breaking", changes: []diff.Change{nonBreaking, breaking}, code: exitBreaking, message: "1 breaking changes"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := diffStatus(&diff.Report{Changes: tc.changes})
+			if tc.code == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			code, err := exitStatus(err)
+			assert.Equal(t, tc.code, code)
+			if tc.message == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tc.message, err.Error())
+		})
+	}
+
+	// Failures keep 1 to themselves.
+	code, err := exitStatus(errors.New("can't load spec"))
+	assert.Equal(t, 1, code)
+	assert.EqualError(t, err, "can't load spec")
+}
+
+func TestDiffFlags(t *testing.T) {
+	for _, args := range [][]string{{"--format", "json"}, {"--output", "json"}, {"-o", "json"}} {
+		var opts diffOptions
+		require.NoError(t, diffFlags(&opts).Parse(append(args, "old.json", "new.json")))
+		assert.Equal(t, diffOptions{format: "json", title: "API changes"}, opts, "%v", args)
+	}
+	var opts diffOptions
+	require.NoError(t, diffFlags(&opts).Parse(nil))
+	assert.Equal(t, diffOptions{format: "text", title: "API changes"}, opts)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/kube-openapi/pkg/validation/lint"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// runLint lints a spec and validates its structure, examples and defaults,
+// and prints the findings as text, as a JSON report or as a SARIF log. It
+// fails when there is an error finding.
+func runLint(args []string) error {
+	fs := pflag.NewFlagSet("lint", pflag.ContinueOnError)
+	file := fs.StringP("file", "f", "", "path to the JSON or YAML spec to lint")
+	format := fs.StringP("output", "o", "text", "output format: text, json or sarif")
+	disable := fs.StringSlice("disable", nil, "rules to disable")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: swaggerctl lint -f <spec> [flags]\n\nflags:\n%s", fs.FlagUsages())
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		fs.Usage()
+		return fmt.Errorf("--file is required")
+	}
+	s, err := loadSpec(*file)
+	if err != nil {
+		return err
+	}
+
+	l := lint.NewSpecLinter().Disable(*disable...)
+	findings := append(lint.FromResult(validate.Spec(s).Merge(validate.Examples(s), validate.Defaults(s))), l.Lint(s)...)
+	switch *format {
+	case "text":
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+	case "json":
+		err = lint.WriteJSON(os.Stdout, *file, findings)
+	case "sarif":
+		err = lint.WriteSARIF(os.Stdout, *file, l.Rules(), findings)
+	default:
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+	if max, ok := lint.MaxSeverity(findings); ok && max == lint.Error {
+		return fmt.Errorf("%s has errors", *file)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/kube-openapi/pkg/mock"
+)
+
+// runMock serves the operations of a spec with their examples or generated
+// data, validating the requests and allowing cross-origin ones, until
+// interrupted.
+func runMock(args []string) error {
+	fs := pflag.NewFlagSet("mock", pflag.ContinueOnError)
+	port := fs.IntP("port", "p", 8080, "port to listen on")
+	seed := fs.Int64("seed", 0, "seed of the generated data")
+	noValidate := fs.Bool("no-validate", false, "answer requests not conforming to the spec instead of rejecting them")
+	noCORS := fs.Bool("no-cors", false, "don't allow cross-origin requests")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: swaggerctl mock [flags] <spec>\n\nflags:\n%s", fs.FlagUsages())
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a single spec to serve")
+	}
+	s, err := loadSpec(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	h, err := mock.New(s, mock.Options{Seed: *seed, SkipValidation: *noValidate, CORS: !*noCORS})
+	if err != nil {
+		return err
+	}
+	addr := fmt.Sprintf(":%d", *port)
+	log.Printf("serving %s on %s", fs.Arg(0), addr)
+	return http.ListenAndServe(addr, h)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/kube-openapi/pkg/modelgen"
+)
+
+// runModels generates Go models from the definitions of a spec.
+func runModels(args []string) error {
+	fs := pflag.NewFlagSet("models", pflag.ContinueOnError)
+	file := fs.StringP("file", "f", "", "path to the JSON or YAML spec")
+	pkg := fs.StringP("package", "p", "models", "name of the generated package")
+	output := fs.StringP("output", "o", "", "file to write the models to, instead of stdout")
+	validate := fs.Bool("validate", true, "generate Validate methods")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: swaggerctl models -f <spec> [flags]\n\nflags:\n%s", fs.FlagUsages())
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		fs.Usage()
+		return fmt.Errorf("--file is required")
+	}
+	s, err := loadSpec(*file)
+	if err != nil {
+		return err
+	}
+	src, err := modelgen.Generate(s.Definitions, modelgen.Options{Package: *pkg, Validate: *validate})
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(*output, src, 0644)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/kube-openapi/pkg/query"
+)
+
+// runQuery answers the query given as arguments, or starts an interactive
+// session reading one query per line from stdin when there is none.
+func runQuery(args []string) error {
+	fs := pflag.NewFlagSet("query", pflag.ContinueOnError)
+	file := fs.StringP("file", "f", "", "path to the JSON or YAML spec to query")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: swaggerctl query -f <spec> [query]\n\n%s\n\nflags:\n%s", query.Help, fs.FlagUsages())
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		fs.Usage()
+		return fmt.Errorf("--file is required")
+	}
+	s, err := loadSpec(*file)
+	if err != nil {
+		return err
+	}
+	ix, err := query.NewIndex(s)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		out, err := ix.Run(strings.Join(fs.Args(), " "))
+		if err != nil {
+			return err
+		}
+		fmt.Println(out)
+		return nil
+	}
+	return repl(ix, os.Stdin, os.Stdout)
+}
+
+func repl(ix *query.Index, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "quit" || line == "exit" {
+			return nil
+		}
+		result, err := ix.Run(line)
+		if err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+			continue
+		}
+		if result != "" {
+			fmt.Fprintln(out, result)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// swaggerctl is a command line tool to work with OpenAPI specs.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/kube-openapi/pkg/loader"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// commands maps each subcommand to its entry point, which receives the
+// arguments following the subcommand name.
+var commands = map[string]func(args []string) error{
+	"bundle":   runBundle,
+	"convert":  runConvert,
+	"diff":     runDiff,
+	"flatten":  runFlatten,
+	"lint":     runLint,
+	"mock":     runMock,
+	"models":   runModels,
+	"query":    runQuery,
+	"validate": runValidate,
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(os.Stderr, "usage: swaggerctl <command> [flags]\n\ncommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", name)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd(os.Args[2:]); err != nil {
+		code, err := exitStatus(err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "swaggerctl %s: %v\n", os.Args[1], err)
+		}
+		os.Exit(code)
+	}
+}
+
+// exitStatus returns the exit code for the error a command failed with,
+// and the error to print, if any.
+func exitStatus(err error) (int, error) {
+	if e, ok := err.(*exitError); ok {
+		return e.code, e.err
+	}
+	return 1, err
+}
+
+// exitError makes a command exit with a given code. err, if set, is printed.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+// loadSpec reads a JSON or YAML spec file.
+func loadSpec(path string) (*spec.Swagger, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return loader.LoadSwagger(os.DirFS(filepath.Dir(abs)), filepath.Base(abs))
+}
+
+// formatUsage documents the --format flag of the commands writing specs.
+const formatUsage = "output format: json or yaml, by default yaml for .yaml and .yml output files and json otherwise"
+
+func checkFormat(format string) error {
+	if format != "" && format != "json" && format != "yaml" {
+		return fmt.Errorf("unknown output format %q", format)
+	}
+	return nil
+}
+
+// writeSpec writes a spec to the output file, or to stdout if there is
+// none, as JSON or YAML, see formatUsage.
+func writeSpec(v interface{}, output, format string) error {
+	if format == "" {
+		format = "json"
+		if ext := filepath.Ext(output); ext == ".yaml" || ext == ".yml" {
+			format = "yaml"
+		}
+	}
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if format == "yaml" {
+		if out, err = yaml.JSONToYAML(out); err != nil {
+			return err
+		}
+	} else {
+		out = append(out, '\n')
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	return ioutil.WriteFile(output, out, 0644)
+}
+
+// loadDocument reads a JSON or YAML spec file and the files it references.
+func loadDocument(path string) (*loader.Document, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return loader.Load(os.DirFS(filepath.Dir(abs)), filepath.Base(abs))
+}
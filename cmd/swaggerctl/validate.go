@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/kube-openapi/pkg/validation/lint"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// runValidate loads a spec with the files it references, checks that its
+// references resolve, validates its structure, examples and defaults and
+// lints it, and prints the findings as text or as a JSON report. It fails
+// when there is an error finding.
+func runValidate(args []string) error {
+	fs := pflag.NewFlagSet("validate", pflag.ContinueOnError)
+	format := fs.StringP("output", "o", "text", "output format: text or json")
+	noLint := fs.Bool("no-lint", false, "only run the structural checks")
+	disable := fs.StringSlice("disable", nil, "lint rules to disable")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: swaggerctl validate [flags] <spec>\n\nflags:\n%s", fs.FlagUsages())
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a single spec to validate")
+	}
+	file := fs.Arg(0)
+	doc, err := loadDocument(file)
+	if err != nil {
+		return err
+	}
+
+	res := &validate.Result{}
+	res.AddErrors(doc.CheckRefs()...)
+	s := doc.Spec
+	res.Merge(validate.Spec(s), validate.Examples(s), validate.Defaults(s))
+	findings := lint.FromResult(res)
+	if !*noLint {
+		findings = append(findings, lint.NewSpecLinter().Disable(*disable...).Lint(s)...)
+	}
+	switch *format {
+	case "text":
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+		r := lint.NewReport(file, findings)
+		fmt.Printf("%s: %d errors, %d warnings\n", file, r.Summary[lint.Error], r.Summary[lint.Warning])
+	case "json":
+		err = lint.WriteJSON(os.Stdout, file, findings)
+	default:
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+	if max, ok := lint.MaxSeverity(findings); ok && max == lint.Error {
+		return fmt.Errorf("%s is invalid", file)
+	}
+	return nil
+}
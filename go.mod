@@ -1,12 +1,12 @@
 module k8s.io/kube-openapi
 
-go 1.12
+go 1.16
 
 require (
-	github.com/NYTimes/gziphandler v0.0.0-20170623195520-56545f4a5d46
 	github.com/asaskevich/govalidator v0.0.0-20190424111038-f61b66f89f4a
 	github.com/davecgh/go-spew v1.1.1
 	github.com/emicklei/go-restful v0.0.0-20170410110728-ff4f55a20633
+	github.com/go-openapi/jsonpointer v0.19.3
 	github.com/go-openapi/jsonreference v0.19.3
 	github.com/go-openapi/swag v0.19.5
 	github.com/golang/protobuf v1.4.2
@@ -15,7 +15,6 @@ require (
 	github.com/googleapis/gnostic v0.5.1
 	github.com/json-iterator/go v1.1.6
 	github.com/mitchellh/mapstructure v1.1.2
-	github.com/munnerz/goautoneg v0.0.0-20120707110453-a547fc61f48d
 	github.com/onsi/ginkgo v0.0.0-20170829012221-11459a886d9c
 	github.com/onsi/gomega v0.0.0-20170829124025-dcabb60a477c
 	github.com/spf13/pflag v0.0.0-20170130214245-9ff6c6923cff
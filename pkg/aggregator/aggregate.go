@@ -0,0 +1,225 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// ConflictPolicy decides what an Aggregator does when two sources define
+// different definitions with the same name.
+type ConflictPolicy int
+
+const (
+	// RejectConflicts fails the aggregation on the first conflict.
+	RejectConflicts ConflictPolicy = iota
+	// RenameConflicts renames the conflicting definition of the later
+	// source, e.g. Pet to Pet_v2, and updates its references.
+	RenameConflicts
+)
+
+// Source is one of the specs merged by an Aggregator.
+type Source struct {
+	// Name identifies the source in errors, e.g. the name of a service.
+	Name string
+	Spec *spec.Swagger
+	// DefinitionPrefix, if set, is prepended to the names of all the
+	// definitions of the source, e.g. "billing." turns Invoice into
+	// billing.Invoice, so that sources don't collide.
+	DefinitionPrefix string
+	// PathPrefix, if set, is prepended to all the paths of the source, e.g.
+	// "/billing" for a service mounted there by a gateway.
+	PathPrefix string
+	// Hash, if set, identifies the content of Spec, e.g. the ETag it was
+	// downloaded with. Otherwise a hash of Spec is computed on every
+	// Aggregate call.
+	Hash string
+}
+
+// Aggregator merges the paths and definitions of several specs into one.
+// Identical definitions are unified, definitions differing only by their
+// x-kubernetes-group-version-kind extension are merged, and other
+// conflicts are handled according to DefinitionConflicts. Sources are
+// merged in the order they were added and are not mutated.
+//
+// The processed form of each source, with its definitions and paths
+// prefixed, is cached along with the hash of the source, so that updating
+// one source only reprocesses that source. The merged spec is cached as
+// well and returned as is while no source changes.
+type Aggregator struct {
+	// Info is the info of the aggregated spec.
+	Info *spec.Info
+	// DefinitionConflicts is the policy for conflicting definitions.
+	DefinitionConflicts ConflictPolicy
+	// IgnorePathConflicts keeps the path of the first source defining it
+	// instead of failing.
+	IgnorePathConflicts bool
+
+	sources []Source
+	// processed caches the processed form of the sources by name.
+	processed map[string]*processedSource
+	// merged is the last merged spec, for the sources of mergedFrom.
+	merged     *spec.Swagger
+	mergedFrom []mergedSource
+}
+
+type processedSource struct {
+	hash string
+	spec *spec.Swagger
+}
+
+// mergedSource identifies the state of a source in a merged spec.
+type mergedSource struct {
+	name, hash string
+}
+
+// NewAggregator returns an Aggregator rejecting conflicts.
+func NewAggregator(info *spec.Info) *Aggregator {
+	return &Aggregator{Info: info}
+}
+
+// Add appends sources to merge. Use Set to update a source.
+func (a *Aggregator) Add(sources ...Source) *Aggregator {
+	a.sources = append(a.sources, sources...)
+	return a
+}
+
+// Set replaces the source with the same name, or appends it if there is
+// none.
+func (a *Aggregator) Set(src Source) {
+	for i := range a.sources {
+		if a.sources[i].Name == src.Name {
+			a.sources[i] = src
+			return
+		}
+	}
+	a.sources = append(a.sources, src)
+}
+
+// Remove removes the source with the given name and returns whether there
+// was one.
+func (a *Aggregator) Remove(name string) bool {
+	for i := range a.sources {
+		if a.sources[i].Name == name {
+			a.sources = append(a.sources[:i], a.sources[i+1:]...)
+			delete(a.processed, name)
+			return true
+		}
+	}
+	return false
+}
+
+// Aggregate merges the sources into a spec. The result is shared with later
+// calls while the sources don't change and must not be modified.
+func (a *Aggregator) Aggregate() (*spec.Swagger, error) {
+	if a.processed == nil {
+		a.processed = map[string]*processedSource{}
+	}
+	from := make([]mergedSource, 0, len(a.sources))
+	processed := make([]*spec.Swagger, 0, len(a.sources))
+	for i, src := range a.sources {
+		name := src.Name
+		if name == "" {
+			name = fmt.Sprintf("source %d", i)
+		}
+		if src.Spec == nil {
+			return nil, fmt.Errorf("%s has no spec", name)
+		}
+		hash, err := sourceHash(&src)
+		if err != nil {
+			return nil, fmt.Errorf("hashing %s: %v", name, err)
+		}
+		p, ok := a.processed[name]
+		if !ok || p.hash != hash {
+			p = &processedSource{hash: hash, spec: process(&src)}
+			a.processed[name] = p
+		}
+		from = append(from, mergedSource{name: name, hash: hash})
+		processed = append(processed, p.spec)
+	}
+	from = append(from, mergedSource{hash: fmt.Sprintf("%d %t", a.DefinitionConflicts, a.IgnorePathConflicts)})
+	if a.merged != nil && reflect.DeepEqual(from, a.mergedFrom) && reflect.DeepEqual(a.Info, a.merged.Info) {
+		return a.merged, nil
+	}
+
+	ret := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Swagger:     "2.0",
+			Info:        a.Info.DeepCopy(),
+			Paths:       &spec.Paths{Paths: map[string]spec.PathItem{}},
+			Definitions: spec.Definitions{},
+		},
+	}
+	for i, s := range processed {
+		if err := mergeSpecs(ret, s, a.DefinitionConflicts == RenameConflicts, a.IgnorePathConflicts); err != nil {
+			return nil, fmt.Errorf("merging %s: %v", from[i].name, err)
+		}
+	}
+	a.merged, a.mergedFrom = ret, from
+	return ret, nil
+}
+
+// sourceHash identifies a source along with the settings its processed form
+// depends on.
+func sourceHash(src *Source) (string, error) {
+	hash := src.Hash
+	if hash == "" {
+		data, err := json.Marshal(src.Spec)
+		if err != nil {
+			return "", err
+		}
+		hash = fmt.Sprintf("%X", sha512.Sum512(data))
+	}
+	return fmt.Sprintf("%s %q %q", hash, src.DefinitionPrefix, src.PathPrefix), nil
+}
+
+// process prefixes the definitions and paths of a source. The result shares
+// data structures with the source, which is not mutated.
+func process(src *Source) *spec.Swagger {
+	s := src.Spec
+	if src.DefinitionPrefix != "" {
+		renames := make(map[string]string, len(s.Definitions))
+		for k := range s.Definitions {
+			renames[k] = src.DefinitionPrefix + k
+		}
+		s = renameDefinition(s, renames)
+	}
+	if src.PathPrefix != "" && s.Paths != nil {
+		ret := *s
+		ret.Paths = prefixPaths(s.Paths, src.PathPrefix)
+		s = &ret
+	}
+	return s
+}
+
+func prefixPaths(paths *spec.Paths, prefix string) *spec.Paths {
+	prefix = strings.TrimSuffix(prefix, "/")
+	ret := &spec.Paths{
+		VendorExtensible: paths.VendorExtensible,
+		Paths:            make(map[string]spec.PathItem, len(paths.Paths)),
+	}
+	for p, item := range paths.Paths {
+		ret.Paths[prefix+p] = item
+	}
+	return ret
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func mustSpec(t *testing.T, s string) *spec.Swagger {
+	var ret spec.Swagger
+	require.NoError(t, yaml.Unmarshal([]byte(s), &ret))
+	return &ret
+}
+
+const petsSpec = `
+swagger: "2.0"
+paths:
+  /pets:
+    get:
+      responses:
+        200:
+          description: OK
+          schema:
+            $ref: "#/definitions/Pet"
+definitions:
+  Pet:
+    type: object
+    properties:
+      name:
+        type: string
+  Error:
+    type: string
+`
+
+const ownersSpec = `
+swagger: "2.0"
+paths:
+  /owners:
+    get:
+      responses:
+        200:
+          description: OK
+          schema:
+            $ref: "#/definitions/Pet"
+        default:
+          description: error
+          schema:
+            $ref: "#/definitions/Error"
+definitions:
+  Pet:
+    type: object
+    properties:
+      owner:
+        type: string
+  Error:
+    type: string
+`
+
+func TestAggregatorRejectsConflicts(t *testing.T) {
+	_, err := NewAggregator(nil).Add(
+		Source{Name: "pets", Spec: mustSpec(t, petsSpec)},
+		Source{Name: "owners", Spec: mustSpec(t, ownersSpec)},
+	).Aggregate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "merging owners")
+	assert.Contains(t, err.Error(), "model name conflict in merging OpenAPI spec: Pet")
+
+	_, err = NewAggregator(nil).Add(
+		Source{Name: "pets", Spec: mustSpec(t, petsSpec)},
+		Source{Name: "again", Spec: mustSpec(t, petsSpec), DefinitionPrefix: "again."},
+	).Aggregate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicated path /pets")
+
+	_, err = NewAggregator(nil).Add(Source{}).Aggregate()
+	assert.EqualError(t, err, "source 0 has no spec")
+}
+
+func TestAggregatorRenamesConflicts(t *testing.T) {
+	pets, owners := mustSpec(t, petsSpec), mustSpec(t, ownersSpec)
+	a := NewAggregator(&spec.Info{InfoProps: spec.InfoProps{Title: "gateway"}})
+	a.DefinitionConflicts = RenameConflicts
+	s, err := a.Add(Source{Name: "pets", Spec: pets}, Source{Name: "owners", Spec: owners}).Aggregate()
+	require.NoError(t, err)
+
+	assert.Equal(t, "gateway", s.Info.Title)
+	assert.Equal(t, "2.0", s.Swagger)
+	assert.Len(t, s.Definitions, 3)
+	assert.Contains(t, s.Definitions["Pet_v2"].Properties, "owner")
+	resps := s.Paths.Paths["/owners"].Get.Responses
+	assert.Equal(t, "#/definitions/Pet_v2", resps.StatusCodeResponses[200].Schema.Ref.String())
+	// Identical definitions are unified.
+	assert.Equal(t, "#/definitions/Error", resps.Default.Schema.Ref.String())
+
+	// Sources are not mutated.
+	assert.Equal(t, "#/definitions/Pet", owners.Paths.Paths["/owners"].Get.Responses.StatusCodeResponses[200].Schema.Ref.String())
+}
+
+func TestAggregatorPrefixes(t *testing.T) {
+	s, err := NewAggregator(nil).Add(
+		Source{Name: "pets", Spec: mustSpec(t, petsSpec), DefinitionPrefix: "pets.", PathPrefix: "/pets-svc/"},
+		Source{Name: "again", Spec: mustSpec(t, petsSpec), DefinitionPrefix: "again.", PathPrefix: "/again"},
+	).Aggregate()
+	require.NoError(t, err)
+
+	assert.Len(t, s.Paths.Paths, 2)
+	assert.Equal(t, "#/definitions/pets.Pet", s.Paths.Paths["/pets-svc/pets"].Get.Responses.StatusCodeResponses[200].Schema.Ref.String())
+	assert.Equal(t, "#/definitions/again.Pet", s.Paths.Paths["/again/pets"].Get.Responses.StatusCodeResponses[200].Schema.Ref.String())
+	assert.Contains(t, s.Definitions, "pets.Error")
+	assert.Contains(t, s.Definitions, "again.Error")
+}
+
+func TestAggregatorIgnorePathConflicts(t *testing.T) {
+	a := NewAggregator(nil)
+	a.IgnorePathConflicts = true
+	a.DefinitionConflicts = RenameConflicts
+	s, err := a.Add(
+		Source{Name: "pets", Spec: mustSpec(t, petsSpec)},
+		Source{Name: "again", Spec: mustSpec(t, petsSpec)},
+		Source{Name: "owners", Spec: mustSpec(t, ownersSpec)},
+	).Aggregate()
+	require.NoError(t, err)
+	assert.Len(t, s.Paths.Paths, 2)
+	assert.Len(t, s.Definitions, 3)
+}
+
+func TestAggregatorCaching(t *testing.T) {
+	pets, owners := mustSpec(t, petsSpec), mustSpec(t, ownersSpec)
+	a := NewAggregator(nil).Add(
+		Source{Name: "pets", Spec: pets, DefinitionPrefix: "pets."},
+		Source{Name: "owners", Spec: owners, DefinitionPrefix: "owners."},
+	)
+	s1, err := a.Aggregate()
+	require.NoError(t, err)
+	processedPets := a.processed["pets"].spec
+
+	s2, err := a.Aggregate()
+	require.NoError(t, err)
+	assert.Same(t, s1, s2, "unchanged sources are not merged again")
+
+	// Changing a source only reprocesses that source.
+	owners = mustSpec(t, ownersSpec)
+	owners.Paths.Paths["/owners/{id}"] = owners.Paths.Paths["/owners"]
+	a.Set(Source{Name: "owners", Spec: owners, DefinitionPrefix: "owners."})
+	s3, err := a.Aggregate()
+	require.NoError(t, err)
+	assert.NotSame(t, s1, s3)
+	assert.Same(t, processedPets, a.processed["pets"].spec)
+	assert.Len(t, s3.Paths.Paths, 3)
+	assert.Len(t, s1.Paths.Paths, 2)
+
+	// So does changing its settings or the aggregator's.
+	a.Set(Source{Name: "pets", Spec: pets, DefinitionPrefix: "p."})
+	s4, err := a.Aggregate()
+	require.NoError(t, err)
+	assert.Contains(t, s4.Definitions, "p.Pet")
+	a.Info = &spec.Info{InfoProps: spec.InfoProps{Title: "gateway"}}
+	s5, err := a.Aggregate()
+	require.NoError(t, err)
+	assert.Equal(t, "gateway", s5.Info.Title)
+
+	// A source hash replaces hashing the spec.
+	a.Set(Source{Name: "pets", Spec: pets, DefinitionPrefix: "p.", Hash: "1"})
+	s6, err := a.Aggregate()
+	require.NoError(t, err)
+	changed := mustSpec(t, petsSpec)
+	delete(changed.Definitions, "Error")
+	a.Set(Source{Name: "pets", Spec: changed, DefinitionPrefix: "p.", Hash: "1"})
+	s7, err := a.Aggregate()
+	require.NoError(t, err)
+	assert.Same(t, s6, s7)
+	assert.Contains(t, s7.Definitions, "p.Error")
+
+	assert.True(t, a.Remove("owners"))
+	assert.False(t, a.Remove("owners"))
+	s8, err := a.Aggregate()
+	require.NoError(t, err)
+	assert.Len(t, s8.Paths.Paths, 1)
+}
+
+func TestAggregatorMergesGVKsWithoutMutatingSources(t *testing.T) {
+	withGVK := func(kind string) *spec.Swagger {
+		s := mustSpec(t, petsSpec)
+		pet := s.Definitions["Pet"]
+		pet.AddExtension(gvkKey, []interface{}{map[string]interface{}{"group": "", "version": "v1", "kind": kind}})
+		s.Definitions["Pet"] = pet
+		return s
+	}
+	first, second := withGVK("Pet"), withGVK("Animal")
+	s, err := NewAggregator(nil).Add(
+		Source{Name: "first", Spec: first},
+		Source{Name: "second", Spec: second, PathPrefix: "/second"},
+	).Aggregate()
+	require.NoError(t, err)
+	assert.Len(t, s.Definitions["Pet"].Extensions[gvkKey], 2)
+	assert.Len(t, first.Definitions["Pet"].Extensions[gvkKey], 1)
+}
+
+func TestRenameDefinitions(t *testing.T) {
+	s := mustSpec(t, petsSpec)
+	renamed := RenameDefinitions(s, map[string]string{"Pet": "io.example.Pet"})
+	assert.Contains(t, renamed.Definitions, "io.example.Pet")
+	assert.NotContains(t, renamed.Definitions, "Pet")
+	assert.Equal(t, "#/definitions/io.example.Pet", renamed.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200].Schema.Ref.String())
+	assert.Contains(t, s.Definitions, "Pet", "the input must not be mutated")
+}
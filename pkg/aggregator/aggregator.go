@@ -76,24 +76,37 @@ func FilterSpecByPathsWithoutSideEffects(sp *spec.Swagger, keepPathPrefixes []st
 		ret.Paths.Paths[path] = pathItem
 	}
 
+	ret.Definitions = prunedDefinitions(&ret, sp.Definitions, initialUsedDefinitions)
+	return &ret
+}
+
+// prunedDefinitions returns the definitions that are still used by sp, or
+// that were not used in the first place.
+func prunedDefinitions(sp *spec.Swagger, definitions spec.Definitions, initialUsedDefinitions map[string]bool) spec.Definitions {
 	// Walk all references to find all definition references.
-	usedDefinitions := usedDefinitionForSpec(&ret)
+	usedDefinitions := usedDefinitionForSpec(sp)
 
 	// Remove unused definitions
-	ret.Definitions = spec.Definitions{}
-	for k, v := range sp.Definitions {
+	ret := spec.Definitions{}
+	for k, v := range definitions {
 		if usedDefinitions[k] || !initialUsedDefinitions[k] {
-			ret.Definitions[k] = v
+			ret[k] = v
 		}
 	}
-
-	return &ret
+	return ret
 }
 
 type rename struct {
 	from, to string
 }
 
+// RenameDefinitions renames definitions and the references to them, e.g.
+// following a namer.DefinitionNamer. The input is not mutated but the output
+// might share data structures with it.
+func RenameDefinitions(s *spec.Swagger, renames map[string]string) *spec.Swagger {
+	return renameDefinition(s, renames)
+}
+
 // renameDefinition renames references, without mutating the input.
 // The output might share data structures with the input.
 func renameDefinition(s *spec.Swagger, renames map[string]string) *spec.Swagger {
@@ -238,7 +251,14 @@ DEFINITIONLOOP:
 		} else if merged, changed, err := mergedGVKs(&existing, &v); err != nil {
 			return err
 		} else if changed {
-			existing.Extensions[gvkKey] = merged
+			// The extensions may be shared with an earlier source, copy them.
+			extensions := make(spec.Extensions, len(existing.Extensions))
+			for k, v := range existing.Extensions {
+				extensions[k] = v
+			}
+			extensions[gvkKey] = merged
+			existing.Extensions = extensions
+			dest.Definitions[k] = existing
 		}
 	}
 
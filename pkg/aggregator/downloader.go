@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/kube-openapi/pkg/handler"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	mimeJSON = "application/json"
+	// mimeProtobuf is the protobuf media type served by the handler package.
+	mimeProtobuf = "application/com.github.proto-openapi.spec.v2@v1.0+protobuf"
+)
+
+// Downloader fetches the specs of remote services for aggregation. It sends
+// the ETag of the last download so that unchanged specs are neither
+// transferred nor decoded again, and retries transient failures.
+type Downloader struct {
+	// Client sends the requests, http.DefaultClient if nil.
+	Client *http.Client
+	// Timeout bounds each attempt, including reading the body. Zero means
+	// no timeout.
+	Timeout time.Duration
+	// Retries is the number of retries after a network error, a 429 or a
+	// 5xx response.
+	Retries int
+	// Backoff is the delay before the first retry, doubled for each of the
+	// following ones.
+	Backoff time.Duration
+	// Protobuf asks for protobuf, which is smaller and faster to decode than
+	// JSON, falling back to JSON for servers that don't serve it.
+	Protobuf bool
+	// Limits, if set, bounds the size of the downloaded specs, once
+	// decompressed, and the nesting and duplicate keys of JSON ones, e.g.
+	// spec.DefaultLimits for untrusted servers.
+	Limits *spec.Limits
+}
+
+// NewDownloader returns a Downloader with a 30s timeout and 3 retries,
+// asking for protobuf.
+func NewDownloader() *Downloader {
+	return &Downloader{
+		Timeout:  30 * time.Second,
+		Retries:  3,
+		Backoff:  500 * time.Millisecond,
+		Protobuf: true,
+	}
+}
+
+// DownloadResult is the result of a download.
+type DownloadResult struct {
+	// Spec is the downloaded spec, nil if NotModified.
+	Spec *spec.Swagger
+	// ETag identifies the content of the spec. It is the ETag header of the
+	// response, or a hash of the body when there is none, and can be passed
+	// as Source.Hash.
+	ETag string
+	// NotModified is set when the spec didn't change since the given ETag.
+	NotModified bool
+}
+
+// Download fetches the spec at url. If etag is not empty and the spec
+// didn't change, the result is NotModified.
+func (d *Downloader) Download(ctx context.Context, url, etag string) (*DownloadResult, error) {
+	backoff := d.Backoff
+	for attempt := 0; ; attempt++ {
+		res, retry, err := d.download(ctx, url, etag)
+		if err == nil || !retry || attempt >= d.Retries {
+			return res, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%v (giving up: %v)", err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// download makes a single attempt, and returns whether a failure is worth
+// retrying.
+func (d *Downloader) download(ctx context.Context, url, etag string) (*DownloadResult, bool, error) {
+	if d.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req = req.WithContext(ctx)
+	if d.Protobuf {
+		req.Header.Set("Accept", mimeProtobuf+", "+mimeJSON+";q=0.9")
+	} else {
+		req.Header.Set("Accept", mimeJSON)
+	}
+	// Setting Accept-Encoding disables the transparent decompression of the
+	// transport, the body is decompressed below.
+	req.Header.Set("Accept-Encoding", "gzip")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil || ctx.Err() == context.DeadlineExceeded, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return &DownloadResult{ETag: etag, NotModified: true}, false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, true, fmt.Errorf("downloading %s: %s", url, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+
+	var body io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, false, fmt.Errorf("downloading %s: %v", url, err)
+		}
+		defer gz.Close()
+		body = gz
+	}
+	if d.Limits != nil && d.Limits.MaxSize > 0 {
+		body = io.LimitReader(body, int64(d.Limits.MaxSize)+1)
+	}
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, true, fmt.Errorf("downloading %s: %v", url, err)
+	}
+
+	s, err := decodeSpec(data, resp.Header.Get("Content-Type"), d.Limits)
+	if err != nil {
+		return nil, false, fmt.Errorf("decoding %s: %v", url, err)
+	}
+	ret := &DownloadResult{Spec: s, ETag: resp.Header.Get("Etag")}
+	if ret.ETag == "" {
+		ret.ETag = fmt.Sprintf("\"%X\"", sha512.Sum512(data))
+	}
+	return ret, false, nil
+}
+
+// decodeSpec decodes a JSON or protobuf spec. Servers don't always send a
+// meaningful content type, so bodies starting with "{" are taken for JSON.
+func decodeSpec(data []byte, contentType string, limits *spec.Limits) (*spec.Swagger, error) {
+	if limits != nil && limits.MaxSize > 0 && len(data) > limits.MaxSize {
+		return nil, fmt.Errorf("spec exceeds the maximum size of %d bytes", limits.MaxSize)
+	}
+	isJSON := strings.HasPrefix(contentType, mimeJSON)
+	if !isJSON && !strings.Contains(contentType, "protobuf") {
+		isJSON = bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+	}
+	if !isJSON {
+		return handler.FromProtoBinary(data)
+	}
+	decode := json.Unmarshal
+	if limits != nil {
+		decode = limits.Decode
+	}
+	s := &spec.Swagger{}
+	if err := decode(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/handler"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestDownloader(t *testing.T) {
+	mux := http.NewServeMux()
+	// Protobuf documents require an info.
+	s := mustSpec(t, petsSpec)
+	s.Info = &spec.Info{InfoProps: spec.InfoProps{Title: "pets", Version: "v1"}}
+	_, err := handler.RegisterOpenAPIVersionedService(s, "/openapi/v2", mux)
+	require.NoError(t, err)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for _, protobuf := range []bool{false, true} {
+		d := NewDownloader()
+		d.Protobuf = protobuf
+		res, err := d.Download(context.Background(), server.URL+"/openapi/v2", "")
+		require.NoError(t, err, "protobuf: %t", protobuf)
+		assert.False(t, res.NotModified)
+		assert.NotEmpty(t, res.ETag)
+		require.NotNil(t, res.Spec)
+		assert.Contains(t, res.Spec.Definitions, "Pet")
+		assert.Contains(t, res.Spec.Paths.Paths, "/pets")
+
+		again, err := d.Download(context.Background(), server.URL+"/openapi/v2", res.ETag)
+		require.NoError(t, err)
+		assert.True(t, again.NotModified)
+		assert.Nil(t, again.Spec)
+		assert.Equal(t, res.ETag, again.ETag)
+	}
+}
+
+func TestDownloaderRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case atomic.AddInt32(&calls, 1) <= 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"swagger": "2.0", "paths": {}}`))
+		}
+	}))
+	defer server.Close()
+
+	d := &Downloader{Retries: 2, Backoff: time.Millisecond}
+	res, err := d.Download(context.Background(), server.URL, "")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0", res.Spec.Swagger)
+	// Without an ETag header, the ETag is a hash of the body.
+	assert.NotEmpty(t, res.ETag)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+	d.Retries = 1
+	_, err = d.Download(context.Background(), server.URL, "")
+	assert.EqualError(t, err, "downloading "+server.URL+": 503 Service Unavailable")
+
+	atomic.StoreInt32(&calls, 10)
+	_, err = d.Download(context.Background(), server.URL+"/missing", "")
+	assert.Error(t, err)
+	assert.Equal(t, int32(10), atomic.LoadInt32(&calls), "404 is not retried")
+}
+
+func TestDownloaderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	d := &Downloader{Timeout: 10 * time.Millisecond}
+	_, err := d.Download(context.Background(), server.URL, "")
+	assert.Error(t, err)
+}
+
+func TestDownloaderGzip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Etag", `"1"`)
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(`{"swagger": "2.0", "info": {"title": "gz"}, "paths": {}}`))
+		gz.Close()
+	}))
+	defer server.Close()
+
+	res, err := NewDownloader().Download(context.Background(), server.URL, "")
+	require.NoError(t, err)
+	assert.Equal(t, "gz", res.Spec.Info.Title)
+	assert.Equal(t, `"1"`, res.ETag)
+}
+
+func TestDownloaderLimits(t *testing.T) {
+	body := `{"swagger": "2.0", "info": {"title": "pets"}, "paths": {}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	d := NewDownloader()
+	d.Limits = &spec.Limits{MaxSize: 1000, RejectDuplicateKeys: true}
+	res, err := d.Download(context.Background(), server.URL, "")
+	require.NoError(t, err)
+	assert.Equal(t, "pets", res.Spec.Info.Title)
+
+	body = `{"swagger": "2.0", "swagger": "3.0", "paths": {}}`
+	_, err = d.Download(context.Background(), server.URL, "")
+	assert.Error(t, err)
+
+	body = `{"swagger": "2.0", "info": {"description": "` + strings.Repeat("x", 1000) + `"}, "paths": {}}`
+	_, err = d.Download(context.Background(), server.URL, "")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "maximum size of 1000 bytes")
+	}
+}
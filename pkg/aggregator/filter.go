@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// FilterSpecByTags removes the operations that have none of the given tags,
+// the paths left without operations, and the definitions only used by them,
+// like FilterSpecByPaths does for paths.
+func FilterSpecByTags(sp *spec.Swagger, keepTags []string) {
+	*sp = *FilterSpecByTagsWithoutSideEffects(sp, keepTags)
+}
+
+// FilterSpecByTagsWithoutSideEffects removes the operations that have none of
+// the given tags, the paths left without operations, and the definitions only
+// used by them. Tag declarations no operation uses anymore are removed too.
+// It does not modify the input, but the output shares data structures with the input.
+func FilterSpecByTagsWithoutSideEffects(sp *spec.Swagger, keepTags []string) *spec.Swagger {
+	keep := make(map[string]bool, len(keepTags))
+	for _, t := range keepTags {
+		keep[t] = true
+	}
+	return filterSpecByTags(sp, func(tags []string) bool {
+		for _, t := range tags {
+			if keep[t] {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// defaultTag is the tag SplitByTag files the operations without tags under.
+const defaultTag = "default"
+
+// SplitByTag splits a spec into one spec per tag used by its operations,
+// e.g. to publish a document per team out of a single spec. Each spec holds
+// the operations with the tag, the definitions they use and the ones the
+// input doesn't use, like FilterSpecByTags, so it is self-contained.
+// Operations with several tags are in the spec of each, and operations
+// without tags are in the spec of the "default" tag.
+//
+// The input is not modified and the specs don't share data structures with
+// it nor with each other.
+func SplitByTag(sp *spec.Swagger) map[string]*spec.Swagger {
+	ret := map[string]*spec.Swagger{}
+	if sp.Paths == nil {
+		return ret
+	}
+	tags := map[string]bool{}
+	for _, pathItem := range sp.Paths.Paths {
+		for _, m := range pathItem.Operations() {
+			op := m.Operation
+			if len(op.Tags) == 0 {
+				tags[defaultTag] = true
+			}
+			for _, t := range op.Tags {
+				tags[t] = true
+			}
+		}
+	}
+	for tag := range tags {
+		tag := tag
+		ret[tag] = filterSpecByTags(sp, func(opTags []string) bool {
+			if len(opTags) == 0 {
+				return tag == defaultTag
+			}
+			for _, t := range opTags {
+				if t == tag {
+					return true
+				}
+			}
+			return false
+		}).DeepCopy()
+	}
+	return ret
+}
+
+// filterSpecByTags keeps the operations whose tags are accepted by keep,
+// see FilterSpecByTagsWithoutSideEffects.
+func filterSpecByTags(sp *spec.Swagger, keep func(tags []string) bool) *spec.Swagger {
+	if sp.Paths == nil {
+		return sp
+	}
+
+	// As for paths, only remove the definitions that become unused.
+	initialUsedDefinitions := usedDefinitionForSpec(sp)
+
+	usedTags := map[string]bool{}
+	filter := func(op *spec.Operation) *spec.Operation {
+		if op == nil || !keep(op.Tags) {
+			return nil
+		}
+		for _, t := range op.Tags {
+			usedTags[t] = true
+		}
+		return op
+	}
+	ret := *sp
+	ret.Paths = &spec.Paths{
+		VendorExtensible: sp.Paths.VendorExtensible,
+		Paths:            map[string]spec.PathItem{},
+	}
+	for path, pathItem := range sp.Paths.Paths {
+		pathItem.Get = filter(pathItem.Get)
+		pathItem.Put = filter(pathItem.Put)
+		pathItem.Post = filter(pathItem.Post)
+		pathItem.Delete = filter(pathItem.Delete)
+		pathItem.Options = filter(pathItem.Options)
+		pathItem.Head = filter(pathItem.Head)
+		pathItem.Patch = filter(pathItem.Patch)
+		if pathItem.Get == nil && pathItem.Put == nil && pathItem.Post == nil && pathItem.Delete == nil &&
+			pathItem.Options == nil && pathItem.Head == nil && pathItem.Patch == nil {
+			continue
+		}
+		ret.Paths.Paths[path] = pathItem
+	}
+
+	if sp.Tags != nil {
+		ret.Tags = []spec.Tag{}
+		for _, t := range sp.Tags {
+			if usedTags[t.Name] {
+				ret.Tags = append(ret.Tags, t)
+			}
+		}
+	}
+	ret.Definitions = prunedDefinitions(&ret, sp.Definitions, initialUsedDefinitions)
+	return &ret
+}
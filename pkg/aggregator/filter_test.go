@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const taggedSpec = `
+swagger: "2.0"
+tags:
+- name: public
+- name: internal
+- name: shared
+paths:
+  /pets:
+    get:
+      tags: [public, shared]
+      responses:
+        200:
+          description: OK
+          schema:
+            $ref: "#/definitions/Pet"
+    delete:
+      tags: [internal]
+      responses:
+        200:
+          description: OK
+          schema:
+            $ref: "#/definitions/Receipt"
+  /admin:
+    post:
+      tags: [internal]
+      parameters:
+      - name: body
+        in: body
+        schema:
+          $ref: "#/definitions/Command"
+      responses:
+        200:
+          description: OK
+  /health:
+    get:
+      responses:
+        200:
+          description: OK
+definitions:
+  Pet:
+    type: object
+    properties:
+      owner:
+        $ref: "#/definitions/Owner"
+  Owner:
+    type: string
+  Receipt:
+    type: string
+  Command:
+    type: object
+    properties:
+      owner:
+        $ref: "#/definitions/Owner"
+  Orphan:
+    type: string
+`
+
+func TestFilterSpecByTags(t *testing.T) {
+	sp := mustSpec(t, taggedSpec)
+	filtered := FilterSpecByTagsWithoutSideEffects(sp, []string{"public"})
+
+	require.Len(t, filtered.Paths.Paths, 1)
+	pets := filtered.Paths.Paths["/pets"]
+	assert.NotNil(t, pets.Get)
+	assert.Nil(t, pets.Delete)
+	var tags []string
+	for _, tag := range filtered.Tags {
+		tags = append(tags, tag.Name)
+	}
+	assert.Equal(t, []string{"public", "shared"}, tags)
+	var defs []string
+	for name := range filtered.Definitions {
+		defs = append(defs, name)
+	}
+	// Orphan was never used, so it is kept.
+	assert.ElementsMatch(t, []string{"Pet", "Owner", "Orphan"}, defs)
+
+	// The input is not modified.
+	assert.Len(t, sp.Paths.Paths, 3)
+	assert.NotNil(t, sp.Paths.Paths["/pets"].Delete)
+	assert.Len(t, sp.Definitions, 5)
+
+	FilterSpecByTags(sp, []string{"internal"})
+	assert.Len(t, sp.Paths.Paths, 2)
+	assert.Nil(t, sp.Paths.Paths["/pets"].Get)
+	assert.NotNil(t, sp.Paths.Paths["/pets"].Delete)
+	assert.Contains(t, sp.Definitions, "Owner")
+	assert.Contains(t, sp.Definitions, "Command")
+	assert.NotContains(t, sp.Definitions, "Pet")
+	assert.Len(t, sp.Tags, 1)
+}
+
+func TestFilterSpecByTagsAndPaths(t *testing.T) {
+	sp := FilterSpecByPathsWithoutSideEffects(mustSpec(t, taggedSpec), []string{"/pets", "/health"})
+	sp = FilterSpecByTagsWithoutSideEffects(sp, []string{"internal"})
+	require.Len(t, sp.Paths.Paths, 1)
+	assert.NotNil(t, sp.Paths.Paths["/pets"].Delete)
+	assert.Contains(t, sp.Definitions, "Receipt")
+	assert.NotContains(t, sp.Definitions, "Command")
+	assert.NotContains(t, sp.Definitions, "Owner")
+}
+
+func TestSplitByTag(t *testing.T) {
+	sp := mustSpec(t, taggedSpec)
+	specs := SplitByTag(sp)
+	var tags []string
+	for tag := range specs {
+		tags = append(tags, tag)
+	}
+	assert.ElementsMatch(t, []string{"public", "internal", "shared", "default"}, tags)
+
+	shared := specs["shared"]
+	require.Len(t, shared.Paths.Paths, 1)
+	assert.NotNil(t, shared.Paths.Paths["/pets"].Get)
+	assert.Nil(t, shared.Paths.Paths["/pets"].Delete)
+	assert.Contains(t, shared.Definitions, "Pet")
+	assert.Contains(t, shared.Definitions, "Owner")
+	assert.NotContains(t, shared.Definitions, "Receipt")
+
+	internal := specs["internal"]
+	assert.Len(t, internal.Paths.Paths, 2)
+	assert.Contains(t, internal.Definitions, "Command")
+	assert.Contains(t, internal.Definitions, "Owner")
+	assert.NotContains(t, internal.Definitions, "Pet")
+
+	def := specs["default"]
+	require.Len(t, def.Paths.Paths, 1)
+	assert.NotNil(t, def.Paths.Paths["/health"].Get)
+	assert.Empty(t, def.Tags)
+
+	// The specs don't share data with the input.
+	owner := internal.Definitions["Owner"]
+	owner.Description = "changed"
+	internal.Definitions["Owner"] = owner
+	internal.Paths.Paths["/admin"].Post.Summary = "changed"
+	assert.Empty(t, sp.Definitions["Owner"].Description)
+	assert.Empty(t, shared.Definitions["Owner"].Description)
+	assert.Empty(t, sp.Paths.Paths["/admin"].Post.Summary)
+}
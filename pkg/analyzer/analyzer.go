@@ -0,0 +1,282 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package analyzer computes a resolved view of the operations of a spec:
+// their effective parameters, merged from the path item and the operation
+// with references followed, their effective media types and security
+// requirements, their responses and the definitions they reach. It is the
+// common ground of code binding requests, routing them or generating
+// clients, which otherwise all need to apply the inheritance rules of the
+// Swagger 2.0 specification themselves. Stats reports the size and the
+// complexity of a spec on top of this view.
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	definitionPrefix = "#/definitions/"
+	parameterPrefix  = "#/parameters/"
+	responsePrefix   = "#/responses/"
+)
+
+// Operation is the resolved view of an operation. Its slices and maps must
+// not be modified.
+type Operation struct {
+	// Method is the uppercase HTTP method, e.g. "GET".
+	Method string
+	Path   string
+	// Operation is the operation as declared in the spec.
+	Operation *spec.Operation
+	// Parameters are the path item parameters not overridden by the
+	// operation, followed by the operation parameters, with references
+	// followed.
+	Parameters []spec.Parameter
+	// Consumes and Produces are the media types of the operation, or the
+	// ones of the spec when the operation doesn't declare any.
+	Consumes []string
+	Produces []string
+	// Security are the security requirements of the operation, or the ones
+	// of the spec when the operation doesn't declare any. An empty,
+	// non-nil slice means the operation requires no security.
+	Security []map[string][]string
+	// Responses are the responses of the operation by status code, with
+	// references followed.
+	Responses map[int]spec.Response
+	// DefaultResponse is the default response with references followed,
+	// or nil.
+	DefaultResponse *spec.Response
+}
+
+// Response returns the response documenting a status code: the response
+// of the status code itself, else the default response. It returns false
+// if none applies. Swagger 2.0 has no status code ranges; they are dropped
+// when converting OpenAPI 3 specs, see spec3.Responses.Match for these.
+func (o *Operation) Response(code int) (*spec.Response, bool) {
+	if r, ok := o.Responses[code]; ok {
+		return &r, true
+	}
+	return o.DefaultResponse, o.DefaultResponse != nil
+}
+
+// Analyzer is a read-only resolved view of a spec. It must not be used
+// after the spec is modified.
+type Analyzer struct {
+	spec       *spec.Swagger
+	operations []*Operation
+	byID       map[string]*Operation
+}
+
+// New analyzes a spec. It fails when a parameter or a response references
+// something else than a parameter or a response of the spec.
+func New(s *spec.Swagger) (*Analyzer, error) {
+	a := &Analyzer{spec: s, byID: map[string]*Operation{}}
+	if s.Paths == nil {
+		return a, nil
+	}
+	paths := make([]string, 0, len(s.Paths.Paths))
+	for p := range s.Paths.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		item := s.Paths.Paths[p]
+		common, err := a.parameters("paths."+p, item.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range item.Operations() {
+			op, err := a.operation(p, m.Method, common, m.Operation)
+			if err != nil {
+				return nil, err
+			}
+			a.operations = append(a.operations, op)
+			if id := m.Operation.ID; id != "" {
+				a.byID[id] = op
+			}
+		}
+	}
+	return a, nil
+}
+
+func (a *Analyzer) operation(path, method string, common []spec.Parameter, op *spec.Operation) (*Operation, error) {
+	name := "paths." + path + "." + strings.ToLower(method)
+	params, err := a.parameters(name, op.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	ret := &Operation{
+		Method:     method,
+		Path:       path,
+		Operation:  op,
+		Parameters: mergeParameters(common, params),
+		Consumes:   op.Consumes,
+		Produces:   op.Produces,
+		Security:   op.Security,
+		Responses:  map[int]spec.Response{},
+	}
+	if ret.Consumes == nil {
+		ret.Consumes = a.spec.Consumes
+	}
+	if ret.Produces == nil {
+		ret.Produces = a.spec.Produces
+	}
+	if ret.Security == nil {
+		ret.Security = a.spec.Security
+	}
+	if op.Responses == nil {
+		return ret, nil
+	}
+	for code, r := range op.Responses.StatusCodeResponses {
+		resolved, err := a.response(fmt.Sprintf("%s.responses.%d", name, code), r)
+		if err != nil {
+			return nil, err
+		}
+		ret.Responses[code] = resolved
+	}
+	if op.Responses.Default != nil {
+		resolved, err := a.response(name+".responses.default", *op.Responses.Default)
+		if err != nil {
+			return nil, err
+		}
+		ret.DefaultResponse = &resolved
+	}
+	return ret, nil
+}
+
+// parameters returns the given parameters with their references followed.
+func (a *Analyzer) parameters(name string, params []spec.Parameter) ([]spec.Parameter, error) {
+	ret := make([]spec.Parameter, 0, len(params))
+	for i, p := range params {
+		if ref := p.Ref.String(); ref != "" {
+			resolved, ok := a.spec.Parameters[strings.TrimPrefix(ref, parameterPrefix)]
+			if !strings.HasPrefix(ref, parameterPrefix) || !ok {
+				return nil, fmt.Errorf("%s.parameters[%d]: can't resolve %s", name, i, ref)
+			}
+			p = resolved
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+func (a *Analyzer) response(name string, r spec.Response) (spec.Response, error) {
+	ref := r.Ref.String()
+	if ref == "" {
+		return r, nil
+	}
+	resolved, ok := a.spec.Responses[strings.TrimPrefix(ref, responsePrefix)]
+	if !strings.HasPrefix(ref, responsePrefix) || !ok {
+		return spec.Response{}, fmt.Errorf("%s: can't resolve %s", name, ref)
+	}
+	return resolved, nil
+}
+
+// mergeParameters returns the path item parameters not overridden by the
+// operation, followed by the operation parameters.
+func mergeParameters(common, params []spec.Parameter) []spec.Parameter {
+	overridden := map[string]bool{}
+	for _, p := range params {
+		overridden[p.In+"/"+p.Name] = true
+	}
+	var ret []spec.Parameter
+	for _, p := range common {
+		if !overridden[p.In+"/"+p.Name] {
+			ret = append(ret, p)
+		}
+	}
+	return append(ret, params...)
+}
+
+// Operations returns the operations of the spec sorted by path and method.
+func (a *Analyzer) Operations() []*Operation {
+	return a.operations
+}
+
+// OperationByID returns the operation with the given ID.
+func (a *Analyzer) OperationByID(id string) (*Operation, bool) {
+	op, ok := a.byID[id]
+	return op, ok
+}
+
+// OperationAt returns the operation for a method and path. The method is
+// case insensitive.
+func (a *Analyzer) OperationAt(method, path string) (*Operation, bool) {
+	method = strings.ToUpper(method)
+	for _, op := range a.operations {
+		if op.Method == method && op.Path == path {
+			return op, true
+		}
+	}
+	return nil, false
+}
+
+// ReachableSchemas returns the sorted names of the definitions reachable
+// from the parameters and the responses of an operation, directly or
+// through other definitions. References to missing definitions are
+// ignored.
+func (a *Analyzer) ReachableSchemas(op *Operation) []string {
+	seen := map[string]bool{}
+	var queue []string
+	visit := func(ref string) {
+		if !strings.HasPrefix(ref, definitionPrefix) {
+			return
+		}
+		name := ref[len(definitionPrefix):]
+		if _, ok := a.spec.Definitions[name]; ok && !seen[name] {
+			seen[name] = true
+			queue = append(queue, name)
+		}
+	}
+	for _, p := range op.Parameters {
+		walkRefs(p.Schema, visit)
+	}
+	responses := make([]spec.Response, 0, len(op.Responses)+1)
+	for _, r := range op.Responses {
+		responses = append(responses, r)
+	}
+	if op.DefaultResponse != nil {
+		responses = append(responses, *op.DefaultResponse)
+	}
+	for _, r := range responses {
+		walkRefs(r.Schema, visit)
+	}
+	for len(queue) > 0 {
+		def := a.spec.Definitions[queue[0]]
+		queue = queue[1:]
+		walkRefs(&def, visit)
+	}
+	ret := make([]string, 0, len(seen))
+	for name := range seen {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// walkRefs calls fn with the references of a schema and of its subschemas.
+func walkRefs(s *spec.Schema, fn func(ref string)) {
+	spec.WalkSchema(s, "", func(sub *spec.Schema, _ string) {
+		if ref := sub.Ref.String(); ref != "" {
+			fn(ref)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const testSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "security": [{"token": []}],
+  "parameters": {
+    "id": {"name": "id", "in": "path", "required": true, "type": "string"},
+    "limit": {"name": "limit", "in": "query", "type": "integer"}
+  },
+  "responses": {
+    "Error": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}
+  },
+  "paths": {
+    "/pets": {
+      "parameters": [{"$ref": "#/parameters/limit"}],
+      "get": {
+        "operationId": "listPets",
+        "security": [],
+        "responses": {
+          "200": {"description": "ok", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}},
+          "default": {"$ref": "#/responses/Error"}
+        }
+      },
+      "post": {
+        "operationId": "createPet",
+        "consumes": ["application/yaml"],
+        "parameters": [
+          {"name": "limit", "in": "query", "type": "string"},
+          {"name": "body", "in": "body", "schema": {"$ref": "#/definitions/NewPet"}}
+        ],
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/pets/{id}": {
+      "parameters": [{"$ref": "#/parameters/id"}],
+      "delete": {
+        "produces": ["text/plain"],
+        "security": [{"basic": []}],
+        "responses": {"204": {"description": "deleted"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/Owner"}}},
+    "Owner": {"type": "object", "properties": {"pets": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}},
+    "NewPet": {"allOf": [{"$ref": "#/definitions/Tag"}]},
+    "Tag": {"type": "string"},
+    "Error": {"type": "object"},
+    "Unused": {"type": "object"}
+  }
+}`
+
+func newTestAnalyzer(t *testing.T) *Analyzer {
+	var s spec.Swagger
+	require.NoError(t, json.Unmarshal([]byte(testSpec), &s))
+	a, err := New(&s)
+	require.NoError(t, err)
+	return a
+}
+
+func TestOperations(t *testing.T) {
+	a := newTestAnalyzer(t)
+	var ops []string
+	for _, op := range a.Operations() {
+		ops = append(ops, op.Method+" "+op.Path)
+	}
+	assert.Equal(t, []string{"GET /pets", "POST /pets", "DELETE /pets/{id}"}, ops)
+
+	list, ok := a.OperationByID("listPets")
+	require.True(t, ok)
+	require.Len(t, list.Parameters, 1)
+	assert.Equal(t, "integer", list.Parameters[0].Type)
+	assert.Equal(t, []string{"application/json"}, list.Consumes)
+	assert.Equal(t, []map[string][]string{}, list.Security)
+	require.NotNil(t, list.DefaultResponse)
+	assert.Equal(t, "error", list.DefaultResponse.Description)
+
+	create, ok := a.OperationAt("post", "/pets")
+	require.True(t, ok)
+	require.Len(t, create.Parameters, 2)
+	assert.Equal(t, "string", create.Parameters[0].Type)
+	assert.Equal(t, "body", create.Parameters[1].Name)
+	assert.Equal(t, []string{"application/yaml"}, create.Consumes)
+	assert.Equal(t, []string{"application/json"}, create.Produces)
+	assert.Equal(t, []map[string][]string{{"token": {}}}, create.Security)
+
+	del, ok := a.OperationAt("DELETE", "/pets/{id}")
+	require.True(t, ok)
+	require.Len(t, del.Parameters, 1)
+	assert.Equal(t, "path", del.Parameters[0].In)
+	assert.Equal(t, []string{"text/plain"}, del.Produces)
+	assert.Equal(t, []map[string][]string{{"basic": {}}}, del.Security)
+	assert.Contains(t, del.Responses, 204)
+
+	r, ok := list.Response(200)
+	require.True(t, ok)
+	assert.Equal(t, "ok", r.Description)
+	r, ok = list.Response(503)
+	require.True(t, ok)
+	assert.Equal(t, "error", r.Description)
+	_, ok = del.Response(404)
+	assert.False(t, ok)
+
+	_, ok = a.OperationByID("missing")
+	assert.False(t, ok)
+	_, ok = a.OperationAt("GET", "/missing")
+	assert.False(t, ok)
+}
+
+func TestReachableSchemas(t *testing.T) {
+	a := newTestAnalyzer(t)
+	list, _ := a.OperationByID("listPets")
+	assert.Equal(t, []string{"Error", "Owner", "Pet"}, a.ReachableSchemas(list))
+	create, _ := a.OperationByID("createPet")
+	assert.Equal(t, []string{"NewPet", "Tag"}, a.ReachableSchemas(create))
+	del, _ := a.OperationAt("DELETE", "/pets/{id}")
+	assert.Empty(t, a.ReachableSchemas(del))
+}
+
+func TestNewUnresolvable(t *testing.T) {
+	s := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+		"/a": {PathItemProps: spec.PathItemProps{Get: &spec.Operation{OperationProps: spec.OperationProps{
+			Parameters: []spec.Parameter{{Refable: spec.Refable{Ref: spec.MustCreateRef("#/parameters/missing")}}},
+		}}}},
+	}}}}
+	_, err := New(s)
+	assert.EqualError(t, err, "paths./a.get.parameters[0]: can't resolve #/parameters/missing")
+
+	s.Paths.Paths["/a"].Get.Parameters = nil
+	s.Paths.Paths["/a"].Get.Responses = &spec.Responses{ResponsesProps: spec.ResponsesProps{
+		Default: &spec.Response{Refable: spec.Refable{Ref: spec.MustCreateRef("#/definitions/Error")}},
+	}}
+	_, err = New(s)
+	assert.EqualError(t, err, "paths./a.get.responses.default: can't resolve #/definitions/Error")
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"sort"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// largestSchemas is the number of definitions reported by Stats.Largest.
+const largestSchemas = 10
+
+// Stats summarizes the size and the complexity of a spec, e.g. to alert
+// before it grows too expensive to serve or to validate.
+type Stats struct {
+	Operations int `json:"operations"`
+	// Parameters is the number of effective parameters of all operations.
+	Parameters int `json:"parameters"`
+	// Schemas is the number of definitions.
+	Schemas int `json:"schemas"`
+	// MaxDepth is the deepest nesting of subschemas in a definition or an
+	// operation, references not followed. A schema without subschemas has
+	// depth 1.
+	MaxDepth int `json:"maxDepth"`
+	// MaxRefFanOut is the largest number of distinct references held by a
+	// single definition.
+	MaxRefFanOut int `json:"maxRefFanOut"`
+	// Costs are the estimated validation costs of the operations, highest
+	// first.
+	Costs []OperationCost `json:"costs,omitempty"`
+	// Largest are the largest definitions, at most 10, largest first.
+	Largest []SchemaSize `json:"largest,omitempty"`
+}
+
+// OperationCost is the estimated cost of validating the requests and the
+// responses of an operation: the number of schema nodes of its parameters
+// and responses, plus the ones of the definitions they reach. Each
+// definition is counted once however often it is referenced, so this is
+// an estimate rather than a bound.
+type OperationCost struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	ID     string `json:"operationId,omitempty"`
+	Cost   int    `json:"cost"`
+}
+
+// SchemaSize is the number of schema nodes of a definition, references not
+// followed.
+type SchemaSize struct {
+	Name  string `json:"name"`
+	Nodes int    `json:"nodes"`
+}
+
+// Analyze computes the statistics of a spec. It fails like New.
+func Analyze(s *spec.Swagger) (*Stats, error) {
+	a, err := New(s)
+	if err != nil {
+		return nil, err
+	}
+	return a.Stats(), nil
+}
+
+// Stats computes the statistics of the analyzed spec.
+func (a *Analyzer) Stats() *Stats {
+	st := &Stats{Operations: len(a.operations), Schemas: len(a.spec.Definitions)}
+	sizes := make(map[string]int, len(a.spec.Definitions))
+	for name := range a.spec.Definitions {
+		def := a.spec.Definitions[name]
+		nodes, depth := measure(&def)
+		sizes[name] = nodes
+		st.Largest = append(st.Largest, SchemaSize{Name: name, Nodes: nodes})
+		if depth > st.MaxDepth {
+			st.MaxDepth = depth
+		}
+		refs := map[string]bool{}
+		walkRefs(&def, func(ref string) { refs[ref] = true })
+		if len(refs) > st.MaxRefFanOut {
+			st.MaxRefFanOut = len(refs)
+		}
+	}
+	sort.Slice(st.Largest, func(i, j int) bool {
+		if st.Largest[i].Nodes != st.Largest[j].Nodes {
+			return st.Largest[i].Nodes > st.Largest[j].Nodes
+		}
+		return st.Largest[i].Name < st.Largest[j].Name
+	})
+	if len(st.Largest) > largestSchemas {
+		st.Largest = st.Largest[:largestSchemas]
+	}
+
+	for _, op := range a.operations {
+		st.Parameters += len(op.Parameters)
+		cost := 0
+		add := func(s *spec.Schema) {
+			nodes, depth := measure(s)
+			cost += nodes
+			if depth > st.MaxDepth {
+				st.MaxDepth = depth
+			}
+		}
+		for i := range op.Parameters {
+			p := &op.Parameters[i]
+			if p.Schema != nil {
+				add(p.Schema)
+				continue
+			}
+			// Non-body parameters are a chain of items.
+			for items := p.Items; ; items = items.Items {
+				cost++
+				if items == nil {
+					break
+				}
+			}
+		}
+		for _, r := range op.Responses {
+			add(r.Schema)
+		}
+		if op.DefaultResponse != nil {
+			add(op.DefaultResponse.Schema)
+		}
+		for _, name := range a.ReachableSchemas(op) {
+			cost += sizes[name]
+		}
+		st.Costs = append(st.Costs, OperationCost{Method: op.Method, Path: op.Path, ID: op.Operation.ID, Cost: cost})
+	}
+	// Operations are sorted by path and method already.
+	sort.SliceStable(st.Costs, func(i, j int) bool {
+		return st.Costs[i].Cost > st.Costs[j].Cost
+	})
+	return st
+}
+
+// measure returns the number of nodes and the depth of a schema,
+// references not followed. A nil schema has none.
+func measure(s *spec.Schema) (nodes, depth int) {
+	if s == nil {
+		return 0, 0
+	}
+	nodes = 1
+	spec.EachSubschema(s, func(sub *spec.Schema, _ string) {
+		n, d := measure(sub)
+		nodes += n
+		if d > depth {
+			depth = d
+		}
+	})
+	return nodes, depth + 1
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestAnalyze(t *testing.T) {
+	var s spec.Swagger
+	require.NoError(t, json.Unmarshal([]byte(testSpec), &s))
+	st, err := Analyze(&s)
+	require.NoError(t, err)
+	assert.Equal(t, &Stats{
+		Operations:   3,
+		Parameters:   4,
+		Schemas:      6,
+		MaxDepth:     3,
+		MaxRefFanOut: 1,
+		Costs: []OperationCost{
+			{Method: "GET", Path: "/pets", ID: "listPets", Cost: 10},
+			{Method: "POST", Path: "/pets", ID: "createPet", Cost: 5},
+			{Method: "DELETE", Path: "/pets/{id}", Cost: 1},
+		},
+		Largest: []SchemaSize{
+			{Name: "Owner", Nodes: 3},
+			{Name: "NewPet", Nodes: 2},
+			{Name: "Pet", Nodes: 2},
+			{Name: "Error", Nodes: 1},
+			{Name: "Tag", Nodes: 1},
+			{Name: "Unused", Nodes: 1},
+		},
+	}, st)
+
+	_, err = Analyze(&spec.Swagger{SwaggerProps: spec.SwaggerProps{Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+		"/a": {PathItemProps: spec.PathItemProps{Parameters: []spec.Parameter{
+			{Refable: spec.Refable{Ref: spec.MustCreateRef("#/parameters/missing")}},
+		}}},
+	}}}})
+	assert.Error(t, err)
+}
+
+func TestAnalyzeLargest(t *testing.T) {
+	s := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: spec.Definitions{}}}
+	all := spec.Schema{SchemaProps: spec.SchemaProps{Properties: map[string]spec.Schema{}}}
+	for i := 0; i < 12; i++ {
+		name := fmt.Sprintf("D%02d", i)
+		s.Definitions[name] = spec.Schema{}
+		all.Properties[name] = spec.Schema{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("#/definitions/" + name)}}
+	}
+	s.Definitions["All"] = all
+	st, err := Analyze(s)
+	require.NoError(t, err)
+	assert.Equal(t, 13, st.Schemas)
+	assert.Equal(t, 12, st.MaxRefFanOut)
+	assert.Equal(t, 2, st.MaxDepth)
+	require.Len(t, st.Largest, 10)
+	assert.Equal(t, SchemaSize{Name: "All", Nodes: 13}, st.Largest[0])
+	assert.Equal(t, SchemaSize{Name: "D08", Nodes: 1}, st.Largest[9])
+	assert.Empty(t, st.Costs)
+}
@@ -102,7 +102,10 @@ func newOpenAPI(config *common.Config) openAPI {
 	}
 	if o.config.GetDefinitionName == nil {
 		o.config.GetDefinitionName = func(name string) (string, spec.Extensions) {
-			return name[strings.LastIndex(name, "/")+1:], nil
+			// Keep the package paths of type arguments, e.g.
+			// "v1.List[k8s.io/api/core/v1.Pod]".
+			base, _ := util.SplitTypeArguments(name)
+			return name[strings.LastIndex(base, "/")+1:], nil
 		}
 	}
 	o.definitions = o.config.GetDefinitions(func(name string) spec.Ref {
@@ -0,0 +1,254 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// ValidationMarkers are the validation constraints declared next to a Go
+// type, e.g. with the "+maxLength=63" comment tags read by openapi-gen or
+// the `openapi:"maxLength=63"` struct tags read at runtime.
+//
+// Supported markers are minimum, maximum, exclusiveMinimum,
+// exclusiveMaximum, multipleOf, minLength, maxLength, pattern, minItems,
+// maxItems, uniqueItems, minProperties, maxProperties, keyPattern, enum,
+// default and nullable. keyPattern constrains the keys of maps and is set as
+// the x-key-pattern extension. Boolean markers may omit their value. Enum values are comma
+// separated and default is a JSON value; enum values that aren't valid
+// JSON are strings.
+type ValidationMarkers struct {
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum bool
+	ExclusiveMaximum bool
+	MultipleOf       *float64
+	MinLength        *int64
+	MaxLength        *int64
+	Pattern          string
+	MinItems         *int64
+	MaxItems         *int64
+	UniqueItems      bool
+	MinProperties    *int64
+	MaxProperties    *int64
+	KeyPattern       string
+	Enum             []interface{}
+	Default          interface{}
+	Nullable         bool
+}
+
+// ParseValidationMarkers parses the validation markers of the given tags,
+// mapping marker names to their values like gengo's
+// types.ExtractCommentTags does. Other tags are ignored. It returns nil if
+// there is no validation marker.
+func ParseValidationMarkers(tags map[string][]string) (*ValidationMarkers, error) {
+	ret := &ValidationMarkers{}
+	found := false
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := tags[name]
+		if len(values) == 0 {
+			continue
+		}
+		parse, ok := validationMarkerParsers[name]
+		if !ok {
+			continue
+		}
+		if len(values) > 1 {
+			return nil, fmt.Errorf("multiple values are not allowed for marker %s", name)
+		}
+		if err := parse(ret, values[0]); err != nil {
+			return nil, fmt.Errorf("invalid %s marker %q: %v", name, values[0], err)
+		}
+		found = true
+	}
+	if !found {
+		return nil, nil
+	}
+	return ret, nil
+}
+
+// ParseValidationStructTag parses the validation markers of an openapi
+// struct tag value, e.g. "minimum=0;maxLength=63;nullable". Markers are
+// separated by semicolons.
+func ParseValidationStructTag(tag string) (*ValidationMarkers, error) {
+	tags := map[string][]string{}
+	for _, marker := range strings.Split(tag, ";") {
+		marker = strings.TrimSpace(marker)
+		if marker == "" {
+			continue
+		}
+		name, value := marker, ""
+		if i := strings.Index(marker, "="); i >= 0 {
+			name, value = marker[:i], marker[i+1:]
+		}
+		if _, ok := validationMarkerParsers[name]; !ok {
+			return nil, fmt.Errorf("unknown validation marker %q", name)
+		}
+		tags[name] = append(tags[name], value)
+	}
+	return ParseValidationMarkers(tags)
+}
+
+// ApplyTo sets the constraints on a schema.
+func (v *ValidationMarkers) ApplyTo(s *spec.Schema) {
+	if v.Minimum != nil {
+		s.WithMinimum(*v.Minimum, v.ExclusiveMinimum)
+	}
+	if v.Maximum != nil {
+		s.WithMaximum(*v.Maximum, v.ExclusiveMaximum)
+	}
+	if v.MultipleOf != nil {
+		s.WithMultipleOf(*v.MultipleOf)
+	}
+	if v.MinLength != nil {
+		s.WithMinLength(*v.MinLength)
+	}
+	if v.MaxLength != nil {
+		s.WithMaxLength(*v.MaxLength)
+	}
+	if v.Pattern != "" {
+		s.WithPattern(v.Pattern)
+	}
+	if v.MinItems != nil {
+		s.WithMinItems(*v.MinItems)
+	}
+	if v.MaxItems != nil {
+		s.WithMaxItems(*v.MaxItems)
+	}
+	if v.UniqueItems {
+		s.UniqueValues()
+	}
+	if v.MinProperties != nil {
+		s.WithMinProperties(*v.MinProperties)
+	}
+	if v.MaxProperties != nil {
+		s.WithMaxProperties(*v.MaxProperties)
+	}
+	if v.KeyPattern != "" {
+		// Copy the extensions, which may be shared with other schemas.
+		extensions := spec.Extensions{}
+		for k, e := range s.Extensions {
+			extensions[k] = e
+		}
+		extensions.Add("x-key-pattern", v.KeyPattern)
+		s.Extensions = extensions
+	}
+	if len(v.Enum) > 0 {
+		s.WithEnum(v.Enum...)
+	}
+	if v.Default != nil {
+		s.WithDefault(v.Default)
+	}
+	if v.Nullable {
+		s.Nullable = true
+	}
+}
+
+var validationMarkerParsers = map[string]func(v *ValidationMarkers, value string) error{
+	"minimum":          floatMarker(func(v *ValidationMarkers) **float64 { return &v.Minimum }),
+	"maximum":          floatMarker(func(v *ValidationMarkers) **float64 { return &v.Maximum }),
+	"multipleOf":       floatMarker(func(v *ValidationMarkers) **float64 { return &v.MultipleOf }),
+	"minLength":        intMarker(func(v *ValidationMarkers) **int64 { return &v.MinLength }),
+	"maxLength":        intMarker(func(v *ValidationMarkers) **int64 { return &v.MaxLength }),
+	"minItems":         intMarker(func(v *ValidationMarkers) **int64 { return &v.MinItems }),
+	"maxItems":         intMarker(func(v *ValidationMarkers) **int64 { return &v.MaxItems }),
+	"minProperties":    intMarker(func(v *ValidationMarkers) **int64 { return &v.MinProperties }),
+	"maxProperties":    intMarker(func(v *ValidationMarkers) **int64 { return &v.MaxProperties }),
+	"exclusiveMinimum": boolMarker(func(v *ValidationMarkers) *bool { return &v.ExclusiveMinimum }),
+	"exclusiveMaximum": boolMarker(func(v *ValidationMarkers) *bool { return &v.ExclusiveMaximum }),
+	"uniqueItems":      boolMarker(func(v *ValidationMarkers) *bool { return &v.UniqueItems }),
+	"nullable":         boolMarker(func(v *ValidationMarkers) *bool { return &v.Nullable }),
+	"pattern": func(v *ValidationMarkers, value string) error {
+		if value == "" {
+			return fmt.Errorf("empty pattern")
+		}
+		v.Pattern = value
+		return nil
+	},
+	"keyPattern": func(v *ValidationMarkers, value string) error {
+		if value == "" {
+			return fmt.Errorf("empty pattern")
+		}
+		v.KeyPattern = value
+		return nil
+	},
+	"enum": func(v *ValidationMarkers, value string) error {
+		if value == "" {
+			return fmt.Errorf("no values")
+		}
+		for _, e := range strings.Split(value, ",") {
+			e = strings.TrimSpace(e)
+			var parsed interface{}
+			if err := json.Unmarshal([]byte(e), &parsed); err != nil {
+				parsed = e
+			}
+			v.Enum = append(v.Enum, parsed)
+		}
+		return nil
+	},
+	"default": func(v *ValidationMarkers, value string) error {
+		return json.Unmarshal([]byte(value), &v.Default)
+	},
+}
+
+func floatMarker(field func(*ValidationMarkers) **float64) func(*ValidationMarkers, string) error {
+	return func(v *ValidationMarkers, value string) error {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("expected a number")
+		}
+		*field(v) = &f
+		return nil
+	}
+}
+
+func intMarker(field func(*ValidationMarkers) **int64) func(*ValidationMarkers, string) error {
+	return func(v *ValidationMarkers, value string) error {
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || i < 0 {
+			return fmt.Errorf("expected a non-negative integer")
+		}
+		*field(v) = &i
+		return nil
+	}
+}
+
+func boolMarker(field func(*ValidationMarkers) *bool) func(*ValidationMarkers, string) error {
+	return func(v *ValidationMarkers, value string) error {
+		if value == "" {
+			*field(v) = true
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected true or false")
+		}
+		*field(v) = b
+		return nil
+	}
+}
@@ -0,0 +1,347 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package coverage tracks which parts of a contract a test suite exercises:
+// operations, parameters, response codes and schema branches, i.e. the
+// oneOf and anyOf arms and the enum values of the schemas of request and
+// response bodies. Tests record their exchanges with a Tracker, e.g. from a
+// test client or a handler wrapper, and the Report lists what was left
+// untested.
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/kube-openapi/pkg/analyzer"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+const definitionPrefix = "#/definitions/"
+
+// Exchange is a request sent to an operation and the response it got.
+type Exchange struct {
+	// Parameters are the names of the parameters of the request.
+	Parameters []string
+	// Body is the decoded JSON body of the request, or nil.
+	Body interface{}
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// Response is the decoded JSON body of the response, or nil.
+	Response interface{}
+}
+
+// Tracker records the exchanges of a test suite. It is safe for concurrent
+// use.
+type Tracker struct {
+	a    *analyzer.Analyzer
+	spec *spec.Swagger
+
+	lock       sync.Mutex
+	operations map[*analyzer.Operation]*operation
+}
+
+type operation struct {
+	calls        int
+	parameters   map[string]bool
+	responses    map[string]bool
+	undocumented map[int]bool
+	branches     map[string]bool
+}
+
+// NewTracker returns a tracker of the operations of the analyzed spec s.
+func NewTracker(s *spec.Swagger, a *analyzer.Analyzer) *Tracker {
+	return &Tracker{a: a, spec: s, operations: map[*analyzer.Operation]*operation{}}
+}
+
+// Record records an exchange with an operation of the analyzer.
+func (t *Tracker) Record(op *analyzer.Operation, ex Exchange) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	o, ok := t.operations[op]
+	if !ok {
+		o = &operation{
+			parameters:   map[string]bool{},
+			responses:    map[string]bool{},
+			undocumented: map[int]bool{},
+			branches:     map[string]bool{},
+		}
+		t.operations[op] = o
+	}
+	o.calls++
+	for _, p := range ex.Parameters {
+		o.parameters[p] = true
+	}
+	if body := bodyParameter(op); body != nil && ex.Body != nil {
+		o.parameters[body.Name] = true
+		t.cover(body.Schema, requestLocation(op), ex.Body, o.branches)
+	}
+
+	code := strconv.Itoa(ex.StatusCode)
+	r, ok := op.Responses[ex.StatusCode]
+	switch {
+	case ok:
+	case op.DefaultResponse != nil:
+		code, r = "default", *op.DefaultResponse
+	default:
+		o.undocumented[ex.StatusCode] = true
+		return
+	}
+	o.responses[code] = true
+	if ex.Response != nil {
+		t.cover(r.Schema, responseLocation(op, code), ex.Response, o.branches)
+	}
+}
+
+// Report returns the coverage of the operations of the spec so far.
+func (t *Tracker) Report() *Report {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	r := &Report{}
+	for _, op := range t.a.Operations() {
+		o := t.operations[op]
+		if o == nil {
+			o = &operation{}
+		}
+		oc := OperationCoverage{Method: op.Method, Path: op.Path, ID: op.Operation.ID, Calls: o.calls}
+		for _, p := range op.Parameters {
+			oc.Parameters = append(oc.Parameters, Item{Name: p.Name, Covered: o.parameters[p.Name]})
+		}
+		codes := make([]int, 0, len(op.Responses))
+		for code := range op.Responses {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			name := strconv.Itoa(code)
+			oc.Responses = append(oc.Responses, Item{Name: name, Covered: o.responses[name]})
+		}
+		if op.DefaultResponse != nil {
+			oc.Responses = append(oc.Responses, Item{Name: "default", Covered: o.responses["default"]})
+		}
+		for code := range o.undocumented {
+			oc.UndocumentedResponses = append(oc.UndocumentedResponses, code)
+		}
+		sort.Ints(oc.UndocumentedResponses)
+
+		// Definitions reached through several bodies are listed, and
+		// counted, once per body.
+		var branches []string
+		seen := map[string]bool{}
+		add := func(name string) {
+			if !seen[name] {
+				seen[name] = true
+				branches = append(branches, name)
+			}
+		}
+		if body := bodyParameter(op); body != nil {
+			t.branches(body.Schema, requestLocation(op), map[string]bool{}, add)
+		}
+		for _, code := range codes {
+			t.branches(op.Responses[code].Schema, responseLocation(op, strconv.Itoa(code)), map[string]bool{}, add)
+		}
+		if op.DefaultResponse != nil {
+			t.branches(op.DefaultResponse.Schema, responseLocation(op, "default"), map[string]bool{}, add)
+		}
+		for _, b := range branches {
+			oc.Branches = append(oc.Branches, Item{Name: b, Covered: o.branches[b]})
+		}
+
+		oc.Covered, oc.Total = o.calls > 0, 1
+		for _, l := range [][]Item{oc.Parameters, oc.Responses, oc.Branches} {
+			for _, item := range l {
+				oc.Total++
+				if item.Covered {
+					r.Covered++
+				}
+			}
+		}
+		if oc.Covered {
+			r.Covered++
+		}
+		r.Total += oc.Total
+		r.Operations = append(r.Operations, oc)
+	}
+	return r
+}
+
+func bodyParameter(op *analyzer.Operation) *spec.Parameter {
+	for i := range op.Parameters {
+		if op.Parameters[i].In == "body" {
+			return &op.Parameters[i]
+		}
+	}
+	return nil
+}
+
+// requestLocation and responseLocation name the inline body schemas of an
+// operation in branch names, since they have no definition name.
+func requestLocation(op *analyzer.Operation) string {
+	return op.Method + " " + op.Path + " request"
+}
+
+func responseLocation(op *analyzer.Operation, code string) string {
+	return op.Method + " " + op.Path + " " + code + " response"
+}
+
+// resolve returns the definition a schema references, or the schema and its
+// location when it isn't a reference.
+func (t *Tracker) resolve(s *spec.Schema, loc string) (*spec.Schema, string) {
+	ref := s.Ref.String()
+	if !strings.HasPrefix(ref, definitionPrefix) {
+		return s, loc
+	}
+	def, ok := t.spec.Definitions[ref[len(definitionPrefix):]]
+	if !ok {
+		return nil, ""
+	}
+	return &def, ref
+}
+
+// branches calls add with the names of the branches of s at loc, following
+// references once.
+func (t *Tracker) branches(s *spec.Schema, loc string, visited map[string]bool, add func(name string)) {
+	if s == nil {
+		return
+	}
+	if s, loc = t.resolve(s, loc); s == nil || visited[loc] {
+		return
+	}
+	visited[loc] = true
+	for _, v := range s.Enum {
+		add(enumBranch(loc, v))
+	}
+	for _, l := range []struct {
+		key     string
+		schemas []spec.Schema
+	}{{"oneOf", s.OneOf}, {"anyOf", s.AnyOf}} {
+		for i := range l.schemas {
+			add(fmt.Sprintf("%s: %s[%d]", loc, l.key, i))
+			t.branches(&l.schemas[i], fmt.Sprintf("%s/%s/%d", loc, l.key, i), visited, add)
+		}
+	}
+	for i := range s.AllOf {
+		t.branches(&s.AllOf[i], fmt.Sprintf("%s/allOf/%d", loc, i), visited, add)
+	}
+	for _, name := range sortedKeys(s.Properties) {
+		prop := s.Properties[name]
+		t.branches(&prop, loc+"/properties/"+name, visited, add)
+	}
+	if s.AdditionalProperties != nil {
+		t.branches(s.AdditionalProperties.Schema, loc+"/additionalProperties", visited, add)
+	}
+	if s.Items != nil {
+		t.branches(s.Items.Schema, loc+"/items", visited, add)
+		for i := range s.Items.Schemas {
+			t.branches(&s.Items.Schemas[i], fmt.Sprintf("%s/items/%d", loc, i), visited, add)
+		}
+	}
+}
+
+// cover marks the branches of s at loc exercised by value.
+func (t *Tracker) cover(s *spec.Schema, loc string, value interface{}, covered map[string]bool) {
+	if s == nil {
+		return
+	}
+	if s, loc = t.resolve(s, loc); s == nil {
+		return
+	}
+	for _, v := range s.Enum {
+		if equal(v, value) {
+			covered[enumBranch(loc, v)] = true
+		}
+	}
+	for _, l := range []struct {
+		key     string
+		schemas []spec.Schema
+	}{{"oneOf", s.OneOf}, {"anyOf", s.AnyOf}} {
+		for i := range l.schemas {
+			if t.matches(&l.schemas[i], value) {
+				covered[fmt.Sprintf("%s: %s[%d]", loc, l.key, i)] = true
+				t.cover(&l.schemas[i], fmt.Sprintf("%s/%s/%d", loc, l.key, i), value, covered)
+			}
+		}
+	}
+	for i := range s.AllOf {
+		t.cover(&s.AllOf[i], fmt.Sprintf("%s/allOf/%d", loc, i), value, covered)
+	}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			if prop, ok := s.Properties[k]; ok {
+				t.cover(&prop, loc+"/properties/"+k, v, covered)
+			} else if s.AdditionalProperties != nil {
+				t.cover(s.AdditionalProperties.Schema, loc+"/additionalProperties", v, covered)
+			}
+		}
+	case []interface{}:
+		if s.Items == nil {
+			return
+		}
+		for i, v := range value {
+			if s.Items.Schema != nil {
+				t.cover(s.Items.Schema, loc+"/items", v, covered)
+			} else if i < len(s.Items.Schemas) {
+				t.cover(&s.Items.Schemas[i], fmt.Sprintf("%s/items/%d", loc, i), v, covered)
+			}
+		}
+	}
+}
+
+func (t *Tracker) matches(s *spec.Schema, value interface{}) bool {
+	expanded := validate.ExpandRefs(s, func(ref string) *spec.Schema {
+		if !strings.HasPrefix(ref, definitionPrefix) {
+			return nil
+		}
+		def, ok := t.spec.Definitions[ref[len(definitionPrefix):]]
+		if !ok {
+			return nil
+		}
+		return &def
+	})
+	return validate.NewSchemaValidator(expanded, nil, "", strfmt.Default).Validate(value).IsValid()
+}
+
+func enumBranch(loc string, v interface{}) string {
+	data, _ := json.Marshal(v)
+	return fmt.Sprintf("%s: enum %s", loc, data)
+}
+
+// equal compares JSON values through their serialization, so that numbers
+// of different Go types compare equal.
+func equal(a, b interface{}) bool {
+	da, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	db, err := json.Marshal(b)
+	return err == nil && string(da) == string(db)
+}
+
+func sortedKeys(m map[string]spec.Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
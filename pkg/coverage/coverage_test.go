@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coverage
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/analyzer"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const petstore = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "parameters": [{"name": "limit", "in": "query", "type": "integer"}],
+        "responses": {
+          "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}},
+          "default": {"description": "error"}
+        }
+      },
+      "post": {
+        "operationId": "createPet",
+        "parameters": [{"name": "pet", "in": "body", "schema": {"$ref": "#/definitions/Pet"}}],
+        "responses": {"201": {"description": "created"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "status": {"type": "string", "enum": ["available", "sold"]},
+        "tag": {"oneOf": [{"type": "string"}, {"type": "integer"}]},
+        "parent": {"$ref": "#/definitions/Pet"}
+      }
+    }
+  }
+}`
+
+func newTracker(t *testing.T) *Tracker {
+	var s spec.Swagger
+	require.NoError(t, json.Unmarshal([]byte(petstore), &s))
+	a, err := analyzer.New(&s)
+	require.NoError(t, err)
+	return NewTracker(&s, a)
+}
+
+func items(names ...string) []Item {
+	var ret []Item
+	for _, n := range names {
+		ret = append(ret, Item{Name: n})
+	}
+	return ret
+}
+
+func TestReportUncovered(t *testing.T) {
+	r := newTracker(t).Report()
+	require.Len(t, r.Operations, 2)
+	list := r.Operations[0]
+	assert.Equal(t, "GET", list.Method)
+	assert.False(t, list.Covered)
+	assert.Equal(t, items("limit"), list.Parameters)
+	assert.Equal(t, items("200", "default"), list.Responses)
+	assert.Equal(t, items(
+		`#/definitions/Pet/properties/status: enum "available"`,
+		`#/definitions/Pet/properties/status: enum "sold"`,
+		`#/definitions/Pet/properties/tag: oneOf[0]`,
+		`#/definitions/Pet/properties/tag: oneOf[1]`,
+	), list.Branches)
+	assert.Equal(t, 0, r.Covered)
+	assert.Equal(t, 15, r.Total)
+	assert.Equal(t, "GET /pets: not called\nPOST /pets: not called\n", r.Uncovered())
+}
+
+func TestRecord(t *testing.T) {
+	tr := newTracker(t)
+	list, ok := tr.a.OperationByID("listPets")
+	require.True(t, ok)
+	create, ok := tr.a.OperationAt("post", "/pets")
+	require.True(t, ok)
+
+	var pets interface{}
+	require.NoError(t, json.Unmarshal([]byte(`[{"status": "sold", "tag": 3, "parent": {"tag": "x"}}]`), &pets))
+	tr.Record(list, Exchange{StatusCode: 200, Response: pets})
+	tr.Record(list, Exchange{StatusCode: 500})
+	tr.Record(create, Exchange{Body: map[string]interface{}{"status": "available"}, StatusCode: 409})
+
+	r := tr.Report()
+	got := r.Operations[0]
+	assert.Equal(t, 2, got.Calls)
+	assert.Equal(t, []Item{{Name: "limit"}}, got.Parameters)
+	assert.Equal(t, []Item{{Name: "200", Covered: true}, {Name: "default", Covered: true}}, got.Responses)
+	assert.Equal(t, []Item{
+		{Name: `#/definitions/Pet/properties/status: enum "available"`},
+		{Name: `#/definitions/Pet/properties/status: enum "sold"`, Covered: true},
+		{Name: `#/definitions/Pet/properties/tag: oneOf[0]`, Covered: true},
+		{Name: `#/definitions/Pet/properties/tag: oneOf[1]`, Covered: true},
+	}, got.Branches)
+
+	got = r.Operations[1]
+	assert.Equal(t, []Item{{Name: "pet", Covered: true}}, got.Parameters)
+	assert.Equal(t, []Item{{Name: "201"}}, got.Responses)
+	assert.Equal(t, []int{409}, got.UndocumentedResponses)
+
+	assert.Equal(t, 9, r.Covered)
+	assert.Equal(t, 15, r.Total)
+	assert.Equal(t, `GET /pets: parameter limit
+GET /pets: branch #/definitions/Pet/properties/status: enum "available"
+POST /pets: response 201
+POST /pets: branch #/definitions/Pet/properties/status: enum "sold"
+POST /pets: branch #/definitions/Pet/properties/tag: oneOf[0]
+POST /pets: branch #/definitions/Pet/properties/tag: oneOf[1]
+POST /pets: undocumented response 409
+`, r.Uncovered())
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coverage
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Report is the coverage of the operations of a spec. It serializes to JSON
+// as is.
+type Report struct {
+	Operations []OperationCoverage `json:"operations"`
+	// Covered counts the covered items, operations included, of Total.
+	Covered int `json:"covered"`
+	Total   int `json:"total"`
+}
+
+// OperationCoverage is the coverage of an operation.
+type OperationCoverage struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	ID     string `json:"operationId,omitempty"`
+	// Calls is the number of recorded exchanges.
+	Calls   int  `json:"calls"`
+	Covered bool `json:"covered"`
+	// Total counts the operation and its items.
+	Total      int    `json:"total"`
+	Parameters []Item `json:"parameters,omitempty"`
+	// Responses are the documented status codes and "default".
+	Responses []Item `json:"responses,omitempty"`
+	// UndocumentedResponses are the recorded status codes the operation
+	// doesn't document, nor a default response.
+	UndocumentedResponses []int `json:"undocumentedResponses,omitempty"`
+	// Branches are the oneOf and anyOf arms and the enum values of the body
+	// schemas, e.g. `#/definitions/Pet/properties/status: enum "sold"`.
+	Branches []Item `json:"branches,omitempty"`
+}
+
+// Item is a part of a contract and whether it was exercised.
+type Item struct {
+	Name    string `json:"name"`
+	Covered bool   `json:"covered"`
+}
+
+// Percent returns the covered percentage of the report.
+func (r *Report) Percent() float64 {
+	if r.Total == 0 {
+		return 100
+	}
+	return 100 * float64(r.Covered) / float64(r.Total)
+}
+
+// Uncovered renders the items left untested as text, one per line, e.g.
+// "GET /pets/{id}: response 404".
+func (r *Report) Uncovered() string {
+	var buf bytes.Buffer
+	for _, op := range r.Operations {
+		prefix := op.Method + " " + op.Path
+		if !op.Covered {
+			fmt.Fprintf(&buf, "%s: not called\n", prefix)
+			continue
+		}
+		for _, l := range []struct {
+			kind  string
+			items []Item
+		}{{"parameter", op.Parameters}, {"response", op.Responses}, {"branch", op.Branches}} {
+			for _, item := range l.items {
+				if !item.Covered {
+					fmt.Fprintf(&buf, "%s: %s %s\n", prefix, l.kind, item.Name)
+				}
+			}
+		}
+		for _, code := range op.UndocumentedResponses {
+			fmt.Fprintf(&buf, "%s: undocumented response %d\n", prefix, code)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+)
+
+// Entry groups the changes of a changelog about the same operation, path or
+// definition, breaking changes first.
+type Entry struct {
+	// Subject is what the changes are about, e.g. "GET /pets", "/pets" for
+	// changes to a whole path, or "Pet" for a definition.
+	Subject string `json:"subject"`
+	// Definition is set when Subject is a definition.
+	Definition  bool     `json:"definition,omitempty"`
+	Breaking    []Change `json:"breaking,omitempty"`
+	NonBreaking []Change `json:"nonBreaking,omitempty"`
+}
+
+// Changelog groups the changes of the report into entries. The entries about
+// paths and operations come first, sorted by path, followed by the entries
+// about definitions, sorted by name.
+func (r *Report) Changelog() []Entry {
+	var paths, definitions []*Entry
+	index := map[string]*Entry{}
+	for _, c := range r.Changes {
+		subject, definition := subjectOf(c.Path)
+		key := fmt.Sprintf("%t %s", definition, subject)
+		e, ok := index[key]
+		if !ok {
+			e = &Entry{Subject: subject, Definition: definition}
+			index[key] = e
+			if definition {
+				definitions = append(definitions, e)
+			} else {
+				paths = append(paths, e)
+			}
+		}
+		if c.Breaking {
+			e.Breaking = append(e.Breaking, c)
+		} else {
+			e.NonBreaking = append(e.NonBreaking, c)
+		}
+	}
+	ret := make([]Entry, 0, len(paths)+len(definitions))
+	for _, e := range append(paths, definitions...) {
+		ret = append(ret, *e)
+	}
+	return ret
+}
+
+// subjectOf returns the subject of a change from its JSON pointer.
+func subjectOf(pointer string) (string, bool) {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i := range tokens {
+		tokens[i] = jsonpointer.Unescape(tokens[i])
+	}
+	switch {
+	case len(tokens) >= 2 && tokens[0] == "definitions":
+		return tokens[1], true
+	case len(tokens) >= 3 && tokens[0] == "paths" && tokens[2] != "parameters":
+		return strings.ToUpper(tokens[2]) + " " + tokens[1], false
+	case len(tokens) >= 2 && tokens[0] == "paths":
+		return tokens[1], false
+	}
+	return pointer, false
+}
+
+// Markdown renders the changelog of the report as Markdown release notes
+// with the given title, one section per entry.
+func (r *Report) Markdown(title string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n", title)
+	entries := r.Changelog()
+	if len(entries) == 0 {
+		b.WriteString("\nNo changes.\n")
+		return b.Bytes()
+	}
+	if n := len(r.Breaking()); n > 0 {
+		fmt.Fprintf(&b, "\n%d of the %d changes are breaking.\n", n, len(r.Changes))
+	}
+
+	section := ""
+	for _, e := range entries {
+		name := "Paths"
+		if e.Definition {
+			name = "Definitions"
+		}
+		if name != section {
+			section = name
+			fmt.Fprintf(&b, "\n## %s\n", section)
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", e.Subject)
+		for _, c := range e.Breaking {
+			fmt.Fprintf(&b, "- **Breaking:** %s\n", capitalize(c.Message))
+		}
+		for _, c := range e.NonBreaking {
+			fmt.Fprintf(&b, "- %s\n", capitalize(c.Message))
+		}
+	}
+	return b.Bytes()
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
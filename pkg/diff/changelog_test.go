@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangelog(t *testing.T) {
+	r := Diff(load(t, oldSpec), load(t, newSpec))
+	var subjects []string
+	for _, e := range r.Changelog() {
+		subjects = append(subjects, e.Subject)
+	}
+	assert.Equal(t, []string{"/owners", "GET /pets", "POST /pets", "/pets/{id}", "New", "Old", "Pet"}, subjects)
+
+	get := r.Changelog()[1]
+	assert.False(t, get.Definition)
+	assert.Len(t, get.Breaking, 5)
+	assert.Len(t, get.NonBreaking, 3)
+	pet := r.Changelog()[6]
+	assert.True(t, pet.Definition)
+	assert.Equal(t, []Change{
+		{Kind: PropertyAdded, Path: "/definitions/Pet/properties/size", Message: "property size was added"},
+		{Kind: RequiredAdded, Path: "/definitions/Pet/required", Message: "property color became required"},
+	}, pet.NonBreaking)
+}
+
+func TestMarkdown(t *testing.T) {
+	r := Diff(load(t, oldSpec), load(t, newSpec))
+	md := string(r.Markdown("Pets API v2"))
+	assert.Contains(t, md, "# Pets API v2\n\n8 of the 16 changes are breaking.\n\n## Paths\n\n### /owners\n\n- **Breaking:** Path /owners was removed\n")
+	assert.Contains(t, md, "\n### GET /pets\n\n- **Breaking:** Parameter query limit became required\n")
+	assert.Contains(t, md, "- Optional parameter query tag was added\n")
+	assert.Contains(t, md, "\n## Definitions\n\n### New\n\n- Definition New was added\n")
+	assert.Contains(t, md, "\n### Pet\n\n- **Breaking:** Type changed from integer (int32) to integer (int64)\n- Property size was added\n- Property color became required\n")
+
+	empty := Diff(load(t, oldSpec), load(t, oldSpec))
+	assert.Equal(t, "# Changes\n\nNo changes.\n", string(empty.Markdown("Changes")))
+}
@@ -0,0 +1,542 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diff compares two versions of an OpenAPI spec and classifies each
+// change as breaking or not for existing clients, so that CI can reject
+// incompatible API changes.
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Kind is the kind of a change.
+type Kind string
+
+// The kinds of changes reported by Diff.
+const (
+	PathAdded         Kind = "path-added"
+	PathRemoved       Kind = "path-removed"
+	OperationAdded    Kind = "operation-added"
+	OperationRemoved  Kind = "operation-removed"
+	ParameterAdded    Kind = "parameter-added"
+	ParameterRemoved  Kind = "parameter-removed"
+	ParameterRequired Kind = "parameter-required"
+	ResponseAdded     Kind = "response-added"
+	ResponseRemoved   Kind = "response-removed"
+	DefinitionAdded   Kind = "definition-added"
+	DefinitionRemoved Kind = "definition-removed"
+	PropertyAdded     Kind = "property-added"
+	PropertyRemoved   Kind = "property-removed"
+	RequiredAdded     Kind = "required-added"
+	RequiredRemoved   Kind = "required-removed"
+	TypeChanged       Kind = "type-changed"
+	EnumNarrowed      Kind = "enum-narrowed"
+	EnumWidened       Kind = "enum-widened"
+)
+
+// Change is a difference between two specs.
+type Change struct {
+	Kind     Kind `json:"kind"`
+	Breaking bool `json:"breaking"`
+	// Path is the JSON pointer of the changed object in the new spec, or in
+	// the old spec for removals.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (c Change) String() string {
+	prefix := "non-breaking"
+	if c.Breaking {
+		prefix = "breaking"
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", prefix, c.Path, c.Message, c.Kind)
+}
+
+// Report lists the changes between two specs, sorted by path then kind.
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// Breaking returns the breaking changes of the report.
+func (r *Report) Breaking() []Change {
+	var ret []Change
+	for _, c := range r.Changes {
+		if c.Breaking {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// HasBreaking returns whether the report has a breaking change.
+func (r *Report) HasBreaking() bool {
+	return len(r.Breaking()) > 0
+}
+
+// direction tells whether a schema describes requests, responses or both.
+// Whether a change breaks clients depends on it: clients may send fewer
+// values than before, but may not receive more, and the other way round.
+type direction int
+
+const (
+	request direction = 1 << iota
+	response
+	both = request | response
+)
+
+// breaking returns whether a change is breaking in direction dir, given
+// whether it is breaking for requests and for responses.
+func (dir direction) breaking(forRequests, forResponses bool) bool {
+	return (dir&request != 0 && forRequests) || (dir&response != 0 && forResponses)
+}
+
+type differ struct {
+	old, new *spec.Swagger
+	// usage is the direction of the definitions, as referenced from the
+	// operations of either spec.
+	usage   map[string]direction
+	changes []Change
+}
+
+func (d *differ) add(kind Kind, breaking bool, path, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{Kind: kind, Breaking: breaking, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// Diff compares the old and new versions of a spec. Removing a path, an
+// operation, a parameter, a response or a definition is breaking, and so is
+// adding a required parameter, making a parameter required or changing a
+// type. In request schemas, removing enum values or adding a required
+// property is breaking; in response schemas, adding enum values, removing a
+// property or making one optional is. Other changes are not breaking.
+// Schemas referenced by both versions are compared once, as definitions;
+// definitions used by both requests and responses, or by neither, are held
+// to both rules. Their composition (allOf, anyOf, oneOf) is not compared.
+func Diff(old, new *spec.Swagger) *Report {
+	d := &differ{old: old, new: new, usage: map[string]direction{}}
+	usage(old, d.usage)
+	usage(new, d.usage)
+	d.paths()
+	d.definitions()
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		if d.changes[i].Path != d.changes[j].Path {
+			return d.changes[i].Path < d.changes[j].Path
+		}
+		return d.changes[i].Kind < d.changes[j].Kind
+	})
+	if d.changes == nil {
+		d.changes = []Change{}
+	}
+	return &Report{Changes: d.changes}
+}
+
+// usage adds the directions in which the operations of s use definitions,
+// directly or through other definitions, to dirs.
+func usage(s *spec.Swagger, dirs map[string]direction) {
+	const prefix = "#/definitions/"
+	type root struct {
+		name string
+		dir  direction
+	}
+	var roots []root
+	refs := map[string][]string{}
+	s.WalkRefs(func(ref *spec.Ref, pointer string) {
+		if !strings.HasPrefix(ref.String(), prefix) {
+			return
+		}
+		name, err := unescape(strings.SplitN(strings.TrimPrefix(ref.String(), prefix), "/", 2)[0])
+		if err != nil {
+			return
+		}
+		tokens := strings.Split(pointer, "/")[1:]
+		switch tokens[0] {
+		case "definitions":
+			if from, err := unescape(tokens[1]); err == nil {
+				refs[from] = append(refs[from], name)
+			}
+		case "parameters":
+			roots = append(roots, root{name, request})
+		case "responses":
+			roots = append(roots, root{name, response})
+		case "paths":
+			// Path item parameters are at /paths/<path>/parameters, the
+			// others are below an operation.
+			if len(tokens) > 3 && tokens[3] == "responses" {
+				roots = append(roots, root{name, response})
+			} else if len(tokens) > 2 {
+				roots = append(roots, root{name, request})
+			}
+		}
+	})
+	var mark func(name string, dir direction)
+	mark = func(name string, dir direction) {
+		if dirs[name]&dir == dir {
+			return
+		}
+		dirs[name] |= dir
+		for _, to := range refs[name] {
+			mark(to, dir)
+		}
+	}
+	for _, r := range roots {
+		mark(r.name, r.dir)
+	}
+}
+
+func unescape(token string) (string, error) {
+	p, err := jsonpointer.New("/" + token)
+	if err != nil {
+		return "", err
+	}
+	return p.DecodedTokens()[0], nil
+}
+
+// definitionDirection returns the direction of a definition, holding the
+// unused ones to both rules.
+func (d *differ) definitionDirection(name string) direction {
+	if dir := d.usage[name]; dir != 0 {
+		return dir
+	}
+	return both
+}
+
+func pathItems(s *spec.Swagger) map[string]spec.PathItem {
+	if s.Paths == nil {
+		return nil
+	}
+	return s.Paths.Paths
+}
+
+func (d *differ) paths() {
+	oldPaths, newPaths := pathItems(d.old), pathItems(d.new)
+	for _, p := range sortedKeys(oldPaths, newPaths) {
+		pointer := "/paths/" + jsonpointer.Escape(p)
+		oldItem, inOld := oldPaths[p]
+		newItem, inNew := newPaths[p]
+		switch {
+		case !inNew:
+			d.add(PathRemoved, true, pointer, "path %s was removed", p)
+		case !inOld:
+			d.add(PathAdded, false, pointer, "path %s was added", p)
+		default:
+			d.operations(pointer, &oldItem, &newItem)
+		}
+	}
+}
+
+// operations returns the operations of a path item keyed by lowercase
+// method, the key of their JSON pointer.
+func operations(item *spec.PathItem) map[string]*spec.Operation {
+	ret := map[string]*spec.Operation{}
+	for _, m := range item.Operations() {
+		ret[strings.ToLower(m.Method)] = m.Operation
+	}
+	return ret
+}
+
+func (d *differ) operations(pointer string, oldItem, newItem *spec.PathItem) {
+	oldOps, newOps := operations(oldItem), operations(newItem)
+	for _, method := range sortedKeys(oldOps, newOps) {
+		opPointer := pointer + "/" + method
+		oldOp, inOld := oldOps[method]
+		newOp, inNew := newOps[method]
+		switch {
+		case !inNew:
+			d.add(OperationRemoved, true, opPointer, "operation %s was removed", strings.ToUpper(method))
+		case !inOld:
+			d.add(OperationAdded, false, opPointer, "operation %s was added", strings.ToUpper(method))
+		default:
+			d.parameters(parameterSet(d.old, pointer, method, oldItem, oldOp), parameterSet(d.new, pointer, method, newItem, newOp))
+			d.responses(opPointer, oldOp.Responses, newOp.Responses)
+		}
+	}
+}
+
+type parameter struct {
+	pointer string
+	param   *spec.Parameter
+}
+
+// parameterSet returns the effective parameters of an operation keyed by
+// location and name, with their local references resolved.
+func parameterSet(s *spec.Swagger, pointer, method string, item *spec.PathItem, op *spec.Operation) map[string]parameter {
+	ret := map[string]parameter{}
+	add := func(pointer string, params []spec.Parameter) {
+		for i := range params {
+			p := &params[i]
+			if name := strings.TrimPrefix(p.Ref.String(), "#/parameters/"); name != p.Ref.String() {
+				if resolved, ok := s.Parameters[name]; ok {
+					p = &resolved
+				}
+			}
+			ret[p.In+" "+p.Name] = parameter{pointer: pointer + "/parameters/" + strconv.Itoa(i), param: p}
+		}
+	}
+	add(pointer, item.Parameters)
+	add(pointer+"/"+method, op.Parameters)
+	return ret
+}
+
+func (d *differ) parameters(oldParams, newParams map[string]parameter) {
+	for _, key := range sortedKeys(oldParams, newParams) {
+		oldParam, inOld := oldParams[key]
+		newParam, inNew := newParams[key]
+		switch {
+		case !inNew:
+			d.add(ParameterRemoved, true, oldParam.pointer, "parameter %s was removed", key)
+		case !inOld:
+			if newParam.param.Required {
+				d.add(ParameterAdded, true, newParam.pointer, "required parameter %s was added", key)
+			} else {
+				d.add(ParameterAdded, false, newParam.pointer, "optional parameter %s was added", key)
+			}
+		default:
+			o, n := oldParam.param, newParam.param
+			if n.Required && !o.Required {
+				d.add(ParameterRequired, true, newParam.pointer, "parameter %s became required", key)
+			}
+			if o.Schema != nil || n.Schema != nil {
+				d.schema(newParam.pointer+"/schema", o.Schema, n.Schema, request)
+				continue
+			}
+			d.simpleSchema(newParam.pointer, o.Type, o.Format, n.Type, n.Format, o.Enum, n.Enum)
+			d.items(newParam.pointer+"/items", o.Items, n.Items)
+		}
+	}
+}
+
+func (d *differ) items(pointer string, o, n *spec.Items) {
+	if o == nil || n == nil {
+		return
+	}
+	d.simpleSchema(pointer, o.Type, o.Format, n.Type, n.Format, o.Enum, n.Enum)
+	d.items(pointer+"/items", o.Items, n.Items)
+}
+
+// simpleSchema compares the schemas of non body parameters, which are only
+// sent in requests.
+func (d *differ) simpleSchema(pointer, oldType, oldFormat, newType, newFormat string, oldEnum, newEnum []interface{}) {
+	if oldType != newType || oldFormat != newFormat {
+		d.add(TypeChanged, true, pointer, "type changed from %s to %s", typeName(oldType, oldFormat), typeName(newType, newFormat))
+	}
+	d.enum(pointer, oldEnum, newEnum, request)
+}
+
+func typeName(tpe, format string) string {
+	if tpe == "" {
+		tpe = "any"
+	}
+	if format == "" {
+		return tpe
+	}
+	return tpe + " (" + format + ")"
+}
+
+// enum compares enums. Narrowing an enum breaks the clients sending values,
+// widening it the clients receiving them.
+func (d *differ) enum(pointer string, oldEnum, newEnum []interface{}, dir direction) {
+	narrowed, widened := dir.breaking(true, false), dir.breaking(false, true)
+	// An empty enum allows any value.
+	switch {
+	case len(oldEnum) == 0 && len(newEnum) == 0:
+	case len(oldEnum) == 0:
+		d.add(EnumNarrowed, narrowed, pointer, "values were restricted to an enum")
+	case len(newEnum) == 0:
+		d.add(EnumWidened, widened, pointer, "enum was removed")
+	default:
+		if removed := missing(oldEnum, newEnum); len(removed) > 0 {
+			d.add(EnumNarrowed, narrowed, pointer, "enum values %s were removed", valueList(removed))
+		}
+		if added := missing(newEnum, oldEnum); len(added) > 0 {
+			d.add(EnumWidened, widened, pointer, "enum values %s were added", valueList(added))
+		}
+	}
+}
+
+// missing returns the values of a not in b.
+func missing(a, b []interface{}) []interface{} {
+	var ret []interface{}
+	for _, va := range a {
+		found := false
+		for _, vb := range b {
+			if reflect.DeepEqual(va, vb) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret = append(ret, va)
+		}
+	}
+	return ret
+}
+
+func valueList(values []interface{}) string {
+	s := make([]string, 0, len(values))
+	for _, v := range values {
+		s = append(s, fmt.Sprintf("%v", v))
+	}
+	return strings.Join(s, ", ")
+}
+
+func (d *differ) responses(pointer string, o, n *spec.Responses) {
+	oldResponses, newResponses := map[string]*spec.Response{}, map[string]*spec.Response{}
+	for _, rs := range []struct {
+		responses *spec.Responses
+		into      map[string]*spec.Response
+	}{{o, oldResponses}, {n, newResponses}} {
+		if rs.responses == nil {
+			continue
+		}
+		if rs.responses.Default != nil {
+			rs.into["default"] = rs.responses.Default
+		}
+		for code := range rs.responses.StatusCodeResponses {
+			r := rs.responses.StatusCodeResponses[code]
+			rs.into[strconv.Itoa(code)] = &r
+		}
+	}
+	for _, code := range sortedKeys(oldResponses, newResponses) {
+		rPointer := pointer + "/responses/" + code
+		oldResponse, inOld := oldResponses[code]
+		newResponse, inNew := newResponses[code]
+		switch {
+		case !inNew:
+			d.add(ResponseRemoved, true, rPointer, "response %s was removed", code)
+		case !inOld:
+			d.add(ResponseAdded, false, rPointer, "response %s was added", code)
+		default:
+			d.schema(rPointer+"/schema", oldResponse.Schema, newResponse.Schema, response)
+		}
+	}
+}
+
+func (d *differ) definitions() {
+	for _, name := range sortedKeys(d.old.Definitions, d.new.Definitions) {
+		pointer := "/definitions/" + jsonpointer.Escape(name)
+		o, inOld := d.old.Definitions[name]
+		n, inNew := d.new.Definitions[name]
+		switch {
+		case !inNew:
+			d.add(DefinitionRemoved, true, pointer, "definition %s was removed", name)
+		case !inOld:
+			d.add(DefinitionAdded, false, pointer, "definition %s was added", name)
+		default:
+			d.schema(pointer, &o, &n, d.definitionDirection(name))
+		}
+	}
+}
+
+// schema compares schemas used in direction dir. Removing a property or
+// making it optional breaks the clients receiving it, requiring a property
+// the clients sending it.
+func (d *differ) schema(pointer string, o, n *spec.Schema, dir direction) {
+	switch {
+	case o == nil && n == nil:
+		return
+	case o == nil:
+		d.add(TypeChanged, true, pointer, "schema was added")
+		return
+	case n == nil:
+		d.add(TypeChanged, true, pointer, "schema was removed")
+		return
+	}
+	if oldRef, newRef := o.Ref.String(), n.Ref.String(); oldRef != "" || newRef != "" {
+		if oldRef != newRef {
+			d.add(TypeChanged, true, pointer, "type changed from %s to %s", schemaName(o), schemaName(n))
+		}
+		return
+	}
+	if !reflect.DeepEqual([]string(o.Type), []string(n.Type)) || o.Format != n.Format {
+		d.add(TypeChanged, true, pointer, "type changed from %s to %s", schemaName(o), schemaName(n))
+		return
+	}
+	d.enum(pointer, o.Enum, n.Enum, dir)
+
+	oldRequired, newRequired := stringSet(o.Required), stringSet(n.Required)
+	for _, name := range sortedKeys(o.Properties, n.Properties) {
+		propPointer := pointer + "/properties/" + jsonpointer.Escape(name)
+		oldProp, inOld := o.Properties[name]
+		newProp, inNew := n.Properties[name]
+		switch {
+		case !inNew:
+			d.add(PropertyRemoved, dir.breaking(false, true), propPointer, "property %s was removed", name)
+		case !inOld:
+			d.add(PropertyAdded, false, propPointer, "property %s was added", name)
+		default:
+			d.schema(propPointer, &oldProp, &newProp, dir)
+		}
+	}
+	for _, name := range sortedKeys(oldRequired, newRequired) {
+		switch {
+		case !oldRequired[name]:
+			d.add(RequiredAdded, dir.breaking(true, false), pointer+"/required", "property %s became required", name)
+		case !newRequired[name]:
+			d.add(RequiredRemoved, dir.breaking(false, true), pointer+"/required", "property %s is no longer required", name)
+		}
+	}
+
+	if o.Items != nil && n.Items != nil {
+		d.schema(pointer+"/items", o.Items.Schema, n.Items.Schema, dir)
+	}
+	if o.AdditionalProperties != nil && n.AdditionalProperties != nil {
+		d.schema(pointer+"/additionalProperties", o.AdditionalProperties.Schema, n.AdditionalProperties.Schema, dir)
+	}
+}
+
+func schemaName(s *spec.Schema) string {
+	if ref := s.Ref.String(); ref != "" {
+		return ref
+	}
+	if len(s.Type) == 0 {
+		return typeName("", s.Format)
+	}
+	return typeName(strings.Join(s.Type, "|"), s.Format)
+}
+
+func stringSet(l []string) map[string]bool {
+	ret := make(map[string]bool, len(l))
+	for _, s := range l {
+		ret[s] = true
+	}
+	return ret
+}
+
+// sortedKeys returns the sorted union of the keys of two maps with string
+// keys.
+func sortedKeys(a, b interface{}) []string {
+	seen := map[string]bool{}
+	for _, m := range []interface{}{a, b} {
+		v := reflect.ValueOf(m)
+		for _, k := range v.MapKeys() {
+			seen[k.String()] = true
+		}
+	}
+	ret := make([]string, 0, len(seen))
+	for k := range seen {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const oldSpec = `{
+  "swagger": "2.0",
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer"}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"$ref": "#/parameters/limit"},
+          {"name": "order", "in": "query", "type": "string", "enum": ["asc", "desc"]},
+          {"name": "owner", "in": "query", "type": "string"}
+        ],
+        "responses": {"200": {"description": "ok", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}, "404": {"description": "not found"}}
+      },
+      "delete": {"responses": {"204": {"description": "deleted"}}}
+    },
+    "/owners": {"get": {"responses": {"200": {"description": "ok"}}}}
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string"},
+        "age": {"type": "integer", "format": "int32"},
+        "color": {"type": "string"}
+      }
+    },
+    "Old": {"type": "string"}
+  }
+}`
+
+const newSpec = `{
+  "swagger": "2.0",
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer", "required": true}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"$ref": "#/parameters/limit"},
+          {"name": "order", "in": "query", "type": "string", "enum": ["asc", "random"]},
+          {"name": "tag", "in": "query", "type": "string"},
+          {"name": "X-Tenant", "in": "header", "type": "string", "required": true}
+        ],
+        "responses": {"200": {"description": "ok", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}, "default": {"description": "error"}}
+      },
+      "post": {"responses": {"201": {"description": "created"}}},
+      "delete": {"responses": {"204": {"description": "deleted"}}}
+    },
+    "/pets/{id}": {"get": {"responses": {"200": {"description": "ok"}}}}
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["name", "color"],
+      "properties": {
+        "name": {"type": "string"},
+        "age": {"type": "integer", "format": "int64"},
+        "color": {"type": "string"},
+        "size": {"type": "string"}
+      }
+    },
+    "New": {"type": "string"}
+  }
+}`
+
+func load(t *testing.T, data string) *spec.Swagger {
+	s := &spec.Swagger{}
+	require.NoError(t, json.Unmarshal([]byte(data), s))
+	return s
+}
+
+func TestDiff(t *testing.T) {
+	r := Diff(load(t, oldSpec), load(t, newSpec))
+	assert.Equal(t, []Change{
+		{Kind: DefinitionAdded, Path: "/definitions/New", Message: "definition New was added"},
+		{Kind: DefinitionRemoved, Breaking: true, Path: "/definitions/Old", Message: "definition Old was removed"},
+		{Kind: TypeChanged, Breaking: true, Path: "/definitions/Pet/properties/age", Message: "type changed from integer (int32) to integer (int64)"},
+		{Kind: PropertyAdded, Path: "/definitions/Pet/properties/size", Message: "property size was added"},
+		{Kind: RequiredAdded, Path: "/definitions/Pet/required", Message: "property color became required"},
+		{Kind: PathRemoved, Breaking: true, Path: "/paths/~1owners", Message: "path /owners was removed"},
+		{Kind: ParameterRequired, Breaking: true, Path: "/paths/~1pets/get/parameters/0", Message: "parameter query limit became required"},
+		{Kind: EnumNarrowed, Breaking: true, Path: "/paths/~1pets/get/parameters/1", Message: "enum values desc were removed"},
+		{Kind: EnumWidened, Path: "/paths/~1pets/get/parameters/1", Message: "enum values random were added"},
+		{Kind: ParameterAdded, Path: "/paths/~1pets/get/parameters/2", Message: "optional parameter query tag was added"},
+		{Kind: ParameterRemoved, Breaking: true, Path: "/paths/~1pets/get/parameters/2", Message: "parameter query owner was removed"},
+		{Kind: ParameterAdded, Breaking: true, Path: "/paths/~1pets/get/parameters/3", Message: "required parameter header X-Tenant was added"},
+		{Kind: ResponseRemoved, Breaking: true, Path: "/paths/~1pets/get/responses/404", Message: "response 404 was removed"},
+		{Kind: ResponseAdded, Path: "/paths/~1pets/get/responses/default", Message: "response default was added"},
+		{Kind: OperationAdded, Path: "/paths/~1pets/post", Message: "operation POST was added"},
+		{Kind: PathAdded, Path: "/paths/~1pets~1{id}", Message: "path /pets/{id} was added"},
+	}, r.Changes)
+	assert.True(t, r.HasBreaking())
+	assert.Len(t, r.Breaking(), 8)
+	assert.Equal(t, "breaking: /paths/~1owners: path /owners was removed [path-removed]", r.Changes[5].String())
+}
+
+func TestDiffEqual(t *testing.T) {
+	r := Diff(load(t, oldSpec), load(t, oldSpec))
+	assert.Empty(t, r.Changes)
+	assert.False(t, r.HasBreaking())
+
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"changes": []}`, string(data))
+}
+
+func TestDiffSchemas(t *testing.T) {
+	d := &differ{}
+	d.schema("/s", spec.StringProperty(), spec.ArrayProperty(spec.StringProperty()), request)
+	d.schema("/e", spec.StringProperty(), spec.StringProperty().WithEnum("a"), request)
+	d.schema("/f", spec.StringProperty().WithEnum("a"), spec.StringProperty(), request)
+	d.schema("/r", spec.RefProperty("#/definitions/A"), spec.RefProperty("#/definitions/B"), request)
+	d.schema("/n", nil, spec.StringProperty(), request)
+	assert.Equal(t, []Change{
+		{Kind: TypeChanged, Breaking: true, Path: "/s", Message: "type changed from string to array"},
+		{Kind: EnumNarrowed, Breaking: true, Path: "/e", Message: "values were restricted to an enum"},
+		{Kind: EnumWidened, Path: "/f", Message: "enum was removed"},
+		{Kind: TypeChanged, Breaking: true, Path: "/r", Message: "type changed from #/definitions/A to #/definitions/B"},
+		{Kind: TypeChanged, Breaking: true, Path: "/n", Message: "schema was added"},
+	}, d.changes)
+}
+
+func TestDiffSchemaDirections(t *testing.T) {
+	o := &spec.Schema{}
+	o.Typed("object", "")
+	o.WithEnum("a", "b").WithRequired("name").
+		SetProperty("name", *spec.StringProperty()).
+		SetProperty("color", *spec.StringProperty())
+	n := &spec.Schema{}
+	n.Typed("object", "")
+	n.WithEnum("a", "c").WithRequired("color").
+		SetProperty("name", *spec.StringProperty()).
+		SetProperty("size", *spec.StringProperty())
+	changes := func(dir direction) []Change {
+		d := &differ{}
+		d.schema("/s", o, n, dir)
+		return d.changes
+	}
+
+	assert.Equal(t, []Change{
+		{Kind: EnumNarrowed, Breaking: true, Path: "/s", Message: "enum values b were removed"},
+		{Kind: EnumWidened, Path: "/s", Message: "enum values c were added"},
+		{Kind: PropertyRemoved, Path: "/s/properties/color", Message: "property color was removed"},
+		{Kind: PropertyAdded, Path: "/s/properties/size", Message: "property size was added"},
+		{Kind: RequiredAdded, Breaking: true, Path: "/s/required", Message: "property color became required"},
+		{Kind: RequiredRemoved, Path: "/s/required", Message: "property name is no longer required"},
+	}, changes(request))
+	assert.Equal(t, []Change{
+		{Kind: EnumNarrowed, Path: "/s", Message: "enum values b were removed"},
+		{Kind: EnumWidened, Breaking: true, Path: "/s", Message: "enum values c were added"},
+		{Kind: PropertyRemoved, Breaking: true, Path: "/s/properties/color", Message: "property color was removed"},
+		{Kind: PropertyAdded, Path: "/s/properties/size", Message: "property size was added"},
+		{Kind: RequiredAdded, Path: "/s/required", Message: "property color became required"},
+		{Kind: RequiredRemoved, Breaking: true, Path: "/s/required", Message: "property name is no longer required"},
+	}, changes(response))
+	for _, c := range changes(both) {
+		assert.Equal(t, c.Kind != PropertyAdded, c.Breaking, c.Kind)
+	}
+}
+
+func TestDiffDefinitionDirections(t *testing.T) {
+	const tmpl = `{
+  "swagger": "2.0",
+  "paths": {
+    "/pets": {
+      "post": {
+        "parameters": [{"name": "body", "in": "body", "schema": {"$ref": "#/definitions/PetRequest"}}],
+        "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/PetList"}}}
+      },
+      "put": {
+        "parameters": [{"name": "body", "in": "body", "schema": {"$ref": "#/definitions/Pet"}}],
+        "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Pet"}}}
+      }
+    }
+  },
+  "definitions": {
+    "PetRequest": {"type": "object", "properties": {"kind": {"$ref": "#/definitions/Kind"}}},
+    "PetList": {"type": "array", "items": {"$ref": "#/definitions/Status"}},
+    "Pet": {"type": "object", "properties": {"name": {"type": "string"}}},
+    "Kind": {"type": "string", "enum": [%s]},
+    "Status": {"type": "string", "enum": [%s]},
+    "Unused": {"type": "string", "enum": [%s]}
+  }
+}`
+	load := func(enum string) *spec.Swagger {
+		return load(t, strings.Replace(tmpl, "%s", enum, -1))
+	}
+	data := func(pet string) *spec.Swagger {
+		s := load(`"a"`)
+		s.Definitions["Pet"] = *spec.MapProperty(spec.StringProperty()).WithRequired(pet)
+		return s
+	}
+
+	// Narrowing breaks the requests, reaching Kind through PetRequest, and
+	// the unused definition, but not the responses.
+	r := Diff(load(`"a", "b"`), load(`"a"`))
+	assert.Equal(t, []Change{
+		{Kind: EnumNarrowed, Breaking: true, Path: "/definitions/Kind", Message: "enum values b were removed"},
+		{Kind: EnumNarrowed, Path: "/definitions/Status", Message: "enum values b were removed"},
+		{Kind: EnumNarrowed, Breaking: true, Path: "/definitions/Unused", Message: "enum values b were removed"},
+	}, r.Changes)
+
+	// Widening breaks the responses, reaching Status through PetList, but
+	// not the requests.
+	r = Diff(load(`"a"`), load(`"a", "b"`))
+	assert.Equal(t, []Change{
+		{Kind: EnumWidened, Path: "/definitions/Kind", Message: "enum values b were added"},
+		{Kind: EnumWidened, Breaking: true, Path: "/definitions/Status", Message: "enum values b were added"},
+		{Kind: EnumWidened, Breaking: true, Path: "/definitions/Unused", Message: "enum values b were added"},
+	}, r.Changes)
+
+	// Pet is both sent and received, so requiring a property and making it
+	// optional are both breaking.
+	for _, c := range Diff(data("name"), data("color")).Changes {
+		assert.True(t, c.Breaking, c.Message)
+	}
+	assert.Len(t, Diff(data("name"), data("color")).Changes, 2)
+}
@@ -0,0 +1,382 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package docs extracts a documentation model from an OpenAPI spec:
+// operations grouped by tag, parameter tables and schema field tables with
+// their constraints. The model serializes to JSON as is and can be rendered
+// to Markdown, so custom documentation doesn't need to walk spec structs.
+package docs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// defaultTag groups the operations that don't declare any tag.
+const defaultTag = "default"
+
+const definitionPrefix = "#/definitions/"
+
+// Document is the documentation model of a spec.
+type Document struct {
+	Title       string `json:"title,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Description string `json:"description,omitempty"`
+	Tags        []Tag  `json:"tags,omitempty"`
+	Schemas     []Type `json:"schemas,omitempty"`
+}
+
+// Tag is a group of operations sharing a tag. Operations with several tags
+// appear in each of their groups.
+type Tag struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Operations  []Operation `json:"operations"`
+}
+
+// Operation documents a single operation.
+type Operation struct {
+	ID          string     `json:"operationId,omitempty"`
+	Method      string     `json:"method"`
+	Path        string     `json:"path"`
+	Summary     string     `json:"summary,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Deprecated  bool       `json:"deprecated,omitempty"`
+	Parameters  []Field    `json:"parameters,omitempty"`
+	Responses   []Response `json:"responses,omitempty"`
+}
+
+// Response documents one of the responses of an operation.
+type Response struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+// Type documents a definition with its fields.
+type Type struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Type        string  `json:"type,omitempty"`
+	Fields      []Field `json:"fields,omitempty"`
+}
+
+// Field is a row of a parameter or schema field table. In is only set for
+// parameters.
+type Field struct {
+	Name        string   `json:"name"`
+	In          string   `json:"in,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Constraints []string `json:"constraints,omitempty"`
+}
+
+// Extract builds the documentation model of a spec.
+func Extract(s *spec.Swagger) *Document {
+	d := &Document{}
+	if s.Info != nil {
+		d.Title = s.Info.Title
+		d.Version = s.Info.Version
+		d.Description = s.Info.Description
+	}
+
+	// Tags declared at the top level come first, in their declared order,
+	// followed by the tags only used by operations, sorted by name.
+	tags := map[string]*Tag{}
+	var declared, undeclared []string
+	for _, t := range s.Tags {
+		if _, ok := tags[t.Name]; !ok {
+			tags[t.Name] = &Tag{Name: t.Name, Description: t.Description}
+			declared = append(declared, t.Name)
+		}
+	}
+	if s.Paths != nil {
+		paths := make([]string, 0, len(s.Paths.Paths))
+		for p := range s.Paths.Paths {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			item := s.Paths.Paths[p]
+			for _, m := range item.Operations() {
+				op := extractOperation(p, m.Method, item.Parameters, m.Operation)
+				opTags := m.Operation.Tags
+				if len(opTags) == 0 {
+					opTags = []string{defaultTag}
+				}
+				for _, name := range opTags {
+					t, ok := tags[name]
+					if !ok {
+						t = &Tag{Name: name}
+						tags[name] = t
+						undeclared = append(undeclared, name)
+					}
+					t.Operations = append(t.Operations, op)
+				}
+			}
+		}
+	}
+	sort.Strings(undeclared)
+	for _, name := range append(declared, undeclared...) {
+		if t := tags[name]; len(t.Operations) > 0 {
+			d.Tags = append(d.Tags, *t)
+		}
+	}
+
+	names := make([]string, 0, len(s.Definitions))
+	for name := range s.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := s.Definitions[name]
+		d.Schemas = append(d.Schemas, Type{
+			Name:        name,
+			Description: def.Description,
+			Type:        typeName(&def),
+			Fields:      fields(&def),
+		})
+	}
+	return d
+}
+
+func extractOperation(path, method string, common []spec.Parameter, op *spec.Operation) Operation {
+	ret := Operation{
+		ID:          op.ID,
+		Method:      method,
+		Path:        path,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Deprecated:  op.Deprecated,
+	}
+
+	// Operation parameters override path parameters with the same name and location.
+	overridden := map[string]bool{}
+	for _, p := range op.Parameters {
+		overridden[p.In+"/"+p.Name] = true
+	}
+	for _, p := range common {
+		if !overridden[p.In+"/"+p.Name] {
+			ret.Parameters = append(ret.Parameters, parameterField(&p))
+		}
+	}
+	for _, p := range op.Parameters {
+		ret.Parameters = append(ret.Parameters, parameterField(&p))
+	}
+
+	if op.Responses != nil {
+		codes := make([]int, 0, len(op.Responses.StatusCodeResponses))
+		for code := range op.Responses.StatusCodeResponses {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			r := op.Responses.StatusCodeResponses[code]
+			ret.Responses = append(ret.Responses, response(strconv.Itoa(code), &r))
+		}
+		if op.Responses.Default != nil {
+			ret.Responses = append(ret.Responses, response("default", op.Responses.Default))
+		}
+	}
+	return ret
+}
+
+func response(code string, r *spec.Response) Response {
+	ret := Response{Code: code, Description: r.Description}
+	if ref := r.Ref.String(); ref != "" {
+		ret.Type = refName(ref)
+	} else if r.Schema != nil {
+		ret.Type = typeName(r.Schema)
+	}
+	return ret
+}
+
+func parameterField(p *spec.Parameter) Field {
+	f := Field{
+		Name:        p.Name,
+		In:          p.In,
+		Required:    p.Required,
+		Description: p.Description,
+	}
+	if ref := p.Ref.String(); ref != "" {
+		f.Type = refName(ref)
+		return f
+	}
+	if p.Schema != nil {
+		f.Type = typeName(p.Schema)
+		f.Constraints = schemaConstraints(p.Schema)
+		return f
+	}
+	f.Type = simpleTypeName(p.Type, p.Format, p.Items)
+	f.Constraints = constraints(&p.CommonValidations, p.Default, p.CollectionFormat)
+	return f
+}
+
+func fields(s *spec.Schema) []Field {
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var ret []Field
+	for _, name := range names {
+		prop := s.Properties[name]
+		ret = append(ret, Field{
+			Name:        name,
+			Type:        typeName(&prop),
+			Required:    required[name],
+			Description: prop.Description,
+			Constraints: schemaConstraints(&prop),
+		})
+	}
+	return ret
+}
+
+// typeName renders the type of a schema in a compact form, e.g. "[]Pet" or
+// "map[string]integer (int32)".
+func typeName(s *spec.Schema) string {
+	if ref := s.Ref.String(); ref != "" {
+		return refName(ref)
+	}
+	switch {
+	case s.Type.Contains("array"):
+		if s.Items != nil && s.Items.Schema != nil {
+			return "[]" + typeName(s.Items.Schema)
+		}
+		return "[]"
+	case s.Type.Contains("object") || len(s.Type) == 0:
+		if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+			return "map[string]" + typeName(s.AdditionalProperties.Schema)
+		}
+		if len(s.Type) == 0 {
+			return ""
+		}
+	}
+	return withFormat(strings.Join(s.Type, "|"), s.Format)
+}
+
+func simpleTypeName(tpe, format string, items *spec.Items) string {
+	if tpe == "array" && items != nil {
+		return "[]" + simpleTypeName(items.Type, items.Format, items.Items)
+	}
+	return withFormat(tpe, format)
+}
+
+func withFormat(tpe, format string) string {
+	if format == "" {
+		return tpe
+	}
+	return fmt.Sprintf("%s (%s)", tpe, format)
+}
+
+func refName(ref string) string {
+	if strings.HasPrefix(ref, definitionPrefix) {
+		return ref[len(definitionPrefix):]
+	}
+	return ref
+}
+
+func schemaConstraints(s *spec.Schema) []string {
+	ret := constraints(&spec.CommonValidations{
+		Maximum:          s.Maximum,
+		ExclusiveMaximum: s.ExclusiveMaximum,
+		Minimum:          s.Minimum,
+		ExclusiveMinimum: s.ExclusiveMinimum,
+		MaxLength:        s.MaxLength,
+		MinLength:        s.MinLength,
+		Pattern:          s.Pattern,
+		MaxItems:         s.MaxItems,
+		MinItems:         s.MinItems,
+		UniqueItems:      s.UniqueItems,
+		MultipleOf:       s.MultipleOf,
+		Enum:             s.Enum,
+	}, s.Default, "")
+	if s.MinProperties != nil {
+		ret = append(ret, fmt.Sprintf("minProperties: %d", *s.MinProperties))
+	}
+	if s.MaxProperties != nil {
+		ret = append(ret, fmt.Sprintf("maxProperties: %d", *s.MaxProperties))
+	}
+	if s.Nullable {
+		ret = append(ret, "nullable")
+	}
+	if s.ReadOnly {
+		ret = append(ret, "readOnly")
+	}
+	return ret
+}
+
+func constraints(v *spec.CommonValidations, def interface{}, collectionFormat string) []string {
+	var ret []string
+	if v.Minimum != nil {
+		op := ">="
+		if v.ExclusiveMinimum {
+			op = ">"
+		}
+		ret = append(ret, fmt.Sprintf("%s %v", op, *v.Minimum))
+	}
+	if v.Maximum != nil {
+		op := "<="
+		if v.ExclusiveMaximum {
+			op = "<"
+		}
+		ret = append(ret, fmt.Sprintf("%s %v", op, *v.Maximum))
+	}
+	if v.MultipleOf != nil {
+		ret = append(ret, fmt.Sprintf("multipleOf: %v", *v.MultipleOf))
+	}
+	if v.MinLength != nil {
+		ret = append(ret, fmt.Sprintf("minLength: %d", *v.MinLength))
+	}
+	if v.MaxLength != nil {
+		ret = append(ret, fmt.Sprintf("maxLength: %d", *v.MaxLength))
+	}
+	if v.Pattern != "" {
+		ret = append(ret, fmt.Sprintf("pattern: %s", v.Pattern))
+	}
+	if v.MinItems != nil {
+		ret = append(ret, fmt.Sprintf("minItems: %d", *v.MinItems))
+	}
+	if v.MaxItems != nil {
+		ret = append(ret, fmt.Sprintf("maxItems: %d", *v.MaxItems))
+	}
+	if v.UniqueItems {
+		ret = append(ret, "uniqueItems")
+	}
+	if len(v.Enum) > 0 {
+		values := make([]string, 0, len(v.Enum))
+		for _, e := range v.Enum {
+			values = append(values, fmt.Sprintf("%v", e))
+		}
+		ret = append(ret, "enum: "+strings.Join(values, ", "))
+	}
+	if def != nil {
+		ret = append(ret, fmt.Sprintf("default: %v", def))
+	}
+	if collectionFormat != "" {
+		ret = append(ret, "collectionFormat: "+collectionFormat)
+	}
+	return ret
+}
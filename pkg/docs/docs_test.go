@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const testSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "Pets", "version": "v1", "description": "The pet store."},
+  "tags": [{"name": "pets", "description": "Pet operations"}, {"name": "unused"}],
+  "paths": {
+    "/pets/{name}": {
+      "parameters": [
+        {"name": "name", "in": "path", "type": "string", "required": true},
+        {"name": "pretty", "in": "query", "type": "boolean", "description": "common"}
+      ],
+      "get": {
+        "operationId": "getPet",
+        "tags": ["pets", "admin"],
+        "summary": "Get a pet",
+        "parameters": [
+          {"name": "pretty", "in": "query", "type": "string", "description": "a | b"},
+          {"name": "limit", "in": "query", "type": "integer", "format": "int32", "minimum": 1, "maximum": 100, "exclusiveMaximum": true}
+        ],
+        "responses": {
+          "200": {"description": "OK", "schema": {"$ref": "#/definitions/Pet"}},
+          "default": {"description": "failure"}
+        }
+      },
+      "delete": {"deprecated": true, "responses": {"204": {"description": "gone"}}}
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "description": "A pet.",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string", "minLength": 1},
+        "tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+        "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+        "kind": {"type": "string", "enum": ["cat", "dog"], "default": "cat"}
+      }
+    },
+    "Age": {"type": "integer", "format": "int64"}
+  }
+}`
+
+func loadTestSpec(t *testing.T) *spec.Swagger {
+	s := &spec.Swagger{}
+	require.NoError(t, json.Unmarshal([]byte(testSpec), s))
+	return s
+}
+
+func TestExtract(t *testing.T) {
+	d := Extract(loadTestSpec(t))
+	assert.Equal(t, "Pets", d.Title)
+	assert.Equal(t, "v1", d.Version)
+
+	// Declared tags first, unused tags dropped, untagged operations under default.
+	var tags []string
+	for _, tag := range d.Tags {
+		tags = append(tags, tag.Name)
+	}
+	assert.Equal(t, []string{"pets", "admin", "default"}, tags)
+	assert.Equal(t, "Pet operations", d.Tags[0].Description)
+
+	get := d.Tags[0].Operations[0]
+	assert.Equal(t, "GET", get.Method)
+	assert.Equal(t, "/pets/{name}", get.Path)
+	assert.Equal(t, get, d.Tags[1].Operations[0])
+	assert.Equal(t, []Field{
+		{Name: "name", In: "path", Type: "string", Required: true},
+		{Name: "pretty", In: "query", Type: "string", Description: "a | b"},
+		{Name: "limit", In: "query", Type: "integer (int32)", Constraints: []string{">= 1", "< 100"}},
+	}, get.Parameters)
+	assert.Equal(t, []Response{
+		{Code: "200", Description: "OK", Type: "Pet"},
+		{Code: "default", Description: "failure"},
+	}, get.Responses)
+
+	del := d.Tags[2].Operations[0]
+	assert.Equal(t, "DELETE", del.Method)
+	assert.True(t, del.Deprecated)
+
+	require.Len(t, d.Schemas, 2)
+	assert.Equal(t, Type{Name: "Age", Type: "integer (int64)"}, d.Schemas[0])
+	pet := d.Schemas[1]
+	assert.Equal(t, "Pet", pet.Name)
+	assert.Equal(t, "object", pet.Type)
+	assert.Equal(t, []Field{
+		{Name: "kind", Type: "string", Constraints: []string{"enum: cat, dog", "default: cat"}},
+		{Name: "labels", Type: "map[string]string"},
+		{Name: "name", Type: "string", Required: true, Constraints: []string{"minLength: 1"}},
+		{Name: "tags", Type: "[]string", Constraints: []string{"uniqueItems"}},
+	}, pet.Fields)
+}
+
+func TestMarkdown(t *testing.T) {
+	md := string(Extract(loadTestSpec(t)).Markdown())
+	assert.Contains(t, md, "# Pets v1\n\nThe pet store.\n")
+	assert.Contains(t, md, "\n## pets\n\nPet operations\n")
+	assert.Contains(t, md, "\n### GET /pets/{name}\n\nOperation ID: `getPet`\n\nGet a pet\n")
+	assert.Contains(t, md, "| pretty | query | string | no | a \\| b |  |\n")
+	assert.Contains(t, md, "| limit | query | integer (int32) | no |  | >= 1, < 100 |\n")
+	assert.Contains(t, md, "| 200 | Pet | OK |\n")
+	assert.Contains(t, md, "\n### DELETE /pets/{name}\n\n**Deprecated**\n")
+	assert.Contains(t, md, "\n## Definitions\n\n### Age\n\nType: `integer (int64)`\n")
+	assert.Contains(t, md, "| name | string | yes |  | minLength: 1 |\n")
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Markdown renders the document as Markdown, with one section per tag and a
+// final section listing the definitions.
+func (d *Document) Markdown() []byte {
+	var b bytes.Buffer
+	title := d.Title
+	if title == "" {
+		title = "API"
+	}
+	if d.Version != "" {
+		title += " " + d.Version
+	}
+	fmt.Fprintf(&b, "# %s\n", title)
+	if d.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", d.Description)
+	}
+
+	for _, t := range d.Tags {
+		fmt.Fprintf(&b, "\n## %s\n", t.Name)
+		if t.Description != "" {
+			fmt.Fprintf(&b, "\n%s\n", t.Description)
+		}
+		for _, op := range t.Operations {
+			fmt.Fprintf(&b, "\n### %s %s\n", op.Method, op.Path)
+			if op.ID != "" {
+				fmt.Fprintf(&b, "\nOperation ID: `%s`\n", op.ID)
+			}
+			if op.Deprecated {
+				b.WriteString("\n**Deprecated**\n")
+			}
+			for _, text := range []string{op.Summary, op.Description} {
+				if text != "" {
+					fmt.Fprintf(&b, "\n%s\n", text)
+				}
+			}
+			if len(op.Parameters) > 0 {
+				b.WriteString("\n#### Parameters\n\n")
+				b.WriteString("| Name | In | Type | Required | Description | Constraints |\n")
+				b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+				for _, p := range op.Parameters {
+					row(&b, p.Name, p.In, p.Type, yesNo(p.Required), p.Description, strings.Join(p.Constraints, ", "))
+				}
+			}
+			if len(op.Responses) > 0 {
+				b.WriteString("\n#### Responses\n\n")
+				b.WriteString("| Code | Type | Description |\n")
+				b.WriteString("| --- | --- | --- |\n")
+				for _, r := range op.Responses {
+					row(&b, r.Code, r.Type, r.Description)
+				}
+			}
+		}
+	}
+
+	if len(d.Schemas) > 0 {
+		b.WriteString("\n## Definitions\n")
+		for _, s := range d.Schemas {
+			fmt.Fprintf(&b, "\n### %s\n", s.Name)
+			if s.Description != "" {
+				fmt.Fprintf(&b, "\n%s\n", s.Description)
+			}
+			if len(s.Fields) == 0 {
+				if s.Type != "" {
+					fmt.Fprintf(&b, "\nType: `%s`\n", s.Type)
+				}
+				continue
+			}
+			b.WriteString("\n| Field | Type | Required | Description | Constraints |\n")
+			b.WriteString("| --- | --- | --- | --- | --- |\n")
+			for _, f := range s.Fields {
+				row(&b, f.Name, f.Type, yesNo(f.Required), f.Description, strings.Join(f.Constraints, ", "))
+			}
+		}
+	}
+	return b.Bytes()
+}
+
+func row(b *bytes.Buffer, cells ...string) {
+	b.WriteString("|")
+	for _, c := range cells {
+		b.WriteString(" ")
+		b.WriteString(escapeCell(c))
+		b.WriteString(" |")
+	}
+	b.WriteString("\n")
+}
+
+// escapeCell keeps a value on a single table row.
+func escapeCell(s string) string {
+	s = strings.Replace(s, "|", "\\|", -1)
+	s = strings.Replace(s, "\r\n", " ", -1)
+	return strings.Replace(s, "\n", " ", -1)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,357 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fuzz fires randomized requests generated from a spec at an
+// http.Handler and checks that the handler only answers with the responses
+// the spec declares. Half of the requests conform to the spec; the others
+// break one constraint of an operation, e.g. miss a required parameter, so
+// that the error paths of the handler are exercised too. Undocumented
+// status codes, typically 500s, and response bodies not matching their
+// schemas are reported as failures.
+package fuzz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/analyzer"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// Fuzzer generates requests for the operations of a spec.
+type Fuzzer struct {
+	spec *spec.Swagger
+	a    *analyzer.Analyzer
+	gen  *Generator
+}
+
+// New returns a fuzzer of the operations of s whose random choices are
+// seeded with seed, so that a failing run can be reproduced.
+func New(s *spec.Swagger, seed int64) (*Fuzzer, error) {
+	a, err := analyzer.New(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Fuzzer{spec: s, a: a, gen: NewGenerator(s, seed)}, nil
+}
+
+// Failure is a response of the handler the spec doesn't declare.
+type Failure struct {
+	Method string
+	Path   string
+	// Request is the URL of the request, with its query.
+	Request string
+	// Mutation describes how the request breaks the spec, or is empty when
+	// it conforms.
+	Mutation   string
+	StatusCode int
+	Err        error
+}
+
+func (f Failure) Error() string {
+	s := fmt.Sprintf("%s %s", f.Method, f.Request)
+	if f.Mutation != "" {
+		s += " (" + f.Mutation + ")"
+	}
+	return fmt.Sprintf("%s: %d: %v", s, f.StatusCode, f.Err)
+}
+
+// Run sends n requests to h for every operation, alternating conforming
+// requests and requests breaking the spec, and returns the failures.
+func (f *Fuzzer) Run(h http.Handler, n int) []Failure {
+	var ret []Failure
+	for _, op := range f.a.Operations() {
+		for i := 0; i < n; i++ {
+			req, mutation := f.Request(op, i%2 == 1)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if err := f.check(op, w.Result()); err != nil {
+				ret = append(ret, Failure{
+					Method:     op.Method,
+					Path:       op.Path,
+					Request:    req.URL.RequestURI(),
+					Mutation:   mutation,
+					StatusCode: w.Code,
+					Err:        err,
+				})
+			}
+		}
+	}
+	return ret
+}
+
+// check returns an error if the spec doesn't declare the response to op.
+func (f *Fuzzer) check(op *analyzer.Operation, resp *http.Response) error {
+	r, ok := op.Response(resp.StatusCode)
+	if !ok {
+		return fmt.Errorf("undocumented status code")
+	}
+	if r.Schema == nil || op.Method == http.MethodHead {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+	var body interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("invalid JSON body: %v", err)
+	}
+	return validate.AgainstSchema(validate.ExpandRefs(r.Schema, f.gen.resolve), body, strfmt.Default)
+}
+
+// Request returns a random request to op. When invalid is set, the request
+// breaks one constraint of the operation, described by the returned
+// mutation; it conforms if the operation has no constraint to break.
+func (f *Fuzzer) Request(op *analyzer.Operation, invalid bool) (*http.Request, string) {
+	values := map[string]interface{}{}
+	var body interface{}
+	hasBody := false
+	for i := range op.Parameters {
+		p := &op.Parameters[i]
+		if !p.Required && f.gen.rand.Intn(2) == 0 {
+			continue
+		}
+		if p.In == "body" {
+			body, hasBody = f.gen.Value(p.Schema), true
+			continue
+		}
+		values[p.In+"/"+p.Name] = f.gen.Value(parameterSchema(p))
+	}
+
+	mutation := ""
+	if invalid {
+		var mutations []func() string
+		for i := range op.Parameters {
+			mutations = append(mutations, f.mutations(&op.Parameters[i], values, &body, &hasBody)...)
+		}
+		if len(mutations) > 0 {
+			mutation = mutations[f.gen.rand.Intn(len(mutations))]()
+		}
+	}
+
+	path := f.spec.BasePath + op.Path
+	query := url.Values{}
+	header := http.Header{}
+	form := url.Values{}
+	files := map[string]string{}
+	for _, p := range op.Parameters {
+		v, ok := values[p.In+"/"+p.Name]
+		if !ok {
+			continue
+		}
+		s := format(v, p.CollectionFormat)
+		switch p.In {
+		case "path":
+			path = strings.Replace(path, "{"+p.Name+"}", url.PathEscape(s), -1)
+		case "query":
+			query.Set(p.Name, s)
+		case "header":
+			header.Set(p.Name, s)
+		case "formData":
+			if p.Type == "file" {
+				files[p.Name] = s
+			} else {
+				form.Set(p.Name, s)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	contentType := ""
+	switch {
+	case hasBody:
+		data, _ := json.Marshal(body)
+		buf.Write(data)
+		contentType = "application/json"
+		for _, c := range op.Consumes {
+			if mediaType, _, _ := mime.ParseMediaType(c); strings.HasSuffix(mediaType, "json") {
+				contentType = c
+				break
+			}
+		}
+	case len(files) > 0 || hasMediaType(op.Consumes, "multipart/form-data"):
+		mw := multipart.NewWriter(&buf)
+		for name, v := range form {
+			mw.WriteField(name, v[0])
+		}
+		for name, v := range files {
+			fw, _ := mw.CreateFormFile(name, name)
+			fw.Write([]byte(v))
+		}
+		mw.Close()
+		contentType = mw.FormDataContentType()
+	case len(form) > 0:
+		buf.WriteString(form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	}
+
+	u := &url.URL{Path: path, RawQuery: query.Encode()}
+	req := httptest.NewRequest(op.Method, u.String(), &buf)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if len(op.Produces) > 0 {
+		req.Header.Set("Accept", strings.Join(op.Produces, ", "))
+	}
+	return req, mutation
+}
+
+// mutations returns the ways a request may break the constraints of
+// parameter p, each changing the values of the request and describing the
+// change. Path parameters are not dropped, since the request would no
+// longer be routed to the operation.
+func (f *Fuzzer) mutations(p *spec.Parameter, values map[string]interface{}, body *interface{}, hasBody *bool) []func() string {
+	if p.In == "body" {
+		ret := []func() string{func() string {
+			*body, *hasBody = f.wrongType(p.Schema), true
+			return fmt.Sprintf("body %s has the wrong type", p.Name)
+		}}
+		if p.Required {
+			ret = append(ret, func() string {
+				*body, *hasBody = nil, false
+				return fmt.Sprintf("missing body %s", p.Name)
+			})
+		}
+		return ret
+	}
+
+	key := p.In + "/" + p.Name
+	var ret []func() string
+	if p.Required && p.In != "path" {
+		ret = append(ret, func() string {
+			delete(values, key)
+			return fmt.Sprintf("missing %s parameter %s", p.In, p.Name)
+		})
+	}
+	if len(p.Enum) > 0 {
+		ret = append(ret, func() string {
+			values[key] = "not-" + f.gen.str(nil, nil)
+			return fmt.Sprintf("%s parameter %s is not one of its values", p.In, p.Name)
+		})
+	}
+	if p.Type == "integer" || p.Type == "number" || p.Type == "boolean" {
+		ret = append(ret, func() string {
+			values[key] = "x" + f.gen.str(nil, nil)
+			return fmt.Sprintf("%s parameter %s is not a %s", p.In, p.Name, p.Type)
+		})
+	}
+	if p.Maximum != nil {
+		ret = append(ret, func() string {
+			values[key] = *p.Maximum + 1
+			return fmt.Sprintf("%s parameter %s is above its maximum", p.In, p.Name)
+		})
+	}
+	if p.MaxLength != nil {
+		ret = append(ret, func() string {
+			values[key] = strings.Repeat("x", int(*p.MaxLength)+1)
+			return fmt.Sprintf("%s parameter %s is longer than its maximum length", p.In, p.Name)
+		})
+	}
+	return ret
+}
+
+// wrongType returns a value of another type than the values of s.
+func (f *Fuzzer) wrongType(s *spec.Schema) interface{} {
+	if s != nil && s.Ref.String() != "" {
+		s = f.gen.resolve(s.Ref.String())
+	}
+	if s != nil && schemaType(s) == "string" {
+		return []interface{}{}
+	}
+	return f.gen.str(nil, nil)
+}
+
+// parameterSchema returns the schema of the values of a non body parameter.
+func parameterSchema(p *spec.Parameter) *spec.Schema {
+	return simpleSchema(&p.SimpleSchema, &p.CommonValidations)
+}
+
+func simpleSchema(s *spec.SimpleSchema, v *spec.CommonValidations) *spec.Schema {
+	ret := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Format:           s.Format,
+		Default:          s.Default,
+		Maximum:          v.Maximum,
+		ExclusiveMaximum: v.ExclusiveMaximum,
+		Minimum:          v.Minimum,
+		ExclusiveMinimum: v.ExclusiveMinimum,
+		MaxLength:        v.MaxLength,
+		MinLength:        v.MinLength,
+		Pattern:          v.Pattern,
+		MaxItems:         v.MaxItems,
+		MinItems:         v.MinItems,
+		UniqueItems:      v.UniqueItems,
+		MultipleOf:       v.MultipleOf,
+		Enum:             v.Enum,
+	}}
+	ret.Example = s.Example
+	if s.Type != "" && s.Type != "file" {
+		ret.Type = spec.StringOrArray{s.Type}
+	}
+	if s.Items != nil {
+		ret.Items = &spec.SchemaOrArray{Schema: simpleSchema(&s.Items.SimpleSchema, &s.Items.CommonValidations)}
+	}
+	return ret
+}
+
+// format renders a parameter value, joining arrays as collectionFormat
+// says. Multi arrays are joined with commas too, since a single value is
+// sent per parameter.
+func format(v interface{}, collectionFormat string) string {
+	l, ok := v.([]interface{})
+	if !ok {
+		if s, ok := v.(string); ok {
+			return s
+		}
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	sep := ","
+	switch collectionFormat {
+	case "ssv":
+		sep = " "
+	case "tsv":
+		sep = "\t"
+	case "pipes":
+		sep = "|"
+	}
+	parts := make([]string, 0, len(l))
+	for _, item := range l {
+		parts = append(parts, format(item, ""))
+	}
+	return strings.Join(parts, sep)
+}
+
+func hasMediaType(mediaTypes []string, want string) bool {
+	for _, m := range mediaTypes {
+		if mediaType, _, _ := mime.ParseMediaType(m); mediaType == want {
+			return true
+		}
+	}
+	return false
+}
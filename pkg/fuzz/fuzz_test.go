@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const petstore = `{
+  "swagger": "2.0",
+  "basePath": "/v1",
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [{"name": "limit", "in": "query", "type": "integer", "required": true, "minimum": 1, "maximum": 10}],
+        "responses": {
+          "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}},
+          "400": {"description": "bad request"}
+        }
+      },
+      "post": {
+        "parameters": [{"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}],
+        "responses": {"201": {"description": "created"}, "400": {"description": "bad request"}}
+      }
+    },
+    "/pets/{id}": {
+      "delete": {
+        "parameters": [{"name": "id", "in": "path", "type": "string", "required": true, "maxLength": 5}],
+        "responses": {"204": {"description": "deleted"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}
+  }
+}`
+
+// handler serves the pets, answering 500 to the requests it can't parse when
+// strict is unset.
+func handler(strict bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/pets":
+			limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+			if err != nil || limit < 1 || limit > 10 {
+				if strict {
+					w.WriteHeader(http.StatusBadRequest)
+				} else {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode([]map[string]interface{}{{"name": "rex"}})
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/pets":
+			var pet map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&pet); err != nil || pet["name"] == nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v1/pets/"):
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func newFuzzer(t *testing.T) *Fuzzer {
+	var s spec.Swagger
+	require.NoError(t, json.Unmarshal([]byte(petstore), &s))
+	f, err := New(&s, 1)
+	require.NoError(t, err)
+	return f
+}
+
+func TestRun(t *testing.T) {
+	assert.Empty(t, newFuzzer(t).Run(handler(true), 20))
+
+	failures := newFuzzer(t).Run(handler(false), 20)
+	require.NotEmpty(t, failures)
+	for _, f := range failures {
+		assert.Equal(t, "GET", f.Method)
+		assert.Equal(t, http.StatusInternalServerError, f.StatusCode)
+		assert.NotEmpty(t, f.Mutation)
+		assert.Contains(t, f.Error(), "GET /v1/pets")
+		assert.Contains(t, f.Error(), "500: undocumented status code")
+	}
+}
+
+func TestRunChecksBodies(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"age": 3}]`))
+	})
+	failures := newFuzzer(t).Run(h, 1)
+	require.NotEmpty(t, failures)
+	assert.Equal(t, "/pets", failures[0].Path)
+	assert.Contains(t, failures[0].Err.Error(), "name")
+}
+
+func TestRequest(t *testing.T) {
+	f := newFuzzer(t)
+	list, _ := f.a.OperationAt("GET", "/pets")
+	for i := 0; i < 10; i++ {
+		req, mutation := f.Request(list, false)
+		assert.Empty(t, mutation)
+		assert.Equal(t, "/v1/pets", req.URL.Path)
+		limit, err := strconv.Atoi(req.URL.Query().Get("limit"))
+		require.NoError(t, err)
+		assert.True(t, limit >= 1 && limit <= 10, "limit %d", limit)
+	}
+
+	del, _ := f.a.OperationAt("DELETE", "/pets/{id}")
+	req, mutation := f.Request(del, true)
+	assert.Equal(t, "path parameter id is longer than its maximum length", mutation)
+	assert.Equal(t, "/v1/pets/xxxxxx", req.URL.Path)
+
+	create, _ := f.a.OperationAt("POST", "/pets")
+	req, _ = f.Request(create, false)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	var pet map[string]interface{}
+	require.NoError(t, json.NewDecoder(req.Body).Decode(&pet))
+	assert.Contains(t, pet, "name")
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const definitionPrefix = "#/definitions/"
+
+// maxDepth bounds the nesting of generated values: deeper objects only get
+// their required properties and deeper arrays their minimum items, so that
+// recursive schemas terminate.
+const maxDepth = 4
+
+const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Generator generates random values conforming to schemas. Generated strings
+// don't honour patterns: the example or the default of a schema with a
+// pattern is used instead when it has one.
+type Generator struct {
+	rand *rand.Rand
+	// resolve returns the schema a reference points to, or nil.
+	resolve func(ref string) *spec.Schema
+}
+
+// NewGenerator returns a generator seeded with seed, resolving references to
+// the definitions of s.
+func NewGenerator(s *spec.Swagger, seed int64) *Generator {
+	return &Generator{
+		rand: rand.New(rand.NewSource(seed)),
+		resolve: func(ref string) *spec.Schema {
+			if !strings.HasPrefix(ref, definitionPrefix) {
+				return nil
+			}
+			def, ok := s.Definitions[ref[len(definitionPrefix):]]
+			if !ok {
+				return nil
+			}
+			return &def
+		},
+	}
+}
+
+// Value returns a random value conforming to s, made of the types
+// encoding/json decodes to.
+func (g *Generator) Value(s *spec.Schema) interface{} {
+	return g.value(s, 0)
+}
+
+func (g *Generator) value(s *spec.Schema, depth int) interface{} {
+	if s == nil {
+		return g.str(nil, nil)
+	}
+	if ref := s.Ref.String(); ref != "" {
+		resolved := g.resolve(ref)
+		if resolved == nil {
+			return nil
+		}
+		return g.value(resolved, depth)
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[g.rand.Intn(len(s.Enum))]
+	}
+	if s.Pattern != "" {
+		if s.Example != nil {
+			return s.Example
+		}
+		if s.Default != nil {
+			return s.Default
+		}
+	}
+	if s.Nullable && g.rand.Intn(10) == 0 {
+		return nil
+	}
+	if len(s.OneOf) > 0 {
+		return g.value(&s.OneOf[g.rand.Intn(len(s.OneOf))], depth)
+	}
+	if len(s.AnyOf) > 0 {
+		return g.value(&s.AnyOf[g.rand.Intn(len(s.AnyOf))], depth)
+	}
+
+	switch tpe := schemaType(s); tpe {
+	case "object":
+		return g.object(s, depth)
+	case "array":
+		return g.array(s, depth)
+	case "integer", "number":
+		return g.number(tpe == "integer", &s.SchemaProps)
+	case "boolean":
+		return g.rand.Intn(2) == 0
+	case "null":
+		return nil
+	}
+	return g.format(s.Format, s.MinLength, s.MaxLength)
+}
+
+// schemaType returns the type of a schema, picking one of several types at
+// random and guessing it from the keywords when it has none.
+func schemaType(s *spec.Schema) string {
+	switch {
+	case len(s.Type) > 0:
+		return s.Type[0]
+	case len(s.Properties) > 0 || len(s.AllOf) > 0 || s.AdditionalProperties != nil:
+		return "object"
+	case s.Items != nil:
+		return "array"
+	case s.Minimum != nil || s.Maximum != nil || s.MultipleOf != nil:
+		return "number"
+	}
+	return "string"
+}
+
+func (g *Generator) object(s *spec.Schema, depth int) map[string]interface{} {
+	ret := map[string]interface{}{}
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !required[name] && (depth >= maxDepth || g.rand.Intn(2) == 0) {
+			continue
+		}
+		prop := s.Properties[name]
+		if prop.ReadOnly && !required[name] {
+			continue
+		}
+		ret[name] = g.value(&prop, depth+1)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil && depth < maxDepth && g.rand.Intn(2) == 0 {
+		ret[g.str(nil, nil)] = g.value(s.AdditionalProperties.Schema, depth+1)
+	}
+	for i := range s.AllOf {
+		if part, ok := g.value(&s.AllOf[i], depth).(map[string]interface{}); ok {
+			for k, v := range part {
+				ret[k] = v
+			}
+		}
+	}
+	return ret
+}
+
+func (g *Generator) array(s *spec.Schema, depth int) []interface{} {
+	lo, hi := 0, 3
+	if s.MinItems != nil {
+		lo = int(*s.MinItems)
+		hi = lo + 3
+	}
+	if s.MaxItems != nil && int(*s.MaxItems) < hi {
+		hi = int(*s.MaxItems)
+	}
+	n := lo
+	if depth < maxDepth && hi > lo {
+		n += g.rand.Intn(hi - lo + 1)
+	}
+	ret := make([]interface{}, 0, n)
+	seen := map[string]bool{}
+	for attempts := 0; len(ret) < n && attempts < 10*n; attempts++ {
+		var item *spec.Schema
+		if s.Items != nil {
+			item = s.Items.Schema
+			if item == nil && len(s.Items.Schemas) > 0 {
+				item = &s.Items.Schemas[len(ret)%len(s.Items.Schemas)]
+			}
+		}
+		v := g.value(item, depth+1)
+		if s.UniqueItems {
+			data, _ := json.Marshal(v)
+			if seen[string(data)] {
+				continue
+			}
+			seen[string(data)] = true
+		}
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+func (g *Generator) number(integer bool, v *spec.SchemaProps) interface{} {
+	lo, hi := 0.0, 100.0
+	switch {
+	case v.Minimum != nil && v.Maximum != nil:
+		lo, hi = *v.Minimum, *v.Maximum
+	case v.Minimum != nil:
+		lo, hi = *v.Minimum, *v.Minimum+100
+	case v.Maximum != nil:
+		lo, hi = *v.Maximum-100, *v.Maximum
+	}
+	if integer {
+		ilo, ihi := int64(math.Ceil(lo)), int64(math.Floor(hi))
+		if v.ExclusiveMinimum && float64(ilo) == lo {
+			ilo++
+		}
+		if v.ExclusiveMaximum && float64(ihi) == hi {
+			ihi--
+		}
+		if ihi < ilo {
+			return ilo
+		}
+		n := ilo + g.rand.Int63n(ihi-ilo+1)
+		if v.MultipleOf != nil && *v.MultipleOf >= 1 {
+			m := int64(*v.MultipleOf)
+			if k := n / m * m; k >= ilo {
+				n = k
+			} else {
+				n = k + m
+			}
+		}
+		return float64(n)
+	}
+	n := lo + g.rand.Float64()*(hi-lo)
+	if v.MultipleOf != nil && *v.MultipleOf > 0 {
+		n = math.Ceil(lo / *v.MultipleOf) * *v.MultipleOf
+	}
+	if (v.ExclusiveMinimum && n == lo) || (v.ExclusiveMaximum && n == hi) {
+		n = (lo + hi) / 2
+	}
+	return n
+}
+
+// format returns a random string of the given format, or of letters.
+func (g *Generator) format(format string, minLength, maxLength *int64) string {
+	switch format {
+	case "date-time":
+		return fmt.Sprintf("20%02d-%02d-%02dT%02d:%02d:%02dZ", g.rand.Intn(100), 1+g.rand.Intn(12), 1+g.rand.Intn(28), g.rand.Intn(24), g.rand.Intn(60), g.rand.Intn(60))
+	case "date":
+		return fmt.Sprintf("20%02d-%02d-%02d", g.rand.Intn(100), 1+g.rand.Intn(12), 1+g.rand.Intn(28))
+	case "uuid":
+		b := make([]byte, 16)
+		g.rand.Read(b)
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "email":
+		return g.str(nil, nil) + "@example.com"
+	case "hostname":
+		return strings.ToLower(g.str(nil, nil)) + ".example.com"
+	case "uri", "url":
+		return "https://example.com/" + g.str(nil, nil)
+	case "ipv4":
+		return fmt.Sprintf("10.%d.%d.%d", g.rand.Intn(256), g.rand.Intn(256), g.rand.Intn(256))
+	case "byte":
+		return base64.StdEncoding.EncodeToString([]byte(g.str(nil, nil)))
+	}
+	return g.str(minLength, maxLength)
+}
+
+func (g *Generator) str(minLength, maxLength *int64) string {
+	lo, hi := 1, 10
+	if minLength != nil {
+		lo = int(*minLength)
+		hi = lo + 10
+	}
+	if maxLength != nil && int(*maxLength) < hi {
+		hi = int(*maxLength)
+	}
+	n := lo
+	if hi > lo {
+		n += g.rand.Intn(hi - lo + 1)
+	}
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[g.rand.Intn(len(letters))]
+	}
+	return string(b)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fuzz
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+const definitions = `{
+  "swagger": "2.0",
+  "paths": {},
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["name", "status"],
+      "properties": {
+        "name": {"type": "string", "minLength": 3, "maxLength": 5},
+        "status": {"type": "string", "enum": ["available", "sold"]},
+        "age": {"type": "integer", "minimum": 1, "maximum": 30, "exclusiveMaximum": true},
+        "weight": {"type": "number", "minimum": 0.5, "multipleOf": 0.5},
+        "born": {"type": "string", "format": "date-time"},
+        "id": {"type": "string", "format": "uuid"},
+        "tags": {"type": "array", "minItems": 1, "maxItems": 4, "uniqueItems": true, "items": {"type": "integer", "minimum": 0, "maximum": 9}},
+        "labels": {"type": "object", "additionalProperties": {"type": "boolean"}},
+        "parent": {"$ref": "#/definitions/Pet"},
+        "owner": {"oneOf": [{"type": "string", "format": "email"}, {"type": "integer"}]},
+        "code": {"type": "string", "pattern": "^[A-Z]{3}$", "example": "ABC"}
+      }
+    },
+    "Cat": {
+      "allOf": [
+        {"$ref": "#/definitions/Pet"},
+        {"type": "object", "required": ["lives"], "properties": {"lives": {"type": "integer", "minimum": 1, "maximum": 9}}}
+      ]
+    }
+  }
+}`
+
+func TestGeneratorValue(t *testing.T) {
+	var s spec.Swagger
+	require.NoError(t, json.Unmarshal([]byte(definitions), &s))
+	for seed := int64(0); seed < 50; seed++ {
+		g := NewGenerator(&s, seed)
+		for _, name := range []string{"Pet", "Cat"} {
+			def := s.Definitions[name]
+			v := g.Value(&def)
+			err := validate.AgainstSchema(validate.ExpandRefs(&def, g.resolve), v, strfmt.Default)
+			assert.NoError(t, err, "seed %d: %s %v", seed, name, v)
+		}
+	}
+}
+
+func TestGeneratorSeed(t *testing.T) {
+	var s spec.Swagger
+	require.NoError(t, json.Unmarshal([]byte(definitions), &s))
+	def := s.Definitions["Pet"]
+	assert.Equal(t, NewGenerator(&s, 1).Value(&def), NewGenerator(&s, 1).Value(&def))
+}
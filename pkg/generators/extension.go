@@ -61,6 +61,10 @@ var tagToExtension = map[string]extensionAttributes{
 		kind:          types.Map,
 		allowedValues: sets.NewString("atomic", "granular"),
 	},
+	"keyPattern": {
+		xName: "x-key-pattern",
+		kind:  types.Map,
+	},
 	"structType": {
 		xName:         "x-kubernetes-map-type",
 		kind:          types.Struct,
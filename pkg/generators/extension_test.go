@@ -64,6 +64,12 @@ func TestSingleTagExtension(t *testing.T) {
 			extensionName:   "x-kubernetes-map-type",
 			extensionValues: []string{"granular"},
 		},
+		{
+			comments:        []string{"+keyPattern=^[a-z]+$"},
+			extensionTag:    "keyPattern",
+			extensionName:   "x-key-pattern",
+			extensionValues: []string{"^[a-z]+$"},
+		},
 		{
 			comments:        []string{"+k8s:openapi-gen=x-kubernetes-member-tag:member_test"},
 			extensionTag:    "k8s:openapi-gen",
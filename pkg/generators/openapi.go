@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
 	"k8s.io/gengo/generator"
@@ -401,6 +402,9 @@ func (g openAPITypeWriter) generate(t *types.Type) error {
 		if len(required) > 0 {
 			g.Do("Required: []string{\"$.$\"},\n", strings.Join(required, "\",\""))
 		}
+		if u := parseSumTypeUnion(t); u != nil {
+			u.emitOneOf(g)
+		}
 		g.Do("},\n", nil)
 		if err := g.generateStructExtensions(t); err != nil {
 			return err
@@ -482,7 +486,7 @@ func (g openAPITypeWriter) emitExtensions(extensions []extension, unions []union
 			g.Do("[]interface{}{\n", nil)
 		}
 		for _, value := range extension.values {
-			g.Do("\"$.$\",\n", value)
+			g.Do("$.$,\n", strconv.Quote(value))
 		}
 		if extension.hasMultipleValues() || extension.isAlwaysArrayFormat() {
 			g.Do("},\n", nil)
@@ -636,6 +640,9 @@ func (g openAPITypeWriter) generateProperty(m *types.Member, parent *types.Type)
 	if err := g.generateDefault(m.CommentLines, m.Type, omitEmpty); err != nil {
 		return fmt.Errorf("failed to generate default in %v: %v: %v", parent, m.Name, err)
 	}
+	if err := g.generateValidations(m); err != nil {
+		return fmt.Errorf("failed to generate validations in %v: %v: %v", parent, m.Name, err)
+	}
 	t := resolveAliasAndPtrType(m.Type)
 	// If we can get a openAPI type and format for this type, we consider it to be simple property
 	typeString, format := openapi.OpenAPITypeFormat(t.String())
@@ -716,6 +716,61 @@ Required: []string{"StringToArray"},
 `, funcBuffer.String())
 }
 
+func TestMapKeyPattern(t *testing.T) {
+	callErr, funcErr, assert, _, funcBuffer := testOpenAPITypeWriter(t, `
+package foo
+
+// Blah is a test.
+type Blah struct {
+	// Keys are dotted lowercase names.
+	// +keyPattern=^[a-z]+(\.[a-z]+)*$
+	Labels map[string]string
+}
+	`)
+	if callErr != nil {
+		t.Fatal(callErr)
+	}
+	if funcErr != nil {
+		t.Fatal(funcErr)
+	}
+	assert.Equal(`func schema_base_foo_Blah(ref common.ReferenceCallback) common.OpenAPIDefinition {
+return common.OpenAPIDefinition{
+Schema: spec.Schema{
+SchemaProps: spec.SchemaProps{
+Description: "Blah is a test.",
+Type: []string{"object"},
+Properties: map[string]spec.Schema{
+"Labels": {
+VendorExtensible: spec.VendorExtensible{
+Extensions: spec.Extensions{
+"x-key-pattern": "^[a-z]+(\\.[a-z]+)*$",
+},
+},
+SchemaProps: spec.SchemaProps{
+Description: "Keys are dotted lowercase names.",
+Type: []string{"object"},
+AdditionalProperties: &spec.SchemaOrBool{
+Allows: true,
+Schema: &spec.Schema{
+SchemaProps: spec.SchemaProps{
+Default: "",
+Type: []string{"string"},
+Format: "",
+},
+},
+},
+},
+},
+},
+Required: []string{"Labels"},
+},
+},
+}
+}
+
+`, funcBuffer.String())
+}
+
 func TestNestedMapInt(t *testing.T) {
 	callErr, funcErr, assert, callBuffer, funcBuffer := testOpenAPITypeWriter(t, `
 package foo
@@ -1518,3 +1573,99 @@ map[string]interface{}{
 
 `, funcBuffer.String())
 }
+
+func TestSumTypeUnion(t *testing.T) {
+	callErr, funcErr, assert, _, funcBuffer := testOpenAPITypeWriter(t, `
+package foo
+
+// Blah is a test.
+// +union
+type Blah struct {
+	// +unionDiscriminator
+	Type string `+"`"+`json:"type"`+"`"+`
+	// +optional
+	Numeric *int `+"`"+`json:"numeric,omitempty"`+"`"+`
+	// +optional
+	String *string `+"`"+`json:"string,omitempty"`+"`"+`
+}
+		`)
+	if callErr != nil {
+		t.Fatal(callErr)
+	}
+	if funcErr != nil {
+		t.Fatal(funcErr)
+	}
+	assert.Equal(`func schema_base_foo_Blah(ref common.ReferenceCallback) common.OpenAPIDefinition {
+return common.OpenAPIDefinition{
+Schema: spec.Schema{
+SchemaProps: spec.SchemaProps{
+Description: "Blah is a test.",
+Type: []string{"object"},
+Properties: map[string]spec.Schema{
+"type": {
+SchemaProps: spec.SchemaProps{
+Default: "",
+Type: []string{"string"},
+Format: "",
+},
+},
+"numeric": {
+SchemaProps: spec.SchemaProps{
+Type: []string{"integer"},
+Format: "int32",
+},
+},
+"string": {
+SchemaProps: spec.SchemaProps{
+Type: []string{"string"},
+Format: "",
+},
+},
+},
+Required: []string{"type"},
+OneOf: []spec.Schema{
+{
+SchemaProps: spec.SchemaProps{
+Required: []string{"numeric"},
+Properties: map[string]spec.Schema{
+"type": {
+SchemaProps: spec.SchemaProps{
+Enum: []interface{}{"Numeric"},
+},
+},
+},
+},
+},
+{
+SchemaProps: spec.SchemaProps{
+Required: []string{"string"},
+Properties: map[string]spec.Schema{
+"type": {
+SchemaProps: spec.SchemaProps{
+Enum: []interface{}{"String"},
+},
+},
+},
+},
+},
+},
+},
+VendorExtensible: spec.VendorExtensible{
+Extensions: spec.Extensions{
+"x-kubernetes-unions": []interface{}{
+map[string]interface{}{
+"discriminator": "type",
+"fields-to-discriminateBy": map[string]interface{}{
+"numeric": "Numeric",
+"string": "String",
+},
+},
+},
+},
+},
+},
+}
+}
+
+`, funcBuffer.String())
+}
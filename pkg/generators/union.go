@@ -55,6 +55,28 @@ func (u *union) emit(g openAPITypeWriter) {
 	g.Do("},\n", nil)
 }
 
+// emitOneOf prints a oneOf requiring exactly one member of the union to be
+// set. With a discriminator, each alternative also restricts the
+// discriminator to the value naming its member.
+func (u *union) emitOneOf(g openAPITypeWriter) {
+	keys := []string{}
+	for field := range u.fieldsToDiscriminated {
+		keys = append(keys, field)
+	}
+	sort.Strings(keys)
+	g.Do("OneOf: []spec.Schema{\n", nil)
+	for _, field := range keys {
+		g.Do("{\nSchemaProps: spec.SchemaProps{\n", nil)
+		g.Do("Required: []string{\"$.$\"},\n", field)
+		if u.discriminator != "" {
+			g.Do("Properties: map[string]spec.Schema{\n\"$.$\": {\n", u.discriminator)
+			g.Do("SchemaProps: spec.SchemaProps{\nEnum: []interface{}{\"$.$\"},\n},\n},\n},\n", u.fieldsToDiscriminated[field])
+		}
+		g.Do("},\n},\n", nil)
+	}
+	g.Do("},\n", nil)
+}
+
 // Sets the discriminator if it's not set yet, otherwise return an error
 func (u *union) setDiscriminator(value string) []error {
 	errors := []error{}
@@ -205,3 +227,19 @@ func parseUnionMembers(t *types.Type) (*union, []error) {
 	}
 	return u, append(errors, u.isValid()...)
 }
+
+// parseSumTypeUnion returns the union of a struct tagged with +union whose
+// members are all pointers, so that they can be mutually exclusive. It
+// returns nil for other types and for invalid unions.
+func parseSumTypeUnion(t *types.Type) *union {
+	u, errors := parseUnionStruct(t)
+	if u == nil || len(errors) > 0 || len(u.isValid()) > 0 || len(u.fieldsToDiscriminated) == 0 {
+		return nil
+	}
+	for _, m := range t.Members {
+		if _, ok := u.fieldsToDiscriminated[getReferableName(&m)]; ok && m.Type.Kind != types.Pointer {
+			return nil
+		}
+	}
+	return u
+}
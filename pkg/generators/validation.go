@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/gengo/types"
+	openapi "k8s.io/kube-openapi/pkg/common"
+)
+
+const swagPackagePath = "github.com/go-openapi/swag"
+
+// validationMarkers returns the validation markers of a member. Markers of
+// the member's named type, e.g. "+enum=Always,Never" on "type Policy
+// string", apply to every member of that type unless the member overrides
+// them.
+func validationMarkers(m *types.Member) (*openapi.ValidationMarkers, error) {
+	tags := map[string][]string{}
+	t := m.Type
+	for t.Kind == types.Pointer {
+		t = t.Elem
+	}
+	if t.Kind == types.Alias {
+		for k, v := range types.ExtractCommentTags("+", t.CommentLines) {
+			tags[k] = v
+		}
+	}
+	for k, v := range types.ExtractCommentTags("+", m.CommentLines) {
+		tags[k] = v
+	}
+	// Defaults are generated by generateDefault.
+	delete(tags, tagDefault)
+	return openapi.ParseValidationMarkers(tags)
+}
+
+func (g openAPITypeWriter) generateValidations(m *types.Member) error {
+	v, err := validationMarkers(m)
+	if err != nil || v == nil {
+		return err
+	}
+	args := map[string]interface{}{
+		"Float64": types.Ref(swagPackagePath, "Float64"),
+		"Int64":   types.Ref(swagPackagePath, "Int64"),
+	}
+	float := func(field string, f *float64) {
+		if f != nil {
+			args["value"] = strconv.FormatFloat(*f, 'g', -1, 64)
+			g.Do(field+": $.Float64|raw$($.value$),\n", args)
+		}
+	}
+	integer := func(field string, i *int64) {
+		if i != nil {
+			args["value"] = strconv.FormatInt(*i, 10)
+			g.Do(field+": $.Int64|raw$($.value$),\n", args)
+		}
+	}
+	flag := func(field string, b bool) {
+		if b {
+			g.Do(field+": true,\n", nil)
+		}
+	}
+	float("Minimum", v.Minimum)
+	flag("ExclusiveMinimum", v.ExclusiveMinimum)
+	float("Maximum", v.Maximum)
+	flag("ExclusiveMaximum", v.ExclusiveMaximum)
+	float("MultipleOf", v.MultipleOf)
+	integer("MinLength", v.MinLength)
+	integer("MaxLength", v.MaxLength)
+	if v.Pattern != "" {
+		g.Do("Pattern: $.$,\n", strconv.Quote(v.Pattern))
+	}
+	integer("MinItems", v.MinItems)
+	integer("MaxItems", v.MaxItems)
+	flag("UniqueItems", v.UniqueItems)
+	integer("MinProperties", v.MinProperties)
+	integer("MaxProperties", v.MaxProperties)
+	if len(v.Enum) > 0 {
+		values := make([]string, 0, len(v.Enum))
+		for _, e := range v.Enum {
+			values = append(values, fmt.Sprintf("%#v", e))
+		}
+		g.Do("Enum: []interface{}{$.$},\n", strings.Join(values, ", "))
+	}
+	flag("Nullable", v.Nullable)
+	return g.Error()
+}
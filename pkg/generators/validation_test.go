@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"testing"
+)
+
+func TestValidationMarkers(t *testing.T) {
+	callErr, funcErr, assert, _, funcBuffer := testOpenAPITypeWriter(t, `
+package foo
+
+// Policy is a policy.
+// +enum=Always,Never
+type Policy string
+
+// Blah is a test.
+type Blah struct {
+	// A bounded int
+	// +minimum=0
+	// +maximum=10
+	// +exclusiveMaximum
+	Int int
+	// A restricted string
+	// +maxLength=63
+	// +pattern=^[a-z$]+$
+	String string
+	// A set of strings
+	// +minItems=1
+	// +uniqueItems=true
+	Strings []string
+	// A nullable map
+	// +nullable
+	// +maxProperties=5
+	Map map[string]string `+"`"+`json:"map,omitempty"`+"`"+`
+	// A policy
+	Policy Policy
+	// A policy with its own values
+	// +enum=1,"two"
+	// +default="two"
+	OverriddenPolicy Policy `+"`"+`json:"overriddenPolicy,omitempty"`+"`"+`
+}
+	`)
+	if callErr != nil {
+		t.Fatal(callErr)
+	}
+	if funcErr != nil {
+		t.Fatal(funcErr)
+	}
+	assert.Equal(`func schema_base_foo_Blah(ref common.ReferenceCallback) common.OpenAPIDefinition {
+return common.OpenAPIDefinition{
+Schema: spec.Schema{
+SchemaProps: spec.SchemaProps{
+Description: "Blah is a test.",
+Type: []string{"object"},
+Properties: map[string]spec.Schema{
+"Int": {
+SchemaProps: spec.SchemaProps{
+Description: "A bounded int",
+Default: 0,
+Minimum: swag.Float64(0),
+Maximum: swag.Float64(10),
+ExclusiveMaximum: true,
+Type: []string{"integer"},
+Format: "int32",
+},
+},
+"String": {
+SchemaProps: spec.SchemaProps{
+Description: "A restricted string",
+Default: "",
+MaxLength: swag.Int64(63),
+Pattern: "^[a-z$]+$",
+Type: []string{"string"},
+Format: "",
+},
+},
+"Strings": {
+SchemaProps: spec.SchemaProps{
+Description: "A set of strings",
+MinItems: swag.Int64(1),
+UniqueItems: true,
+Type: []string{"array"},
+Items: &spec.SchemaOrArray{
+Schema: &spec.Schema{
+SchemaProps: spec.SchemaProps{
+Default: "",
+Type: []string{"string"},
+Format: "",
+},
+},
+},
+},
+},
+"map": {
+SchemaProps: spec.SchemaProps{
+Description: "A nullable map",
+MaxProperties: swag.Int64(5),
+Nullable: true,
+Type: []string{"object"},
+AdditionalProperties: &spec.SchemaOrBool{
+Allows: true,
+Schema: &spec.Schema{
+SchemaProps: spec.SchemaProps{
+Default: "",
+Type: []string{"string"},
+Format: "",
+},
+},
+},
+},
+},
+"Policy": {
+SchemaProps: spec.SchemaProps{
+Description: "A policy",
+Default: "",
+Enum: []interface{}{"Always", "Never"},
+Type: []string{"string"},
+Format: "",
+},
+},
+"overriddenPolicy": {
+SchemaProps: spec.SchemaProps{
+Description: "A policy with its own values",
+Default: "two",
+Enum: []interface{}{1, "two"},
+Type: []string{"string"},
+Format: "",
+},
+},
+},
+Required: []string{"Int","String","Strings","Policy"},
+},
+},
+}
+}
+
+`, funcBuffer.String())
+}
+
+func TestValidationMarkersErrors(t *testing.T) {
+	_, funcErr, assert, _, _ := testOpenAPITypeWriter(t, `
+package foo
+
+// Blah is a test.
+type Blah struct {
+	// +maxLength=-1
+	String string
+}
+	`)
+	assert.EqualError(funcErr, `failed to generate validations in base/foo.Blah: String: invalid maxLength marker "-1": expected a non-negative integer`)
+}
@@ -20,21 +20,24 @@ import (
 	"bytes"
 	"compress/gzip"
 	"crypto/sha512"
+	"encoding/json"
 	"fmt"
 	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
-	"github.com/NYTimes/gziphandler"
 	"github.com/emicklei/go-restful"
 	"github.com/golang/protobuf/proto"
 	openapi_v2 "github.com/googleapis/gnostic/openapiv2"
 	jsoniter "github.com/json-iterator/go"
-	"github.com/munnerz/goautoneg"
 	"gopkg.in/yaml.v2"
 	"k8s.io/kube-openapi/pkg/builder"
 	"k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/logging"
+	"k8s.io/kube-openapi/pkg/negotiation"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
@@ -50,18 +53,24 @@ const (
 // OpenAPIService is the service responsible for serving OpenAPI spec. It has
 // the ability to safely change the spec while serving it.
 type OpenAPIService struct {
+	// Logger, if set, receives the content negotiation decisions of the
+	// registered handlers. It must be set before the service is registered.
+	Logger logging.Logger
+
 	// rwMutex protects All members of this service.
 	rwMutex sync.RWMutex
 
 	lastModified time.Time
 
-	specBytes []byte
-	specPb    []byte
-	specPbGz  []byte
+	specBytes   []byte
+	specBytesGz []byte
+	specPb      []byte
+	specPbGz    []byte
 
-	specBytesETag string
-	specPbETag    string
-	specPbGzETag  string
+	specBytesETag   string
+	specBytesGzETag string
+	specPbETag      string
+	specPbGzETag    string
 }
 
 func init() {
@@ -89,6 +98,12 @@ func (o *OpenAPIService) getSwaggerBytes() ([]byte, string, time.Time) {
 	return o.specBytes, o.specBytesETag, o.lastModified
 }
 
+func (o *OpenAPIService) getSwaggerGzBytes() ([]byte, string, time.Time) {
+	o.rwMutex.RLock()
+	defer o.rwMutex.RUnlock()
+	return o.specBytesGz, o.specBytesGzETag, o.lastModified
+}
+
 func (o *OpenAPIService) getSwaggerPbBytes() ([]byte, string, time.Time) {
 	o.rwMutex.RLock()
 	defer o.rwMutex.RUnlock()
@@ -101,6 +116,9 @@ func (o *OpenAPIService) getSwaggerPbGzBytes() ([]byte, string, time.Time) {
 	return o.specPbGz, o.specPbGzETag, o.lastModified
 }
 
+// UpdateSpec replaces the served spec. The JSON, protobuf and gzipped
+// representations are computed once here, and swapped at once for all
+// requests.
 func (o *OpenAPIService) UpdateSpec(openapiSpec *spec.Swagger) (err error) {
 	specBytes, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(openapiSpec)
 	if err != nil {
@@ -110,9 +128,11 @@ func (o *OpenAPIService) UpdateSpec(openapiSpec *spec.Swagger) (err error) {
 	if err != nil {
 		return err
 	}
+	specBytesGz := toGzip(specBytes)
 	specPbGz := toGzip(specPb)
 
 	specBytesETag := computeETag(specBytes)
+	specBytesGzETag := computeETag(specBytesGz)
 	specPbETag := computeETag(specPb)
 	specPbGzETag := computeETag(specPbGz)
 
@@ -122,9 +142,11 @@ func (o *OpenAPIService) UpdateSpec(openapiSpec *spec.Swagger) (err error) {
 	defer o.rwMutex.Unlock()
 
 	o.specBytes = specBytes
+	o.specBytesGz = specBytesGz
 	o.specPb = specPb
 	o.specPbGz = specPbGz
 	o.specBytesETag = specBytesETag
+	o.specBytesGzETag = specBytesGzETag
 	o.specPbETag = specPbETag
 	o.specPbGzETag = specPbGzETag
 	o.lastModified = lastModified
@@ -174,6 +196,8 @@ func jsonToYAMLValue(j interface{}) interface{} {
 	return j
 }
 
+// ToProtoBinary converts a JSON or YAML serialized spec into its gnostic
+// protobuf wire format.
 func ToProtoBinary(json []byte) ([]byte, error) {
 	document, err := openapi_v2.ParseDocument(json)
 	if err != nil {
@@ -182,6 +206,43 @@ func ToProtoBinary(json []byte) ([]byte, error) {
 	return proto.Marshal(document)
 }
 
+// ToProtoDocument converts a spec into the gnostic OpenAPI v2 protobuf document.
+func ToProtoDocument(openapiSpec *spec.Swagger) (*openapi_v2.Document, error) {
+	specBytes, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(openapiSpec)
+	if err != nil {
+		return nil, err
+	}
+	return openapi_v2.ParseDocument(specBytes)
+}
+
+// FromProtoDocument converts a gnostic OpenAPI v2 protobuf document back into a spec.
+func FromProtoDocument(document *openapi_v2.Document) (*spec.Swagger, error) {
+	var raw interface{}
+	if err := document.ToRawInfo().Decode(&raw); err != nil {
+		return nil, err
+	}
+	specBytes, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	openapiSpec := &spec.Swagger{}
+	if err := json.Unmarshal(specBytes, openapiSpec); err != nil {
+		return nil, err
+	}
+	return openapiSpec, nil
+}
+
+// FromProtoBinary parses a spec serialized in the gnostic protobuf wire format,
+// as served with the application/com.github.proto-openapi.spec.v2@v1.0+protobuf
+// media type.
+func FromProtoBinary(data []byte) (*spec.Swagger, error) {
+	document := &openapi_v2.Document{}
+	if err := proto.Unmarshal(data, document); err != nil {
+		return nil, err
+	}
+	return FromProtoDocument(document)
+}
+
 func toGzip(data []byte) []byte {
 	var buf bytes.Buffer
 	zw := gzip.NewWriter(&buf)
@@ -202,50 +263,81 @@ func RegisterOpenAPIVersionedService(spec *spec.Swagger, servePath string, handl
 }
 
 // RegisterOpenAPIVersionedService registers a handler to provide access to provided swagger spec.
+// The representation is negotiated with the Accept header, and clients
+// accepting gzip get the gzipped representation computed by UpdateSpec.
 func (o *OpenAPIService) RegisterOpenAPIVersionedService(servePath string, handler common.PathHandler) error {
 	accepted := []struct {
-		Type           string
-		SubType        string
-		GetDataAndETag func() ([]byte, string, time.Time)
+		MediaType        string
+		GetDataAndETag   func() ([]byte, string, time.Time)
+		GetGzDataAndETag func() ([]byte, string, time.Time)
 	}{
-		{"application", "json", o.getSwaggerBytes},
-		{"application", "com.github.proto-openapi.spec.v2@v1.0+protobuf", o.getSwaggerPbBytes},
+		{"application/json", o.getSwaggerBytes, o.getSwaggerGzBytes},
+		{"application/com.github.proto-openapi.spec.v2@v1.0+protobuf", o.getSwaggerPbBytes, o.getSwaggerPbGzBytes},
+	}
+	offers := make([]string, 0, len(accepted))
+	for _, accepts := range accepted {
+		offers = append(offers, accepts.MediaType)
 	}
 
-	handler.Handle(servePath, gziphandler.GzipHandler(http.HandlerFunc(
+	logger := o.Logger
+	if logger == nil {
+		logger = logging.Discard
+	}
+	handler.Handle(servePath, http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
-			decipherableFormats := r.Header.Get("Accept")
-			if decipherableFormats == "" {
-				decipherableFormats = "*/*"
-			}
-			clauses := goautoneg.ParseAccept(decipherableFormats)
 			w.Header().Add("Vary", "Accept")
-			for _, clause := range clauses {
-				for _, accepts := range accepted {
-					if clause.Type != accepts.Type && clause.Type != "*" {
-						continue
-					}
-					if clause.SubType != accepts.SubType && clause.SubType != "*" {
-						continue
-					}
-
-					// serve the first matching media type in the sorted clause list
-					data, etag, lastModified := accepts.GetDataAndETag()
-					w.Header().Set("Etag", etag)
-					// ServeContent will take care of caching using eTag.
-					http.ServeContent(w, r, servePath, lastModified, bytes.NewReader(data))
-					return
+			w.Header().Add("Vary", "Accept-Encoding")
+			mediaType := negotiation.Negotiate(r.Header.Get("Accept"), offers)
+			logger.Debugf("negotiated media type", "path", servePath, "accept", r.Header.Get("Accept"), "mediaType", mediaType)
+			for _, accepts := range accepted {
+				if accepts.MediaType != mediaType {
+					continue
+				}
+				getDataAndETag := accepts.GetDataAndETag
+				if acceptsGzip(r) {
+					getDataAndETag = accepts.GetGzDataAndETag
+					w.Header().Set("Content-Encoding", "gzip")
 				}
+				data, etag, lastModified := getDataAndETag()
+				w.Header().Set("Content-Type", accepts.MediaType)
+				w.Header().Set("Etag", etag)
+				// ServeContent will take care of caching using eTag.
+				http.ServeContent(w, r, servePath, lastModified, bytes.NewReader(data))
+				return
 			}
 			// Return 406 for not acceptable format
+			logger.Warnf("no acceptable media type", "path", servePath, "accept", r.Header.Get("Accept"))
 			w.WriteHeader(406)
 			return
 		}),
-	))
+	)
 
 	return nil
 }
 
+// acceptsGzip returns whether the Accept-Encoding header of a request
+// accepts gzip, e.g. "gzip, deflate" or "*;q=0.5" but not "gzip;q=0".
+func acceptsGzip(r *http.Request) bool {
+	wildcard := false
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		params := strings.Split(part, ";")
+		coding := strings.TrimSpace(params[0])
+		q := 1.0
+		for _, param := range params[1:] {
+			if kv := strings.SplitN(strings.TrimSpace(param), "=", 2); len(kv) == 2 && kv[0] == "q" {
+				q, _ = strconv.ParseFloat(kv[1], 64)
+			}
+		}
+		switch coding {
+		case "gzip":
+			return q > 0
+		case "*":
+			wildcard = q > 0
+		}
+	}
+	return wildcard
+}
+
 // BuildAndRegisterOpenAPIVersionedService builds the spec and registers a handler to provide access to it.
 // Use this method if your OpenAPI spec is static. If you want to update the spec, use BuildOpenAPISpec then RegisterOpenAPIVersionedService.
 func BuildAndRegisterOpenAPIVersionedService(servePath string, webServices []*restful.WebService, config *common.Config, handler common.PathHandler) (*OpenAPIService, error) {
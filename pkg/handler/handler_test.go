@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"io/ioutil"
 	"math"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	json "github.com/json-iterator/go"
 	yaml "gopkg.in/yaml.v2"
+	"k8s.io/kube-openapi/pkg/logging"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
@@ -172,8 +174,193 @@ func TestToProtoBinary(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if _, err := ToProtoBinary(bs); err != nil {
+	pb, err := ToProtoBinary(bs)
+	if err != nil {
 		t.Fatal()
 	}
-	// TODO: add some kind of roundtrip test here
+
+	var expected spec.Swagger
+	if err := json.Unmarshal(bs, &expected); err != nil {
+		t.Fatal(err)
+	}
+	roundTripped, err := FromProtoBinary(pb)
+	if err != nil {
+		t.Fatalf("Unexpected error in decoding protobuf: %v", err)
+	}
+	expectedJSON, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTrippedJSON, err := json.Marshal(roundTripped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, got interface{}
+	if err := json.Unmarshal(expectedJSON, &want); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(roundTrippedJSON, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Spec mismatches after protobuf round trip, \nwant: %s, \ngot:  %s", expectedJSON, roundTrippedJSON)
+	}
+}
+
+func TestToProtoDocument(t *testing.T) {
+	var s spec.Swagger
+	if err := s.UnmarshalJSON(returnedSwagger); err != nil {
+		t.Fatal(err)
+	}
+	document, err := ToProtoDocument(&s)
+	if err != nil {
+		t.Fatalf("Unexpected error in converting to protobuf document: %v", err)
+	}
+	if document.Info.Title != "Kubernetes" || document.Info.Version != "v1.11.0" {
+		t.Errorf("Unexpected info in protobuf document: %v", document.Info)
+	}
+	back, err := FromProtoDocument(document)
+	if err != nil {
+		t.Fatalf("Unexpected error in converting from protobuf document: %v", err)
+	}
+	if !reflect.DeepEqual(back.Info, s.Info) {
+		t.Errorf("Info mismatches, want: %v, got: %v", s.Info, back.Info)
+	}
+}
+
+func TestLogNegotiation(t *testing.T) {
+	var s spec.Swagger
+	if err := s.UnmarshalJSON(returnedSwagger); err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	o, err := NewOpenAPIService(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	o.Logger = logging.Func(func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})
+	if err := o.RegisterOpenAPIVersionedService("/openapi/v2", mux); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, accept := range []string{"application/json", "test/test"} {
+		req := httptest.NewRequest("GET", "/openapi/v2", nil)
+		req.Header.Set("Accept", accept)
+		mux.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	want := []string{
+		`DEBUG negotiated media type path="/openapi/v2" accept="application/json" mediaType="application/json"`,
+		`DEBUG negotiated media type path="/openapi/v2" accept="test/test" mediaType=""`,
+		`WARN no acceptable media type path="/openapi/v2" accept="test/test"`,
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("unexpected log, want:\n%s\ngot:\n%s", spew.Sdump(want), spew.Sdump(lines))
+	}
+}
+
+func TestServeGzipAndETag(t *testing.T) {
+	var s spec.Swagger
+	if err := s.UnmarshalJSON(returnedSwagger); err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	o, err := NewOpenAPIService(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = o.RegisterOpenAPIVersionedService("/openapi/v2", mux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	// Keep the transport from negotiating and decoding gzip by itself.
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+
+	get := func(acceptEncoding, ifNoneMatch string) *http.Response {
+		req, err := http.NewRequest("GET", server.URL+"/openapi/v2", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept", "application/json")
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	body := func(resp *http.Response) []byte {
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	resp := get("gzip, deflate", "")
+	if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("unexpected Content-Encoding %q", got)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("unexpected Content-Type %q", got)
+	}
+	gzETag := resp.Header.Get("Etag")
+	if gzETag != o.specBytesGzETag {
+		t.Errorf("unexpected ETag %q", gzETag)
+	}
+	if got := body(resp); !reflect.DeepEqual(got, o.specBytesGz) {
+		t.Errorf("the body is not the cached gzipped spec")
+	}
+
+	resp = get("gzip;q=0", "")
+	if got := resp.Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("unexpected Content-Encoding %q", got)
+	}
+	if got := body(resp); !reflect.DeepEqual(got, o.specBytes) {
+		t.Errorf("the body is not the cached spec")
+	}
+
+	resp = get("gzip", gzETag)
+	body(resp)
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("unexpected status code %d for a matching ETag", resp.StatusCode)
+	}
+
+	s.Info.Title = "updated"
+	if err := o.UpdateSpec(&s); err != nil {
+		t.Fatal(err)
+	}
+	resp = get("gzip", gzETag)
+	body(resp)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Etag") == gzETag {
+		t.Errorf("expected a new ETag after UpdateSpec, got status %d and ETag %q", resp.StatusCode, resp.Header.Get("Etag"))
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	for header, want := range map[string]bool{
+		"":                    false,
+		"gzip":                true,
+		"deflate, gzip;q=0.5": true,
+		"gzip;q=0":            false,
+		"*":                   true,
+		"*;q=0.1, deflate":    true,
+		"gzip;q=0, *":         false,
+		"deflate":             false,
+	} {
+		r := &http.Request{Header: http.Header{"Accept-Encoding": []string{header}}}
+		if got := acceptsGzip(r); got != want {
+			t.Errorf("Accept-Encoding: %q: want %t, got %t", header, want, got)
+		}
+	}
 }
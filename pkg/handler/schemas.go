@@ -0,0 +1,197 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/jsonpointer"
+
+	"k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	definitionPrefix = "#/definitions/"
+	// jsonSchemaDraft4 is the JSON Schema dialect of Swagger 2.0 schemas.
+	jsonSchemaDraft4 = "http://json-schema.org/draft-04/schema#"
+)
+
+// SchemaService serves each definition of a spec as a standalone JSON
+// Schema document at <servePath>/<definition>, and the sorted list of the
+// definitions at servePath. References to other definitions are rewritten
+// to the URLs they are served at, so that tools can fetch just the
+// schemas they need. It has the ability to safely change the spec while
+// serving it.
+type SchemaService struct {
+	// baseURL is the URL the definitions are served under, e.g.
+	// "https://example.com/schemas".
+	baseURL string
+
+	// rwMutex protects All members below.
+	rwMutex      sync.RWMutex
+	lastModified time.Time
+	index        []byte
+	indexETag    string
+	schemas      map[string][]byte
+	schemaETags  map[string]string
+}
+
+// NewSchemaService builds a SchemaService for the given spec. baseURL is
+// the absolute URL servePath is exposed at, used as the prefix of the
+// rewritten references and the ids of the schemas.
+func NewSchemaService(baseURL string, openapiSpec *spec.Swagger) (*SchemaService, error) {
+	s := &SchemaService{baseURL: strings.TrimSuffix(baseURL, "/")}
+	if err := s.UpdateSpec(openapiSpec); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SchemaURL returns the URL a definition is served at.
+func (s *SchemaService) SchemaURL(name string) string {
+	return s.baseURL + "/" + url.PathEscape(name)
+}
+
+// UpdateSpec replaces the served definitions.
+func (s *SchemaService) UpdateSpec(openapiSpec *spec.Swagger) error {
+	schemas := make(map[string][]byte, len(openapiSpec.Definitions))
+	etags := make(map[string]string, len(openapiSpec.Definitions))
+	names := make([]string, 0, len(openapiSpec.Definitions))
+	for name, def := range openapiSpec.Definitions {
+		data, err := s.standalone(name, def)
+		if err != nil {
+			return fmt.Errorf("definition %s: %v", name, err)
+		}
+		schemas[name] = data
+		etags[name] = computeETag(data)
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	index, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+
+	lastModified := time.Now()
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.schemas = schemas
+	s.schemaETags = etags
+	s.index = index
+	s.indexETag = computeETag(index)
+	s.lastModified = lastModified
+
+	return nil
+}
+
+// standalone returns the JSON Schema document of a definition.
+func (s *SchemaService) standalone(name string, def spec.Schema) ([]byte, error) {
+	def.ID = s.SchemaURL(name)
+	def.Schema = jsonSchemaDraft4
+	data, err := json.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(s.rewriteRefs(v))
+}
+
+// rewriteRefs replaces the references to definitions in a decoded JSON
+// value by the URLs of the definitions.
+func (s *SchemaService) rewriteRefs(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if ref, ok := child.(string); ok && k == "$ref" && strings.HasPrefix(ref, definitionPrefix) {
+				v[k] = s.rewriteRef(ref)
+				continue
+			}
+			v[k] = s.rewriteRefs(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = s.rewriteRefs(child)
+		}
+	}
+	return v
+}
+
+// rewriteRef rewrites a reference into the definitions, possibly into a
+// property of a definition as in "#/definitions/Pet/properties/name".
+func (s *SchemaService) rewriteRef(ref string) string {
+	fragment, err := url.PathUnescape(ref[len(definitionPrefix):])
+	if err != nil {
+		return ref
+	}
+	tokens := strings.SplitN(fragment, "/", 2)
+	ret := s.SchemaURL(jsonpointer.Unescape(tokens[0]))
+	if len(tokens) == 2 {
+		ret += "#/" + tokens[1]
+	}
+	return ret
+}
+
+func (s *SchemaService) getSchema(name string) ([]byte, string, time.Time, bool) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	data, ok := s.schemas[name]
+	return data, s.schemaETags[name], s.lastModified, ok
+}
+
+func (s *SchemaService) getIndex() ([]byte, string, time.Time) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	return s.index, s.indexETag, s.lastModified
+}
+
+// RegisterSchemaService registers the handlers of the definitions under
+// servePath, e.g. "/schemas".
+func (s *SchemaService) RegisterSchemaService(servePath string, handler common.PathHandler) {
+	servePath = strings.TrimSuffix(servePath, "/")
+	serve := func(w http.ResponseWriter, r *http.Request, data []byte, etag string, lastModified time.Time) {
+		w.Header().Set("Content-Type", mimeJson)
+		w.Header().Set("Etag", etag)
+		// ServeContent will take care of caching using eTag.
+		http.ServeContent(w, r, r.URL.Path, lastModified, bytes.NewReader(data))
+	}
+	handler.Handle(servePath, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, etag, lastModified := s.getIndex()
+		serve(w, r, data, etag, lastModified)
+	}))
+	handler.Handle(servePath+"/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, etag, lastModified, ok := s.getSchema(strings.TrimPrefix(r.URL.Path, servePath+"/"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		serve(w, r, data, etag, lastModified)
+	}))
+}
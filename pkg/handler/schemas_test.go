@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const definitionsSwagger = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "v1"},
+  "paths": {},
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "owner": {"$ref": "#/definitions/io.k8s.Owner"},
+        "tags": {"type": "array", "items": {"$ref": "#/definitions/Tag"}},
+        "nick": {"$ref": "#/definitions/Tag/properties/name"}
+      }
+    },
+    "io.k8s.Owner": {"type": "string"},
+    "Tag": {"type": "object", "properties": {"name": {"type": "string"}}}
+  }
+}`
+
+func TestSchemaService(t *testing.T) {
+	var s spec.Swagger
+	if err := json.Unmarshal([]byte(definitionsSwagger), &s); err != nil {
+		t.Fatal(err)
+	}
+	o, err := NewSchemaService("https://example.com/schemas/", &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	o.RegisterSchemaService("/schemas", mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	get := func(path, ifNoneMatch string) (*http.Response, interface{}) {
+		req, err := http.NewRequest("GET", server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v interface{}
+		if resp.StatusCode == http.StatusOK {
+			if err := json.Unmarshal(data, &v); err != nil {
+				t.Fatalf("%s: %v", path, err)
+			}
+		}
+		return resp, v
+	}
+
+	resp, index := get("/schemas", "")
+	if want := []interface{}{"Pet", "Tag", "io.k8s.Owner"}; !reflect.DeepEqual(index, want) {
+		t.Errorf("unexpected index %v", index)
+	}
+
+	resp, pet := get("/schemas/Pet", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code %d", resp.StatusCode)
+	}
+	var want interface{}
+	if err := json.Unmarshal([]byte(`{
+	  "id": "https://example.com/schemas/Pet",
+	  "$schema": "http://json-schema.org/draft-04/schema#",
+	  "type": "object",
+	  "properties": {
+	    "owner": {"$ref": "https://example.com/schemas/io.k8s.Owner"},
+	    "tags": {"type": "array", "items": {"$ref": "https://example.com/schemas/Tag"}},
+	    "nick": {"$ref": "https://example.com/schemas/Tag#/properties/name"}
+	  }
+	}`), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pet, want) {
+		t.Errorf("unexpected schema:\n%v\nwant:\n%v", pet, want)
+	}
+
+	resp, _ = get("/schemas/Pet", resp.Header.Get("Etag"))
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("unexpected status code %d for a matching ETag", resp.StatusCode)
+	}
+	if resp, _ = get("/schemas/Missing", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unexpected status code %d for a missing definition", resp.StatusCode)
+	}
+
+	delete(s.Definitions, "Tag")
+	if err := o.UpdateSpec(&s); err != nil {
+		t.Fatal(err)
+	}
+	if resp, _ = get("/schemas/Tag", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unexpected status code %d for a removed definition", resp.StatusCode)
+	}
+}
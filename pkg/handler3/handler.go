@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package handler3 serves OpenAPI v3 documents split by group, like
+// aggregated API servers do under /openapi/v3: a discovery document lists
+// the groups along with a hash of their content, so that clients only
+// fetch the groups that changed, and each group is served on its own.
+package handler3
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/spec3"
+)
+
+// Discovery is the discovery document listing the groups.
+type Discovery struct {
+	// Paths are keyed by group, e.g. "apis/apps/v1".
+	Paths map[string]DiscoveryGroup `json:"paths"`
+}
+
+// DiscoveryGroup locates the document of a group.
+type DiscoveryGroup struct {
+	// ServerRelativeURL is the URL of the document, with a hash query
+	// parameter changing along with the document, e.g.
+	// "/openapi/v3/apis/apps/v1?hash=0123…".
+	ServerRelativeURL string `json:"serverRelativeURL"`
+}
+
+// OpenAPIService serves the documents of a set of groups. It is safe to
+// update the groups while serving them.
+type OpenAPIService struct {
+	// rwMutex protects all members of this service.
+	rwMutex   sync.RWMutex
+	servePath string
+	groups    map[string]*group
+
+	discovery             []byte
+	discoveryETag         string
+	discoveryLastModified time.Time
+}
+
+type group struct {
+	data         []byte
+	hash         string
+	lastModified time.Time
+}
+
+// NewOpenAPIService returns a service without groups, serving under
+// servePath, e.g. "/openapi/v3".
+func NewOpenAPIService(servePath string) *OpenAPIService {
+	o := &OpenAPIService{
+		servePath: strings.TrimSuffix(servePath, "/"),
+		groups:    map[string]*group{},
+	}
+	o.updateDiscovery()
+	return o
+}
+
+func computeHash(data []byte) string {
+	return fmt.Sprintf("%X", sha512.Sum512(data))
+}
+
+// UpdateGroup adds or replaces the document of a group, e.g. "apis/apps/v1".
+func (o *OpenAPIService) UpdateGroup(name string, doc *spec3.OpenAPI) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	name = strings.Trim(name, "/")
+	hash := computeHash(data)
+
+	o.rwMutex.Lock()
+	defer o.rwMutex.Unlock()
+	if g, ok := o.groups[name]; ok && g.hash == hash {
+		return nil
+	}
+	o.groups[name] = &group{data: data, hash: hash, lastModified: time.Now()}
+	o.updateDiscovery()
+	return nil
+}
+
+// DeleteGroup removes a group.
+func (o *OpenAPIService) DeleteGroup(name string) {
+	o.rwMutex.Lock()
+	defer o.rwMutex.Unlock()
+	name = strings.Trim(name, "/")
+	if _, ok := o.groups[name]; ok {
+		delete(o.groups, name)
+		o.updateDiscovery()
+	}
+}
+
+// updateDiscovery must be called with the lock held.
+func (o *OpenAPIService) updateDiscovery() {
+	d := Discovery{Paths: make(map[string]DiscoveryGroup, len(o.groups))}
+	for name, g := range o.groups {
+		d.Paths[name] = DiscoveryGroup{ServerRelativeURL: o.groupURL(name, g.hash)}
+	}
+	// Marshaling a map sorts its keys, so the document is stable.
+	o.discovery, _ = json.Marshal(d)
+	o.discoveryETag = fmt.Sprintf("%q", computeHash(o.discovery))
+	o.discoveryLastModified = time.Now()
+}
+
+func (o *OpenAPIService) groupURL(name, hash string) string {
+	return o.servePath + "/" + name + "?hash=" + url.QueryEscape(hash)
+}
+
+// Groups returns the sorted names of the groups.
+func (o *OpenAPIService) Groups() []string {
+	o.rwMutex.RLock()
+	defer o.rwMutex.RUnlock()
+	ret := make([]string, 0, len(o.groups))
+	for name := range o.groups {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// HandleDiscovery serves the discovery document.
+func (o *OpenAPIService) HandleDiscovery(w http.ResponseWriter, r *http.Request) {
+	o.rwMutex.RLock()
+	data, etag, lastModified := o.discovery, o.discoveryETag, o.discoveryLastModified
+	o.rwMutex.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Etag", etag)
+	http.ServeContent(w, r, o.servePath, lastModified, bytes.NewReader(data))
+}
+
+// HandleGroup serves the document of the group named by the request path.
+// Requests for the current hash may be cached forever, requests for another
+// hash are redirected to the current one.
+func (o *OpenAPIService) HandleGroup(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, o.servePath), "/")
+	o.rwMutex.RLock()
+	g, ok := o.groups[name]
+	o.rwMutex.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if hash := r.URL.Query().Get("hash"); hash != "" {
+		if hash != g.hash {
+			http.Redirect(w, r, o.groupURL(name, g.hash), http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Cache-Control", "public, immutable, max-age=31536000")
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Etag", fmt.Sprintf("%q", g.hash))
+	http.ServeContent(w, r, name, g.lastModified, bytes.NewReader(g.data))
+}
+
+// RegisterOpenAPIV3VersionedService registers the discovery document at the
+// serve path of the service, and the groups under it.
+func (o *OpenAPIService) RegisterOpenAPIV3VersionedService(handler common.PathHandler) {
+	handler.Handle(o.servePath, http.HandlerFunc(o.HandleDiscovery))
+	handler.Handle(o.servePath+"/", http.HandlerFunc(o.HandleGroup))
+}
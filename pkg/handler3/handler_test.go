@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler3
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func doc(title string) *spec3.OpenAPI {
+	return &spec3.OpenAPI{OpenAPIProps: spec3.OpenAPIProps{
+		Version: "3.0.3",
+		Info:    &spec.Info{InfoProps: spec.InfoProps{Title: title, Version: "v1"}},
+	}}
+}
+
+func TestOpenAPIService(t *testing.T) {
+	o := NewOpenAPIService("/openapi/v3/")
+	require.NoError(t, o.UpdateGroup("apis/apps/v1", doc("apps")))
+	require.NoError(t, o.UpdateGroup("/api/v1/", doc("core")))
+	assert.Equal(t, []string{"api/v1", "apis/apps/v1"}, o.Groups())
+
+	mux := http.NewServeMux()
+	o.RegisterOpenAPIV3VersionedService(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	get := func(path string, header ...string) (*http.Response, []byte) {
+		req, err := http.NewRequest("GET", server.URL+path, nil)
+		require.NoError(t, err)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, data
+	}
+	discovery := func() Discovery {
+		resp, data := get("/openapi/v3")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var d Discovery
+		require.NoError(t, json.Unmarshal(data, &d))
+		return d
+	}
+
+	d := discovery()
+	require.Len(t, d.Paths, 2)
+	appsURL := d.Paths["apis/apps/v1"].ServerRelativeURL
+	assert.Regexp(t, `^/openapi/v3/apis/apps/v1\?hash=[0-9A-F]+$`, appsURL)
+
+	resp, data := get(appsURL)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "public, immutable, max-age=31536000", resp.Header.Get("Cache-Control"))
+	var apps spec3.OpenAPI
+	require.NoError(t, json.Unmarshal(data, &apps))
+	assert.Equal(t, "apps", apps.Info.Title)
+
+	resp, _ = get("/openapi/v3/apis/apps/v1")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+	resp, _ = get("/openapi/v3/apis/apps/v1", "If-None-Match", resp.Header.Get("Etag"))
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	// Only the updated group changes hash.
+	coreURL := d.Paths["api/v1"].ServerRelativeURL
+	require.NoError(t, o.UpdateGroup("apis/apps/v1", doc("apps v2")))
+	d = discovery()
+	assert.NotEqual(t, appsURL, d.Paths["apis/apps/v1"].ServerRelativeURL)
+	assert.Equal(t, coreURL, d.Paths["api/v1"].ServerRelativeURL)
+
+	// Stale hashes are redirected to the current one.
+	resp, _ = get(appsURL)
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(t, d.Paths["apis/apps/v1"].ServerRelativeURL, resp.Header.Get("Location"))
+
+	o.DeleteGroup("apis/apps/v1")
+	assert.Len(t, discovery().Paths, 1)
+	resp, _ = get("/openapi/v3/apis/apps/v1")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
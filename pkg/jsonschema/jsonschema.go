@@ -0,0 +1,283 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jsonschema exports OpenAPI schemas as standalone JSON Schema
+// documents, for use with external JSON Schema validators. Swagger 2.0 and
+// OpenAPI 3.0 schemas are an extended subset of JSON Schema draft 4, so the
+// OpenAPI keywords are translated to their JSON Schema equivalent:
+//
+//	nullable, x-nullable   "null" added to the types
+//	type: file             type: string, contentMediaType: application/octet-stream
+//	example                examples
+//	boolean exclusive*     numeric exclusiveMaximum and exclusiveMinimum
+//	id                     $id
+//
+// and, for 2020-12, definitions become $defs and tuple items become
+// prefixItems. Other keywords, including extensions, are kept as is.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Dialect is a JSON Schema dialect, identified by its meta-schema.
+type Dialect string
+
+const (
+	// Draft07 is JSON Schema draft 7.
+	Draft07 Dialect = "http://json-schema.org/draft-07/schema#"
+	// Draft202012 is JSON Schema 2020-12, the dialect of OpenAPI 3.1.
+	Draft202012 Dialect = "https://json-schema.org/draft/2020-12/schema"
+)
+
+const definitionPrefix = "#/definitions/"
+
+// ToJSONSchema converts a schema to a JSON Schema document of the given
+// dialect. References to "#/definitions/..." are kept, pointing to the
+// "$defs" of the document for 2020-12; use Bundle to include the
+// definitions a schema references.
+func ToJSONSchema(schema *spec.Schema, dialect Dialect) ([]byte, error) {
+	if dialect != Draft07 && dialect != Draft202012 {
+		return nil, fmt.Errorf("unsupported dialect %q", dialect)
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var v map[string]interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	delete(v, "$schema")
+	c := converter{dialect: dialect}
+	ret := map[string]interface{}{"$schema": string(dialect)}
+	for k, value := range c.schema(v) {
+		ret[k] = value
+	}
+	return json.Marshal(ret)
+}
+
+// Bundle returns a copy of the definition with the given name in which
+// the definitions it references, directly or not, are embedded, so that
+// the result of ToJSONSchema is self-contained.
+func Bundle(sp *spec.Swagger, name string) (*spec.Schema, error) {
+	def, ok := sp.Definitions[name]
+	if !ok {
+		return nil, fmt.Errorf("no definition %q", name)
+	}
+	ret := def.DeepCopy()
+	ret.Definitions = spec.Definitions{}
+	queue := []*spec.Schema{&def}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for _, ref := range references(s) {
+			if !strings.HasPrefix(ref, definitionPrefix) {
+				continue
+			}
+			dep := strings.SplitN(ref[len(definitionPrefix):], "/", 2)[0]
+			if _, seen := ret.Definitions[dep]; seen {
+				continue
+			}
+			depDef, ok := sp.Definitions[dep]
+			if !ok {
+				return nil, fmt.Errorf("definition %q references missing definition %q", name, dep)
+			}
+			ret.Definitions[dep] = *depDef.DeepCopy()
+			queue = append(queue, &depDef)
+		}
+	}
+	return ret, nil
+}
+
+// references returns the references found in a schema.
+func references(s *spec.Schema) []string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil
+	}
+	var ret []string
+	var collect func(v interface{})
+	collect = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				if ref, ok := child.(string); ok && k == "$ref" {
+					ret = append(ret, ref)
+					continue
+				}
+				collect(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				collect(child)
+			}
+		}
+	}
+	collect(v)
+	return ret
+}
+
+type converter struct {
+	dialect Dialect
+}
+
+// schema converts a decoded schema in place and returns it.
+func (c *converter) schema(s map[string]interface{}) map[string]interface{} {
+	// Subschemas first, so that wrapping a nullable schema doesn't convert
+	// it twice.
+	for _, k := range []string{"properties", "patternProperties", "definitions", "dependencies"} {
+		if m, ok := s[k].(map[string]interface{}); ok {
+			for name, sub := range m {
+				if sub, ok := sub.(map[string]interface{}); ok {
+					m[name] = c.schema(sub)
+				}
+			}
+		}
+	}
+	for _, k := range []string{"additionalProperties", "additionalItems", "not", "items"} {
+		if sub, ok := s[k].(map[string]interface{}); ok {
+			s[k] = c.schema(sub)
+		}
+	}
+	for _, k := range []string{"allOf", "anyOf", "oneOf", "items"} {
+		if l, ok := s[k].([]interface{}); ok {
+			for i, sub := range l {
+				if sub, ok := sub.(map[string]interface{}); ok {
+					l[i] = c.schema(sub)
+				}
+			}
+		}
+	}
+
+	if id, ok := s["id"]; ok {
+		delete(s, "id")
+		s["$id"] = id
+	}
+	if example, ok := s["example"]; ok {
+		delete(s, "example")
+		s["examples"] = []interface{}{example}
+	}
+	for _, bound := range []string{"Maximum", "Minimum"} {
+		exclusive, limit := "exclusive"+bound, strings.ToLower(bound)
+		if b, ok := s[exclusive].(bool); ok {
+			delete(s, exclusive)
+			if value, ok := s[limit]; ok && b {
+				delete(s, limit)
+				s[exclusive] = value
+			}
+		}
+	}
+	if ref, ok := s["$ref"].(string); ok && c.dialect == Draft202012 && strings.HasPrefix(ref, definitionPrefix) {
+		s["$ref"] = "#/$defs/" + ref[len(definitionPrefix):]
+	}
+	if c.dialect == Draft202012 {
+		if defs, ok := s["definitions"]; ok {
+			delete(s, "definitions")
+			s["$defs"] = defs
+		}
+		if items, ok := s["items"].([]interface{}); ok {
+			delete(s, "items")
+			s["prefixItems"] = items
+			if additional, ok := s["additionalItems"]; ok {
+				delete(s, "additionalItems")
+				s["items"] = additional
+			}
+		}
+	}
+
+	types := typeList(s["type"])
+	for i, t := range types {
+		if t == "file" {
+			types[i] = "string"
+			s["contentMediaType"] = "application/octet-stream"
+		}
+	}
+	if len(types) > 0 {
+		s["type"] = typeValue(types)
+	}
+
+	nullable, _ := s["nullable"].(bool)
+	if xNullable, ok := s["x-nullable"].(bool); ok && xNullable {
+		nullable = true
+	}
+	delete(s, "nullable")
+	delete(s, "x-nullable")
+	if !nullable {
+		return s
+	}
+	if _, isRef := s["$ref"]; isRef || len(types) == 0 {
+		// $ref siblings are ignored by draft 7, and schemas without type
+		// can't be made nullable by adding one.
+		ret := map[string]interface{}{"anyOf": []interface{}{s, map[string]interface{}{"type": "null"}}}
+		// Keep the identity and definitions of the schema where references
+		// expect them.
+		for _, k := range []string{"$id", "definitions", "$defs"} {
+			if v, ok := s[k]; ok {
+				delete(s, k)
+				ret[k] = v
+			}
+		}
+		return ret
+	}
+	if !containsString(types, "null") {
+		s["type"] = append(types, "null")
+	}
+	if enum, ok := s["enum"].([]interface{}); ok {
+		s["enum"] = append(enum, nil)
+	}
+	return s
+}
+
+func typeList(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		ret := make([]string, 0, len(v))
+		for _, t := range v {
+			if t, ok := t.(string); ok {
+				ret = append(ret, t)
+			}
+		}
+		return ret
+	}
+	return nil
+}
+
+func typeValue(types []string) interface{} {
+	if len(types) == 1 {
+		return types[0]
+	}
+	return types
+}
+
+func containsString(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
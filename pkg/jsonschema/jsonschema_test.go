@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func mustSchema(t *testing.T, s string) *spec.Schema {
+	var ret spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(s), &ret))
+	return &ret
+}
+
+const petSchema = `{
+  "id": "https://example.com/pet",
+  "type": "object",
+  "required": ["name"],
+  "example": {"name": "rex"},
+  "properties": {
+    "name": {"type": "string", "x-nullable": true, "enum": ["rex", "max"]},
+    "age": {"type": "integer", "maximum": 30, "exclusiveMaximum": true, "minimum": 0},
+    "photo": {"type": "file"},
+    "owner": {"$ref": "#/definitions/Owner", "nullable": true},
+    "point": {"type": "array", "items": [{"type": "number"}, {"type": "number"}], "additionalItems": false},
+    "any": {"nullable": true}
+  },
+  "definitions": {"Owner": {"type": "string", "x-kubernetes-int-or-string": true}}
+}`
+
+func TestToJSONSchemaDraft07(t *testing.T) {
+	data, err := ToJSONSchema(mustSchema(t, petSchema), Draft07)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+	  "$schema": "http://json-schema.org/draft-07/schema#",
+	  "$id": "https://example.com/pet",
+	  "type": "object",
+	  "required": ["name"],
+	  "examples": [{"name": "rex"}],
+	  "properties": {
+	    "name": {"type": ["string", "null"], "enum": ["rex", "max", null]},
+	    "age": {"type": "integer", "exclusiveMaximum": 30, "minimum": 0},
+	    "photo": {"type": "string", "contentMediaType": "application/octet-stream"},
+	    "owner": {"anyOf": [{"$ref": "#/definitions/Owner"}, {"type": "null"}]},
+	    "point": {"type": "array", "items": [{"type": "number"}, {"type": "number"}], "additionalItems": false},
+	    "any": {"anyOf": [{}, {"type": "null"}]}
+	  },
+	  "definitions": {"Owner": {"type": "string", "x-kubernetes-int-or-string": true}}
+	}`, string(data))
+}
+
+func TestToJSONSchema202012(t *testing.T) {
+	data, err := ToJSONSchema(mustSchema(t, petSchema), Draft202012)
+	require.NoError(t, err)
+	var v map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &v))
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", v["$schema"])
+	assert.Contains(t, v, "$defs")
+	assert.NotContains(t, v, "definitions")
+	props := v["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"anyOf": []interface{}{
+		map[string]interface{}{"$ref": "#/$defs/Owner"},
+		map[string]interface{}{"type": "null"},
+	}}, props["owner"])
+	assert.Equal(t, map[string]interface{}{
+		"type":        "array",
+		"prefixItems": []interface{}{map[string]interface{}{"type": "number"}, map[string]interface{}{"type": "number"}},
+		"items":       false,
+	}, props["point"])
+
+	// Nullable roots keep their definitions at the root.
+	data, err = ToJSONSchema(mustSchema(t, `{"$ref": "#/definitions/A", "x-nullable": true, "definitions": {"A": {"type": "string"}}}`), Draft202012)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+	  "$schema": "https://json-schema.org/draft/2020-12/schema",
+	  "anyOf": [{"$ref": "#/$defs/A"}, {"type": "null"}],
+	  "$defs": {"A": {"type": "string"}}
+	}`, string(data))
+
+	_, err = ToJSONSchema(&spec.Schema{}, Dialect("draft-04"))
+	assert.Error(t, err)
+}
+
+func TestBundle(t *testing.T) {
+	sp := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: spec.Definitions{
+		"Pet":    *mustSchema(t, `{"type": "object", "properties": {"owner": {"$ref": "#/definitions/Owner"}, "tag": {"$ref": "#/definitions/Tag/properties/name"}}}`),
+		"Owner":  *mustSchema(t, `{"type": "object", "properties": {"pets": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}}`),
+		"Tag":    *mustSchema(t, `{"type": "object", "properties": {"name": {"type": "string"}}}`),
+		"Unused": *mustSchema(t, `{"type": "string"}`),
+		"Broken": *mustSchema(t, `{"$ref": "#/definitions/Missing"}`),
+	}}}
+	pet, err := Bundle(sp, "Pet")
+	require.NoError(t, err)
+	var names []string
+	for name := range pet.Definitions {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"Pet", "Owner", "Tag"}, names)
+	assert.Empty(t, sp.Definitions["Pet"].Definitions, "the spec is not modified")
+
+	_, err = Bundle(sp, "Broken")
+	assert.Error(t, err)
+	_, err = Bundle(sp, "Nope")
+	assert.Error(t, err)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Bundle returns the root spec with the files it references merged in, for
+// tools that only read single file specs. Path items, parameters and
+// responses of other files are inlined where they are referenced. Schemas
+// of other files, which may be recursive, become definitions named after
+// the last element of their JSON pointer, or after their file, suffixed
+// with "_2", "_3" and so on when the name is taken.
+func (d *Document) Bundle() (*spec.Swagger, error) {
+	root, ok := spec.DeepCopyJSONValue(d.files[d.Root]).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is not an object", d.Root)
+	}
+	b := &bundler{d: d, names: map[string]string{}, defs: map[string]interface{}{}, taken: map[string]bool{}}
+	defs, _ := root["definitions"].(map[string]interface{})
+	for name := range defs {
+		b.taken[name] = true
+	}
+	if err := b.walk(root, d.Root, ""); err != nil {
+		return nil, err
+	}
+	if len(b.defs) > 0 {
+		if defs == nil {
+			defs = map[string]interface{}{}
+			root["definitions"] = defs
+		}
+		for name, def := range b.defs {
+			defs[name] = def
+		}
+	}
+	data, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	return parseSwagger(d.Root, data)
+}
+
+type bundler struct {
+	d *Document
+	// names maps the locations of the bundled schemas, as "file#pointer",
+	// to their definition name.
+	names map[string]string
+	defs  map[string]interface{}
+	taken map[string]bool
+}
+
+// walk rewrites the references of v, a value of file at pointer in the
+// bundled document.
+func (b *bundler) walk(v interface{}, file, pointer string) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			inlined, err := b.ref(v, ref, file, pointer)
+			if err != nil || inlined {
+				return err
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := b.walk(v[k], file, pointer+"/"+jsonpointer.Escape(k)); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			if err := b.walk(child, file, fmt.Sprintf("%s/%d", pointer, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ref rewrites the reference of obj and returns whether obj was replaced by
+// the referenced value, which is walked then.
+func (b *bundler) ref(obj map[string]interface{}, ref, file, pointer string) (bool, error) {
+	target, fragment, err := location(ref, file)
+	if err != nil {
+		return false, fmt.Errorf("invalid reference %q in %s: %v", ref, file, err)
+	}
+	if target == b.d.Root {
+		obj["$ref"] = "#" + fragment
+		return false, nil
+	}
+	if !schemaLocation(pointer) {
+		v, err := b.d.value(target, fragment)
+		if err != nil {
+			return false, fmt.Errorf("%s: %v", file, err)
+		}
+		inlined, ok := spec.DeepCopyJSONValue(v).(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("%s: reference %q is not to an object", file, ref)
+		}
+		delete(obj, "$ref")
+		for k, child := range inlined {
+			obj[k] = child
+		}
+		return true, b.walk(obj, target, pointer)
+	}
+	name, err := b.definition(target, fragment)
+	if err != nil {
+		return false, fmt.Errorf("%s: %v", file, err)
+	}
+	obj["$ref"] = "#/definitions/" + jsonpointer.Escape(name)
+	return false, nil
+}
+
+// definition returns the name of the definition holding the schema of file
+// at fragment, adding it on first use.
+func (b *bundler) definition(file, fragment string) (string, error) {
+	key := file + "#" + fragment
+	if name, ok := b.names[key]; ok {
+		return name, nil
+	}
+	v, err := b.d.value(file, fragment)
+	if err != nil {
+		return "", err
+	}
+	base := fragment[strings.LastIndex(fragment, "/")+1:]
+	if base == "" {
+		base = strings.TrimSuffix(path.Base(file), path.Ext(file))
+	} else {
+		base = jsonpointer.Unescape(base)
+	}
+	name := base
+	for i := 2; b.taken[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	b.taken[name] = true
+	b.names[key] = name
+	def := spec.DeepCopyJSONValue(v)
+	b.defs[name] = def
+	// The name is registered first so that recursive schemas terminate.
+	return name, b.walk(def, file, "/definitions/"+jsonpointer.Escape(name))
+}
+
+// value returns the decoded JSON value of file at a JSON pointer.
+func (d *Document) value(file, pointer string) (interface{}, error) {
+	ref, err := spec.NewRef("#" + pointer)
+	if err != nil {
+		return nil, err
+	}
+	v, _, err := d.resolve(ref, file)
+	return v, err
+}
+
+// schemaLocation returns whether pointer is the location of a schema in a
+// spec, rather than of a path item, a parameter or a response.
+func schemaLocation(pointer string) bool {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	switch {
+	case len(tokens) == 2 && (tokens[0] == "parameters" || tokens[0] == "responses" || tokens[0] == "paths"):
+		return false
+	case len(tokens) == 4 && tokens[0] == "paths" && tokens[2] == "parameters":
+		return false
+	case len(tokens) == 5 && tokens[0] == "paths" && (tokens[3] == "parameters" || tokens[3] == "responses"):
+		return false
+	}
+	return true
+}
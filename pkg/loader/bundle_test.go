@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestBundle(t *testing.T) {
+	doc, err := Load(testFS, "api/swagger.yaml")
+	require.NoError(t, err)
+	sw, err := doc.Bundle()
+	require.NoError(t, err)
+	resp := sw.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200]
+	assert.Equal(t, "#/definitions/Pet", resp.Schema.Ref.String())
+	owner := sw.Definitions["Pet"].Properties["owner"]
+	assert.Equal(t, "#/definitions/Owner", owner.Ref.String())
+	assert.Equal(t, "the owner", sw.Definitions["Owner"].Description)
+	assert.Contains(t, sw.Definitions, "Local")
+
+	// The document is not modified.
+	orig := doc.Spec.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200]
+	assert.Equal(t, "models/pet.yaml#/Pet", orig.Schema.Ref.String())
+}
+
+func TestBundleInlines(t *testing.T) {
+	fsys := fstest.MapFS{
+		"swagger.yaml": {Data: []byte(`
+swagger: "2.0"
+paths:
+  /pets:
+    $ref: "paths/pets.yaml"
+definitions:
+  Pet:
+    type: string
+`)},
+		"paths/pets.yaml": {Data: []byte(`
+get:
+  parameters:
+  - $ref: "../common.yaml#/parameters/limit"
+  responses:
+    200:
+      $ref: "../common.yaml#/responses/Pets"
+`)},
+		"common.yaml": {Data: []byte(`
+parameters:
+  limit:
+    name: limit
+    in: query
+    type: integer
+responses:
+  Pets:
+    description: the pets
+    schema:
+      type: array
+      items:
+        $ref: "#/definitions/Pet"
+definitions:
+  Pet:
+    type: object
+    properties:
+      parent:
+        $ref: "#/definitions/Pet"
+      local:
+        $ref: "swagger.yaml#/definitions/Pet"
+`)},
+	}
+	doc, err := Load(fsys, "swagger.yaml")
+	require.NoError(t, err)
+	sw, err := doc.Bundle()
+	require.NoError(t, err)
+
+	get := sw.Paths.Paths["/pets"].Get
+	require.NotNil(t, get)
+	require.Len(t, get.Parameters, 1)
+	assert.Equal(t, "limit", get.Parameters[0].Name)
+	resp := get.Responses.StatusCodeResponses[200]
+	assert.Equal(t, "the pets", resp.Description)
+	assert.Equal(t, "#/definitions/Pet_2", resp.Schema.Items.Schema.Ref.String())
+
+	// The bundled Pet is renamed since the root has a Pet already, and is
+	// recursive.
+	pet := sw.Definitions["Pet_2"]
+	assert.Equal(t, spec.StringOrArray{"object"}, pet.Type)
+	parent, local := pet.Properties["parent"], pet.Properties["local"]
+	assert.Equal(t, "#/definitions/Pet_2", parent.Ref.String())
+	assert.Equal(t, "#/definitions/Pet", local.Ref.String())
+	assert.Equal(t, spec.StringOrArray{"string"}, sw.Definitions["Pet"].Type)
+	assert.Len(t, sw.Definitions, 2)
+}
@@ -0,0 +1,22 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package loader reads OpenAPI specs, including specs split over several
+// files that reference each other through $ref, from an fs.FS. Any file
+// system works, so binaries can ship their contracts in an embed.FS and
+// resolve references without touching the real file system. Bundle merges
+// the files of a document into a single spec.
+package loader
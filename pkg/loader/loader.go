@@ -0,0 +1,290 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/kube-openapi/pkg/logging"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Document is an OpenAPI spec loaded from a file system together with every
+// file it references, directly or transitively, through relative $refs.
+type Document struct {
+	// Root is the cleaned path of the root document in the file system.
+	Root string
+	// Spec is the parsed root document. References are kept as they are.
+	Spec *spec.Swagger
+	// Logger, if set, receives the resolution of every reference.
+	Logger logging.Logger
+
+	// files holds the decoded JSON of every loaded file keyed by its path.
+	files map[string]interface{}
+}
+
+// LoadSwagger reads and parses a single JSON or YAML spec file from fsys.
+// External references are not followed, use Load for that.
+func LoadSwagger(fsys fs.FS, name string) (*spec.Swagger, error) {
+	data, err := readJSON(fsys, path.Clean(name))
+	if err != nil {
+		return nil, err
+	}
+	return parseSwagger(name, data)
+}
+
+// Load reads the root spec file name from fsys and every file reachable from
+// it through relative $refs. References to remote URLs are rejected since
+// they can't be served from fsys, and so are files outside of its root.
+func Load(fsys fs.FS, name string) (*Document, error) {
+	d := &Document{
+		Root:  path.Clean(name),
+		files: map[string]interface{}{},
+	}
+	rootData, err := readJSON(fsys, d.Root)
+	if err != nil {
+		return nil, err
+	}
+	if d.Spec, err = parseSwagger(d.Root, rootData); err != nil {
+		return nil, err
+	}
+
+	queue := []string{d.Root}
+	raw := map[string][]byte{d.Root: rootData}
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+
+		data, ok := raw[file]
+		if !ok {
+			if data, err = readJSON(fsys, file); err != nil {
+				return nil, err
+			}
+		}
+		var doc interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", file, err)
+		}
+		d.files[file] = doc
+
+		var refErr error
+		walkRefs(doc, func(ref string) {
+			if refErr != nil {
+				return
+			}
+			target, _, err := location(ref, file)
+			if err != nil {
+				refErr = fmt.Errorf("invalid reference %q in %s: %v", ref, file, err)
+				return
+			}
+			if _, loaded := d.files[target]; loaded || contains(queue, target) {
+				return
+			}
+			queue = append(queue, target)
+		})
+		if refErr != nil {
+			return nil, refErr
+		}
+	}
+	return d, nil
+}
+
+// Files returns the sorted paths of all files loaded into the document.
+func (d *Document) Files() []string {
+	files := make([]string, 0, len(d.files))
+	for f := range d.files {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// CheckRefs resolves every reference of every loaded file and returns the
+// errors of the ones that can't be, e.g. pointing to a missing definition,
+// sorted by file and reference.
+func (d *Document) CheckRefs() []error {
+	var errs []error
+	for _, file := range d.Files() {
+		seen := map[string]bool{}
+		var refs []string
+		walkRefs(d.files[file], func(ref string) {
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		})
+		sort.Strings(refs)
+		for _, ref := range refs {
+			r, err := spec.NewRef(ref)
+			if err == nil {
+				_, _, err = d.resolve(r, file)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", file, err))
+			}
+		}
+	}
+	return errs
+}
+
+// Location returns the file and JSON pointer a reference points to. Relative
+// references are resolved against base, the path of the file the reference
+// appears in. An empty base stands for the root document.
+func (d *Document) Location(ref spec.Ref, base string) (file, pointer string, err error) {
+	if base == "" {
+		base = d.Root
+	}
+	return location(ref.String(), path.Clean(base))
+}
+
+// Resolve returns the decoded JSON value a reference points to. See Location
+// for how base is used.
+func (d *Document) Resolve(ref spec.Ref, base string) (interface{}, error) {
+	v, file, err := d.resolve(ref, base)
+	if d.Logger != nil {
+		if err != nil {
+			d.Logger.Debugf("failed to resolve reference", "ref", ref.String(), "base", base, "error", err)
+		} else {
+			d.Logger.Debugf("resolved reference", "ref", ref.String(), "base", base, "file", file)
+		}
+	}
+	return v, err
+}
+
+func (d *Document) resolve(ref spec.Ref, base string) (interface{}, string, error) {
+	file, pointer, err := d.Location(ref, base)
+	if err != nil {
+		return nil, "", err
+	}
+	doc, ok := d.files[file]
+	if !ok {
+		return nil, "", fmt.Errorf("reference %q points to file %s which is not loaded", ref.String(), file)
+	}
+	p, err := jsonpointer.New(pointer)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid reference %q: %v", ref.String(), err)
+	}
+	v, _, err := p.Get(doc)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to resolve reference %q: %v", ref.String(), err)
+	}
+	return v, file, nil
+}
+
+// ResolveInto resolves a reference like Resolve and decodes the target into
+// v, e.g. a *spec.Schema or a *spec.Parameter.
+func (d *Document) ResolveInto(ref spec.Ref, base string, v interface{}) error {
+	target, err := d.Resolve(ref, base)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// ResolveSchema resolves a reference to a schema.
+func (d *Document) ResolveSchema(ref spec.Ref, base string) (*spec.Schema, error) {
+	s := &spec.Schema{}
+	if err := d.ResolveInto(ref, base, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// location splits ref into the file it points to, resolved against base,
+// and the JSON pointer within that file.
+func location(ref, base string) (string, string, error) {
+	r, err := spec.NewRef(ref)
+	if err != nil {
+		return "", "", err
+	}
+	if r.HasFullURL {
+		return "", "", fmt.Errorf("remote references are not supported")
+	}
+	u := r.GetURL()
+	if u.Path == "" {
+		return base, u.Fragment, nil
+	}
+	file := u.Path
+	if strings.HasPrefix(file, "/") {
+		file = strings.TrimPrefix(path.Clean(file), "/")
+	} else {
+		file = path.Join(path.Dir(base), file)
+	}
+	if !fs.ValidPath(file) {
+		return "", "", fmt.Errorf("%s is outside of the file system root", u.Path)
+	}
+	return file, u.Fragment, nil
+}
+
+// walkRefs calls fn on the value of every $ref found in a decoded JSON value.
+func walkRefs(v interface{}, fn func(ref string)) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			fn(ref)
+		}
+		for _, child := range v {
+			walkRefs(child, fn)
+		}
+	case []interface{}:
+		for _, child := range v {
+			walkRefs(child, fn)
+		}
+	}
+}
+
+func readJSON(fsys fs.FS, name string) ([]byte, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	// YAML is a superset of JSON, so this handles both formats.
+	data, err = yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", name, err)
+	}
+	return data, nil
+}
+
+func parseSwagger(name string, data []byte) (*spec.Swagger, error) {
+	sw := &spec.Swagger{}
+	if err := json.Unmarshal(data, sw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", name, err)
+	}
+	return sw, nil
+}
+
+func contains(l []string, s string) bool {
+	for _, x := range l {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/logging"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var testFS = fstest.MapFS{
+	"api/swagger.yaml": {Data: []byte(`
+swagger: "2.0"
+info:
+  title: pets
+  version: "1.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        200:
+          description: OK
+          schema:
+            $ref: "models/pet.yaml#/Pet"
+definitions:
+  Local:
+    type: string
+`)},
+	"api/models/pet.yaml": {Data: []byte(`
+Pet:
+  type: object
+  properties:
+    name:
+      type: string
+    owner:
+      $ref: "../common.json#/definitions/Owner"
+`)},
+	"api/common.json": {Data: []byte(`{"definitions": {"Owner": {"type": "string", "description": "the owner"}}}`)},
+	"remote.yaml": {Data: []byte(`
+swagger: "2.0"
+paths: {}
+definitions:
+  R:
+    $ref: "https://example.com/schemas.json#/R"
+`)},
+	"broken.yaml": {Data: []byte(`
+swagger: "2.0"
+paths: {}
+definitions:
+  A:
+    $ref: "#/definitions/Missing"
+  B:
+    $ref: "api/common.json#/definitions/Nope"
+  C:
+    $ref: "#/definitions/Missing"
+`)},
+	"escape.yaml": {Data: []byte(`
+swagger: "2.0"
+paths: {}
+definitions:
+  E:
+    $ref: "../outside.json"
+`)},
+}
+
+func TestLoadSwagger(t *testing.T) {
+	sw, err := LoadSwagger(testFS, "api/swagger.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "pets", sw.Info.Title)
+	assert.Equal(t, "listPets", sw.Paths.Paths["/pets"].Get.ID)
+	assert.Contains(t, sw.Definitions, "Local")
+
+	_, err = LoadSwagger(testFS, "missing.yaml")
+	assert.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	doc, err := Load(testFS, "api/swagger.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "api/swagger.yaml", doc.Root)
+	assert.Equal(t, []string{"api/common.json", "api/models/pet.yaml", "api/swagger.yaml"}, doc.Files())
+
+	resp := doc.Spec.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200]
+	pet, err := doc.ResolveSchema(resp.Schema.Ref, "")
+	require.NoError(t, err)
+	assert.Equal(t, spec.StringOrArray{"object"}, pet.Type)
+
+	// Refs inside a referenced file are relative to that file.
+	owner := pet.Properties["owner"]
+	file, pointer, err := doc.Location(owner.Ref, "api/models/pet.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "api/common.json", file)
+	assert.Equal(t, "/definitions/Owner", pointer)
+	ownerSchema, err := doc.ResolveSchema(owner.Ref, "api/models/pet.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "the owner", ownerSchema.Description)
+
+	local, err := doc.ResolveSchema(spec.MustCreateRef("#/definitions/Local"), "")
+	require.NoError(t, err)
+	assert.Equal(t, spec.StringOrArray{"string"}, local.Type)
+
+	_, err = doc.Resolve(spec.MustCreateRef("#/definitions/Missing"), "")
+	assert.Error(t, err)
+}
+
+func TestResolveLogs(t *testing.T) {
+	doc, err := Load(testFS, "api/swagger.yaml")
+	require.NoError(t, err)
+	var lines []string
+	doc.Logger = logging.Func(func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})
+	_, err = doc.Resolve(spec.MustCreateRef("../common.json#/definitions/Owner"), "api/models/pet.yaml")
+	require.NoError(t, err)
+	_, err = doc.Resolve(spec.MustCreateRef("#/definitions/Missing"), "")
+	require.Error(t, err)
+	if assert.Len(t, lines, 2) {
+		assert.Equal(t, `DEBUG resolved reference ref="../common.json#/definitions/Owner" base="api/models/pet.yaml" file="api/common.json"`, lines[0])
+		assert.Contains(t, lines[1], `DEBUG failed to resolve reference ref="#/definitions/Missing"`)
+	}
+}
+
+func TestLoadRejectsUnreachableRefs(t *testing.T) {
+	_, err := Load(testFS, "remote.yaml")
+	assert.Error(t, err)
+	_, err = Load(testFS, "escape.yaml")
+	assert.Error(t, err)
+}
+
+func TestCheckRefs(t *testing.T) {
+	doc, err := Load(testFS, "api/swagger.yaml")
+	require.NoError(t, err)
+	assert.Empty(t, doc.CheckRefs())
+
+	doc, err = Load(testFS, "broken.yaml")
+	require.NoError(t, err)
+	errs := doc.CheckRefs()
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), `broken.yaml: unable to resolve reference "#/definitions/Missing"`)
+	assert.Contains(t, errs[1].Error(), `broken.yaml: unable to resolve reference "api/common.json#/definitions/Nope"`)
+}
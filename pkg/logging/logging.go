@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logging defines the logger the spec loader, the validator and the
+// serving handlers report to, so integrators can follow spec resolution,
+// content negotiation and validation failures through their own logging
+// library.
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Logger receives structured log entries. msg is a constant message and
+// keysAndValues alternate keys and values, e.g.
+//
+//	l.Debugf("resolved reference", "ref", "#/definitions/Pet", "file", "pets.yaml")
+//
+// Implementations must be safe for concurrent use.
+type Logger interface {
+	// Debugf reports details useful when troubleshooting.
+	Debugf(msg string, keysAndValues ...interface{})
+	// Warnf reports unexpected situations that don't prevent progress.
+	Warnf(msg string, keysAndValues ...interface{})
+}
+
+// Discard is a Logger that drops every entry.
+var Discard Logger = discard{}
+
+type discard struct{}
+
+func (discard) Debugf(string, ...interface{}) {}
+func (discard) Warnf(string, ...interface{})  {}
+
+// Func adapts a printf-like function, e.g. log.Printf, to a Logger. Entries
+// are rendered as "LEVEL msg key=value ...".
+type Func func(format string, args ...interface{})
+
+// Debugf implements Logger.
+func (f Func) Debugf(msg string, keysAndValues ...interface{}) {
+	f("%s", Format("DEBUG", msg, keysAndValues...))
+}
+
+// Warnf implements Logger.
+func (f Func) Warnf(msg string, keysAndValues ...interface{}) {
+	f("%s", Format("WARN", msg, keysAndValues...))
+}
+
+// Format renders a log entry as "level msg key=value ...". A trailing key
+// without a value is rendered with the value "(missing)".
+func Format(level, msg string, keysAndValues ...interface{}) string {
+	var b strings.Builder
+	b.WriteString(level)
+	b.WriteString(" ")
+	b.WriteString(msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		var v interface{} = "(missing)"
+		if i+1 < len(keysAndValues) {
+			v = keysAndValues[i+1]
+		}
+		fmt.Fprintf(&b, " %v=%q", keysAndValues[i], fmt.Sprint(v))
+	}
+	return b.String()
+}
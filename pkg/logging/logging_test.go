@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, `DEBUG resolved reference ref="#/definitions/Pet" line="3"`, Format("DEBUG", "resolved reference", "ref", "#/definitions/Pet", "line", 3))
+	assert.Equal(t, `WARN odd key="(missing)"`, Format("WARN", "odd", "key"))
+	assert.Equal(t, "WARN plain", Format("WARN", "plain"))
+}
+
+func TestFunc(t *testing.T) {
+	var lines []string
+	var l Logger = Func(func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})
+	l.Debugf("negotiated", "mediaType", "application/json")
+	l.Warnf("not acceptable")
+	Discard.Warnf("dropped")
+	assert.Equal(t, []string{`DEBUG negotiated mediaType="application/json"`, "WARN not acceptable"}, lines)
+}
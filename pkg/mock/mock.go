@@ -0,0 +1,407 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mock serves the operations of a spec without implementing them,
+// e.g. for frontend development against an API still being built. Requests
+// are routed to the operations of the spec and validated against their
+// parameters, and answered with the first success response of the
+// operation: its example for the negotiated media type if it has one, else
+// the example of its schema, else data generated from the schema.
+package mock
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/kube-openapi/pkg/analyzer"
+	"k8s.io/kube-openapi/pkg/fuzz"
+	"k8s.io/kube-openapi/pkg/negotiation"
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+const definitionPrefix = "#/definitions/"
+
+// maxMemory bounds the memory used to parse multipart forms, the rest going
+// to temporary files.
+const maxMemory = 32 << 20
+
+// Options configures a Handler.
+type Options struct {
+	// Seed seeds the generated data, so that runs can be reproduced.
+	Seed int64
+	// SkipValidation answers requests not conforming to the spec as the
+	// others instead of rejecting them with a 400.
+	SkipValidation bool
+	// CORS allows requests from any origin, answering preflight requests.
+	CORS bool
+}
+
+// Handler serves mock responses for the operations of a spec. It is safe
+// for concurrent use.
+type Handler struct {
+	spec    *spec.Swagger
+	opts    Options
+	routes  []route
+	resolve func(ref string) *spec.Schema
+
+	// mu guards gen, whose random source isn't safe for concurrent use.
+	mu  sync.Mutex
+	gen *fuzz.Generator
+}
+
+// route matches the requests to an operation.
+type route struct {
+	op *analyzer.Operation
+	re *regexp.Regexp
+	// params are the names of the path parameters, by submatch.
+	params []string
+}
+
+var pathParamRegexp = regexp.MustCompile(`{([^{}]+)}`)
+
+// New returns a handler of the operations of s. The spec must not be
+// modified afterwards.
+func New(s *spec.Swagger, opts Options) (*Handler, error) {
+	a, err := analyzer.New(s)
+	if err != nil {
+		return nil, err
+	}
+	h := &Handler{
+		spec: s,
+		opts: opts,
+		gen:  fuzz.NewGenerator(s, opts.Seed),
+		resolve: func(ref string) *spec.Schema {
+			if !strings.HasPrefix(ref, definitionPrefix) {
+				return nil
+			}
+			def, ok := s.Definitions[ref[len(definitionPrefix):]]
+			if !ok {
+				return nil
+			}
+			return &def
+		},
+	}
+	for _, op := range a.Operations() {
+		h.routes = append(h.routes, newRoute(op))
+	}
+	// Templates with fewer parameters are more specific: /pets/mine is
+	// matched before /pets/{id}.
+	sort.SliceStable(h.routes, func(i, j int) bool {
+		return len(h.routes[i].params) < len(h.routes[j].params)
+	})
+	return h, nil
+}
+
+func newRoute(op *analyzer.Operation) route {
+	r := route{op: op}
+	pattern := "^"
+	last := 0
+	for _, m := range pathParamRegexp.FindAllStringSubmatchIndex(op.Path, -1) {
+		pattern += regexp.QuoteMeta(op.Path[last:m[0]]) + "([^/]+)"
+		r.params = append(r.params, op.Path[m[2]:m[3]])
+		last = m[1]
+	}
+	r.re = regexp.MustCompile(pattern + regexp.QuoteMeta(op.Path[last:]) + "$")
+	return r
+}
+
+// match returns the operation of a request with its path parameters, or
+// the methods allowed on the path when no operation has the method of the
+// request.
+func (h *Handler) match(r *http.Request) (*analyzer.Operation, map[string]string, []string) {
+	path := r.URL.EscapedPath()
+	if base := strings.TrimSuffix(h.spec.BasePath, "/"); base != "" {
+		if !strings.HasPrefix(path, base+"/") {
+			return nil, nil, nil
+		}
+		path = path[len(base):]
+	}
+	var allowed []string
+	for _, rt := range h.routes {
+		m := rt.re.FindStringSubmatch(path)
+		if m == nil {
+			continue
+		}
+		if rt.op.Method != r.Method {
+			allowed = append(allowed, rt.op.Method)
+			continue
+		}
+		params := make(map[string]string, len(rt.params))
+		for i, name := range rt.params {
+			v, err := url.PathUnescape(m[i+1])
+			if err != nil {
+				continue
+			}
+			params[name] = v
+		}
+		return rt.op, params, nil
+	}
+	sort.Strings(allowed)
+	return nil, nil, allowed
+}
+
+// ServeHTTP answers a request with the mock response of its operation.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.opts.CORS && h.cors(w, r) {
+		return
+	}
+	op, params, allowed := h.match(r)
+	if op == nil {
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeError(w, http.StatusMethodNotAllowed)
+			return
+		}
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	if !h.opts.SkipValidation {
+		if res := h.validate(op, params, r); !res.IsValid() {
+			writeError(w, http.StatusBadRequest, res.Errors...)
+			return
+		}
+	}
+	h.respond(w, r, op)
+}
+
+// cors sets the CORS headers of a response, and answers preflight requests,
+// returning true for these.
+func (h *Handler) cors(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Add("Vary", "Origin")
+	method := r.Header.Get("Access-Control-Request-Method")
+	if r.Method != http.MethodOptions || method == "" {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Methods", method)
+	if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+		w.Header().Set("Access-Control-Allow-Headers", headers)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// validate checks a request against the parameters of its operation.
+func (h *Handler) validate(op *analyzer.Operation, params map[string]string, r *http.Request) *validate.Result {
+	res := new(validate.Result)
+	for i := range op.Parameters {
+		p := &op.Parameters[i]
+		switch p.In {
+		case "body":
+			res.Merge(h.validateBody(p, r))
+		case "path":
+			res.Merge(validate.Parameter(p, []string{params[p.Name]}))
+		case "query":
+			res.Merge(validate.Parameter(p, r.URL.Query()[p.Name]))
+		case "header":
+			values := r.Header[textproto.CanonicalMIMEHeaderKey(p.Name)]
+			if len(values) > 1 && p.Type == "array" && p.CollectionFormat != "multi" {
+				values = []string{strings.Join(values, ",")}
+			}
+			res.Merge(validate.Parameter(p, values))
+		case "formData":
+			res.Merge(validate.Parameter(p, formValues(r, p)))
+		}
+	}
+	return res
+}
+
+func formValues(r *http.Request, p *spec.Parameter) []string {
+	if err := r.ParseMultipartForm(maxMemory); err != nil && err != http.ErrNotMultipart {
+		return nil
+	}
+	if p.Type == "file" {
+		if r.MultipartForm == nil {
+			return nil
+		}
+		var names []string
+		for _, f := range r.MultipartForm.File[p.Name] {
+			names = append(names, f.Filename)
+		}
+		return names
+	}
+	return r.PostForm[p.Name]
+}
+
+func (h *Handler) validateBody(p *spec.Parameter, r *http.Request) *validate.Result {
+	res := new(validate.Result)
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		res.AddErrors(errors.New(http.StatusBadRequest, "%s in body can't be read: %v", p.Name, err))
+		return res
+	}
+	if len(data) == 0 {
+		if p.Required {
+			res.AddErrors(errors.Required(p.Name, p.In))
+		}
+		return res
+	}
+	if !isJSON(r.Header.Get("Content-Type")) {
+		return res
+	}
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		res.AddErrors(errors.New(http.StatusBadRequest, "%s in body is not valid JSON: %v", p.Name, err))
+		return res
+	}
+	return validate.NewSchemaValidator(validate.ExpandRefs(p.Schema, h.resolve), nil, p.Name, strfmt.Default).Validate(body)
+}
+
+// respond writes the first success response of an operation, else its
+// default response as a 200, else its first response.
+func (h *Handler) respond(w http.ResponseWriter, r *http.Request, op *analyzer.Operation) {
+	code, resp := responseOf(op)
+
+	produces := op.Produces
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+	mediaType := negotiation.Negotiate(r.Header.Get("Accept"), produces)
+	if mediaType == "" {
+		writeError(w, http.StatusNotAcceptable)
+		return
+	}
+	if resp == nil {
+		w.WriteHeader(code)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, name := range sortedKeys(resp.Headers) {
+		header := resp.Headers[name]
+		value, err := validate.FormatHeader(&header, h.headerValue(&header))
+		if err == nil {
+			w.Header().Set(name, value)
+		}
+	}
+	example, ok := resp.Examples[mediaType]
+	if !ok && resp.Schema != nil {
+		example, ok = h.example(resp.Schema), true
+	}
+	if !ok || code == http.StatusNoContent || code == http.StatusNotModified {
+		w.WriteHeader(code)
+		return
+	}
+	var data []byte
+	if s, isString := example.(string); isString && !isJSON(mediaType) {
+		data = []byte(s)
+	} else if data, ok = marshal(example); !ok {
+		writeError(w, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(code)
+	if r.Method != http.MethodHead {
+		w.Write(data)
+	}
+}
+
+func responseOf(op *analyzer.Operation) (int, *spec.Response) {
+	codes := make([]int, 0, len(op.Responses))
+	for c := range op.Responses {
+		codes = append(codes, c)
+	}
+	sort.Ints(codes)
+	for _, c := range codes {
+		if c >= 200 && c < 300 {
+			resp, _ := op.Response(c)
+			return c, resp
+		}
+	}
+	if op.DefaultResponse != nil || len(codes) == 0 {
+		return http.StatusOK, op.DefaultResponse
+	}
+	resp, _ := op.Response(codes[0])
+	return codes[0], resp
+}
+
+// example returns the example of a schema, following its reference, or a
+// value generated from it.
+func (h *Handler) example(s *spec.Schema) interface{} {
+	resolved := s
+	if ref := s.Ref.String(); ref != "" {
+		resolved = h.resolve(ref)
+	}
+	if resolved != nil && resolved.Example != nil {
+		return resolved.Example
+	}
+	return h.gen.Value(s)
+}
+
+// headerValue returns the default of a response header or a value
+// generated from its type.
+func (h *Handler) headerValue(header *spec.Header) interface{} {
+	if header.Default != nil {
+		return header.Default
+	}
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{header.Type}, Format: header.Format, Enum: header.Enum}}
+	if header.Items != nil {
+		s.Items = &spec.SchemaOrArray{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{header.Items.Type}, Format: header.Items.Format, Enum: header.Items.Enum}}}
+	}
+	return h.gen.Value(s)
+}
+
+func marshal(v interface{}) ([]byte, bool) {
+	data, err := json.Marshal(v)
+	return data, err == nil
+}
+
+// writeError writes a JSON error with the messages of errs.
+func writeError(w http.ResponseWriter, code int, errs ...error) {
+	body := struct {
+		Message string   `json:"message"`
+		Errors  []string `json:"errors,omitempty"`
+	}{Message: http.StatusText(code)}
+	for _, err := range errs {
+		body.Errors = append(body.Errors, err.Error())
+	}
+	data, _ := json.Marshal(body)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+func sortedKeys(m map[string]spec.Header) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
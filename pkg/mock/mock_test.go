@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const petstore = `{
+  "swagger": "2.0",
+  "basePath": "/v1",
+  "produces": ["application/json"],
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [{"name": "limit", "in": "query", "type": "integer", "required": true, "minimum": 1, "maximum": 10}],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "headers": {"X-Total": {"type": "integer", "default": 2}},
+            "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}
+          },
+          "400": {"description": "bad request"}
+        }
+      },
+      "post": {
+        "parameters": [{"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}],
+        "responses": {"201": {"description": "created", "examples": {"application/json": {"name": "rex"}}}}
+      }
+    },
+    "/pets/mine": {
+      "get": {"responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/Pet"}}}}
+    },
+    "/pets/{id}": {
+      "get": {
+        "parameters": [{"name": "id", "in": "path", "type": "string", "required": true, "maxLength": 5}],
+        "responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/Named"}}}
+      },
+      "delete": {
+        "parameters": [{"name": "id", "in": "path", "type": "string", "required": true, "maxLength": 5}],
+        "responses": {"204": {"description": "deleted"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string", "minLength": 1}}},
+    "Named": {"type": "object", "properties": {"name": {"type": "string"}}, "example": {"name": "tom"}}
+  }
+}`
+
+func newHandler(t *testing.T, opts Options) *Handler {
+	var s spec.Swagger
+	require.NoError(t, json.Unmarshal([]byte(petstore), &s))
+	h, err := New(&s, opts)
+	require.NoError(t, err)
+	return h
+}
+
+func serve(h http.Handler, method, target, body string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestServeExamples(t *testing.T) {
+	h := newHandler(t, Options{})
+
+	w := serve(h, "POST", "/v1/pets", `{"name": "rex"}`, http.Header{"Content-Type": {"application/json"}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"name": "rex"}`, w.Body.String())
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	w = serve(h, "GET", "/v1/pets/tom", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"name": "tom"}`, w.Body.String())
+
+	w = serve(h, "DELETE", "/v1/pets/tom", "", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestServeGeneratedData(t *testing.T) {
+	h := newHandler(t, Options{Seed: 1})
+
+	w := serve(h, "GET", "/v1/pets?limit=2", "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-Total"))
+	var pets []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &pets))
+	for _, pet := range pets {
+		assert.IsType(t, "", pet["name"])
+	}
+
+	// The literal path is preferred over the template.
+	w = serve(h, "GET", "/v1/pets/mine", "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var pet map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &pet))
+	assert.IsType(t, "", pet["name"])
+
+	w = serve(h, "HEAD", "/v1/pets/mine", "", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestServeValidatesRequests(t *testing.T) {
+	h := newHandler(t, Options{})
+
+	for _, tc := range []struct {
+		method, target, body string
+		errors               []string
+	}{
+		{"GET", "/v1/pets", "", []string{"limit in query is required"}},
+		{"GET", "/v1/pets?limit=11", "", []string{"limit in query should be less than or equal to 10"}},
+		{"GET", "/v1/pets?limit=ten", "", []string{"limit in query must be of type integer: \"ten\""}},
+		{"GET", "/v1/pets/toolong", "", []string{"id in path should be at most 5 chars long"}},
+		{"POST", "/v1/pets", "", []string{"pet in body is required"}},
+		{"POST", "/v1/pets", `{}`, []string{"pet.name in body is required"}},
+	} {
+		w := serve(h, tc.method, tc.target, tc.body, http.Header{"Content-Type": {"application/json"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code, tc.target)
+		var body struct {
+			Message string
+			Errors  []string
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "Bad Request", body.Message)
+		assert.Equal(t, tc.errors, body.Errors, tc.target)
+	}
+
+	h = newHandler(t, Options{SkipValidation: true})
+	assert.Equal(t, http.StatusOK, serve(h, "GET", "/v1/pets", "", nil).Code)
+}
+
+func TestServeUnknownRequests(t *testing.T) {
+	h := newHandler(t, Options{})
+
+	assert.Equal(t, http.StatusNotFound, serve(h, "GET", "/v1/owners", "", nil).Code)
+	assert.Equal(t, http.StatusNotFound, serve(h, "GET", "/pets/mine", "", nil).Code)
+
+	w := serve(h, "PUT", "/v1/pets/tom", "", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "DELETE, GET", w.Header().Get("Allow"))
+
+	w = serve(h, "GET", "/v1/pets/mine", "", http.Header{"Accept": {"application/xml"}})
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+}
+
+func TestServeCORS(t *testing.T) {
+	h := newHandler(t, Options{CORS: true})
+
+	w := serve(h, "OPTIONS", "/v1/pets", "", http.Header{
+		"Origin":                         {"http://localhost:3000"},
+		"Access-Control-Request-Method":  {"POST"},
+		"Access-Control-Request-Headers": {"Content-Type"},
+	})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "http://localhost:3000", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "POST", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+
+	w = serve(h, "GET", "/v1/pets/mine", "", http.Header{"Origin": {"http://localhost:3000"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "http://localhost:3000", w.Header().Get("Access-Control-Allow-Origin"))
+
+	h = newHandler(t, Options{})
+	w = serve(h, "GET", "/v1/pets/mine", "", http.Header{"Origin": {"http://localhost:3000"}})
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package example
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	age := int32(3)
+	pet := Pet{Name: "rex", Phase: PhaseRunning, Age: &age, Toys: []PetToysItem{{}}}
+	assert.NoError(t, pet.Validate())
+
+	pet.Name = ""
+	pet.Phase = "Sleeping"
+	assert.Error(t, pet.Validate())
+
+	breed := "beagle"
+	dog := Dog{Pet: Pet{Name: "snoopy", Phase: PhasePending}, Breed: &breed}
+	assert.NoError(t, dog.Validate())
+	dog.Breed = nil
+	assert.NoError(t, dog.Validate())
+
+	level := Level(3)
+	assert.Error(t, level.Validate())
+	level = Level2
+	assert.NoError(t, level.Validate())
+}
@@ -0,0 +1,485 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package modelgen generates Go models from the definitions of a spec:
+// structs with json tags for objects, named types for the other
+// definitions and typed constants for enums. Optional fields are pointers,
+// except for slices, maps and interfaces which are marshaled as is. The
+// generated Validate methods check a model against its definition with the
+// validate package, as a lightweight alternative to full client generators.
+package modelgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const definitionPrefix = "#/definitions/"
+
+// Options configures the generated code.
+type Options struct {
+	// Package is the name of the generated package. Defaults to "models".
+	Package string
+	// TypeName returns the Go type name of a definition. Defaults to the
+	// last dot separated segment of the name, e.g. "Pod" for
+	// "io.k8s.api.core.v1.Pod".
+	TypeName func(definition string) string
+	// Validate adds Validate methods to the types of the definitions.
+	Validate bool
+}
+
+// initialisms are the words written in upper case in Go names.
+var initialisms = map[string]bool{
+	"api": true, "http": true, "id": true, "ip": true, "json": true,
+	"uid": true, "uri": true, "url": true, "uuid": true,
+}
+
+type generator struct {
+	opts        Options
+	definitions spec.Definitions
+	// names maps definition names to Go type names.
+	names map[string]string
+	// declared maps the declared Go names to what declares them.
+	declared map[string]string
+	decls    []string
+	imports  map[string]bool
+	err      error
+}
+
+// Generate returns the formatted source of a file declaring the models of
+// the definitions.
+func Generate(definitions spec.Definitions, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = "models"
+	}
+	if opts.TypeName == nil {
+		opts.TypeName = typeName
+	}
+	g := &generator{
+		opts:        opts,
+		definitions: definitions,
+		names:       map[string]string{},
+		declared:    map[string]string{},
+		imports:     map[string]bool{},
+	}
+	defNames := make([]string, 0, len(definitions))
+	for name := range definitions {
+		defNames = append(defNames, name)
+	}
+	sort.Strings(defNames)
+	for _, name := range defNames {
+		goName := opts.TypeName(name)
+		if other, ok := g.declared[goName]; ok {
+			return nil, fmt.Errorf("definitions %s and %s are both named %s", other, name, goName)
+		}
+		g.names[name] = goName
+		g.declared[goName] = "definition " + name
+	}
+
+	var schemas map[string]interface{}
+	if opts.Validate {
+		data, err := json.Marshal(definitions)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &schemas); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range defNames {
+		def := definitions[name]
+		goName := g.names[name]
+		doc := fmt.Sprintf("%s is the %s definition.", goName, name)
+		if def.Description != "" {
+			doc += "\n\n" + def.Description
+		}
+		i := len(g.decls)
+		g.declare(goName, &def, doc, true)
+		if opts.Validate {
+			schema, err := inline(schemas[name], schemas, []string{name})
+			if err != nil {
+				return nil, err
+			}
+			data, err := json.Marshal(schema)
+			if err != nil {
+				return nil, err
+			}
+			// The method follows the type, before its nested types.
+			g.decls = append(g.decls[:i+1], append([]string{fmt.Sprintf(`// Validate validates the %[1]s against the %[2]s definition.
+func (m *%[1]s) Validate() error {
+	return validateModel(m, %[3]s)
+}
+
+const %[3]s = %[4]s
+`, goName, name, lowerFirst(goName)+"Schema", strconv.Quote(string(data)))}, g.decls[i+1:]...)...)
+		}
+		if g.err != nil {
+			return nil, g.err
+		}
+	}
+	if opts.Validate {
+		for _, p := range []string{"encoding/json", "k8s.io/kube-openapi/pkg/validation/spec", "k8s.io/kube-openapi/pkg/validation/strfmt", "k8s.io/kube-openapi/pkg/validation/validate"} {
+			g.imports[p] = true
+		}
+		g.decls = append(g.decls, `// validateModel validates a model against a JSON schema.
+func validateModel(m interface{}, schema string) error {
+	var s spec.Schema
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		return err
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return validate.AgainstSchema(&s, v, strfmt.Default)
+}
+`)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by modelgen. DO NOT EDIT.\n\npackage %s\n\n", opts.Package)
+	if len(g.imports) > 0 {
+		// Standard library imports come first.
+		var std, other []string
+		for p := range g.imports {
+			if strings.Contains(strings.SplitN(p, "/", 2)[0], ".") {
+				other = append(other, strconv.Quote(p))
+			} else {
+				std = append(std, strconv.Quote(p))
+			}
+		}
+		sort.Strings(std)
+		sort.Strings(other)
+		var groups []string
+		for _, group := range [][]string{std, other} {
+			if len(group) > 0 {
+				groups = append(groups, strings.Join(group, "\n"))
+			}
+		}
+		fmt.Fprintf(&buf, "import (\n%s\n)\n\n", strings.Join(groups, "\n\n"))
+	}
+	buf.WriteString(strings.Join(g.decls, "\n"))
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return src, nil
+}
+
+func (g *generator) fail(format string, args ...interface{}) {
+	if g.err == nil {
+		g.err = fmt.Errorf(format, args...)
+	}
+}
+
+// declare declares a Go type for a schema. Nested types are declared after
+// it.
+func (g *generator) declare(name string, s *spec.Schema, doc string, definition bool) {
+	if !definition {
+		if other, ok := g.declared[name]; ok {
+			g.fail("%s is declared by both %s and a nested schema", name, other)
+			return
+		}
+		g.declared[name] = "a nested schema"
+	}
+	i := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var sb strings.Builder
+	writeComment(&sb, "", doc)
+	if isStruct(s) {
+		fmt.Fprintf(&sb, "type %s struct {\n", name)
+		g.writeFields(&sb, name, s)
+		sb.WriteString("}\n")
+	} else {
+		fmt.Fprintf(&sb, "type %s %s\n", name, g.goType(s, name, false))
+		g.writeEnum(&sb, name, s)
+	}
+	g.decls[i] = sb.String()
+}
+
+func (g *generator) writeFields(sb *strings.Builder, parent string, s *spec.Schema) {
+	properties := map[string]spec.Schema{}
+	required := map[string]bool{}
+	var embedded []string
+	for _, member := range append([]spec.Schema{*s}, s.AllOf...) {
+		if ref := member.Ref.String(); ref != "" {
+			name := g.refType(ref)
+			if def, ok := g.definitions[strings.TrimPrefix(ref, definitionPrefix)]; ok && !isStruct(&def) {
+				g.fail("%s: allOf member %s is not an object", parent, ref)
+			}
+			embedded = append(embedded, name)
+			continue
+		}
+		for k, v := range member.Properties {
+			properties[k] = v
+		}
+		for _, r := range member.Required {
+			required[r] = true
+		}
+	}
+	for _, name := range embedded {
+		fmt.Fprintf(sb, "\t%s\n", name)
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := map[string]string{}
+	for _, name := range names {
+		prop := properties[name]
+		field := identifier(name)
+		if other, ok := fields[field]; ok {
+			g.fail("%s: properties %s and %s are both named %s", parent, other, name, field)
+			return
+		}
+		fields[field] = name
+		t := g.goType(&prop, parent+field, true)
+		tag := name
+		if !required[name] {
+			tag += ",omitempty"
+		}
+		if (!required[name] || prop.Nullable) && pointable(t) {
+			t = "*" + t
+		}
+		writeComment(sb, "\t", prop.Description)
+		fmt.Fprintf(sb, "\t%s %s `json:%s`\n", field, t, strconv.Quote(tag))
+	}
+}
+
+func (g *generator) writeEnum(sb *strings.Builder, name string, s *spec.Schema) {
+	if len(s.Enum) == 0 || !(s.Type.Contains("string") || s.Type.Contains("integer")) {
+		return
+	}
+	fmt.Fprintf(sb, "\n// Values of %s.\nconst (\n", name)
+	seen := map[string]bool{}
+	for _, v := range s.Enum {
+		var literal, suffix string
+		switch v := v.(type) {
+		case string:
+			literal, suffix = strconv.Quote(v), v
+		case float64:
+			literal = strconv.FormatFloat(v, 'f', -1, 64)
+			suffix = strings.Replace(literal, "-", "Minus", 1)
+		case json.Number:
+			literal = v.String()
+			suffix = strings.Replace(literal, "-", "Minus", 1)
+		default:
+			g.fail("%s: unsupported enum value %v", name, v)
+			return
+		}
+		constant := name + camel(suffix)
+		if constant == name {
+			constant = name + "Empty"
+		}
+		if seen[constant] {
+			g.fail("%s: enum values named %s more than once", name, constant)
+			return
+		}
+		seen[constant] = true
+		fmt.Fprintf(sb, "\t%s %s = %s\n", constant, name, literal)
+	}
+	sb.WriteString(")\n")
+}
+
+// goType returns the Go type of a schema. Objects with properties, and
+// enums if named is true, are declared as the type called name.
+func (g *generator) goType(s *spec.Schema, name string, named bool) string {
+	if ref := s.Ref.String(); ref != "" {
+		return g.refType(ref)
+	}
+	if isStruct(s) || (named && len(s.Enum) > 0 && (s.Type.Contains("string") || s.Type.Contains("integer"))) {
+		doc := name + " is generated from a nested schema."
+		if s.Description != "" {
+			doc += "\n\n" + s.Description
+		}
+		g.declare(name, s, doc, false)
+		return name
+	}
+	if len(s.Type) != 1 {
+		return "interface{}"
+	}
+	switch s.Type[0] {
+	case "string":
+		switch s.Format {
+		case "byte":
+			return "[]byte"
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		if s.Items == nil || s.Items.Schema == nil {
+			return "[]interface{}"
+		}
+		return "[]" + g.goType(s.Items.Schema, name+"Item", true)
+	case "object":
+		if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+			return "map[string]" + g.goType(s.AdditionalProperties.Schema, name+"Value", true)
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+func (g *generator) refType(ref string) string {
+	if !strings.HasPrefix(ref, definitionPrefix) {
+		g.fail("unsupported reference %s", ref)
+		return "interface{}"
+	}
+	name, ok := g.names[unescape(ref[len(definitionPrefix):])]
+	if !ok {
+		g.fail("reference to unknown definition %s", ref)
+		return "interface{}"
+	}
+	return name
+}
+
+// isStruct returns whether a schema is generated as a struct.
+func isStruct(s *spec.Schema) bool {
+	return s.Ref.String() == "" && (len(s.Properties) > 0 || len(s.AllOf) > 0)
+}
+
+// pointable returns whether an optional field of the type is a pointer.
+func pointable(t string) bool {
+	return !strings.HasPrefix(t, "[]") && !strings.HasPrefix(t, "map[") && t != "interface{}"
+}
+
+// inline returns a copy of a schema decoded from JSON with the references
+// to definitions replaced by the definitions. Recursive references are
+// replaced by an empty schema.
+func inline(v interface{}, definitions map[string]interface{}, stack []string) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			if !strings.HasPrefix(ref, definitionPrefix) {
+				return nil, fmt.Errorf("unsupported reference %s", ref)
+			}
+			name := unescape(ref[len(definitionPrefix):])
+			for _, n := range stack {
+				if n == name {
+					return map[string]interface{}{}, nil
+				}
+			}
+			def, ok := definitions[name]
+			if !ok {
+				return nil, fmt.Errorf("reference to unknown definition %s", ref)
+			}
+			return inline(def, definitions, append(stack[:len(stack):len(stack)], name))
+		}
+		ret := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			if k == "enum" || k == "default" || k == "example" {
+				ret[k] = child
+				continue
+			}
+			c, err := inline(child, definitions, stack)
+			if err != nil {
+				return nil, err
+			}
+			ret[k] = c
+		}
+		return ret, nil
+	case []interface{}:
+		ret := make([]interface{}, len(v))
+		for i, child := range v {
+			c, err := inline(child, definitions, stack)
+			if err != nil {
+				return nil, err
+			}
+			ret[i] = c
+		}
+		return ret, nil
+	}
+	return v, nil
+}
+
+func typeName(definition string) string {
+	return identifier(definition[strings.LastIndex(definition, ".")+1:])
+}
+
+// identifier converts a name to an exported Go identifier, e.g.
+// "pod-ip" to "PodIP".
+func identifier(name string) string {
+	id := camel(name)
+	if id == "" || unicode.IsDigit([]rune(id)[0]) {
+		id = "X" + id
+	}
+	return id
+}
+
+// camel joins the capitalized words of a name.
+func camel(name string) string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if initialisms[strings.ToLower(word)] {
+			sb.WriteString(strings.ToUpper(word))
+			continue
+		}
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+	return sb.String()
+}
+
+func lowerFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+}
+
+func writeComment(sb *strings.Builder, indent, text string) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		sb.WriteString(strings.TrimRight(indent+"// "+line, " ") + "\n")
+	}
+}
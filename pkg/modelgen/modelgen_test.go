@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelgen
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestGenerate(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/pets.json")
+	require.NoError(t, err)
+	var definitions spec.Definitions
+	require.NoError(t, json.Unmarshal(data, &definitions))
+
+	src, err := Generate(definitions, Options{Package: "example", Validate: true})
+	require.NoError(t, err)
+	golden, err := ioutil.ReadFile("internal/example/zz_generated.models.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), string(src), "regenerate internal/example/zz_generated.models.go")
+}
+
+func TestGenerateWithoutValidation(t *testing.T) {
+	src, err := Generate(spec.Definitions{
+		"io.k8s.api.core.v1.PodStatus": *new(spec.Schema).
+			Typed("object", "").
+			SetProperty("podIP", *spec.StringProperty()).
+			SetProperty("hostIPs", *spec.ArrayProperty(spec.StringProperty())),
+	}, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, `// Code generated by modelgen. DO NOT EDIT.
+
+package models
+
+// PodStatus is the io.k8s.api.core.v1.PodStatus definition.
+type PodStatus struct {
+	HostIPs []string `+"`"+`json:"hostIPs,omitempty"`+"`"+`
+	PodIP   *string  `+"`"+`json:"podIP,omitempty"`+"`"+`
+}
+`, string(src))
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for name, definitions := range map[string]spec.Definitions{
+		"duplicate type": {
+			"v1.Pod": *spec.StringProperty(),
+			"v2.Pod": *spec.StringProperty(),
+		},
+		"unknown reference": {
+			"Pet": *new(spec.Schema).SetProperty("owner", *spec.RefSchema("#/definitions/Owner")),
+		},
+		"remote reference": {
+			"Pet": *new(spec.Schema).SetProperty("owner", *spec.RefSchema("other.json#/Owner")),
+		},
+		"duplicate enum constant": {
+			"Phase": *spec.StringProperty().WithEnum("pending", "Pending"),
+		},
+		"duplicate field": {
+			"Pet": *new(spec.Schema).SetProperty("pod-ip", *spec.StringProperty()).SetProperty("podIP", *spec.StringProperty()),
+		},
+		"nested type declared twice": {
+			"Pet":     *new(spec.Schema).SetProperty("toy", *new(spec.Schema).SetProperty("kind", *spec.StringProperty())),
+			"PetToy":  *spec.StringProperty(),
+			"Options": *spec.StringProperty(),
+		},
+	} {
+		_, err := Generate(definitions, Options{Validate: true})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	for name, expected := range map[string]string{
+		"name":       "Name",
+		"pod-ip":     "PodIP",
+		"hostIPs":    "HostIPs",
+		"api_url":    "APIURL",
+		"2fa":        "X2fa",
+		"x-k8s.kind": "XK8sKind",
+		"":           "X",
+	} {
+		assert.Equal(t, expected, identifier(name), name)
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Flatten moves the inline object schemas of a spec to definitions and
+// references them instead, for generators that only name the types of
+// definitions. Objects with properties or allOf are moved, which excludes
+// maps and the subschemas of compositions.
+//
+// The definitions are named after where the schemas are: the operation, in
+// Pascal case, followed by "Body" for body parameters and by "Response" and
+// the status code for responses, e.g. "ListPetsResponse200"; the parent
+// definition followed by the property name, "Item" for items or "Value" for
+// additional properties, e.g. "PetOwner". Taken names are suffixed with
+// "_2", "_3" and so on. Operations are named after their ID, or their
+// method and path.
+//
+// The spec is modified in place. The names of the new definitions are
+// returned sorted.
+func Flatten(sp *spec.Swagger) []string {
+	f := &flattener{sp: sp, taken: map[string]bool{}}
+	if sp.Definitions == nil {
+		sp.Definitions = spec.Definitions{}
+	}
+	names := make([]string, 0, len(sp.Definitions))
+	for name := range sp.Definitions {
+		names = append(names, name)
+		f.taken[name] = true
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := sp.Definitions[name]
+		f.children(&def, name)
+		sp.Definitions[name] = def
+	}
+
+	names = names[:0]
+	for name := range sp.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := sp.Parameters[name]
+		f.parameter(&p, pascalCase(name))
+		sp.Parameters[name] = p
+	}
+	names = names[:0]
+	for name := range sp.Responses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r := sp.Responses[name]
+		f.schema(r.Schema, pascalCase(name)+"Response")
+		sp.Responses[name] = r
+	}
+
+	if sp.Paths != nil {
+		paths := make([]string, 0, len(sp.Paths.Paths))
+		for p := range sp.Paths.Paths {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			item := sp.Paths.Paths[p]
+			f.operations(p, &item)
+			sp.Paths.Paths[p] = item
+		}
+	}
+	sort.Strings(f.added)
+	return f.added
+}
+
+type flattener struct {
+	sp    *spec.Swagger
+	taken map[string]bool
+	added []string
+}
+
+func (f *flattener) operations(path string, item *spec.PathItem) {
+	for i := range item.Parameters {
+		f.parameter(&item.Parameters[i], path)
+	}
+	for _, m := range item.Operations() {
+		op := m.Operation
+		name := op.ID
+		if name == "" {
+			name = LowerCamelCase(operationWords(m.Method, path))
+		}
+		name = pascalCase(name)
+		for i := range op.Parameters {
+			f.parameter(&op.Parameters[i], name)
+		}
+		if op.Responses == nil {
+			continue
+		}
+		if op.Responses.Default != nil {
+			f.schema(op.Responses.Default.Schema, name+"DefaultResponse")
+		}
+		codes := make([]int, 0, len(op.Responses.StatusCodeResponses))
+		for code := range op.Responses.StatusCodeResponses {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			r := op.Responses.StatusCodeResponses[code]
+			f.schema(r.Schema, fmt.Sprintf("%sResponse%d", name, code))
+			op.Responses.StatusCodeResponses[code] = r
+		}
+	}
+}
+
+func (f *flattener) parameter(p *spec.Parameter, name string) {
+	if p.In == "body" {
+		f.schema(p.Schema, pascalCase(name)+"Body")
+	}
+}
+
+// schema moves s to a definition named after name if it is an object,
+// after its subschemas.
+func (f *flattener) schema(s *spec.Schema, name string) {
+	if s == nil || s.Ref.String() != "" {
+		return
+	}
+	f.children(s, name)
+	if len(s.Properties) == 0 && len(s.AllOf) == 0 {
+		return
+	}
+	unique := name
+	for i := 2; f.taken[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	f.taken[unique] = true
+	f.added = append(f.added, unique)
+	f.sp.Definitions[unique] = *s
+	*s = spec.Schema{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("#/definitions/" + unique)}}
+}
+
+// children moves the object schemas of the properties, items and
+// additional properties of s.
+func (f *flattener) children(s *spec.Schema, name string) {
+	props := make([]string, 0, len(s.Properties))
+	for prop := range s.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+	for _, prop := range props {
+		p := s.Properties[prop]
+		f.schema(&p, name+pascalCase(prop))
+		s.Properties[prop] = p
+	}
+	if s.Items != nil {
+		f.schema(s.Items.Schema, name+"Item")
+		for i := range s.Items.Schemas {
+			f.schema(&s.Items.Schemas[i], fmt.Sprintf("%sItem%d", name, i))
+		}
+	}
+	if s.AdditionalProperties != nil {
+		f.schema(s.AdditionalProperties.Schema, name+"Value")
+	}
+}
+
+// pascalCase capitalizes the words of s and drops what separates them, e.g.
+// "PetsById" for "pets-by_id".
+func pascalCase(s string) string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+	return sb.String()
+}
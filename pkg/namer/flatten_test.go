@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestFlatten(t *testing.T) {
+	var sp spec.Swagger
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "swagger": "2.0",
+  "paths": {
+    "/pets": {
+      "post": {
+        "operationId": "createPet",
+        "parameters": [{"name": "body", "in": "body", "schema": {"type": "object", "properties": {"name": {"type": "string"}}}}],
+        "responses": {
+          "200": {"description": "ok", "schema": {"type": "array", "items": {"type": "object", "properties": {"id": {"type": "string"}}}}},
+          "default": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}
+        }
+      }
+    },
+    "/pets/{id}": {
+      "get": {
+        "responses": {"200": {"description": "ok", "schema": {"type": "object", "properties": {"id": {"type": "string"}}}}}
+      }
+    }
+  },
+  "definitions": {
+    "Error": {"type": "object", "properties": {"code": {"type": "integer"}}},
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "owner": {"type": "object", "properties": {"address": {"allOf": [{"$ref": "#/definitions/Error"}]}}},
+        "labels": {"type": "object", "additionalProperties": {"type": "string"}}
+      }
+    },
+    "PetOwner": {"type": "string"}
+  }
+}`), &sp))
+
+	added := Flatten(&sp)
+	assert.Equal(t, []string{"CreatePetBody", "CreatePetResponse200Item", "GetPetsByIdResponse200", "PetOwnerAddress", "PetOwner_2"}, added)
+
+	post := sp.Paths.Paths["/pets"].Post
+	assert.Equal(t, "#/definitions/CreatePetBody", post.Parameters[0].Schema.Ref.String())
+	ok := post.Responses.StatusCodeResponses[200]
+	assert.Equal(t, "#/definitions/CreatePetResponse200Item", ok.Schema.Items.Schema.Ref.String())
+	assert.Equal(t, "#/definitions/Error", post.Responses.Default.Schema.Ref.String())
+	get := sp.Paths.Paths["/pets/{id}"].Get.Responses.StatusCodeResponses[200]
+	assert.Equal(t, "#/definitions/GetPetsByIdResponse200", get.Schema.Ref.String())
+
+	pet := sp.Definitions["Pet"]
+	owner, labels := pet.Properties["owner"], pet.Properties["labels"]
+	assert.Equal(t, "#/definitions/PetOwner_2", owner.Ref.String())
+	assert.NotNil(t, labels.AdditionalProperties.Schema, "maps are kept inline")
+	address := sp.Definitions["PetOwner_2"].Properties["address"]
+	assert.Equal(t, "#/definitions/PetOwnerAddress", address.Ref.String())
+	assert.Equal(t, spec.StringOrArray{"string"}, sp.Definitions["PetOwner"].Type)
+
+	assert.Empty(t, Flatten(&sp), "flattening is idempotent")
+}
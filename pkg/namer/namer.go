@@ -0,0 +1,255 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package namer gives definitions friendly names following a policy. A
+// DefinitionNamer plugs into common.Config.GetDefinitionName for generated
+// specs, and its renames apply to aggregated specs with
+// aggregator.RenameDefinitions. Names that collide under the policy fall
+// back to the REST friendly form of the full name, so that every
+// definition keeps a unique name, and the collisions are reported.
+// AssignOperationIDs does the same for the operation IDs of a spec, and
+// Flatten names the inline object schemas it moves to definitions.
+package namer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/util"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const gvkKey = "x-kubernetes-group-version-kind"
+
+// Policy returns the friendly name of a definition from its full name,
+// e.g. "k8s.io/api/core/v1.Pod", and its schema. The schema is nil for
+// definitions the namer doesn't know about.
+type Policy func(name string, schema *spec.Schema) string
+
+// StripPackagePath keeps the last element of the package path and the type
+// name, e.g. "v1.Pod". It is the default of the builder.
+func StripPackagePath(name string, _ *spec.Schema) string {
+	base, _ := util.SplitTypeArguments(name)
+	return name[strings.LastIndex(base, "/")+1:]
+}
+
+// RESTFriendly converts the full name with util.ToRESTFriendlyName, e.g.
+// "io.k8s.api.core.v1.Pod".
+func RESTFriendly(name string, _ *spec.Schema) string {
+	return util.ToRESTFriendlyName(name)
+}
+
+// CamelCase joins the capitalized words of the last element of the package
+// path and of the type name, e.g. "V1Pod".
+func CamelCase(name string, schema *spec.Schema) string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(StripPackagePath(name, schema), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+	return sb.String()
+}
+
+// GVK names the definitions of a single Kubernetes kind after its group,
+// version and kind, e.g. "apps.v1.Deployment" or "v1.Pod" for the core
+// group, and the other definitions with the fallback policy.
+func GVK(fallback Policy) Policy {
+	return func(name string, schema *spec.Schema) string {
+		if schema != nil {
+			if gvks, ok := schema.Extensions[gvkKey].([]interface{}); ok && len(gvks) == 1 {
+				group, version, kind := gvkField(gvks[0], "group"), gvkField(gvks[0], "version"), gvkField(gvks[0], "kind")
+				if version != "" && kind != "" {
+					if group == "" {
+						return version + "." + kind
+					}
+					return group + "." + version + "." + kind
+				}
+			}
+		}
+		return fallback(name, schema)
+	}
+}
+
+func gvkField(gvk interface{}, key string) string {
+	switch gvk := gvk.(type) {
+	case map[string]interface{}:
+		s, _ := gvk[key].(string)
+		return s
+	case map[string]string:
+		return gvk[key]
+	}
+	return ""
+}
+
+// Collision is a friendly name shared by several definitions under the
+// policy.
+type Collision struct {
+	Name        string
+	Definitions []string
+}
+
+// Mapping is the friendly name given to a definition.
+type Mapping struct {
+	Name           string
+	DefinitionName string
+	// Collided is set for names falling back to the REST friendly form
+	// because of a collision.
+	Collided bool
+}
+
+// DefinitionNamer names definitions following a policy.
+type DefinitionNamer struct {
+	policy Policy
+
+	lock sync.Mutex
+	// names maps full names to their mapping.
+	names map[string]Mapping
+	// owners maps friendly names to the full name they were given to.
+	owners     map[string]string
+	collisions map[string][]string
+}
+
+// NewDefinitionNamer returns a namer for definitions keyed by their full
+// name. The names of the given definitions don't depend on the order they
+// are asked for.
+func NewDefinitionNamer(policy Policy, definitions spec.Definitions) *DefinitionNamer {
+	n := &DefinitionNamer{
+		policy:     policy,
+		names:      map[string]Mapping{},
+		owners:     map[string]string{},
+		collisions: map[string][]string{},
+	}
+	full := make([]string, 0, len(definitions))
+	for name := range definitions {
+		full = append(full, name)
+	}
+	sort.Strings(full)
+
+	byName := map[string][]string{}
+	friendly := make(map[string]string, len(full))
+	for _, name := range full {
+		def := definitions[name]
+		friendly[name] = policy(name, &def)
+		byName[friendly[name]] = append(byName[friendly[name]], name)
+	}
+	var collided []string
+	for _, name := range full {
+		if len(byName[friendly[name]]) > 1 {
+			n.collisions[friendly[name]] = byName[friendly[name]]
+			collided = append(collided, name)
+			continue
+		}
+		n.names[name] = Mapping{Name: name, DefinitionName: friendly[name]}
+		n.owners[friendly[name]] = name
+	}
+	for _, name := range collided {
+		n.fallback(name)
+	}
+	return n
+}
+
+// NewDefinitionNamerForOpenAPIDefinitions returns a namer for the
+// definitions of a common.Config.
+func NewDefinitionNamerForOpenAPIDefinitions(policy Policy, getDefinitions common.GetOpenAPIDefinitions) *DefinitionNamer {
+	defs := getDefinitions(func(name string) spec.Ref { return spec.MustCreateRef(name) })
+	definitions := make(spec.Definitions, len(defs))
+	for name, def := range defs {
+		definitions[name] = def.Schema
+	}
+	return NewDefinitionNamer(policy, definitions)
+}
+
+// fallback names a definition with the REST friendly form of its full name,
+// suffixed if that is taken too.
+func (n *DefinitionNamer) fallback(name string) Mapping {
+	base := util.ToRESTFriendlyName(name)
+	candidate := base
+	for i := 2; n.owners[candidate] != ""; i++ {
+		candidate = fmt.Sprintf("%s_%d", base, i)
+	}
+	m := Mapping{Name: name, DefinitionName: candidate, Collided: true}
+	n.names[name] = m
+	n.owners[candidate] = name
+	return m
+}
+
+// GetDefinitionName returns the friendly name of a definition, to be used
+// as common.Config.GetDefinitionName. Definitions the namer was not created
+// with are named on first use.
+func (n *DefinitionNamer) GetDefinitionName(name string) (string, spec.Extensions) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if m, ok := n.names[name]; ok {
+		return m.DefinitionName, nil
+	}
+	friendly := n.policy(name, nil)
+	owner, taken := n.owners[friendly]
+	if !taken {
+		n.names[name] = Mapping{Name: name, DefinitionName: friendly}
+		n.owners[friendly] = name
+		return friendly, nil
+	}
+	if len(n.collisions[friendly]) == 0 {
+		n.collisions[friendly] = []string{owner}
+	}
+	n.collisions[friendly] = append(n.collisions[friendly], name)
+	return n.fallback(name).DefinitionName, nil
+}
+
+// Renames returns the friendly names differing from the full names, to be
+// applied with aggregator.RenameDefinitions.
+func (n *DefinitionNamer) Renames() map[string]string {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	ret := map[string]string{}
+	for name, m := range n.names {
+		if m.DefinitionName != name {
+			ret[name] = m.DefinitionName
+		}
+	}
+	return ret
+}
+
+// Report returns the names given so far, sorted by full name.
+func (n *DefinitionNamer) Report() []Mapping {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	ret := make([]Mapping, 0, len(n.names))
+	for _, m := range n.names {
+		ret = append(ret, m)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// Collisions returns the collisions found so far, sorted by name.
+func (n *DefinitionNamer) Collisions() []Collision {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	ret := make([]Collision, 0, len(n.collisions))
+	for name, defs := range n.collisions {
+		ret = append(ret, Collision{Name: name, Definitions: append([]string(nil), defs...)})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func withGVK(group, version, kind string) spec.Schema {
+	s := *spec.StringProperty()
+	s.AddExtension(gvkKey, []interface{}{
+		map[string]interface{}{"group": group, "version": version, "kind": kind},
+	})
+	return s
+}
+
+func TestPolicies(t *testing.T) {
+	const pod = "k8s.io/api/core/v1.Pod"
+	const list = "example.com/pets/v1.List[example.com/pets/v1.Pet]"
+	assert.Equal(t, "v1.Pod", StripPackagePath(pod, nil))
+	assert.Equal(t, "v1.List[example.com/pets/v1.Pet]", StripPackagePath(list, nil))
+	assert.Equal(t, "io.k8s.api.core.v1.Pod", RESTFriendly(pod, nil))
+	assert.Equal(t, "V1Pod", CamelCase(pod, nil))
+
+	gvk := GVK(RESTFriendly)
+	deployment := withGVK("apps", "v1", "Deployment")
+	core := withGVK("", "v1", "Pod")
+	assert.Equal(t, "apps.v1.Deployment", gvk("k8s.io/api/apps/v1.Deployment", &deployment))
+	assert.Equal(t, "v1.Pod", gvk(pod, &core))
+	assert.Equal(t, "io.k8s.api.core.v1.Pod", gvk(pod, nil))
+
+	multi := withGVK("apps", "v1", "DeleteOptions")
+	multi.Extensions[gvkKey] = append(multi.Extensions[gvkKey].([]interface{}), map[string]string{"group": "batch", "version": "v1", "kind": "DeleteOptions"})
+	assert.Equal(t, "io.k8s.apimachinery.pkg.apis.meta.v1.DeleteOptions", gvk("k8s.io/apimachinery/pkg/apis/meta/v1.DeleteOptions", &multi))
+}
+
+func TestDefinitionNamer(t *testing.T) {
+	n := NewDefinitionNamer(StripPackagePath, spec.Definitions{
+		"k8s.io/api/core/v1.Pod":            *spec.StringProperty(),
+		"k8s.io/api/core/v1.Service":        *spec.StringProperty(),
+		"example.com/pets/v1.Pod":           *spec.StringProperty(),
+		"k8s.io/apimachinery/pkg/types.UID": *spec.StringProperty(),
+	})
+
+	name, ext := n.GetDefinitionName("k8s.io/api/core/v1.Service")
+	assert.Equal(t, "v1.Service", name)
+	assert.Nil(t, ext)
+	name, _ = n.GetDefinitionName("k8s.io/api/core/v1.Pod")
+	assert.Equal(t, "io.k8s.api.core.v1.Pod", name)
+	name, _ = n.GetDefinitionName("example.com/pets/v1.Pod")
+	assert.Equal(t, "com.example.pets.v1.Pod", name)
+
+	// Unknown definitions are named on first use.
+	name, _ = n.GetDefinitionName("example.com/pets/v1.Service")
+	assert.Equal(t, "com.example.pets.v1.Service", name)
+	name, _ = n.GetDefinitionName("example.com/pets/v2.UID")
+	assert.Equal(t, "v2.UID", name)
+
+	assert.Equal(t, []Collision{
+		{Name: "v1.Pod", Definitions: []string{"example.com/pets/v1.Pod", "k8s.io/api/core/v1.Pod"}},
+		{Name: "v1.Service", Definitions: []string{"k8s.io/api/core/v1.Service", "example.com/pets/v1.Service"}},
+	}, n.Collisions())
+	report := n.Report()
+	assert.Len(t, report, 6)
+	assert.Equal(t, Mapping{Name: "example.com/pets/v1.Pod", DefinitionName: "com.example.pets.v1.Pod", Collided: true}, report[0])
+	assert.Equal(t, "types.UID", n.Renames()["k8s.io/apimachinery/pkg/types.UID"])
+}
+
+func TestFallbackSuffix(t *testing.T) {
+	// Both names are REST friendly as "x.a.b.C".
+	n := NewDefinitionNamer(func(string, *spec.Schema) string { return "same" }, spec.Definitions{
+		"a.x/b.C": *spec.StringProperty(),
+		"x/a.b.C": *spec.StringProperty(),
+	})
+	assert.Equal(t, map[string]string{"a.x/b.C": "x.a.b.C", "x/a.b.C": "x.a.b.C_2"}, n.Renames())
+}
+
+func mustName(n *DefinitionNamer, name string) string {
+	ret, _ := n.GetDefinitionName(name)
+	return ret
+}
+
+func TestNewDefinitionNamerForOpenAPIDefinitions(t *testing.T) {
+	n := NewDefinitionNamerForOpenAPIDefinitions(GVK(StripPackagePath), func(common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+		return map[string]common.OpenAPIDefinition{
+			"k8s.io/api/apps/v1.Deployment": {Schema: withGVK("apps", "v1", "Deployment")},
+			"k8s.io/api/apps/v1.Spec":       {Schema: *spec.StringProperty()},
+		}
+	})
+	assert.Equal(t, "apps.v1.Deployment", mustName(n, "k8s.io/api/apps/v1.Deployment"))
+	assert.Equal(t, "v1.Spec", mustName(n, "k8s.io/api/apps/v1.Spec"))
+	assert.Empty(t, n.Collisions())
+}
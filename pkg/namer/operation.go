@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Casing joins the words of a synthesized operation ID, e.g. "get", "pets",
+// "by" and "id" for GET /pets/{id}.
+type Casing func(words []string) string
+
+// LowerCamelCase joins words in lower camel case, e.g. "getPetsById".
+func LowerCamelCase(words []string) string {
+	var sb strings.Builder
+	for i, word := range words {
+		if i == 0 {
+			sb.WriteString(strings.ToLower(word))
+			continue
+		}
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+	return sb.String()
+}
+
+// SnakeCase joins lowercased words with underscores, e.g. "get_pets_by_id".
+func SnakeCase(words []string) string {
+	return strings.ToLower(strings.Join(words, "_"))
+}
+
+// KebabCase joins lowercased words with dashes, e.g. "get-pets-by-id".
+func KebabCase(words []string) string {
+	return strings.ToLower(strings.Join(words, "-"))
+}
+
+// OperationIDChange is an operation ID set by AssignOperationIDs. Old is
+// empty for synthesized IDs.
+type OperationIDChange struct {
+	Method string
+	Path   string
+	Old    string
+	New    string
+}
+
+// AssignOperationIDs makes the operation IDs of a spec unique and non-empty,
+// as many generators require. Missing IDs are synthesized from the method
+// and the path with casing. Operations sharing an ID, or whose synthesized
+// ID is taken, are suffixed with "_2", "_3" and so on, the first one by path
+// and method keeping its ID, so the result doesn't depend on map order.
+//
+// The operations are modified in place. The changes are returned in the
+// order of the operations.
+func AssignOperationIDs(sp *spec.Swagger, casing Casing) []OperationIDChange {
+	if sp.Paths == nil {
+		return nil
+	}
+	type operation struct {
+		method, path string
+		op           *spec.Operation
+	}
+	var ops []operation
+	paths := make([]string, 0, len(sp.Paths.Paths))
+	for p := range sp.Paths.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		item := sp.Paths.Paths[p]
+		for _, m := range item.Operations() {
+			ops = append(ops, operation{m.Method, p, m.Operation})
+		}
+	}
+
+	// Existing IDs are claimed first so that synthesized ones never take
+	// them over.
+	taken := map[string]bool{}
+	keep := make([]bool, len(ops))
+	for i, o := range ops {
+		if o.op.ID != "" && !taken[o.op.ID] {
+			taken[o.op.ID] = true
+			keep[i] = true
+		}
+	}
+	var changes []OperationIDChange
+	for i, o := range ops {
+		if keep[i] {
+			continue
+		}
+		base := o.op.ID
+		if base == "" {
+			base = casing(operationWords(o.method, o.path))
+		}
+		candidate := base
+		for n := 2; taken[candidate]; n++ {
+			candidate = fmt.Sprintf("%s_%d", base, n)
+		}
+		taken[candidate] = true
+		changes = append(changes, OperationIDChange{Method: o.method, Path: o.path, Old: o.op.ID, New: candidate})
+		o.op.ID = candidate
+	}
+	return changes
+}
+
+// operationWords splits a method and a path into words, path parameters
+// being introduced by "by", e.g. "get", "pets", "by" and "id" for
+// GET /pets/{id}.
+func operationWords(method, path string) []string {
+	words := []string{strings.ToLower(method)}
+	for _, segment := range strings.Split(path, "/") {
+		param := strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+		parts := strings.FieldsFunc(segment, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if param && len(parts) > 0 {
+			words = append(words, "by")
+		}
+		words = append(words, parts...)
+	}
+	return words
+}
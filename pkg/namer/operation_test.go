@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestCasings(t *testing.T) {
+	words := operationWords("GET", "/pets/{petId}/user-groups")
+	assert.Equal(t, []string{"get", "pets", "by", "petId", "user", "groups"}, words)
+	assert.Equal(t, "getPetsByPetIdUserGroups", LowerCamelCase(words))
+	assert.Equal(t, "get_pets_by_petid_user_groups", SnakeCase(words))
+	assert.Equal(t, "get-pets-by-petid-user-groups", KebabCase(words))
+	assert.Equal(t, []string{"get"}, operationWords("GET", "/"))
+}
+
+func TestAssignOperationIDs(t *testing.T) {
+	op := func(id string) *spec.Operation {
+		return &spec.Operation{OperationProps: spec.OperationProps{ID: id}}
+	}
+	sp := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+		"/pets":      {PathItemProps: spec.PathItemProps{Get: op("listPets"), Post: op("")}},
+		"/pets/{id}": {PathItemProps: spec.PathItemProps{Get: op(""), Delete: op("listPets")}},
+		// The synthesized ID of GET /pets/{id} is already taken.
+		"/z": {PathItemProps: spec.PathItemProps{Get: op("getPetsById")}},
+	}}}}
+	changes := AssignOperationIDs(sp, LowerCamelCase)
+	assert.Equal(t, []OperationIDChange{
+		{Method: "POST", Path: "/pets", New: "postPets"},
+		{Method: "GET", Path: "/pets/{id}", New: "getPetsById_2"},
+		{Method: "DELETE", Path: "/pets/{id}", Old: "listPets", New: "listPets_2"},
+	}, changes)
+	assert.Equal(t, "listPets", sp.Paths.Paths["/pets"].Get.ID)
+	assert.Equal(t, "getPetsById", sp.Paths.Paths["/z"].Get.ID)
+	assert.Equal(t, "getPetsById_2", sp.Paths.Paths["/pets/{id}"].Get.ID)
+
+	assert.Empty(t, AssignOperationIDs(sp, LowerCamelCase), "the IDs are unique now")
+	assert.Empty(t, AssignOperationIDs(&spec.Swagger{}, SnakeCase))
+}
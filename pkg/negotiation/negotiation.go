@@ -0,0 +1,156 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package negotiation selects the media type of a response from the Accept
+// header of a request, following the quality values and the precedence
+// rules of RFC 7231 section 5.3.2.
+package negotiation
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Negotiate returns the offer preferred by an Accept header, or "" when no
+// offer is acceptable. An empty header accepts any offer.
+//
+// The quality of an offer is the quality of the most specific media range
+// matching it: a range with parameters is more specific than the same
+// range without, which is more specific than a structured syntax suffix
+// range such as "application/*+json", then "application/*" and "*/*". An
+// offer with a quality of 0 is not acceptable. Between offers of the same
+// quality, the one matched by the more specific range is preferred, then
+// the one matched by the earlier range of the header, then the first one.
+func Negotiate(acceptHeader string, offers []string) string {
+	if strings.TrimSpace(acceptHeader) == "" {
+		acceptHeader = "*/*"
+	}
+	ranges := parseAccept(acceptHeader)
+	best, bestQ, bestSpecificity, bestIndex := "", 0.0, -1, 0
+	for _, offer := range offers {
+		o, ok := parseMediaType(offer)
+		if !ok {
+			continue
+		}
+		q, specificity, index := -1.0, -1, 0
+		for i, r := range ranges {
+			if s := r.match(o); s > specificity {
+				q, specificity, index = r.q, s, i
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		if q > bestQ || (q == bestQ && (specificity > bestSpecificity || (specificity == bestSpecificity && index < bestIndex))) {
+			best, bestQ, bestSpecificity, bestIndex = offer, q, specificity, index
+		}
+	}
+	return best
+}
+
+type mediaType struct {
+	typ, subtype string
+	params       map[string]string
+}
+
+// mediaRange is a media range of an Accept header with its quality.
+type mediaRange struct {
+	mediaType
+	q float64
+}
+
+// parseAccept returns the media ranges of an Accept header. Invalid ranges
+// are ignored.
+func parseAccept(header string) []mediaRange {
+	var ret []mediaRange
+	for _, part := range strings.Split(header, ",") {
+		m, ok := parseMediaType(part)
+		if !ok {
+			continue
+		}
+		r := mediaRange{mediaType: m, q: 1}
+		if q, ok := m.params["q"]; ok {
+			v, err := strconv.ParseFloat(q, 64)
+			if err != nil || v < 0 || v > 1 {
+				continue
+			}
+			r.q = v
+			delete(r.params, "q")
+		}
+		if r.typ == "*" && r.subtype != "*" {
+			continue
+		}
+		ret = append(ret, r)
+	}
+	return ret
+}
+
+// parseMediaType parses a media type or range such as
+// "application/json; charset=utf-8". Types and parameter names are case
+// insensitive and returned in lower case.
+func parseMediaType(s string) (mediaType, bool) {
+	parts := strings.Split(s, ";")
+	typeAndSubtype := strings.SplitN(strings.ToLower(strings.TrimSpace(parts[0])), "/", 2)
+	if len(typeAndSubtype) != 2 || typeAndSubtype[0] == "" || typeAndSubtype[1] == "" {
+		return mediaType{}, false
+	}
+	m := mediaType{typ: typeAndSubtype[0], subtype: typeAndSubtype[1], params: map[string]string{}}
+	for _, p := range parts[1:] {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		if _, ok := m.params["q"]; ok {
+			// Accept extensions after the weight are ignored.
+			break
+		}
+		m.params[key] = strings.Trim(strings.TrimSpace(kv[1]), `"`)
+	}
+	return m, true
+}
+
+// match returns the specificity of the range if it matches the offer, or
+// -1 if it doesn't.
+func (r mediaRange) match(o mediaType) int {
+	var specificity int
+	switch {
+	case r.typ == "*":
+		specificity = 0
+	case r.typ != o.typ:
+		return -1
+	case r.subtype == "*":
+		specificity = 1
+	case strings.HasPrefix(r.subtype, "*+"):
+		if !strings.HasSuffix(o.subtype, r.subtype[1:]) {
+			return -1
+		}
+		specificity = 2
+	case r.subtype == o.subtype:
+		specificity = 3
+	default:
+		return -1
+	}
+	for k, v := range r.params {
+		if o.params[k] != v {
+			return -1
+		}
+	}
+	if len(r.params) > 0 {
+		return 2*specificity + 1
+	}
+	return 2 * specificity
+}
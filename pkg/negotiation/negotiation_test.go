@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package negotiation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json", "application/merge-patch+json", "application/yaml", "text/plain; charset=utf-8"}
+	for _, tc := range []struct {
+		accept string
+		want   string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/*", "application/json"},
+		{"application/yaml", "application/yaml"},
+		{"APPLICATION/YAML", "application/yaml"},
+		{"application/*+json", "application/merge-patch+json"},
+		{"application/*+yaml", ""},
+		{"image/png", ""},
+		{"application/json;q=0, application/*", "application/merge-patch+json"},
+		{"application/*;q=0.5, application/yaml", "application/yaml"},
+		{"*/*;q=0.1, text/plain", "text/plain; charset=utf-8"},
+		{"text/plain;charset=ascii, */*;q=0.2", "application/json"},
+		{"text/plain;charset=utf-8;q=0.9, */*;q=0.2", "text/plain; charset=utf-8"},
+		// The most specific matching range decides the quality of an offer.
+		{"application/*;q=0.9, application/json;q=0.1", "application/merge-patch+json"},
+		// Same quality: the offer matched by the more specific range wins.
+		{"*/*, application/yaml", "application/yaml"},
+		{"*/*;q=0, application/json;q=0.5", "application/json"},
+		// Then the offer matched by the earlier range.
+		{"application/yaml, application/json", "application/yaml"},
+		// Invalid ranges and weights are ignored.
+		{"application/yaml;q=2, application/json;q=x, */json, text/*", "text/plain; charset=utf-8"},
+		{"garbage", ""},
+	} {
+		assert.Equal(t, tc.want, Negotiate(tc.accept, offers), "Accept: %q", tc.accept)
+	}
+	assert.Equal(t, "", Negotiate("*/*", nil))
+	assert.Equal(t, "application/json", Negotiate("*/*", []string{"invalid", "application/json"}))
+}
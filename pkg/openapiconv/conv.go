@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package openapiconv converts documents between Swagger 2.0 and OpenAPI 3.0,
+// and between OpenAPI 3.0 and 3.1.
+// Conversions are best effort: what can't be represented in the target
+// version is dropped and reported as a Loss.
+package openapiconv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Loss is something of the source document a conversion couldn't carry over
+// exactly.
+type Loss struct {
+	// Path is the JSON pointer of the object in the source document.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (l Loss) String() string {
+	return l.Path + ": " + l.Message
+}
+
+type losses []Loss
+
+func (l *losses) add(path, format string, args ...interface{}) {
+	*l = append(*l, Loss{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l losses) sorted() []Loss {
+	sort.SliceStable(l, func(i, j int) bool { return l[i].Path < l[j].Path })
+	return l
+}
+
+// rewriteRef replaces the from prefix of a local reference by to, and
+// returns whether it did.
+func rewriteRef(ref *spec.Ref, from, to string) bool {
+	s := ref.String()
+	if !strings.HasPrefix(s, from) {
+		return false
+	}
+	*ref = spec.MustCreateRef(to + s[len(from):])
+	return true
+}
+
+func deepCopySecurity(in []map[string][]string) []map[string][]string {
+	if in == nil {
+		return nil
+	}
+	out := make([]map[string][]string, len(in))
+	for i, req := range in {
+		out[i] = make(map[string][]string, len(req))
+		for name, scopes := range req {
+			out[i][name] = append([]string{}, scopes...)
+		}
+	}
+	return out
+}
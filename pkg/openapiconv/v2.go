@@ -0,0 +1,723 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapiconv
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// bodyNameExtension names the body parameter of an operation converted to
+// Swagger 2.0. It is the extension used by code generators for the same
+// purpose.
+const bodyNameExtension = "x-codegen-request-body-name"
+
+// ConvertToV2 converts an OpenAPI 3.0 document to Swagger 2.0, see
+// ConvertToV2WithReport.
+func ConvertToV2(doc *spec3.OpenAPI) (*spec.Swagger, error) {
+	s, _, err := ConvertToV2WithReport(doc)
+	return s, err
+}
+
+// ConvertToV2WithReport converts an OpenAPI 3.0 document to Swagger 2.0 for
+// tools that only read Swagger 2.0, and reports what couldn't be converted
+// exactly. The source document is not modified.
+//
+// The mapping is the reverse of ConvertToV3WithReport. Besides, oneOf and
+// anyOf are moved to the x-oneof and x-anyof extensions; cookie parameters,
+// status code ranges, webhooks, trace operations and OpenID Connect and mutual
+// TLS security schemes are dropped; the request body of each operation
+// becomes a body parameter named after the x-codegen-request-body-name
+// extension, "body" by default, or formData parameters for form media types.
+// Only the first server is kept.
+func ConvertToV2WithReport(doc *spec3.OpenAPI) (*spec.Swagger, []Loss, error) {
+	c := &v2Converter{doc: doc}
+	s, err := c.convert()
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, c.losses.sorted(), nil
+}
+
+type v2Converter struct {
+	doc    *spec3.OpenAPI
+	losses losses
+}
+
+func (c *v2Converter) convert() (*spec.Swagger, error) {
+	doc := c.doc
+	s := &spec.Swagger{}
+	s.Swagger = "2.0"
+	s.Info = doc.Info.DeepCopy()
+	s.Security = deepCopySecurity(doc.Security)
+	for i := range doc.Tags {
+		s.Tags = append(s.Tags, *doc.Tags[i].DeepCopy())
+	}
+	s.ExternalDocs = doc.ExternalDocs.DeepCopy()
+	s.Extensions = doc.Extensions.DeepCopy()
+	if err := c.servers(s, doc.Servers, "/servers"); err != nil {
+		return nil, err
+	}
+	if len(doc.Webhooks) > 0 {
+		c.losses.add("/webhooks", "webhooks were dropped")
+	}
+
+	if comp := doc.Components; comp != nil {
+		for name, sch := range comp.Schemas {
+			if s.Definitions == nil {
+				s.Definitions = spec.Definitions{}
+			}
+			s.Definitions[name] = *c.schema(sch, "/components/schemas/"+jsonpointer.Escape(name))
+		}
+		for name, p := range comp.Parameters {
+			pointer := "/components/parameters/" + jsonpointer.Escape(name)
+			if converted := c.parameter(p, pointer); converted != nil {
+				if s.Parameters == nil {
+					s.Parameters = map[string]spec.Parameter{}
+				}
+				s.Parameters[name] = *converted
+			}
+		}
+		for name, rb := range comp.RequestBodies {
+			pointer := "/components/requestBodies/" + jsonpointer.Escape(name)
+			// Form request bodies are expanded to formData parameters in
+			// the operations using them.
+			if _, form := formMediaType(rb.Content); form || rb.Ref.String() != "" {
+				continue
+			}
+			if s.Parameters == nil {
+				s.Parameters = map[string]spec.Parameter{}
+			}
+			body, _ := c.bodyParameter(name, rb, pointer)
+			s.Parameters[name] = *body
+		}
+		for name, r := range comp.Responses {
+			if s.Responses == nil {
+				s.Responses = map[string]spec.Response{}
+			}
+			s.Responses[name] = *c.response(r, nil, "/components/responses/"+jsonpointer.Escape(name))
+		}
+		for name, ss := range comp.SecuritySchemes {
+			if converted := c.securityScheme(ss, "/components/securitySchemes/"+jsonpointer.Escape(name)); converted != nil {
+				if s.SecurityDefinitions == nil {
+					s.SecurityDefinitions = spec.SecurityDefinitions{}
+				}
+				s.SecurityDefinitions[name] = converted
+			}
+		}
+		if len(comp.PathItems) > 0 {
+			c.losses.add("/components/pathItems", "path item components were dropped")
+		}
+		if len(comp.Links) > 0 {
+			c.losses.add("/components/links", "link components were dropped")
+		}
+		if len(comp.Callbacks) > 0 {
+			c.losses.add("/components/callbacks", "callback components were dropped")
+		}
+		for _, kind := range []struct {
+			name string
+			n    int
+		}{{"examples", len(comp.Examples)}, {"headers", len(comp.Headers)}} {
+			if kind.n > 0 {
+				c.losses.add("/components/"+kind.name, "%s components were dropped, references to them are inlined", kind.name)
+			}
+		}
+	}
+
+	s.Paths = &spec.Paths{}
+	if doc.Paths != nil {
+		s.Paths.Extensions = doc.Paths.Extensions.DeepCopy()
+		for p, item := range doc.Paths.Paths {
+			if s.Paths.Paths == nil {
+				s.Paths.Paths = map[string]spec.PathItem{}
+			}
+			s.Paths.Paths[p] = c.path(item, "/paths/"+jsonpointer.Escape(p))
+		}
+	}
+	return s, nil
+}
+
+// servers keeps the first server as the scheme, host and base path of the
+// document, with its variables replaced by their defaults. The other servers
+// with the same host and base path contribute their scheme.
+func (c *v2Converter) servers(s *spec.Swagger, servers []*spec3.Server, pointer string) error {
+	type location struct{ scheme, host, basePath string }
+	var locations []location
+	for i, server := range servers {
+		u, err := url.Parse(substituteDefaults(server))
+		if err != nil {
+			c.losses.add(pointer+"/"+strconv.Itoa(i), "server URL %q is invalid: %v", server.URL, err)
+			continue
+		}
+		locations = append(locations, location{u.Scheme, u.Host, strings.TrimSuffix(u.Path, "/")})
+	}
+	if len(locations) == 0 {
+		return nil
+	}
+	first := locations[0]
+	s.Host = first.host
+	s.BasePath = first.basePath
+	for i, l := range locations {
+		if l.host != first.host || l.basePath != first.basePath {
+			c.losses.add(pointer+"/"+strconv.Itoa(i), "only the first server is kept")
+			continue
+		}
+		if l.scheme != "" && !containsString(s.Schemes, l.scheme) {
+			s.Schemes = append(s.Schemes, l.scheme)
+		}
+	}
+	return nil
+}
+
+func substituteDefaults(server *spec3.Server) string {
+	u := server.URL
+	for name, v := range server.Variables {
+		u = strings.Replace(u, "{"+name+"}", v.Default, -1)
+	}
+	return u
+}
+
+func containsString(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// schema converts a copy of a schema: references to components/schemas
+// become references to definitions, nullable becomes x-nullable,
+// discriminator objects become property names, and oneOf and anyOf move to
+// extensions.
+func (c *v2Converter) schema(in *spec.Schema, pointer string) *spec.Schema {
+	if in == nil {
+		return nil
+	}
+	s := in.DeepCopy()
+	spec.RewriteSchema(s, pointer, func(s *spec.Schema, pointer string) {
+		c.schemaRef(&s.Ref, pointer)
+		if s.Nullable {
+			s.AddExtension("x-nullable", true)
+			s.Nullable = false
+		}
+		if d, ok := s.ExtraProps["discriminator"].(map[string]interface{}); ok {
+			s.Discriminator, _ = d["propertyName"].(string)
+			if _, ok := d["mapping"]; ok {
+				c.losses.add(pointer+"/discriminator/mapping", "discriminator mapping was dropped")
+			}
+			delete(s.ExtraProps, "discriminator")
+		}
+		for _, l := range []struct {
+			key     string
+			schemas *[]spec.Schema
+		}{{"oneOf", &s.OneOf}, {"anyOf", &s.AnyOf}} {
+			if len(*l.schemas) == 0 {
+				continue
+			}
+			// The subschemas are converted here, as they are removed before
+			// the walk reaches them.
+			converted := make([]*spec.Schema, 0, len(*l.schemas))
+			for i := range *l.schemas {
+				converted = append(converted, c.schema(&(*l.schemas)[i], pointer+"/"+l.key+"/"+strconv.Itoa(i)))
+			}
+			var value interface{}
+			data, _ := json.Marshal(converted)
+			_ = json.Unmarshal(data, &value)
+			s.AddExtension("x-"+l.key, value)
+			*l.schemas = nil
+			c.losses.add(pointer+"/"+l.key, "%s is not supported, it was moved to the x-%s extension", l.key, strings.ToLower(l.key))
+		}
+	})
+	return s
+}
+
+func (c *v2Converter) schemaRef(ref *spec.Ref, pointer string) {
+	if r := ref.String(); r != "" && !rewriteRef(ref, "#/components/schemas/", "#/definitions/") && strings.HasPrefix(r, "#") {
+		c.losses.add(pointer, "reference %s is not to a schema and was kept as is", r)
+	}
+}
+
+// simpleSchema converts a parameter or header schema to a simple schema,
+// which has no object or reference.
+func (c *v2Converter) simpleSchema(s *spec.Schema, ss *spec.SimpleSchema, v *spec.CommonValidations, pointer string) {
+	if s == nil {
+		ss.Type = "string"
+		c.losses.add(pointer, "parameters without schema are converted to strings")
+		return
+	}
+	if s.Ref.String() != "" || s.Type.Contains("object") || len(s.Type) > 1 {
+		ss.Type = "string"
+		c.losses.add(pointer, "schema %s is not a simple type, it was replaced by string", schemaName(s))
+		return
+	}
+	if len(s.Type) == 1 {
+		ss.Type = s.Type[0]
+	}
+	ss.Format = s.Format
+	ss.Default = s.Default
+	ss.Example = s.Example
+	ss.Nullable = s.Nullable
+	v.Maximum = s.Maximum
+	v.ExclusiveMaximum = s.ExclusiveMaximum
+	v.Minimum = s.Minimum
+	v.ExclusiveMinimum = s.ExclusiveMinimum
+	v.MaxLength = s.MaxLength
+	v.MinLength = s.MinLength
+	v.Pattern = s.Pattern
+	v.MaxItems = s.MaxItems
+	v.MinItems = s.MinItems
+	v.UniqueItems = s.UniqueItems
+	v.MultipleOf = s.MultipleOf
+	v.Enum = s.Enum
+	if s.Items != nil && s.Items.Schema != nil {
+		items := &spec.Items{}
+		c.simpleSchema(s.Items.Schema, &items.SimpleSchema, &items.CommonValidations, pointer+"/items")
+		ss.Items = items
+	}
+}
+
+func schemaName(s *spec.Schema) string {
+	if ref := s.Ref.String(); ref != "" {
+		return ref
+	}
+	if len(s.Type) == 0 {
+		return "without type"
+	}
+	return strings.Join(s.Type, "|")
+}
+
+// collectionFormat maps the style and explode of an array to a
+// collectionFormat.
+func (c *v2Converter) collectionFormat(style string, explode *bool, in, pointer string) string {
+	if style == "" {
+		style = "simple"
+		if in == "query" || in == "cookie" || in == "formData" {
+			style = "form"
+		}
+	}
+	exploded := style == "form"
+	if explode != nil {
+		exploded = *explode
+	}
+	switch {
+	case style == "form" && exploded && (in == "query" || in == "formData"):
+		return "multi"
+	case (style == "form" || style == "simple") && !exploded:
+		return "csv"
+	case style == "spaceDelimited" && !exploded:
+		return "ssv"
+	case style == "pipeDelimited" && !exploded:
+		return "pipes"
+	}
+	c.losses.add(pointer, "style %s with explode %t has no equivalent, it was replaced by csv", style, exploded)
+	return "csv"
+}
+
+func (c *v2Converter) parameter(p *spec3.Parameter, pointer string) *spec.Parameter {
+	ret := &spec.Parameter{}
+	if ref := p.Ref.String(); ref != "" {
+		ret.Ref = spec.MustCreateRef(ref)
+		if !rewriteRef(&ret.Ref, "#/components/parameters/", "#/parameters/") {
+			c.losses.add(pointer, "reference %s is not to a parameter and was kept as is", ref)
+		}
+		return ret
+	}
+	if p.In == "cookie" {
+		c.losses.add(pointer, "cookie parameter %s was dropped", p.Name)
+		return nil
+	}
+	ret.Name = p.Name
+	ret.In = p.In
+	ret.Description = p.Description
+	ret.Required = p.Required
+	ret.AllowEmptyValue = p.AllowEmptyValue
+	ret.Extensions = p.Extensions.DeepCopy()
+	if p.Deprecated {
+		ret.AddExtension("x-deprecated", true)
+	}
+	schema := p.Schema
+	if schema == nil && len(p.Content) > 0 {
+		c.losses.add(pointer+"/content", "parameter content is not supported, only its schema was kept")
+		_, mt := firstMediaType(p.Content)
+		schema = mt.Schema
+	}
+	c.simpleSchema(schema, &ret.SimpleSchema, &ret.CommonValidations, pointer+"/schema")
+	if p.Example != nil {
+		ret.Example = p.Example
+	}
+	if ret.Type == "array" {
+		ret.CollectionFormat = c.collectionFormat(p.Style, p.Explode, p.In, pointer)
+	}
+	return ret
+}
+
+// mediaTypes returns the sorted media types of some content.
+func mediaTypes(content map[string]*spec3.MediaType) []string {
+	ret := make([]string, 0, len(content))
+	for t := range content {
+		ret = append(ret, t)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// firstMediaType returns the JSON media type of some content if any, or the
+// first media type in sort order otherwise. It returns an empty JSON media
+// type when there is no content.
+func firstMediaType(content map[string]*spec3.MediaType) (string, *spec3.MediaType) {
+	if mt, ok := content[mimeJSON]; ok {
+		return mimeJSON, mt
+	}
+	types := mediaTypes(content)
+	if len(types) == 0 {
+		return mimeJSON, &spec3.MediaType{}
+	}
+	return types[0], content[types[0]]
+}
+
+// formMediaType returns the form media type of some content.
+func formMediaType(content map[string]*spec3.MediaType) (*spec3.MediaType, bool) {
+	for _, t := range []string{mimeMultipart, mimeForm} {
+		if mt, ok := content[t]; ok {
+			return mt, true
+		}
+	}
+	return nil, false
+}
+
+// bodyParameter converts a request body to a body parameter and returns the
+// media types it consumes.
+func (c *v2Converter) bodyParameter(name string, rb *spec3.RequestBody, pointer string) (*spec.Parameter, []string) {
+	ret := &spec.Parameter{}
+	if ref := rb.Ref.String(); ref != "" {
+		if strings.HasPrefix(ref, "#/components/requestBodies/") {
+			ret.Ref = spec.MustCreateRef("#/parameters/" + strings.TrimPrefix(ref, "#/components/requestBodies/"))
+		} else {
+			ret.Ref = spec.MustCreateRef(ref)
+			c.losses.add(pointer, "reference %s is not to a request body and was kept as is", ref)
+		}
+		return ret, nil
+	}
+	ret.Name = name
+	ret.In = "body"
+	ret.Description = rb.Description
+	ret.Required = rb.Required
+	ret.Extensions = rb.Extensions.DeepCopy()
+	t, mt := firstMediaType(rb.Content)
+	ret.Schema = c.schema(mt.Schema, pointer+"/content/"+jsonpointer.Escape(t)+"/schema")
+	if ret.Schema == nil {
+		ret.Schema = &spec.Schema{}
+	}
+	return ret, mediaTypes(rb.Content)
+}
+
+// formParameters expands the properties of a form request body to formData
+// parameters.
+func (c *v2Converter) formParameters(mt *spec3.MediaType, pointer string) []spec.Parameter {
+	schema := mt.Schema
+	if schema != nil && schema.Ref.String() != "" {
+		name := strings.TrimPrefix(schema.Ref.String(), "#/components/schemas/")
+		if c.doc.Components != nil && c.doc.Components.Schemas[name] != nil {
+			schema = c.doc.Components.Schemas[name]
+		}
+	}
+	if schema == nil {
+		return nil
+	}
+	required := map[string]bool{}
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var ret []spec.Parameter
+	for _, name := range names {
+		prop := schema.Properties[name]
+		propPointer := pointer + "/schema/properties/" + jsonpointer.Escape(name)
+		p := spec.Parameter{}
+		p.Name = name
+		p.In = "formData"
+		p.Description = prop.Description
+		p.Required = required[name]
+		if prop.Type.Contains("string") && prop.Format == "binary" {
+			p.Type = "file"
+		} else {
+			c.simpleSchema(&prop, &p.SimpleSchema, &p.CommonValidations, propPointer)
+		}
+		if p.Type == "array" {
+			var style string
+			var explode *bool
+			if e, ok := mt.Encoding[name]; ok {
+				style, explode = e.Style, e.Explode
+			}
+			p.CollectionFormat = c.collectionFormat(style, explode, "formData", propPointer)
+		}
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+func (c *v2Converter) header(h *spec3.Header, pointer string) spec.Header {
+	ret := spec.Header{}
+	if ref := h.Ref.String(); ref != "" {
+		name := strings.TrimPrefix(ref, "#/components/headers/")
+		if c.doc.Components == nil || c.doc.Components.Headers[name] == nil {
+			c.losses.add(pointer, "header reference %s can't be resolved and was dropped", ref)
+			return ret
+		}
+		h = c.doc.Components.Headers[name]
+	}
+	ret.Description = h.Description
+	ret.Extensions = h.Extensions.DeepCopy()
+	c.simpleSchema(h.Schema, &ret.SimpleSchema, &ret.CommonValidations, pointer+"/schema")
+	if h.Example != nil {
+		ret.Example = h.Example
+	}
+	if ret.Type == "array" {
+		ret.CollectionFormat = c.collectionFormat(h.Style, h.Explode, "header", pointer)
+	}
+	return ret
+}
+
+// response converts a response and adds the media types it produces to
+// produces.
+func (c *v2Converter) response(r *spec3.Response, produces map[string]bool, pointer string) *spec.Response {
+	ret := &spec.Response{}
+	if ref := r.Ref.String(); ref != "" {
+		if strings.HasPrefix(ref, "#/components/responses/") {
+			ret.Ref = spec.MustCreateRef("#/responses/" + strings.TrimPrefix(ref, "#/components/responses/"))
+		} else {
+			ret.Ref = spec.MustCreateRef(ref)
+			c.losses.add(pointer, "reference %s is not to a response and was kept as is", ref)
+		}
+		return ret
+	}
+	ret.Description = r.Description
+	ret.Extensions = r.Extensions.DeepCopy()
+	if len(r.Links) > 0 {
+		c.losses.add(pointer+"/links", "links were dropped")
+	}
+	for name, h := range r.Headers {
+		if ret.Headers == nil {
+			ret.Headers = map[string]spec.Header{}
+		}
+		ret.Headers[name] = c.header(h, pointer+"/headers/"+jsonpointer.Escape(name))
+	}
+	if len(r.Content) == 0 {
+		return ret
+	}
+	first, mt := firstMediaType(r.Content)
+	ret.Schema = c.schema(mt.Schema, pointer+"/content/"+jsonpointer.Escape(first)+"/schema")
+	for _, t := range mediaTypes(r.Content) {
+		if produces != nil {
+			produces[t] = true
+		}
+		if example := r.Content[t].Example; example != nil {
+			ret.AddExample(t, example)
+		}
+	}
+	return ret
+}
+
+func (c *v2Converter) securityScheme(s *spec3.SecurityScheme, pointer string) *spec.SecurityScheme {
+	ret := &spec.SecurityScheme{}
+	ret.Description = s.Description
+	ret.Extensions = s.Extensions.DeepCopy()
+	switch {
+	case s.Type == "http" && strings.EqualFold(s.Scheme, "basic"):
+		ret.Type = "basic"
+	case s.Type == "http":
+		ret.Type = "apiKey"
+		ret.Name = "Authorization"
+		ret.In = "header"
+		c.losses.add(pointer, "http %s authentication was replaced by an Authorization header API key", s.Scheme)
+	case s.Type == "apiKey" && s.In == "cookie":
+		c.losses.add(pointer, "cookie API keys are not supported, the scheme was dropped")
+		return nil
+	case s.Type == "apiKey":
+		ret.Type = "apiKey"
+		ret.Name = s.Name
+		ret.In = s.In
+	case s.Type == "oauth2" && s.Flows != nil:
+		ret.Type = "oauth2"
+		var flows []string
+		for _, f := range []struct {
+			name, v2 string
+			flow     *spec3.OAuthFlow
+		}{
+			{"implicit", "implicit", s.Flows.Implicit},
+			{"password", "password", s.Flows.Password},
+			{"clientCredentials", "application", s.Flows.ClientCredentials},
+			{"authorizationCode", "accessCode", s.Flows.AuthorizationCode},
+		} {
+			if f.flow == nil {
+				continue
+			}
+			flows = append(flows, f.name)
+			if ret.Flow != "" {
+				continue
+			}
+			ret.Flow = f.v2
+			ret.AuthorizationURL = f.flow.AuthorizationURL
+			ret.TokenURL = f.flow.TokenURL
+			for scope, desc := range f.flow.Scopes {
+				ret.AddScope(scope, desc)
+			}
+		}
+		if len(flows) > 1 {
+			c.losses.add(pointer+"/flows", "only the %s flow of %s was kept", flows[0], strings.Join(flows, ", "))
+		}
+	default:
+		c.losses.add(pointer, "%s security schemes are not supported, the scheme was dropped", s.Type)
+		return nil
+	}
+	return ret
+}
+
+func (c *v2Converter) path(item *spec3.Path, pointer string) spec.PathItem {
+	ret := spec.PathItem{}
+	ret.Extensions = item.Extensions.DeepCopy()
+	if ref := item.Ref.String(); ref != "" {
+		c.losses.add(pointer, "path item reference %s was dropped", ref)
+	}
+	for i, p := range item.Parameters {
+		if converted := c.parameter(p, pointer+"/parameters/"+strconv.Itoa(i)); converted != nil {
+			ret.Parameters = append(ret.Parameters, *converted)
+		}
+	}
+	if len(item.Servers) > 0 {
+		c.losses.add(pointer+"/servers", "path item servers were dropped")
+	}
+	for method, op := range item.Operations() {
+		if method == "trace" {
+			c.losses.add(pointer+"/trace", "trace operations are not supported and were dropped")
+			continue
+		}
+		ret.SetOperation(method, c.operation(op, pointer+"/"+method))
+	}
+	return ret
+}
+
+func (c *v2Converter) operation(op *spec3.Operation, pointer string) *spec.Operation {
+	ret := &spec.Operation{}
+	ret.Tags = op.Tags
+	ret.Summary = op.Summary
+	ret.Description = op.Description
+	ret.ExternalDocs = op.ExternalDocs.DeepCopy()
+	ret.ID = op.OperationID
+	ret.Deprecated = op.Deprecated
+	ret.Security = deepCopySecurity(op.Security)
+	ret.Extensions = op.Extensions.DeepCopy()
+	if len(op.Servers) > 0 {
+		c.losses.add(pointer+"/servers", "operation servers were dropped")
+	}
+	if len(op.Callbacks) > 0 {
+		c.losses.add(pointer+"/callbacks", "callbacks were dropped")
+	}
+	for i, p := range op.Parameters {
+		if converted := c.parameter(p, pointer+"/parameters/"+strconv.Itoa(i)); converted != nil {
+			ret.Parameters = append(ret.Parameters, *converted)
+		}
+	}
+
+	if rb := op.RequestBody; rb != nil {
+		rbPointer := pointer + "/requestBody"
+		if ref := rb.Ref.String(); ref != "" {
+			name := strings.TrimPrefix(ref, "#/components/requestBodies/")
+			if resolved := c.requestBody(name); resolved != nil {
+				if _, form := formMediaType(resolved.Content); form {
+					rb = resolved
+				}
+			}
+		}
+		if mt, form := formMediaType(rb.Content); form {
+			ret.Parameters = append(ret.Parameters, c.formParameters(mt, rbPointer+"/content/"+jsonpointer.Escape(formType(rb.Content)))...)
+			ret.Consumes = []string{formType(rb.Content)}
+		} else {
+			name, ok := op.Extensions.GetString(bodyNameExtension)
+			if !ok {
+				name = "body"
+			}
+			body, consumes := c.bodyParameter(name, rb, rbPointer)
+			if consumes == nil {
+				consumes = c.requestBodyTypes(rb)
+			}
+			ret.Parameters = append(ret.Parameters, *body)
+			ret.Consumes = consumes
+		}
+	}
+
+	if op.Responses != nil {
+		produces := map[string]bool{}
+		ret.Responses = &spec.Responses{}
+		ret.Responses.Extensions = op.Responses.Extensions.DeepCopy()
+		if op.Responses.Default != nil {
+			ret.Responses.Default = c.response(op.Responses.Default, produces, pointer+"/responses/default")
+		}
+		for code, r := range op.Responses.StatusCodeResponses {
+			rPointer := pointer + "/responses/" + jsonpointer.Escape(code)
+			status, err := strconv.Atoi(code)
+			if err != nil {
+				c.losses.add(rPointer, "status code range %s was dropped", code)
+				continue
+			}
+			if ret.Responses.StatusCodeResponses == nil {
+				ret.Responses.StatusCodeResponses = map[int]spec.Response{}
+			}
+			ret.Responses.StatusCodeResponses[status] = *c.response(r, produces, rPointer)
+		}
+		for t := range produces {
+			ret.Produces = append(ret.Produces, t)
+		}
+		sort.Strings(ret.Produces)
+	}
+	return ret
+}
+
+func (c *v2Converter) requestBody(name string) *spec3.RequestBody {
+	if c.doc.Components == nil {
+		return nil
+	}
+	return c.doc.Components.RequestBodies[name]
+}
+
+// requestBodyTypes returns the media types of a referenced request body.
+func (c *v2Converter) requestBodyTypes(rb *spec3.RequestBody) []string {
+	if resolved := c.requestBody(strings.TrimPrefix(rb.Ref.String(), "#/components/requestBodies/")); resolved != nil {
+		return mediaTypes(resolved.Content)
+	}
+	return nil
+}
+
+func formType(content map[string]*spec3.MediaType) string {
+	if _, ok := content[mimeMultipart]; ok {
+		return mimeMultipart
+	}
+	return mimeForm
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapiconv
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/spec3"
+)
+
+// downConverted is openAPIDoc converted back to Swagger 2.0. It differs
+// from swaggerDoc where the round trip is lossy: media types are declared per
+// operation, form parameters are inlined and tsv became csv.
+const downConverted = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "schemes": ["https"],
+  "host": "pets.example.com",
+  "basePath": "/v1",
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer", "maximum": 100},
+    "pet": {"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}
+  },
+  "responses": {
+    "Error": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}
+  },
+  "securityDefinitions": {
+    "basic": {"type": "basic"},
+    "oauth": {"type": "oauth2", "flow": "application", "tokenUrl": "https://pets.example.com/token", "scopes": {"read": "read pets"}}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "produces": ["application/json"],
+        "parameters": [
+          {"$ref": "#/parameters/limit"},
+          {"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"},
+          {"name": "ids", "in": "query", "type": "array", "items": {"type": "integer"}, "collectionFormat": "csv"}
+        ],
+        "responses": {
+          "200": {
+            "description": "ok",
+            "headers": {"X-Total": {"type": "integer"}},
+            "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}},
+            "examples": {"application/json": [{"name": "tom"}]}
+          },
+          "default": {"$ref": "#/responses/Error"}
+        }
+      },
+      "post": {
+        "operationId": "createPet",
+        "consumes": ["application/json"],
+        "parameters": [{"$ref": "#/parameters/pet"}],
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/pets/{id}/photo": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "type": "string"}],
+      "put": {
+        "consumes": ["multipart/form-data"],
+        "parameters": [
+          {"name": "file", "in": "formData", "type": "file", "required": true},
+          {"name": "note", "in": "formData", "type": "string"}
+        ],
+        "security": [{"oauth": ["read"]}],
+        "responses": {"204": {"description": "stored"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "discriminator": "kind",
+      "required": ["name", "kind"],
+      "properties": {
+        "name": {"type": "string"},
+        "kind": {"type": "string"},
+        "owner": {"$ref": "#/definitions/Owner"},
+        "nick": {"type": "string", "x-nullable": true}
+      }
+    },
+    "Owner": {"type": "string"},
+    "Error": {"type": "object", "properties": {"message": {"type": "string"}}}
+  }
+}`
+
+func loadOpenAPI(t *testing.T, data string) *spec3.OpenAPI {
+	o := &spec3.OpenAPI{}
+	require.NoError(t, json.Unmarshal([]byte(data), o))
+	return o
+}
+
+func TestConvertToV2(t *testing.T) {
+	doc := loadOpenAPI(t, openAPIDoc)
+	s, losses, err := ConvertToV2WithReport(doc)
+	require.NoError(t, err)
+	assert.Empty(t, losses)
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, downConverted, string(data))
+
+	after, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.JSONEq(t, openAPIDoc, string(after), "the source document must not change")
+}
+
+func TestConvertToV2Losses(t *testing.T) {
+	doc := loadOpenAPI(t, `{
+	  "openapi": "3.0.3",
+	  "info": {"title": "t", "version": "v"},
+	  "servers": [
+	    {"url": "https://{region}.example.com/api/", "variables": {"region": {"default": "eu"}}},
+	    {"url": "http://eu.example.com/api"},
+	    {"url": "https://other.example.com"}
+	  ],
+	  "paths": {"/a": {
+	    "trace": {"responses": {"200": {"description": "ok"}}},
+	    "post": {
+	      "x-codegen-request-body-name": "payload",
+	      "parameters": [
+	        {"name": "session", "in": "cookie", "schema": {"type": "string"}},
+	        {"name": "filter", "in": "query", "style": "deepObject", "schema": {"type": "array", "items": {"type": "string"}}},
+	        {"name": "obj", "in": "query", "schema": {"type": "object"}}
+	      ],
+	      "requestBody": {"content": {"application/json": {"schema": {
+	        "nullable": true,
+	        "oneOf": [{"$ref": "#/components/schemas/A"}, {"type": "string", "nullable": true}]
+	      }}}},
+	      "responses": {"2XX": {"description": "ok"}, "default": {"description": "error", "content": {"text/plain": {"schema": {"type": "string"}}}, "links": {"self": {"operationId": "a"}}}},
+	      "callbacks": {"hook": {"{$request.query.url}": {}}}
+	    }
+	  }},
+	  "components": {
+	    "schemas": {"A": {"type": "object", "discriminator": {"propertyName": "kind", "mapping": {"a": "#/components/schemas/A"}}}},
+	    "securitySchemes": {
+	      "bearer": {"type": "http", "scheme": "bearer"},
+	      "cookie": {"type": "apiKey", "in": "cookie", "name": "sid"},
+	      "oidc": {"type": "openIdConnect", "openIdConnectUrl": "https://example.com/.well-known"},
+	      "oauth": {"type": "oauth2", "flows": {
+	        "implicit": {"authorizationUrl": "https://example.com/auth", "scopes": {}},
+	        "password": {"tokenUrl": "https://example.com/token", "scopes": {}}
+	      }}
+	    }
+	  }
+	}`)
+	s, losses, err := ConvertToV2WithReport(doc)
+	require.NoError(t, err)
+	assert.Equal(t, []Loss{
+		{Path: "/components/schemas/A/discriminator/mapping", Message: "discriminator mapping was dropped"},
+		{Path: "/components/securitySchemes/bearer", Message: "http bearer authentication was replaced by an Authorization header API key"},
+		{Path: "/components/securitySchemes/cookie", Message: "cookie API keys are not supported, the scheme was dropped"},
+		{Path: "/components/securitySchemes/oauth/flows", Message: "only the implicit flow of implicit, password was kept"},
+		{Path: "/components/securitySchemes/oidc", Message: "openIdConnect security schemes are not supported, the scheme was dropped"},
+		{Path: "/paths/~1a/post/callbacks", Message: "callbacks were dropped"},
+		{Path: "/paths/~1a/post/parameters/0", Message: "cookie parameter session was dropped"},
+		{Path: "/paths/~1a/post/parameters/1", Message: "style deepObject with explode false has no equivalent, it was replaced by csv"},
+		{Path: "/paths/~1a/post/parameters/2/schema", Message: "schema object is not a simple type, it was replaced by string"},
+		{Path: "/paths/~1a/post/requestBody/content/application~1json/schema/oneOf", Message: "oneOf is not supported, it was moved to the x-oneof extension"},
+		{Path: "/paths/~1a/post/responses/2XX", Message: "status code range 2XX was dropped"},
+		{Path: "/paths/~1a/post/responses/default/links", Message: "links were dropped"},
+		{Path: "/paths/~1a/trace", Message: "trace operations are not supported and were dropped"},
+		{Path: "/servers/2", Message: "only the first server is kept"},
+	}, losses)
+
+	assert.Equal(t, "eu.example.com", s.Host)
+	assert.Equal(t, "/api", s.BasePath)
+	assert.Equal(t, []string{"https", "http"}, s.Schemes)
+	assert.Equal(t, "kind", s.Definitions["A"].Discriminator)
+	assert.Len(t, s.SecurityDefinitions, 2)
+
+	post := s.Paths.Paths["/a"].Post
+	require.Len(t, post.Parameters, 3)
+	assert.Equal(t, []string{"text/plain"}, post.Produces)
+	body := post.Parameters[2]
+	assert.Equal(t, "payload", body.Name)
+	assert.Equal(t, []string{"application/json"}, post.Consumes)
+	data, err := json.Marshal(body.Schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"x-nullable": true, "x-oneof": [{"$ref": "#/definitions/A"}, {"type": "string", "x-nullable": true}]}`, string(data))
+}
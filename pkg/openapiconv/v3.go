@@ -0,0 +1,580 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapiconv
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// V3Version is the OpenAPI version of the documents produced by ConvertToV3.
+const V3Version = "3.0.3"
+
+const (
+	mimeJSON      = "application/json"
+	mimeForm      = "application/x-www-form-urlencoded"
+	mimeMultipart = "multipart/form-data"
+)
+
+// ConvertToV3 converts a Swagger 2.0 document to OpenAPI 3.0, see
+// ConvertToV3WithReport.
+func ConvertToV3(doc *spec.Swagger) (*spec3.OpenAPI, error) {
+	o, _, err := ConvertToV3WithReport(doc)
+	return o, err
+}
+
+// ConvertToV3WithReport converts a Swagger 2.0 document to OpenAPI 3.0 and
+// reports what couldn't be converted exactly. The source document is not
+// modified.
+//
+// Definitions become components/schemas, body and formData parameters
+// become request bodies, consumes and produces become the media types of
+// request and response contents, collectionFormat becomes style and explode,
+// securityDefinitions become securitySchemes, and schemes, host and basePath
+// become servers. References are rewritten to the components.
+func ConvertToV3WithReport(doc *spec.Swagger) (*spec3.OpenAPI, []Loss, error) {
+	c := &v3Converter{doc: doc.DeepCopy()}
+	o := c.convert()
+	return o, c.losses.sorted(), nil
+}
+
+type v3Converter struct {
+	doc    *spec.Swagger
+	losses losses
+}
+
+func (c *v3Converter) convert() *spec3.OpenAPI {
+	doc := c.doc
+	o := &spec3.OpenAPI{}
+	o.Version = V3Version
+	o.Info = doc.Info
+	o.Servers = servers(doc.Schemes, doc.Host, doc.BasePath)
+	o.Security = doc.Security
+	o.Tags = doc.Tags
+	o.ExternalDocs = doc.ExternalDocs
+	o.Extensions = doc.Extensions
+
+	components := &spec3.Components{}
+	for name := range doc.Definitions {
+		s := doc.Definitions[name]
+		if components.Schemas == nil {
+			components.Schemas = map[string]*spec.Schema{}
+		}
+		components.Schemas[name] = c.schema(&s, "/definitions/"+jsonpointer.Escape(name))
+	}
+	for name, p := range doc.Parameters {
+		pointer := "/parameters/" + jsonpointer.Escape(name)
+		switch p.In {
+		case "body":
+			if components.RequestBodies == nil {
+				components.RequestBodies = map[string]*spec3.RequestBody{}
+			}
+			components.RequestBodies[name] = c.requestBody(&p, doc.Consumes, pointer)
+		case "formData":
+			// Form parameters are merged into the request body schema of
+			// each operation using them.
+		default:
+			if components.Parameters == nil {
+				components.Parameters = map[string]*spec3.Parameter{}
+			}
+			components.Parameters[name] = c.parameter(&p, pointer)
+		}
+	}
+	for name, r := range doc.Responses {
+		if components.Responses == nil {
+			components.Responses = map[string]*spec3.Response{}
+		}
+		components.Responses[name] = c.response(&r, doc.Produces, "/responses/"+jsonpointer.Escape(name))
+	}
+	for name, s := range doc.SecurityDefinitions {
+		if components.SecuritySchemes == nil {
+			components.SecuritySchemes = map[string]*spec3.SecurityScheme{}
+		}
+		components.SecuritySchemes[name] = c.securityScheme(s, "/securityDefinitions/"+jsonpointer.Escape(name))
+	}
+	if components.Schemas != nil || components.RequestBodies != nil || components.Parameters != nil ||
+		components.Responses != nil || components.SecuritySchemes != nil {
+		o.Components = components
+	}
+
+	o.Paths = &spec3.Paths{}
+	if doc.Paths != nil {
+		o.Paths.Extensions = doc.Paths.Extensions
+		for p, item := range doc.Paths.Paths {
+			if o.Paths.Paths == nil {
+				o.Paths.Paths = map[string]*spec3.Path{}
+			}
+			item := item
+			o.Paths.Paths[p] = c.path(&item, "/paths/"+jsonpointer.Escape(p))
+		}
+	}
+	return o
+}
+
+// servers builds the servers from the scheme, host and base path of a
+// document, relative to the document when there is no host.
+func servers(schemes []string, host, basePath string) []*spec3.Server {
+	if host == "" && basePath == "" {
+		return nil
+	}
+	if host == "" || len(schemes) == 0 {
+		schemes = []string{""}
+	}
+	ret := make([]*spec3.Server, 0, len(schemes))
+	for _, scheme := range schemes {
+		url := basePath
+		if host != "" {
+			url = "//" + host + basePath
+			if scheme != "" {
+				url = scheme + ":" + url
+			}
+		}
+		server := &spec3.Server{}
+		server.URL = url
+		ret = append(ret, server)
+	}
+	return ret
+}
+
+// schema converts a schema in place: references to definitions become
+// references to components/schemas, discriminators become objects, file
+// becomes binary strings and x-nullable becomes nullable.
+func (c *v3Converter) schema(s *spec.Schema, pointer string) *spec.Schema {
+	spec.RewriteSchema(s, pointer, func(s *spec.Schema, pointer string) {
+		if ref := s.Ref.String(); ref != "" && !rewriteRef(&s.Ref, "#/definitions/", "#/components/schemas/") && strings.HasPrefix(ref, "#") {
+			c.losses.add(pointer, "reference %s is not to a definition and was kept as is", ref)
+		}
+		if s.Discriminator != "" {
+			if s.ExtraProps == nil {
+				s.ExtraProps = map[string]interface{}{}
+			}
+			s.ExtraProps["discriminator"] = map[string]interface{}{"propertyName": s.Discriminator}
+			s.Discriminator = ""
+		}
+		if s.Type.Contains("file") {
+			s.Type = spec.StringOrArray{"string"}
+			s.Format = "binary"
+		}
+		if nullable, ok := s.Extensions.GetBool("x-nullable"); ok {
+			s.Nullable = nullable
+			s.Extensions.Remove("x-nullable")
+		}
+	})
+	return s
+}
+
+// simpleSchema converts the type of a non body parameter, header or items to
+// a schema.
+func simpleSchema(s *spec.SimpleSchema, v *spec.CommonValidations) *spec.Schema {
+	ret := &spec.Schema{}
+	if s.Type == "file" {
+		ret.Type = spec.StringOrArray{"string"}
+		ret.Format = "binary"
+	} else if s.Type != "" {
+		ret.Type = spec.StringOrArray{s.Type}
+		ret.Format = s.Format
+	}
+	ret.Nullable = s.Nullable
+	ret.Default = s.Default
+	ret.Example = s.Example
+	ret.Maximum = v.Maximum
+	ret.ExclusiveMaximum = v.ExclusiveMaximum
+	ret.Minimum = v.Minimum
+	ret.ExclusiveMinimum = v.ExclusiveMinimum
+	ret.MaxLength = v.MaxLength
+	ret.MinLength = v.MinLength
+	ret.Pattern = v.Pattern
+	ret.MaxItems = v.MaxItems
+	ret.MinItems = v.MinItems
+	ret.UniqueItems = v.UniqueItems
+	ret.MultipleOf = v.MultipleOf
+	ret.Enum = v.Enum
+	if s.Items != nil {
+		ret.Items = &spec.SchemaOrArray{Schema: simpleSchema(&s.Items.SimpleSchema, &s.Items.CommonValidations)}
+	}
+	return ret
+}
+
+// style maps a collectionFormat to a style and explode. simple is the
+// default style of the parameter location.
+func (c *v3Converter) style(collectionFormat, in, pointer string) (string, *bool) {
+	no, yes := false, true
+	switch collectionFormat {
+	case "", "csv":
+		if in == "query" || in == "formData" {
+			return "form", &no
+		}
+		return "simple", &no
+	case "multi":
+		if in == "query" || in == "formData" {
+			return "form", &yes
+		}
+	case "ssv":
+		if in == "query" {
+			return "spaceDelimited", &no
+		}
+	case "pipes":
+		if in == "query" {
+			return "pipeDelimited", &no
+		}
+	}
+	c.losses.add(pointer, "collectionFormat %s has no equivalent in %s, it was replaced by csv", collectionFormat, in)
+	return c.style("csv", in, pointer)
+}
+
+func (c *v3Converter) parameter(p *spec.Parameter, pointer string) *spec3.Parameter {
+	ret := &spec3.Parameter{}
+	if rewriteRef(&p.Ref, "#/parameters/", "#/components/parameters/") {
+		ret.Ref = p.Ref
+		return ret
+	}
+	ret.Name = p.Name
+	ret.In = p.In
+	ret.Description = p.Description
+	ret.Required = p.Required
+	ret.AllowEmptyValue = p.AllowEmptyValue
+	ret.Schema = simpleSchema(&p.SimpleSchema, &p.CommonValidations)
+	ret.Example = p.Example
+	ret.Schema.Example = nil
+	if deprecated, ok := p.Extensions.GetBool("x-deprecated"); ok {
+		ret.Deprecated = deprecated
+		p.Extensions.Remove("x-deprecated")
+	}
+	ret.Extensions = p.Extensions
+	if p.Type == "array" {
+		ret.Style, ret.Explode = c.style(p.CollectionFormat, p.In, pointer)
+	}
+	return ret
+}
+
+// content returns a media type per consumed or produced type, all with the
+// same schema.
+func content(mediaTypes []string, schema *spec.Schema) map[string]*spec3.MediaType {
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{mimeJSON}
+	}
+	ret := make(map[string]*spec3.MediaType, len(mediaTypes))
+	for _, t := range mediaTypes {
+		mt := &spec3.MediaType{}
+		mt.Schema = schema
+		ret[t] = mt
+	}
+	return ret
+}
+
+func (c *v3Converter) requestBody(p *spec.Parameter, consumes []string, pointer string) *spec3.RequestBody {
+	ret := &spec3.RequestBody{}
+	if rewriteRef(&p.Ref, "#/parameters/", "#/components/requestBodies/") {
+		ret.Ref = p.Ref
+		return ret
+	}
+	ret.Description = p.Description
+	ret.Required = p.Required
+	ret.Extensions = p.Extensions
+	var schema *spec.Schema
+	if p.Schema != nil {
+		schema = c.schema(p.Schema, pointer+"/schema")
+	}
+	ret.Content = content(consumes, schema)
+	return ret
+}
+
+// formBody merges form parameters into a request body with an object
+// schema. The media types are the form ones among consumes, or multipart
+// when there is a file and url encoding otherwise.
+func (c *v3Converter) formBody(params []indexedParameter, consumes []string) *spec3.RequestBody {
+	schema := &spec.Schema{}
+	schema.Type = spec.StringOrArray{"object"}
+	hasFile := false
+	encoding := map[string]*spec3.Encoding{}
+	for _, ip := range params {
+		p := ip.param
+		prop := simpleSchema(&p.SimpleSchema, &p.CommonValidations)
+		prop.Description = p.Description
+		schema.SetProperty(p.Name, *prop)
+		if p.Required {
+			schema.Required = append(schema.Required, p.Name)
+		}
+		if p.Type == "file" {
+			hasFile = true
+		}
+		if p.Type == "array" {
+			e := &spec3.Encoding{}
+			e.Style, e.Explode = c.style(p.CollectionFormat, p.In, ip.pointer)
+			encoding[p.Name] = e
+		}
+		if p.AllowEmptyValue {
+			c.losses.add(ip.pointer, "allowEmptyValue is not supported in request bodies")
+		}
+	}
+	sort.Strings(schema.Required)
+
+	var mediaTypes []string
+	for _, t := range consumes {
+		if t == mimeForm || t == mimeMultipart {
+			mediaTypes = append(mediaTypes, t)
+		}
+	}
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{mimeForm}
+		if hasFile {
+			mediaTypes = []string{mimeMultipart}
+		}
+	}
+	ret := &spec3.RequestBody{}
+	ret.Content = content(mediaTypes, schema)
+	for _, mt := range ret.Content {
+		if len(encoding) > 0 {
+			mt.Encoding = encoding
+		}
+	}
+	ret.Required = len(schema.Required) > 0
+	return ret
+}
+
+func (c *v3Converter) header(h *spec.Header, pointer string) *spec3.Header {
+	ret := &spec3.Header{}
+	ret.Description = h.Description
+	ret.Schema = simpleSchema(&h.SimpleSchema, &h.CommonValidations)
+	ret.Example = h.Example
+	ret.Schema.Example = nil
+	ret.Extensions = h.Extensions
+	if h.Type == "array" {
+		ret.Style, ret.Explode = c.style(h.CollectionFormat, "header", pointer)
+	}
+	return ret
+}
+
+func (c *v3Converter) response(r *spec.Response, produces []string, pointer string) *spec3.Response {
+	ret := &spec3.Response{}
+	if rewriteRef(&r.Ref, "#/responses/", "#/components/responses/") {
+		ret.Ref = r.Ref
+		return ret
+	}
+	ret.Description = r.Description
+	ret.Extensions = r.Extensions
+	for name, h := range r.Headers {
+		if ret.Headers == nil {
+			ret.Headers = map[string]*spec3.Header{}
+		}
+		ret.Headers[name] = c.header(&h, pointer+"/headers/"+jsonpointer.Escape(name))
+	}
+	var schema *spec.Schema
+	if r.Schema != nil {
+		schema = c.schema(r.Schema, pointer+"/schema")
+	}
+	if schema != nil || len(r.Examples) > 0 {
+		ret.Content = content(produces, schema)
+	}
+	for t, example := range r.Examples {
+		mt, ok := ret.Content[t]
+		if !ok {
+			mt = &spec3.MediaType{}
+			mt.Schema = schema
+			ret.Content[t] = mt
+		}
+		mt.Example = example
+	}
+	return ret
+}
+
+func (c *v3Converter) securityScheme(s *spec.SecurityScheme, pointer string) *spec3.SecurityScheme {
+	ret := &spec3.SecurityScheme{}
+	ret.Description = s.Description
+	ret.Extensions = s.Extensions
+	switch s.Type {
+	case "basic":
+		ret.Type = "http"
+		ret.Scheme = "basic"
+	case "apiKey":
+		ret.Type = "apiKey"
+		ret.Name = s.Name
+		ret.In = s.In
+	case "oauth2":
+		ret.Type = "oauth2"
+		flow := &spec3.OAuthFlow{
+			AuthorizationURL: s.AuthorizationURL,
+			TokenURL:         s.TokenURL,
+			Scopes:           s.Scopes,
+		}
+		if flow.Scopes == nil {
+			flow.Scopes = map[string]string{}
+		}
+		ret.Flows = &spec3.OAuthFlows{}
+		switch s.Flow {
+		case "implicit":
+			ret.Flows.Implicit = flow
+		case "password":
+			ret.Flows.Password = flow
+		case "application":
+			ret.Flows.ClientCredentials = flow
+		case "accessCode":
+			ret.Flows.AuthorizationCode = flow
+		default:
+			c.losses.add(pointer, "unknown oauth2 flow %q was dropped", s.Flow)
+		}
+	default:
+		ret.Type = s.Type
+		c.losses.add(pointer, "unknown security scheme type %q was kept as is", s.Type)
+	}
+	return ret
+}
+
+type indexedParameter struct {
+	pointer string
+	param   *spec.Parameter
+}
+
+// resolveFormParameter returns the global parameter referenced by p when it
+// is a form parameter, as those can't be referenced in OpenAPI 3.
+func (c *v3Converter) resolveFormParameter(p *spec.Parameter) *spec.Parameter {
+	name := strings.TrimPrefix(p.Ref.String(), "#/parameters/")
+	if name == p.Ref.String() {
+		return p
+	}
+	if global, ok := c.doc.Parameters[name]; ok && global.In == "formData" {
+		return &global
+	}
+	return p
+}
+
+// isBody returns whether p is a body parameter or references one.
+func (c *v3Converter) isBody(p *spec.Parameter) bool {
+	if p.In == "body" {
+		return true
+	}
+	name := strings.TrimPrefix(p.Ref.String(), "#/parameters/")
+	global, ok := c.doc.Parameters[name]
+	return name != p.Ref.String() && ok && global.In == "body"
+}
+
+func (c *v3Converter) path(item *spec.PathItem, pointer string) *spec3.Path {
+	ret := &spec3.Path{}
+	ret.Extensions = item.Extensions
+	if ref := item.Ref.String(); ref != "" {
+		c.losses.add(pointer, "path item reference %s was dropped", ref)
+	}
+
+	// Body and form parameters of the path item are moved to the request
+	// body of each operation.
+	var shared []indexedParameter
+	for i := range item.Parameters {
+		p := c.resolveFormParameter(&item.Parameters[i])
+		ip := indexedParameter{pointer: pointer + "/parameters/" + strconv.Itoa(i), param: p}
+		if p.In == "formData" || c.isBody(p) {
+			shared = append(shared, ip)
+			continue
+		}
+		ret.Parameters = append(ret.Parameters, c.parameter(p, ip.pointer))
+	}
+
+	for _, m := range item.Operations() {
+		method := strings.ToLower(m.Method)
+		ret.SetOperation(method, c.operation(m.Operation, shared, pointer+"/"+method))
+	}
+	return ret
+}
+
+func (c *v3Converter) operation(op *spec.Operation, shared []indexedParameter, pointer string) *spec3.Operation {
+	ret := &spec3.Operation{}
+	ret.Tags = op.Tags
+	ret.Summary = op.Summary
+	ret.Description = op.Description
+	ret.ExternalDocs = op.ExternalDocs
+	ret.OperationID = op.ID
+	ret.Deprecated = op.Deprecated
+	ret.Security = op.Security
+	ret.Extensions = op.Extensions
+	if len(op.Schemes) > 0 {
+		ret.Servers = servers(op.Schemes, c.doc.Host, c.doc.BasePath)
+		if c.doc.Host == "" {
+			c.losses.add(pointer+"/schemes", "schemes can't be expressed without a host")
+			ret.Servers = nil
+		}
+	}
+	consumes := op.Consumes
+	if len(consumes) == 0 {
+		consumes = c.doc.Consumes
+	}
+	produces := op.Produces
+	if len(produces) == 0 {
+		produces = c.doc.Produces
+	}
+
+	// Operation parameters override path item parameters with the same
+	// location and name.
+	overridden := map[string]bool{}
+	var body *indexedParameter
+	var form []indexedParameter
+	bodyParams := func(ip indexedParameter) bool {
+		switch {
+		case c.isBody(ip.param):
+			body = &ip
+		case ip.param.In == "formData":
+			form = append(form, ip)
+		default:
+			return false
+		}
+		return true
+	}
+	for i := range op.Parameters {
+		p := c.resolveFormParameter(&op.Parameters[i])
+		ip := indexedParameter{pointer: pointer + "/parameters/" + strconv.Itoa(i), param: p}
+		overridden[p.In+" "+p.Name] = true
+		if !bodyParams(ip) {
+			ret.Parameters = append(ret.Parameters, c.parameter(p, ip.pointer))
+		}
+	}
+	for _, ip := range shared {
+		if !overridden[ip.param.In+" "+ip.param.Name] && (body == nil || !c.isBody(ip.param)) {
+			bodyParams(ip)
+		}
+	}
+
+	switch {
+	case body != nil && len(form) > 0:
+		c.losses.add(body.pointer, "body and formData parameters can't be used together, the formData parameters were dropped")
+		fallthrough
+	case body != nil:
+		ret.RequestBody = c.requestBody(body.param, consumes, body.pointer)
+	case len(form) > 0:
+		sort.SliceStable(form, func(i, j int) bool { return form[i].param.Name < form[j].param.Name })
+		ret.RequestBody = c.formBody(form, consumes)
+	}
+
+	if op.Responses != nil {
+		ret.Responses = &spec3.Responses{}
+		ret.Responses.Extensions = op.Responses.Extensions
+		if op.Responses.Default != nil {
+			ret.Responses.Default = c.response(op.Responses.Default, produces, pointer+"/responses/default")
+		}
+		for code, r := range op.Responses.StatusCodeResponses {
+			if ret.Responses.StatusCodeResponses == nil {
+				ret.Responses.StatusCodeResponses = map[string]*spec3.Response{}
+			}
+			r := r
+			ret.Responses.StatusCodeResponses[strconv.Itoa(code)] = c.response(&r, produces, pointer+"/responses/"+strconv.Itoa(code))
+		}
+	}
+	return ret
+}
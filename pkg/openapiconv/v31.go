@@ -0,0 +1,295 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapiconv
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-openapi/jsonpointer"
+
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// V31Version is the OpenAPI version of the documents produced by
+// ConvertToV31.
+const V31Version = "3.1.0"
+
+// ConvertToV31 converts an OpenAPI 3.0 document to OpenAPI 3.1, see
+// ConvertToV31WithReport.
+func ConvertToV31(doc *spec3.OpenAPI) (*spec3.OpenAPI, error) {
+	o, _, err := ConvertToV31WithReport(doc)
+	return o, err
+}
+
+// ConvertToV31WithReport converts an OpenAPI 3.0 document to OpenAPI 3.1,
+// whose schemas are JSON Schema 2020-12 schemas. The source document is not
+// modified. Nothing is lost, the report is there for symmetry with the
+// other conversions.
+//
+// Nullable becomes a "null" type, exclusiveMaximum and exclusiveMinimum
+// become the numeric bounds themselves and the example of a schema becomes
+// its examples.
+func ConvertToV31WithReport(doc *spec3.OpenAPI) (*spec3.OpenAPI, []Loss, error) {
+	o, err := copyDocument(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	o.Version = V31Version
+	walkDocumentSchemas(o, func(s *spec.Schema, _ string) {
+		if s.Nullable {
+			if len(s.Type) > 0 && !s.Type.Contains("null") {
+				s.Type = append(s.Type, "null")
+			}
+			s.Nullable = false
+		}
+		if s.ExclusiveMaximum && s.Maximum != nil {
+			setExtraProp(s, "exclusiveMaximum", *s.Maximum)
+			s.Maximum, s.ExclusiveMaximum = nil, false
+		}
+		if s.ExclusiveMinimum && s.Minimum != nil {
+			setExtraProp(s, "exclusiveMinimum", *s.Minimum)
+			s.Minimum, s.ExclusiveMinimum = nil, false
+		}
+		if s.Example != nil {
+			if _, ok := s.ExtraProps["examples"]; !ok {
+				setExtraProp(s, "examples", []interface{}{s.Example})
+			}
+			s.Example = nil
+		}
+	})
+	return o, nil, nil
+}
+
+// ConvertToV30 converts an OpenAPI 3.1 document to OpenAPI 3.0, see
+// ConvertToV30WithReport.
+func ConvertToV30(doc *spec3.OpenAPI) (*spec3.OpenAPI, error) {
+	o, _, err := ConvertToV30WithReport(doc)
+	return o, err
+}
+
+// ConvertToV30WithReport converts an OpenAPI 3.1 document to OpenAPI 3.0 and
+// reports what couldn't be converted exactly. The source document is not
+// modified.
+//
+// The mapping is the reverse of ConvertToV31WithReport. Besides, schemas
+// with several types other than "null" become an anyOf of single typed
+// schemas, const becomes a single valued enum and only the first of the
+// examples of a schema is kept. Webhooks, the path items of the components
+// and the JSON Schema dialect are dropped.
+func ConvertToV30WithReport(doc *spec3.OpenAPI) (*spec3.OpenAPI, []Loss, error) {
+	o, err := copyDocument(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	var l losses
+	o.Version = V3Version
+	if len(o.Webhooks) > 0 {
+		l.add("/webhooks", "webhooks were dropped")
+		o.Webhooks = nil
+	}
+	if o.Components != nil && len(o.Components.PathItems) > 0 {
+		l.add("/components/pathItems", "path items were dropped")
+		o.Components.PathItems = nil
+	}
+	if o.JSONSchemaDialect != "" {
+		l.add("/jsonSchemaDialect", "the JSON Schema dialect was dropped")
+		o.JSONSchemaDialect = ""
+	}
+	walkDocumentSchemas(o, func(s *spec.Schema, pointer string) {
+		if s.Type.Contains("null") {
+			s.Nullable = true
+			var types spec.StringOrArray
+			for _, t := range s.Type {
+				if t != "null" {
+					types = append(types, t)
+				}
+			}
+			s.Type = types
+		}
+		if len(s.Type) > 1 {
+			for _, t := range s.Type {
+				s.AnyOf = append(s.AnyOf, spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{t}}})
+			}
+			s.Type = nil
+		}
+		if c, ok := s.ExtraProps["const"]; ok {
+			if s.Enum == nil {
+				s.Enum = []interface{}{c}
+			}
+			delete(s.ExtraProps, "const")
+		}
+		if examples, ok := s.ExtraProps["examples"].([]interface{}); ok {
+			if len(examples) > 0 && s.Example == nil {
+				s.Example = examples[0]
+			}
+			if len(examples) > 1 {
+				l.add(pointer+"/examples", "only the first of %d examples was kept", len(examples))
+			}
+			delete(s.ExtraProps, "examples")
+		}
+		if len(s.ExtraProps) == 0 {
+			s.ExtraProps = nil
+		}
+	})
+	return o, l.sorted(), nil
+}
+
+func setExtraProp(s *spec.Schema, key string, value interface{}) {
+	if s.ExtraProps == nil {
+		s.ExtraProps = map[string]interface{}{}
+	}
+	s.ExtraProps[key] = value
+}
+
+// copyDocument deep copies a document through its JSON serialization, which
+// also turns numeric exclusive bounds into flags, see spec.Schema.
+func copyDocument(doc *spec3.OpenAPI) (*spec3.OpenAPI, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	o := &spec3.OpenAPI{}
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, fmt.Errorf("failed to copy the document: %v", err)
+	}
+	return o, nil
+}
+
+// walkDocumentSchemas calls fn on every schema of a document and on their
+// subschemas, keeping the changes fn makes, see spec.RewriteSchema.
+// Referenced objects are walked where they are defined.
+func walkDocumentSchemas(o *spec3.OpenAPI, fn func(s *spec.Schema, pointer string)) {
+	w := documentWalker{fn: fn}
+	if c := o.Components; c != nil {
+		for name, s := range c.Schemas {
+			spec.RewriteSchema(s, "/components/schemas/"+jsonpointer.Escape(name), fn)
+		}
+		for name, p := range c.Parameters {
+			w.parameter(p, "/components/parameters/"+jsonpointer.Escape(name))
+		}
+		for name, rb := range c.RequestBodies {
+			if rb != nil {
+				w.content(rb.Content, "/components/requestBodies/"+jsonpointer.Escape(name)+"/content")
+			}
+		}
+		for name, r := range c.Responses {
+			w.response(r, "/components/responses/"+jsonpointer.Escape(name))
+		}
+		for name, h := range c.Headers {
+			w.header(h, "/components/headers/"+jsonpointer.Escape(name))
+		}
+		for name, cb := range c.Callbacks {
+			w.callback(cb, "/components/callbacks/"+jsonpointer.Escape(name))
+		}
+		for name, p := range c.PathItems {
+			w.path(p, "/components/pathItems/"+jsonpointer.Escape(name))
+		}
+	}
+	if o.Paths != nil {
+		for name, p := range o.Paths.Paths {
+			w.path(p, "/paths/"+jsonpointer.Escape(name))
+		}
+	}
+	for name, p := range o.Webhooks {
+		w.path(p, "/webhooks/"+jsonpointer.Escape(name))
+	}
+}
+
+type documentWalker struct {
+	fn func(s *spec.Schema, pointer string)
+}
+
+func (w documentWalker) path(p *spec3.Path, pointer string) {
+	if p == nil {
+		return
+	}
+	for i, param := range p.Parameters {
+		w.parameter(param, fmt.Sprintf("%s/parameters/%d", pointer, i))
+	}
+	for method, op := range p.Operations() {
+		opPointer := pointer + "/" + method
+		for i, param := range op.Parameters {
+			w.parameter(param, fmt.Sprintf("%s/parameters/%d", opPointer, i))
+		}
+		if op.RequestBody != nil {
+			w.content(op.RequestBody.Content, opPointer+"/requestBody/content")
+		}
+		if op.Responses != nil {
+			w.response(op.Responses.Default, opPointer+"/responses/default")
+			for code, r := range op.Responses.StatusCodeResponses {
+				w.response(r, opPointer+"/responses/"+code)
+			}
+		}
+		for name, cb := range op.Callbacks {
+			w.callback(cb, opPointer+"/callbacks/"+jsonpointer.Escape(name))
+		}
+	}
+}
+
+func (w documentWalker) callback(cb *spec3.Callback, pointer string) {
+	if cb == nil {
+		return
+	}
+	for expr, p := range cb.Expressions {
+		w.path(p, pointer+"/"+jsonpointer.Escape(expr))
+	}
+}
+
+func (w documentWalker) parameter(p *spec3.Parameter, pointer string) {
+	if p == nil {
+		return
+	}
+	spec.RewriteSchema(p.Schema, pointer+"/schema", w.fn)
+	w.content(p.Content, pointer+"/content")
+}
+
+func (w documentWalker) header(h *spec3.Header, pointer string) {
+	if h == nil {
+		return
+	}
+	spec.RewriteSchema(h.Schema, pointer+"/schema", w.fn)
+	w.content(h.Content, pointer+"/content")
+}
+
+func (w documentWalker) response(r *spec3.Response, pointer string) {
+	if r == nil {
+		return
+	}
+	for name, h := range r.Headers {
+		w.header(h, pointer+"/headers/"+jsonpointer.Escape(name))
+	}
+	w.content(r.Content, pointer+"/content")
+}
+
+func (w documentWalker) content(content map[string]*spec3.MediaType, pointer string) {
+	for mediaType, mt := range content {
+		if mt == nil {
+			continue
+		}
+		spec.RewriteSchema(mt.Schema, pointer+"/"+jsonpointer.Escape(mediaType)+"/schema", w.fn)
+		for name, enc := range mt.Encoding {
+			if enc == nil {
+				continue
+			}
+			for hname, h := range enc.Headers {
+				w.header(h, pointer+"/"+jsonpointer.Escape(mediaType)+"/encoding/"+jsonpointer.Escape(name)+"/headers/"+jsonpointer.Escape(hname))
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapiconv
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/spec3"
+)
+
+const v30Doc = `{
+  "openapi": "3.0.3",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "minimum": 0, "exclusiveMinimum": true}}],
+        "responses": {
+          "200": {
+            "description": "ok",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string", "nullable": true, "example": "rex"},
+          "age": {"type": "integer", "maximum": 30, "exclusiveMaximum": true}
+        }
+      }
+    }
+  }
+}`
+
+func TestConvertToV31(t *testing.T) {
+	var doc spec3.OpenAPI
+	require.NoError(t, json.Unmarshal([]byte(v30Doc), &doc))
+	o, losses, err := ConvertToV31WithReport(&doc)
+	require.NoError(t, err)
+	assert.Empty(t, losses)
+	assert.True(t, o.Is31())
+
+	data, err := json.Marshal(o.Components.Schemas["Pet"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "type": "object",
+  "properties": {
+    "name": {"type": ["string", "null"], "examples": ["rex"]},
+    "age": {"type": "integer", "exclusiveMaximum": 30}
+  }
+}`, string(data))
+	data, err = json.Marshal(o.Paths.Paths["/pets"].Get.Parameters[0].Schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "integer", "exclusiveMinimum": 0}`, string(data))
+
+	// The source document is not modified.
+	assert.Equal(t, "3.0.3", doc.Version)
+	assert.True(t, doc.Components.Schemas["Pet"].Properties["name"].Nullable)
+
+	// Converting back gives the original document.
+	back, losses, err := ConvertToV30WithReport(o)
+	require.NoError(t, err)
+	assert.Empty(t, losses)
+	data, err = json.Marshal(back)
+	require.NoError(t, err)
+	assert.JSONEq(t, v30Doc, string(data))
+}
+
+func TestConvertToV30(t *testing.T) {
+	var doc spec3.OpenAPI
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "openapi": "3.1.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "jsonSchemaDialect": "https://json-schema.org/draft/2020-12/schema",
+  "webhooks": {"newPet": {"post": {"responses": {"200": {"description": "ok"}}}}},
+  "components": {
+    "schemas": {
+      "Id": {"type": ["string", "integer", "null"], "examples": [1, "a"]},
+      "Kind": {"const": "pet"}
+    }
+  }
+}`), &doc))
+	o, losses, err := ConvertToV30WithReport(&doc)
+	require.NoError(t, err)
+	assert.Equal(t, V3Version, o.Version)
+	assert.Empty(t, o.Webhooks)
+	assert.Empty(t, o.JSONSchemaDialect)
+	assert.Equal(t, []Loss{
+		{Path: "/components/schemas/Id/examples", Message: "only the first of 2 examples was kept"},
+		{Path: "/jsonSchemaDialect", Message: "the JSON Schema dialect was dropped"},
+		{Path: "/webhooks", Message: "webhooks were dropped"},
+	}, losses)
+
+	data, err := json.Marshal(o.Components.Schemas)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "Id": {"anyOf": [{"type": "string"}, {"type": "integer"}], "nullable": true, "example": 1},
+  "Kind": {"enum": ["pet"]}
+}`, string(data))
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapiconv
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const swaggerDoc = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "schemes": ["https"],
+  "host": "pets.example.com",
+  "basePath": "/v1",
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer", "maximum": 100},
+    "pet": {"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}},
+    "note": {"name": "note", "in": "formData", "type": "string"}
+  },
+  "responses": {
+    "Error": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}
+  },
+  "securityDefinitions": {
+    "basic": {"type": "basic"},
+    "oauth": {"type": "oauth2", "flow": "application", "tokenUrl": "https://pets.example.com/token", "scopes": {"read": "read pets"}}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "parameters": [
+          {"$ref": "#/parameters/limit"},
+          {"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"},
+          {"name": "ids", "in": "query", "type": "array", "items": {"type": "integer"}, "collectionFormat": "tsv"}
+        ],
+        "responses": {
+          "200": {
+            "description": "ok",
+            "headers": {"X-Total": {"type": "integer"}},
+            "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}},
+            "examples": {"application/json": [{"name": "tom"}]}
+          },
+          "default": {"$ref": "#/responses/Error"}
+        }
+      },
+      "post": {
+        "operationId": "createPet",
+        "parameters": [{"$ref": "#/parameters/pet"}],
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/pets/{id}/photo": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "type": "string"}],
+      "put": {
+        "consumes": ["multipart/form-data"],
+        "parameters": [
+          {"name": "file", "in": "formData", "type": "file", "required": true},
+          {"$ref": "#/parameters/note"}
+        ],
+        "security": [{"oauth": ["read"]}],
+        "responses": {"204": {"description": "stored"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "discriminator": "kind",
+      "required": ["name", "kind"],
+      "properties": {
+        "name": {"type": "string"},
+        "kind": {"type": "string"},
+        "owner": {"$ref": "#/definitions/Owner"},
+        "nick": {"type": "string", "x-nullable": true}
+      }
+    },
+    "Owner": {"type": "string"},
+    "Error": {"type": "object", "properties": {"message": {"type": "string"}}}
+  }
+}`
+
+const openAPIDoc = `{
+  "openapi": "3.0.3",
+  "info": {"title": "pets", "version": "1.0"},
+  "servers": [{"url": "https://pets.example.com/v1"}],
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "parameters": [
+          {"$ref": "#/components/parameters/limit"},
+          {"name": "tags", "in": "query", "style": "form", "explode": true, "schema": {"type": "array", "items": {"type": "string"}}},
+          {"name": "ids", "in": "query", "style": "form", "explode": false, "schema": {"type": "array", "items": {"type": "integer"}}}
+        ],
+        "responses": {
+          "200": {
+            "description": "ok",
+            "headers": {"X-Total": {"schema": {"type": "integer"}}},
+            "content": {"application/json": {
+              "schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}},
+              "example": [{"name": "tom"}]
+            }}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "operationId": "createPet",
+        "requestBody": {"$ref": "#/components/requestBodies/pet"},
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/pets/{id}/photo": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+      "put": {
+        "requestBody": {
+          "required": true,
+          "content": {"multipart/form-data": {"schema": {
+            "type": "object",
+            "required": ["file"],
+            "properties": {"file": {"type": "string", "format": "binary"}, "note": {"type": "string"}}
+          }}}
+        },
+        "security": [{"oauth": ["read"]}],
+        "responses": {"204": {"description": "stored"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "discriminator": {"propertyName": "kind"},
+        "required": ["name", "kind"],
+        "properties": {
+          "name": {"type": "string"},
+          "kind": {"type": "string"},
+          "owner": {"$ref": "#/components/schemas/Owner"},
+          "nick": {"type": "string", "nullable": true}
+        }
+      },
+      "Owner": {"type": "string"},
+      "Error": {"type": "object", "properties": {"message": {"type": "string"}}}
+    },
+    "responses": {
+      "Error": {"description": "error", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+    },
+    "parameters": {
+      "limit": {"name": "limit", "in": "query", "schema": {"type": "integer", "maximum": 100}}
+    },
+    "requestBodies": {
+      "pet": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}
+    },
+    "securitySchemes": {
+      "basic": {"type": "http", "scheme": "basic"},
+      "oauth": {"type": "oauth2", "flows": {"clientCredentials": {"tokenUrl": "https://pets.example.com/token", "scopes": {"read": "read pets"}}}}
+    }
+  }
+}`
+
+func loadSwagger(t *testing.T, data string) *spec.Swagger {
+	s := &spec.Swagger{}
+	require.NoError(t, json.Unmarshal([]byte(data), s))
+	return s
+}
+
+func TestConvertToV3(t *testing.T) {
+	doc := loadSwagger(t, swaggerDoc)
+	before, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	o, losses, err := ConvertToV3WithReport(doc)
+	require.NoError(t, err)
+	data, err := json.Marshal(o)
+	require.NoError(t, err)
+	assert.JSONEq(t, openAPIDoc, string(data))
+	assert.Equal(t, []Loss{
+		{Path: "/paths/~1pets/get/parameters/2", Message: "collectionFormat tsv has no equivalent in query, it was replaced by csv"},
+	}, losses)
+
+	after, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(before), string(after), "the source document must not change")
+}
+
+func TestConvertToV3Servers(t *testing.T) {
+	assert.Nil(t, servers(nil, "", ""))
+	var urls []string
+	for _, s := range servers([]string{"http", "https"}, "example.com", "/api") {
+		urls = append(urls, s.URL)
+	}
+	assert.Equal(t, []string{"http://example.com/api", "https://example.com/api"}, urls)
+	assert.Equal(t, "//example.com", servers(nil, "example.com", "")[0].URL)
+	assert.Equal(t, "/api", servers([]string{"https"}, "", "/api")[0].URL)
+}
+
+func TestConvertToV3Losses(t *testing.T) {
+	doc := loadSwagger(t, `{
+	  "swagger": "2.0",
+	  "securityDefinitions": {"custom": {"type": "custom"}},
+	  "paths": {"/a": {"post": {
+	    "schemes": ["https"],
+	    "parameters": [
+	      {"name": "b", "in": "body", "schema": {"$ref": "#/parameters/x"}},
+	      {"name": "f", "in": "formData", "type": "string", "allowEmptyValue": true}
+	    ],
+	    "responses": {"200": {"description": "ok", "headers": {"X": {"type": "array", "items": {"type": "string"}, "collectionFormat": "multi"}}}}
+	  }}}
+	}`)
+	_, losses, err := ConvertToV3WithReport(doc)
+	require.NoError(t, err)
+	assert.Equal(t, []Loss{
+		{Path: "/paths/~1a/post/parameters/0", Message: "body and formData parameters can't be used together, the formData parameters were dropped"},
+		{Path: "/paths/~1a/post/parameters/0/schema", Message: "reference #/parameters/x is not to a definition and was kept as is"},
+		{Path: "/paths/~1a/post/responses/200/headers/X", Message: "collectionFormat multi has no equivalent in header, it was replaced by csv"},
+		{Path: "/paths/~1a/post/schemes", Message: "schemes can't be expressed without a host"},
+		{Path: "/securityDefinitions/custom", Message: `unknown security scheme type "custom" was kept as is`},
+	}, losses)
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// operation is an RFC 6902 JSON patch operation.
+type operation struct {
+	op       string
+	path     string
+	from     string
+	value    interface{}
+	hasValue bool
+}
+
+func (o *operation) String() string {
+	if o.from != "" {
+		return fmt.Sprintf("%s %s to %s", o.op, o.from, o.path)
+	}
+	return o.op + " " + o.path
+}
+
+// JSONPatch applies an RFC 6902 JSON patch to a JSON document. Operations
+// are applied in order and the first failing one is returned as an *Error.
+func JSONPatch(original, patch []byte) ([]byte, error) {
+	doc, err := decodeJSON(original, "original document")
+	if err != nil {
+		return nil, err
+	}
+	ops, err := decodeJSONPatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range ops {
+		if doc, err = applyOperation(doc, i, op); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(doc)
+}
+
+func decodeJSONPatch(patch []byte) ([]*operation, error) {
+	v, err := decodeJSON(patch, "patch")
+	if err != nil {
+		return nil, err
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid patch: expected a JSON array of operations")
+	}
+	ops := make([]*operation, 0, len(list))
+	for i, e := range list {
+		obj, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid patch: operation %d is not an object", i)
+		}
+		op := &operation{}
+		op.op, _ = obj["op"].(string)
+		path, ok := obj["path"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid patch: operation %d has no path", i)
+		}
+		op.path = path
+		op.value, op.hasValue = obj["value"]
+		switch op.op {
+		case "add", "replace", "test":
+			if !op.hasValue {
+				return nil, fmt.Errorf("invalid patch: %s operation %d has no value", op.op, i)
+			}
+		case "move", "copy":
+			if op.from, ok = obj["from"].(string); !ok {
+				return nil, fmt.Errorf("invalid patch: %s operation %d has no from", op.op, i)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("invalid patch: unknown op %q in operation %d", op.op, i)
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+func applyOperation(doc interface{}, i int, op *operation) (interface{}, error) {
+	fail := func(err error) (interface{}, error) {
+		return nil, &Error{Path: op.path, Reason: fmt.Sprintf("operation %d (%s): %v", i, op.op, err)}
+	}
+	path, err := parsePointer(op.path)
+	if err != nil {
+		return fail(err)
+	}
+	switch op.op {
+	case "add":
+		doc, err = add(doc, path, copyJSON(op.value))
+	case "remove":
+		doc, _, err = remove(doc, path)
+	case "replace":
+		if doc, _, err = remove(doc, path); err == nil {
+			doc, err = add(doc, path, copyJSON(op.value))
+		}
+	case "move":
+		var from []string
+		if from, err = parsePointer(op.from); err != nil {
+			break
+		}
+		if len(path) > len(from) && reflect.DeepEqual(path[:len(from)], from) {
+			err = fmt.Errorf("can't move %s into one of its children", op.from)
+			break
+		}
+		var v interface{}
+		if doc, v, err = remove(doc, from); err == nil {
+			doc, err = add(doc, path, v)
+		}
+	case "copy":
+		var from []string
+		if from, err = parsePointer(op.from); err != nil {
+			break
+		}
+		var v interface{}
+		if v, err = get(doc, from); err == nil {
+			doc, err = add(doc, path, copyJSON(v))
+		}
+	case "test":
+		var v interface{}
+		if v, err = get(doc, path); err == nil && !jsonEqual(v, op.value) {
+			err = fmt.Errorf("value differs")
+		}
+	}
+	if err != nil {
+		return fail(err)
+	}
+	return doc, nil
+}
+
+// parsePointer splits an RFC 6901 JSON pointer into its unescaped tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(t, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses an array index token. The "-" token refers to the end
+// of the array and is only accepted when allowEnd is set.
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if token == "-" && allowEnd {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	max := length - 1
+	if allowEnd {
+		max = length
+	}
+	if i > max {
+		return 0, fmt.Errorf("array index %d out of bounds", i)
+	}
+	return i, nil
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			v, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("missing field %q", token)
+			}
+			doc = v
+		case []interface{}:
+			i, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("can't look up %q in %s", token, typeName(doc))
+		}
+	}
+	return doc, nil
+}
+
+// update applies f to the container holding the last token of path and
+// returns the document with the container f returns.
+func update(doc interface{}, path []string, f func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return f(doc, path[0])
+	}
+	child, err := get(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	if child, err = update(child, path[1:], f); err != nil {
+		return nil, err
+	}
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		node[path[0]] = child
+	case []interface{}:
+		i, _ := arrayIndex(path[0], len(node), false)
+		node[i] = child
+	}
+	return doc, nil
+}
+
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch node := container.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(node), true)
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("can't add %q to %s", token, typeName(container))
+	})
+}
+
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+	var removed interface{}
+	doc, err := update(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch node := container.(type) {
+		case map[string]interface{}:
+			v, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("missing field %q", token)
+			}
+			removed = v
+			delete(node, token)
+			return node, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[i]
+			return append(node[:i:i], node[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("can't remove %q from %s", token, typeName(container))
+	})
+	return doc, removed, err
+}
+
+// copyJSON deep copies a decoded JSON value, so that patched documents
+// don't share values with the patch or with each other.
+func copyJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			ret[k] = copyJSON(e)
+		}
+		return ret
+	case []interface{}:
+		ret := make([]interface{}, len(v))
+		for i, e := range v {
+			ret[i] = copyJSON(e)
+		}
+		return ret
+	}
+	return v
+}
+
+// jsonEqual compares decoded JSON values, numbers by their value.
+func jsonEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		fa, errA := a.Float64()
+		fb, errB := b.Float64()
+		return errA == nil && errB == nil && fa == fb
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if w, ok := b[k]; !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPatch(t *testing.T) {
+	for _, tc := range []struct {
+		original, patch, expected string
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"foo":"bar","baz":"qux"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc"]}]`, `{"foo":["bar",["abc"]]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"foo":{"a":[1]}}`, `[{"op":"copy","from":"/foo","path":"/bar"},{"op":"add","path":"/bar/a/-","value":2}]`, `{"foo":{"a":[1]},"bar":{"a":[1,2]}}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10},{"op":"remove","path":"/~1"}]`, `{"~1":10}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":null}]`, `{"foo":"bar","baz":null}`},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+	} {
+		got, err := JSONPatch([]byte(tc.original), []byte(tc.patch))
+		require.NoError(t, err, tc.patch)
+		assert.JSONEq(t, tc.expected, string(got), tc.patch)
+	}
+}
+
+func TestJSONPatchErrors(t *testing.T) {
+	for patch, expected := range map[string]string{
+		`{"op":"add"}`:                              "invalid patch: expected a JSON array of operations",
+		`[{"op":"add","path":"/a"}]`:                "invalid patch: add operation 0 has no value",
+		`[{"op":"move","path":"/a"}]`:               "invalid patch: move operation 0 has no from",
+		`[{"op":"update","path":"/a"}]`:             `invalid patch: unknown op "update" in operation 0`,
+		`[{"op":"remove"}]`:                         "invalid patch: operation 0 has no path",
+		`[{"op":"remove","path":"/baz"}]`:           `/baz: operation 0 (remove): missing field "baz"`,
+		`[{"op":"add","path":"/foo/5","value":1}]`:  "/foo/5: operation 0 (add): array index 5 out of bounds",
+		`[{"op":"add","path":"/foo/01","value":1}]`: `/foo/01: operation 0 (add): invalid array index "01"`,
+		`[{"op":"test","path":"/a/b","value":1}]`:   `/a/b: operation 0 (test): missing field "a"`,
+		`[{"op":"remove","path":"/foo/0"},{"op":"test","path":"/foo/0","value":"x"}]`: "/foo/0: operation 1 (test): value differs",
+		`[{"op":"move","from":"/foo","path":"/foo/0"}]`:                               "/foo/0: operation 0 (move): can't move /foo into one of its children",
+		`[{"op":"add","path":"foo","value":1}]`:                                       `foo: operation 0 (add): invalid JSON pointer "foo"`,
+	} {
+		_, err := JSONPatch([]byte(`{"foo":["bar","baz"]}`), []byte(patch))
+		assert.EqualError(t, err, expected, patch)
+	}
+}
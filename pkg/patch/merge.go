@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"encoding/json"
+
+	"github.com/go-openapi/jsonpointer"
+)
+
+// MergePatch applies an RFC 7386 JSON merge patch to a JSON document.
+func MergePatch(original, patch []byte) ([]byte, error) {
+	o, err := decodeJSON(original, "original document")
+	if err != nil {
+		return nil, err
+	}
+	p, err := decodeJSON(patch, "patch")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(o, p))
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, _ := target.(map[string]interface{})
+	result := make(map[string]interface{}, len(t)+len(p))
+	for k, v := range t {
+		result[k] = v
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(result, k)
+		} else {
+			result[k] = mergePatch(result[k], v)
+		}
+	}
+	return result
+}
+
+// mergePatchMember is a member of a merge patch: pointer locates it and
+// patch is the equivalent merge patch changing only that member.
+type mergePatchMember struct {
+	pointer string
+	patch   interface{}
+}
+
+// mergePatchMembers splits a merge patch into patches of its members,
+// sorted by pointer. Applying them in sequence is equivalent to applying
+// the whole patch.
+func mergePatchMembers(patch interface{}) []mergePatchMember {
+	var ret []mergePatchMember
+	var walk func(pointer string, v interface{}, wrap func(interface{}) interface{})
+	walk = func(pointer string, v interface{}, wrap func(interface{}) interface{}) {
+		obj, ok := v.(map[string]interface{})
+		if !ok || len(obj) == 0 {
+			ret = append(ret, mergePatchMember{pointer: pointer, patch: wrap(v)})
+			return
+		}
+		for _, k := range sortedKeys(obj) {
+			k := k
+			walk(pointer+"/"+jsonpointer.Escape(k), obj[k], func(child interface{}) interface{} {
+				return wrap(map[string]interface{}{k: child})
+			})
+		}
+	}
+	walk("", patch, func(v interface{}) interface{} { return v })
+	return ret
+}
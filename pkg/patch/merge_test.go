@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatch(t *testing.T) {
+	// Examples of RFC 7386, appendix A.
+	for _, tc := range []struct {
+		original, patch, expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	} {
+		got, err := MergePatch([]byte(tc.original), []byte(tc.patch))
+		require.NoError(t, err)
+		assert.JSONEq(t, tc.expected, string(got), "%s patched with %s", tc.original, tc.patch)
+	}
+
+	_, err := MergePatch([]byte(`{}`), []byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestMergePatchMembers(t *testing.T) {
+	var pointers []string
+	patch := map[string]interface{}{
+		"a": map[string]interface{}{"b": "c", "d~/": nil, "e": map[string]interface{}{}},
+		"f": []interface{}{1},
+	}
+	doc := interface{}(map[string]interface{}{"a": map[string]interface{}{"d~/": 1, "x": 2}})
+	for _, m := range mergePatchMembers(patch) {
+		pointers = append(pointers, m.pointer)
+		doc = mergePatch(doc, m.patch)
+	}
+	assert.Equal(t, []string{"/a/b", "/a/d~0~1", "/a/e", "/f"}, pointers)
+	assert.Equal(t, mergePatch(map[string]interface{}{"a": map[string]interface{}{"d~/": 1, "x": 2}}, patch), doc)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package patch applies patches to JSON documents described by a spec
+// schema. Strategic merge patches take their list semantics from the
+// x-kubernetes-patch-strategy and x-kubernetes-patch-merge-key extensions
+// of the schema. JSON merge patches (RFC 7386) and JSON patches (RFC 6902)
+// can be applied and validated against the schema in one call.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	definitionPrefix = "#/definitions/"
+
+	// maxRefDepth bounds the number of references followed to resolve a
+	// schema, so that reference cycles can't loop forever.
+	maxRefDepth = 32
+)
+
+// Error is returned when a patch cannot be applied. For strategic merge
+// patches, Path is the field path of the offending value, e.g.
+// "spec.containers[1].name", and is empty for the document root. For JSON
+// patches, it is the path of the failed operation.
+type Error struct {
+	Path   string
+	Reason string
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return e.Reason
+	}
+	return e.Path + ": " + e.Reason
+}
+
+func fieldPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func indexPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+// schemas navigates a schema, resolving references to definitions. A nil
+// schema stands for an unknown one.
+type schemas struct {
+	definitions spec.Definitions
+}
+
+func (c schemas) resolve(s *spec.Schema) *spec.Schema {
+	for i := 0; s != nil && i < maxRefDepth; i++ {
+		ref := s.Ref.String()
+		if ref == "" {
+			return s
+		}
+		if !strings.HasPrefix(ref, definitionPrefix) {
+			return nil
+		}
+		def, ok := c.definitions[ref[len(definitionPrefix):]]
+		if !ok {
+			return nil
+		}
+		s = &def
+	}
+	return nil
+}
+
+func (c schemas) property(s *spec.Schema, name string) *spec.Schema {
+	s = c.resolve(s)
+	if s == nil {
+		return nil
+	}
+	if prop, ok := s.Properties[name]; ok {
+		return &prop
+	}
+	if s.AdditionalProperties != nil {
+		return s.AdditionalProperties.Schema
+	}
+	return nil
+}
+
+func (c schemas) items(s *spec.Schema) *spec.Schema {
+	s = c.resolve(s)
+	if s == nil || s.Items == nil {
+		return nil
+	}
+	return s.Items.Schema
+}
+
+// extension returns a string extension of a schema, looking through
+// references when the referencing schema doesn't set it.
+func (c schemas) extension(s *spec.Schema, name string) string {
+	if s == nil {
+		return ""
+	}
+	if v, ok := s.Extensions.GetString(name); ok {
+		return v
+	}
+	if r := c.resolve(s); r != nil {
+		if v, ok := r.Extensions.GetString(name); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// decodeJSON decodes a JSON document, keeping numbers as json.Number so
+// that they round trip unchanged.
+func decodeJSON(data []byte, what string) (interface{}, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", what, err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("invalid %s: unexpected data after the JSON document", what)
+	}
+	return v, nil
+}
+
+// decodeObject decodes a JSON object. An empty or null document decodes to
+// an empty object.
+func decodeObject(data []byte, what string) (map[string]interface{}, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return map[string]interface{}{}, nil
+	}
+	v, err := decodeJSON(data, what)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid %s: expected a JSON object", what)
+	}
+	return obj, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+	return "number"
+}
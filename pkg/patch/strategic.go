@@ -0,0 +1,330 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	patchStrategyExtension = "x-kubernetes-patch-strategy"
+	patchMergeKeyExtension = "x-kubernetes-patch-merge-key"
+
+	directiveMarker            = "$patch"
+	retainKeysDirective        = "$retainKeys"
+	deleteFromPrimitiveListKey = "$deleteFromPrimitiveList/"
+	setElementOrderKey         = "$setElementOrder/"
+)
+
+// StrategicMergePatch applies a strategic merge patch to a JSON object
+// described by schema, resolving references to the given definitions.
+//
+// Objects are merged recursively and null values delete fields. Lists whose
+// schema has the "merge" patch strategy are merged: lists of objects by
+// the value of their x-kubernetes-patch-merge-key field, lists of
+// primitives as sets. Other lists are replaced. The $patch (replace,
+// merge, delete), $retainKeys, $deleteFromPrimitiveList and
+// $setElementOrder directives are honored.
+//
+// Values contradicting the schema or the patch strategy, e.g. an object
+// patching a list or a list element without its merge key, are returned as
+// an *Error identifying the field path.
+func StrategicMergePatch(original, patch []byte, schema *spec.Schema, definitions spec.Definitions) ([]byte, error) {
+	o, err := decodeObject(original, "original document")
+	if err != nil {
+		return nil, err
+	}
+	p, err := decodeObject(patch, "patch")
+	if err != nil {
+		return nil, err
+	}
+	result, err := strategicMerger{schemas{definitions}}.mergeMap(o, p, schema, "")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(result)
+}
+
+type strategicMerger struct {
+	schemas
+}
+
+// mergeValue merges a patch value into an original value. It returns false
+// if the value must be deleted.
+func (m strategicMerger) mergeValue(original, patch interface{}, s *spec.Schema, path string) (interface{}, bool, error) {
+	switch p := patch.(type) {
+	case nil:
+		return nil, false, nil
+	case map[string]interface{}:
+		if p[directiveMarker] == "delete" {
+			return nil, false, nil
+		}
+		if err := m.checkType(s, p, path); err != nil {
+			return nil, false, err
+		}
+		o, _ := original.(map[string]interface{})
+		merged, err := m.mergeMap(o, p, s, path)
+		return merged, true, err
+	case []interface{}:
+		if err := m.checkType(s, p, path); err != nil {
+			return nil, false, err
+		}
+		o, _ := original.([]interface{})
+		merged, err := m.mergeList(o, p, s, path)
+		return merged, true, err
+	}
+	if err := m.checkType(s, patch, path); err != nil {
+		return nil, false, err
+	}
+	return patch, true, nil
+}
+
+// checkType rejects patch values of a different JSON type than the one
+// declared by the schema.
+func (m strategicMerger) checkType(s *spec.Schema, v interface{}, path string) error {
+	s = m.resolve(s)
+	if s == nil || len(s.Type) != 1 {
+		return nil
+	}
+	expected := s.Type[0]
+	if expected == "integer" {
+		expected = "number"
+	}
+	if got := typeName(v); got != expected {
+		return &Error{Path: path, Reason: fmt.Sprintf("expected %s, got %s", expected, got)}
+	}
+	return nil
+}
+
+func (m strategicMerger) mergeMap(original, patch map[string]interface{}, s *spec.Schema, path string) (map[string]interface{}, error) {
+	switch directive := patch[directiveMarker]; directive {
+	case nil, "merge":
+	case "replace":
+		original = nil
+	default:
+		return nil, &Error{Path: path, Reason: fmt.Sprintf("unknown patch directive %v", directive)}
+	}
+
+	result := make(map[string]interface{}, len(original)+len(patch))
+	for k, v := range original {
+		result[k] = v
+	}
+
+	var deletions, orders []string
+	for _, k := range sortedKeys(patch) {
+		switch {
+		case k == directiveMarker:
+		case k == retainKeysDirective:
+		case strings.HasPrefix(k, deleteFromPrimitiveListKey):
+			deletions = append(deletions, k)
+		case strings.HasPrefix(k, setElementOrderKey):
+			orders = append(orders, k)
+		case strings.HasPrefix(k, "$"):
+			return nil, &Error{Path: fieldPath(path, k), Reason: "unknown patch directive"}
+		default:
+			v, keep, err := m.mergeValue(original[k], patch[k], m.property(s, k), fieldPath(path, k))
+			if err != nil {
+				return nil, err
+			}
+			if keep {
+				result[k] = v
+			} else {
+				delete(result, k)
+			}
+		}
+	}
+
+	for _, k := range deletions {
+		field := strings.TrimPrefix(k, deleteFromPrimitiveListKey)
+		values, ok := patch[k].([]interface{})
+		if !ok {
+			return nil, &Error{Path: fieldPath(path, k), Reason: "expected a list of values to delete"}
+		}
+		if list, ok := result[field].([]interface{}); ok {
+			var kept []interface{}
+			for _, v := range list {
+				if indexOf(values, v) < 0 {
+					kept = append(kept, v)
+				}
+			}
+			result[field] = kept
+		}
+	}
+
+	for _, k := range orders {
+		field := strings.TrimPrefix(k, setElementOrderKey)
+		order, ok := patch[k].([]interface{})
+		if !ok {
+			return nil, &Error{Path: fieldPath(path, k), Reason: "expected a list giving the element order"}
+		}
+		if list, ok := result[field].([]interface{}); ok {
+			mergeKey := m.extension(m.property(s, field), patchMergeKeyExtension)
+			result[field] = reorder(list, order, mergeKey)
+		}
+	}
+
+	if retain, ok := patch[retainKeysDirective]; ok {
+		names, ok := retain.([]interface{})
+		if !ok {
+			return nil, &Error{Path: fieldPath(path, retainKeysDirective), Reason: "expected a list of field names"}
+		}
+		for k := range result {
+			if indexOf(names, k) < 0 {
+				delete(result, k)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m strategicMerger) mergeList(original, patch []interface{}, s *spec.Schema, path string) ([]interface{}, error) {
+	items := m.items(s)
+
+	// A {"$patch": "replace"} element replaces the whole list by the other
+	// elements.
+	replace := !hasStrategy(m.extension(s, patchStrategyExtension), "merge")
+	var elements []interface{}
+	for _, e := range patch {
+		if obj, ok := e.(map[string]interface{}); ok && len(obj) == 1 && obj[directiveMarker] == "replace" {
+			replace = true
+			continue
+		}
+		elements = append(elements, e)
+	}
+	if replace {
+		ret := make([]interface{}, 0, len(elements))
+		for i, e := range elements {
+			v, keep, err := m.mergeValue(nil, e, items, indexPath(path, i))
+			if err != nil {
+				return nil, err
+			}
+			if keep {
+				ret = append(ret, v)
+			}
+		}
+		return ret, nil
+	}
+
+	result := append([]interface{}{}, original...)
+	mergeKey := m.extension(s, patchMergeKeyExtension)
+	for i, e := range elements {
+		elemPath := indexPath(path, i)
+		obj, isObject := e.(map[string]interface{})
+		if mergeKey == "" {
+			if isObject {
+				return nil, &Error{Path: elemPath, Reason: "objects can't be merged into a list without " + patchMergeKeyExtension}
+			}
+			if indexOf(result, e) < 0 {
+				result = append(result, e)
+			}
+			continue
+		}
+		if !isObject {
+			return nil, &Error{Path: elemPath, Reason: fmt.Sprintf("expected an object with merge key %q, got %s", mergeKey, typeName(e))}
+		}
+		key, ok := obj[mergeKey]
+		if !ok {
+			return nil, &Error{Path: elemPath, Reason: fmt.Sprintf("missing merge key %q", mergeKey)}
+		}
+		idx := indexOfKey(result, mergeKey, key)
+		if obj[directiveMarker] == "delete" {
+			if idx >= 0 {
+				result = append(result[:idx], result[idx+1:]...)
+			}
+			continue
+		}
+		if err := m.checkType(items, obj, elemPath); err != nil {
+			return nil, err
+		}
+		var existing map[string]interface{}
+		if idx >= 0 {
+			existing, _ = result[idx].(map[string]interface{})
+		}
+		merged, err := m.mergeMap(existing, obj, items, elemPath)
+		if err != nil {
+			return nil, err
+		}
+		if idx >= 0 {
+			result[idx] = merged
+		} else {
+			result = append(result, merged)
+		}
+	}
+	return result, nil
+}
+
+// hasStrategy reports whether a comma separated patch strategy, e.g.
+// "merge,retainKeys", includes the given strategy.
+func hasStrategy(strategies, strategy string) bool {
+	for _, s := range strings.Split(strategies, ",") {
+		if strings.TrimSpace(s) == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+func indexOf(list []interface{}, v interface{}) int {
+	for i, e := range list {
+		if reflect.DeepEqual(e, v) {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexOfKey(list []interface{}, mergeKey string, key interface{}) int {
+	for i, e := range list {
+		if obj, ok := e.(map[string]interface{}); ok {
+			if v, ok := obj[mergeKey]; ok && reflect.DeepEqual(v, key) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// reorder moves the elements of list named by order to the front, in that
+// order. Elements of a list of objects are named by {mergeKey: value}
+// objects.
+func reorder(list, order []interface{}, mergeKey string) []interface{} {
+	used := make([]bool, len(list))
+	ret := make([]interface{}, 0, len(list))
+	for _, o := range order {
+		idx := -1
+		if mergeKey == "" {
+			idx = indexOf(list, o)
+		} else if obj, ok := o.(map[string]interface{}); ok {
+			idx = indexOfKey(list, mergeKey, obj[mergeKey])
+		}
+		if idx >= 0 && !used[idx] {
+			used[idx] = true
+			ret = append(ret, list[idx])
+		}
+	}
+	for i, e := range list {
+		if !used[i] {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const podDefinitions = `{
+  "io.example.Pod": {
+    "type": "object",
+    "properties": {
+      "metadata": {
+        "type": "object",
+        "properties": {
+          "finalizers": {"type": "array", "items": {"type": "string"}, "x-kubernetes-patch-strategy": "merge"},
+          "labels": {"type": "object", "additionalProperties": {"type": "string"}}
+        }
+      },
+      "spec": {"$ref": "#/definitions/io.example.PodSpec"}
+    }
+  },
+  "io.example.PodSpec": {
+    "type": "object",
+    "properties": {
+      "containers": {
+        "type": "array",
+        "items": {"$ref": "#/definitions/io.example.Container"},
+        "x-kubernetes-patch-strategy": "merge",
+        "x-kubernetes-patch-merge-key": "name"
+      },
+      "volumes": {"type": "array", "items": {"type": "object"}}
+    }
+  },
+  "io.example.Container": {
+    "type": "object",
+    "properties": {
+      "name": {"type": "string"},
+      "image": {"type": "string"},
+      "args": {"type": "array", "items": {"type": "string"}},
+      "ports": {
+        "type": "array",
+        "items": {"type": "object", "properties": {"containerPort": {"type": "integer"}, "protocol": {"type": "string"}}},
+        "x-kubernetes-patch-strategy": "merge",
+        "x-kubernetes-patch-merge-key": "containerPort"
+      }
+    }
+  }
+}`
+
+const pod = `{
+  "metadata": {"finalizers": ["a", "b"], "labels": {"app": "web", "tier": "frontend"}},
+  "spec": {
+    "containers": [
+      {"name": "web", "image": "nginx:1", "args": ["-v"], "ports": [{"containerPort": 80}, {"containerPort": 443}]},
+      {"name": "sidecar", "image": "envoy:1"}
+    ],
+    "volumes": [{"name": "data"}]
+  }
+}`
+
+func podSchema(t *testing.T) (*spec.Schema, spec.Definitions) {
+	var definitions spec.Definitions
+	require.NoError(t, json.Unmarshal([]byte(podDefinitions), &definitions))
+	return spec.RefSchema("#/definitions/io.example.Pod"), definitions
+}
+
+func TestStrategicMergePatch(t *testing.T) {
+	schema, definitions := podSchema(t)
+	for name, tc := range map[string]struct {
+		patch    string
+		expected string
+	}{
+		"merge objects and delete fields": {
+			patch: `{"metadata": {"labels": {"tier": null, "env": "prod"}}}`,
+			expected: `{"metadata": {"finalizers": ["a", "b"], "labels": {"app": "web", "env": "prod"}}, "spec": {
+			  "containers": [
+			    {"name": "web", "image": "nginx:1", "args": ["-v"], "ports": [{"containerPort": 80}, {"containerPort": 443}]},
+			    {"name": "sidecar", "image": "envoy:1"}
+			  ],
+			  "volumes": [{"name": "data"}]
+			}}`,
+		},
+		"merge lists by key": {
+			patch: `{"spec": {"containers": [
+			  {"name": "web", "image": "nginx:2", "ports": [{"containerPort": 443, "protocol": "TCP"}, {"containerPort": 8080}]},
+			  {"name": "sidecar", "$patch": "delete"},
+			  {"name": "init", "image": "busybox"}
+			]}}`,
+			expected: `{"metadata": {"finalizers": ["a", "b"], "labels": {"app": "web", "tier": "frontend"}}, "spec": {
+			  "containers": [
+			    {"name": "web", "image": "nginx:2", "args": ["-v"], "ports": [{"containerPort": 80}, {"containerPort": 443, "protocol": "TCP"}, {"containerPort": 8080}]},
+			    {"name": "init", "image": "busybox"}
+			  ],
+			  "volumes": [{"name": "data"}]
+			}}`,
+		},
+		"replace lists without strategy": {
+			patch: `{"spec": {"volumes": [{"name": "cache"}], "containers": [{"name": "web", "args": ["-q"]}]}}`,
+			expected: `{"metadata": {"finalizers": ["a", "b"], "labels": {"app": "web", "tier": "frontend"}}, "spec": {
+			  "containers": [
+			    {"name": "web", "image": "nginx:1", "args": ["-q"], "ports": [{"containerPort": 80}, {"containerPort": 443}]},
+			    {"name": "sidecar", "image": "envoy:1"}
+			  ],
+			  "volumes": [{"name": "cache"}]
+			}}`,
+		},
+		"primitive lists and directives": {
+			patch: `{
+			  "metadata": {"finalizers": ["c", "a"], "$deleteFromPrimitiveList/finalizers": ["b"], "labels": {"$patch": "replace", "app": "api"}},
+			  "spec": {"$setElementOrder/containers": [{"name": "sidecar"}, {"name": "web"}], "containers": [{"$patch": "replace"}, {"name": "web"}, {"name": "sidecar"}]}
+			}`,
+			expected: `{"metadata": {"finalizers": ["a", "c"], "labels": {"app": "api"}}, "spec": {
+			  "containers": [{"name": "sidecar"}, {"name": "web"}],
+			  "volumes": [{"name": "data"}]
+			}}`,
+		},
+		"retain keys": {
+			patch:    `{"spec": {"$retainKeys": ["containers"], "volumes": [{"name": "ignored"}]}, "metadata": {"$patch": "delete"}}`,
+			expected: `{"spec": {"containers": [{"name": "web", "image": "nginx:1", "args": ["-v"], "ports": [{"containerPort": 80}, {"containerPort": 443}]}, {"name": "sidecar", "image": "envoy:1"}]}}`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := StrategicMergePatch([]byte(pod), []byte(tc.patch), schema, definitions)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(got))
+		})
+	}
+}
+
+func TestStrategicMergePatchErrors(t *testing.T) {
+	schema, definitions := podSchema(t)
+	for patch, expected := range map[string]*Error{
+		`{"spec": {"containers": [{"image": "nginx"}]}}`:          {Path: "spec.containers[0]", Reason: `missing merge key "name"`},
+		`{"spec": {"containers": ["web"]}}`:                       {Path: "spec.containers[0]", Reason: `expected an object with merge key "name", got string`},
+		`{"spec": {"containers": {"name": "web"}}}`:               {Path: "spec.containers", Reason: "expected array, got object"},
+		`{"spec": {"containers": [{"name": "web", "image": 1}]}}`: {Path: "spec.containers[0].image", Reason: "expected string, got number"},
+		`{"metadata": {"finalizers": [{"name": "a"}]}}`:           {Path: "metadata.finalizers[0]", Reason: "objects can't be merged into a list without x-kubernetes-patch-merge-key"},
+		`{"metadata": {"$patch": "remove"}}`:                      {Path: "metadata", Reason: "unknown patch directive remove"},
+		`{"metadata": {"$unknown": true}}`:                        {Path: "metadata.$unknown", Reason: "unknown patch directive"},
+	} {
+		_, err := StrategicMergePatch([]byte(pod), []byte(patch), schema, definitions)
+		assert.Equal(t, expected, err, patch)
+	}
+
+	_, err := StrategicMergePatch([]byte(pod), []byte(`[]`), schema, definitions)
+	assert.EqualError(t, err, "invalid patch: expected a JSON object")
+}
+
+func TestStrategicMergePatchWithoutSchema(t *testing.T) {
+	got, err := StrategicMergePatch(nil, []byte(`{"a": {"b": 1, "c": null}, "l": [1, 2]}`), nil, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": {"b": 1}, "l": [1, 2]}`, string(got))
+
+	got, err = StrategicMergePatch([]byte(`{"n": 12345678901234567890, "l": [1]}`), []byte(`{"l": [2]}`), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"l":[2],"n":12345678901234567890}`, string(got))
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"encoding/json"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// Violation is a schema violation of a patched document.
+type Violation struct {
+	// Err describes the violation.
+	Err error
+	// Operation is the index in Result.Operations of the operation that
+	// introduced the violation, or -1 if the original document already
+	// violated the schema that way.
+	Operation int
+}
+
+// Result is a patched document with its schema violations.
+type Result struct {
+	// Document is the patched document.
+	Document []byte
+	// Operations describes the operations of the patch in the order they
+	// were applied: "op path" for JSON patches, and the JSON pointer of
+	// each changed member for merge patches.
+	Operations []string
+	// Violations lists the schema violations of the patched document.
+	Violations []Violation
+}
+
+// Valid returns whether the patched document is valid.
+func (r *Result) Valid() bool {
+	return len(r.Violations) == 0
+}
+
+// Err returns the violations as a composite validation error, or nil if
+// the patched document is valid.
+func (r *Result) Err() error {
+	if r.Valid() {
+		return nil
+	}
+	errs := make([]error, 0, len(r.Violations))
+	for _, v := range r.Violations {
+		errs = append(errs, v.Err)
+	}
+	return errors.CompositeValidationError(errs...)
+}
+
+// MergePatchAndValidate applies an RFC 7386 JSON merge patch to a JSON
+// document and validates the result against schema, resolving references
+// to the given definitions. Each member of the patch counts as an
+// operation. Errors are only returned for malformed documents; schema
+// violations are reported in the result.
+func MergePatchAndValidate(original, patch []byte, schema *spec.Schema, definitions spec.Definitions) (*Result, error) {
+	doc, err := decodeJSON(original, "original document")
+	if err != nil {
+		return nil, err
+	}
+	p, err := decodeJSON(patch, "patch")
+	if err != nil {
+		return nil, err
+	}
+	var steps []step
+	for _, m := range mergePatchMembers(p) {
+		m := m
+		steps = append(steps, step{
+			description: m.pointer,
+			apply: func(doc interface{}) (interface{}, error) {
+				return mergePatch(doc, m.patch), nil
+			},
+		})
+	}
+	return applyAndValidate(doc, steps, schema, definitions)
+}
+
+// JSONPatchAndValidate applies an RFC 6902 JSON patch to a JSON document
+// and validates the result against schema, resolving references to the
+// given definitions. Failing operations are returned as an *Error; schema
+// violations are reported in the result.
+func JSONPatchAndValidate(original, patch []byte, schema *spec.Schema, definitions spec.Definitions) (*Result, error) {
+	doc, err := decodeJSON(original, "original document")
+	if err != nil {
+		return nil, err
+	}
+	ops, err := decodeJSONPatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	var steps []step
+	for i, op := range ops {
+		i, op := i, op
+		steps = append(steps, step{
+			description: op.String(),
+			apply: func(doc interface{}) (interface{}, error) {
+				return applyOperation(doc, i, op)
+			},
+		})
+	}
+	return applyAndValidate(doc, steps, schema, definitions)
+}
+
+type step struct {
+	description string
+	apply       func(doc interface{}) (interface{}, error)
+}
+
+// applyAndValidate applies the steps in order, validating the document
+// after each of them to find the step introducing each violation. A
+// violation that goes away and comes back is attributed to the step
+// bringing it back.
+func applyAndValidate(doc interface{}, steps []step, schema *spec.Schema, definitions spec.Definitions) (*Result, error) {
+	// The validator doesn't resolve references.
+	expanded := validate.ExpandRefs(schema, func(ref string) *spec.Schema {
+		return schemas{definitions}.resolve(spec.RefSchema(ref))
+	})
+	validator := validate.NewSchemaValidator(expanded, nil, "", strfmt.Default)
+	violations := func(doc interface{}) ([]error, error) {
+		// The validator doesn't handle json.Number everywhere, validate
+		// plain decoded JSON.
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		var plain interface{}
+		if err := json.Unmarshal(data, &plain); err != nil {
+			return nil, err
+		}
+		return validator.Validate(plain).Errors, nil
+	}
+
+	current, err := violations(doc)
+	if err != nil {
+		return nil, err
+	}
+	introducedBy := map[string]int{}
+	for _, e := range current {
+		introducedBy[e.Error()] = -1
+	}
+
+	result := &Result{}
+	for i, s := range steps {
+		if doc, err = s.apply(doc); err != nil {
+			return nil, err
+		}
+		result.Operations = append(result.Operations, s.description)
+		if current, err = violations(doc); err != nil {
+			return nil, err
+		}
+		next := make(map[string]int, len(current))
+		for _, e := range current {
+			op, ok := introducedBy[e.Error()]
+			if !ok {
+				op = i
+			}
+			next[e.Error()] = op
+		}
+		introducedBy = next
+	}
+
+	for _, e := range current {
+		result.Violations = append(result.Violations, Violation{Err: e, Operation: introducedBy[e.Error()]})
+	}
+	if result.Document, err = json.Marshal(doc); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
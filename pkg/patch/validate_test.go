@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const deploymentDefinitions = `{
+  "io.example.Deployment": {
+    "type": "object",
+    "required": ["name"],
+    "properties": {
+      "name": {"type": "string", "minLength": 1},
+      "replicas": {"type": "integer", "minimum": 0},
+      "template": {"$ref": "#/definitions/io.example.Template"}
+    }
+  },
+  "io.example.Template": {
+    "type": "object",
+    "properties": {
+      "image": {"type": "string", "pattern": "^[a-z]+:[0-9]+$"},
+      "children": {"type": "array", "items": {"$ref": "#/definitions/io.example.Template"}}
+    }
+  }
+}`
+
+func deploymentSchema(t *testing.T) (*spec.Schema, spec.Definitions) {
+	var definitions spec.Definitions
+	require.NoError(t, json.Unmarshal([]byte(deploymentDefinitions), &definitions))
+	return spec.RefSchema("#/definitions/io.example.Deployment"), definitions
+}
+
+// violations maps the violation messages of a result to the operation
+// introducing them.
+func violations(r *Result) map[string]int {
+	ret := map[string]int{}
+	for _, v := range r.Violations {
+		ret[v.Err.Error()] = v.Operation
+	}
+	return ret
+}
+
+func TestJSONPatchAndValidate(t *testing.T) {
+	schema, definitions := deploymentSchema(t)
+	original := `{"name": "web", "replicas": -1, "template": {"image": "nginx:1"}}`
+
+	r, err := JSONPatchAndValidate([]byte(original), []byte(`[
+	  {"op": "replace", "path": "/template/image", "value": "nginx"},
+	  {"op": "remove", "path": "/name"},
+	  {"op": "add", "path": "/template/children", "value": {"image": "busybox:1"}},
+	  {"op": "replace", "path": "/template/image", "value": "nginx:2"}
+	]`), schema, definitions)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"replace /template/image", "remove /name", "add /template/children", "replace /template/image"}, r.Operations)
+	assert.JSONEq(t, `{"replicas": -1, "template": {"image": "nginx:2", "children": {"image": "busybox:1"}}}`, string(r.Document))
+	assert.Equal(t, map[string]int{
+		"replicas in body should be greater than or equal to 0":       -1,
+		".name in body is required":                                   1,
+		"template.children in body must be of type array: \"object\"": 2,
+	}, violations(r))
+	assert.False(t, r.Valid())
+	assert.Error(t, r.Err())
+
+	r, err = JSONPatchAndValidate([]byte(original), []byte(`[{"op": "replace", "path": "/replicas", "value": 3}]`), schema, definitions)
+	require.NoError(t, err)
+	assert.True(t, r.Valid())
+	assert.NoError(t, r.Err())
+
+	_, err = JSONPatchAndValidate([]byte(original), []byte(`[{"op": "remove", "path": "/missing"}]`), schema, definitions)
+	assert.EqualError(t, err, `/missing: operation 0 (remove): missing field "missing"`)
+}
+
+func TestMergePatchAndValidate(t *testing.T) {
+	schema, definitions := deploymentSchema(t)
+	r, err := MergePatchAndValidate([]byte(`{"name": "web", "template": {"image": "nginx:1"}}`), []byte(`{
+	  "name": null,
+	  "replicas": 2,
+	  "template": {"image": "nginx", "children": []}
+	}`), schema, definitions)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/name", "/replicas", "/template/children", "/template/image"}, r.Operations)
+	assert.JSONEq(t, `{"replicas": 2, "template": {"image": "nginx", "children": []}}`, string(r.Document))
+	assert.Equal(t, map[string]int{
+		".name in body is required":                             0,
+		"template.image in body should match '^[a-z]+:[0-9]+$'": 3,
+	}, violations(r))
+}
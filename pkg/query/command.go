@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+)
+
+// Help describes the queries understood by Run.
+const Help = `Queries:
+  show operation <operationId>            print an operation
+  show definition <name>                  print a definition
+  show <json-pointer>                     print any value, e.g. show /info
+  who references <ref>                    list the locations referencing <ref>
+  deps <ref>                              list what <ref> transitively references
+  list operations|definitions             list operations or definitions
+  list [required] params of <METHOD> <path>
+                                          list the parameters of an operation
+  help                                    print this help`
+
+// Run evaluates a single query and returns its textual result.
+func (ix *Index) Run(query string) (string, error) {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return "", nil
+	}
+	switch words[0] {
+	case "help":
+		return Help, nil
+	case "show":
+		return ix.show(words[1:])
+	case "who":
+		if len(words) != 3 || words[1] != "references" {
+			return "", fmt.Errorf("usage: who references <ref>")
+		}
+		return lines(ix.ReferencedBy(localRef(words[2]))), nil
+	case "deps":
+		if len(words) != 2 {
+			return "", fmt.Errorf("usage: deps <ref>")
+		}
+		return lines(ix.Dependencies(localRef(words[1]))), nil
+	case "list":
+		return ix.list(words[1:])
+	}
+	return "", fmt.Errorf("unknown query %q, try help", words[0])
+}
+
+func (ix *Index) show(args []string) (string, error) {
+	if len(args) == 2 && args[0] == "operation" {
+		op, ok := ix.Operation(args[1])
+		if !ok {
+			return "", fmt.Errorf("no operation %q", args[1])
+		}
+		return ix.pretty(fmt.Sprintf("%s %s", op.Method, op.Path), op.Pointer())
+	}
+	if len(args) == 2 && args[0] == "definition" {
+		return ix.pretty("", "/definitions/"+jsonpointer.Escape(args[1]))
+	}
+	if len(args) == 1 && strings.HasPrefix(args[0], "/") {
+		return ix.pretty("", args[0])
+	}
+	if len(args) == 1 && strings.HasPrefix(args[0], "#/") {
+		return ix.pretty("", args[0][1:])
+	}
+	return "", fmt.Errorf("usage: show operation <operationId> | show definition <name> | show <json-pointer>")
+}
+
+func (ix *Index) pretty(header, pointer string) (string, error) {
+	v, err := ix.Get(pointer)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if header != "" {
+		return header + "\n" + string(data), nil
+	}
+	return string(data), nil
+}
+
+func (ix *Index) list(args []string) (string, error) {
+	if len(args) == 1 && args[0] == "operations" {
+		var out []string
+		for _, op := range ix.Operations() {
+			out = append(out, strings.TrimSpace(fmt.Sprintf("%-7s %s %s", op.Method, op.Path, op.Operation.ID)))
+		}
+		return lines(out), nil
+	}
+	if len(args) == 1 && args[0] == "definitions" {
+		var out []string
+		for name := range ix.spec.Definitions {
+			out = append(out, name)
+		}
+		sort.Strings(out)
+		return lines(out), nil
+	}
+
+	required := false
+	if len(args) > 0 && args[0] == "required" {
+		required = true
+		args = args[1:]
+	}
+	if len(args) != 4 || args[0] != "params" || args[1] != "of" {
+		return "", fmt.Errorf("usage: list operations | list definitions | list [required] params of <METHOD> <path>")
+	}
+	op, ok := ix.OperationAt(args[2], args[3])
+	if !ok {
+		return "", fmt.Errorf("no operation %s %s", strings.ToUpper(args[2]), args[3])
+	}
+	params, err := ix.Parameters(op)
+	if err != nil {
+		return "", err
+	}
+	var out []string
+	for _, p := range params {
+		if required && !p.Required {
+			continue
+		}
+		line := fmt.Sprintf("%s (in %s)", p.Name, p.In)
+		if p.Required {
+			line += " required"
+		}
+		out = append(out, line)
+	}
+	return lines(out), nil
+}
+
+// localRef accepts a bare definition name as a shorthand for its reference.
+func localRef(ref string) string {
+	if strings.Contains(ref, "#") || strings.Contains(ref, "/") {
+		return ref
+	}
+	return "#/definitions/" + ref
+}
+
+func lines(l []string) string {
+	return strings.Join(l, "\n")
+}
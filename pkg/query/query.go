@@ -0,0 +1,217 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package query answers questions about a spec: where an operation is,
+// what references a definition, which definitions a schema depends on and
+// which parameters an operation takes. It backs the query command of
+// swaggerctl.
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Index is a read-only index over a spec. It must not be used after the
+// spec is modified.
+type Index struct {
+	spec *spec.Swagger
+	// doc is the spec decoded as generic JSON, for JSON pointer lookups.
+	doc interface{}
+	// refs maps each $ref value to the sorted JSON pointers of the objects
+	// holding it.
+	refs map[string][]string
+	// operations maps operation IDs to their location.
+	operations map[string]OperationRef
+}
+
+// OperationRef locates an operation in the spec.
+type OperationRef struct {
+	Method    string
+	Path      string
+	Operation *spec.Operation
+}
+
+// Pointer returns the JSON pointer to the operation.
+func (o OperationRef) Pointer() string {
+	return "/paths/" + jsonpointer.Escape(o.Path) + "/" + strings.ToLower(o.Method)
+}
+
+// NewIndex indexes the given spec.
+func NewIndex(s *spec.Swagger) (*Index, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	ix := &Index{
+		spec:       s,
+		refs:       map[string][]string{},
+		operations: map[string]OperationRef{},
+	}
+	if err := json.Unmarshal(data, &ix.doc); err != nil {
+		return nil, err
+	}
+	s.WalkRefs(func(ref *spec.Ref, pointer string) {
+		ix.refs[ref.String()] = append(ix.refs[ref.String()], pointer)
+	})
+	for _, pointers := range ix.refs {
+		sort.Strings(pointers)
+	}
+	for _, op := range ix.Operations() {
+		if op.Operation.ID != "" {
+			ix.operations[op.Operation.ID] = op
+		}
+	}
+	return ix, nil
+}
+
+// Operations returns all operations of the spec sorted by path and method.
+func (ix *Index) Operations() []OperationRef {
+	var ret []OperationRef
+	if ix.spec.Paths == nil {
+		return ret
+	}
+	paths := make([]string, 0, len(ix.spec.Paths.Paths))
+	for p := range ix.spec.Paths.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		item := ix.spec.Paths.Paths[p]
+		for _, m := range item.Operations() {
+			ret = append(ret, OperationRef{Method: m.Method, Path: p, Operation: m.Operation})
+		}
+	}
+	return ret
+}
+
+// Operation returns the operation with the given ID.
+func (ix *Index) Operation(id string) (OperationRef, bool) {
+	op, ok := ix.operations[id]
+	return op, ok
+}
+
+// OperationAt returns the operation for a method and path. The method is
+// case insensitive.
+func (ix *Index) OperationAt(method, path string) (OperationRef, bool) {
+	method = strings.ToUpper(method)
+	for _, op := range ix.Operations() {
+		if op.Method == method && op.Path == path {
+			return op, true
+		}
+	}
+	return OperationRef{}, false
+}
+
+// Get returns the decoded JSON value at a JSON pointer, e.g. "/info/title".
+func (ix *Index) Get(pointer string) (interface{}, error) {
+	p, err := jsonpointer.New(pointer)
+	if err != nil {
+		return nil, err
+	}
+	v, _, err := p.Get(ix.doc)
+	if err != nil {
+		return nil, fmt.Errorf("nothing at %q: %v", pointer, err)
+	}
+	return v, nil
+}
+
+// ReferencedBy returns the sorted JSON pointers of the objects referencing
+// ref, e.g. "#/definitions/Error".
+func (ix *Index) ReferencedBy(ref string) []string {
+	return ix.refs[ref]
+}
+
+// Dependencies returns the sorted references reachable from the value at the
+// given local reference, directly or through other local references.
+func (ix *Index) Dependencies(ref string) []string {
+	seen := map[string]bool{}
+	queue := []string{ref}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if !strings.HasPrefix(cur, "#") {
+			continue
+		}
+		prefix := strings.TrimPrefix(cur, "#")
+		for target, pointers := range ix.refs {
+			if seen[target] {
+				continue
+			}
+			for _, p := range pointers {
+				if p == prefix || strings.HasPrefix(p, prefix+"/") {
+					seen[target] = true
+					queue = append(queue, target)
+					break
+				}
+			}
+		}
+	}
+	delete(seen, ref)
+	ret := make([]string, 0, len(seen))
+	for target := range seen {
+		ret = append(ret, target)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Parameters returns the effective parameters of an operation, with their
+// references to the spec parameters followed: the path item parameters not
+// overridden by the operation, followed by the operation parameters.
+func (ix *Index) Parameters(op OperationRef) ([]spec.Parameter, error) {
+	common, err := ix.resolveParameters(ix.spec.Paths.Paths[op.Path].Parameters)
+	if err != nil {
+		return nil, err
+	}
+	params, err := ix.resolveParameters(op.Operation.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	overridden := map[string]bool{}
+	for _, p := range params {
+		overridden[p.In+"/"+p.Name] = true
+	}
+	var ret []spec.Parameter
+	for _, p := range common {
+		if !overridden[p.In+"/"+p.Name] {
+			ret = append(ret, p)
+		}
+	}
+	return append(ret, params...), nil
+}
+
+func (ix *Index) resolveParameters(params []spec.Parameter) ([]spec.Parameter, error) {
+	const prefix = "#/parameters/"
+	ret := make([]spec.Parameter, 0, len(params))
+	for _, p := range params {
+		if ref := p.Ref.String(); ref != "" {
+			resolved, ok := ix.spec.Parameters[strings.TrimPrefix(ref, prefix)]
+			if !strings.HasPrefix(ref, prefix) || !ok {
+				return nil, fmt.Errorf("can't resolve %s", ref)
+			}
+			p = resolved
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const testSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "Orders", "version": "v1"},
+  "parameters": {
+    "tenant": {"name": "tenant", "in": "header", "type": "string", "required": true},
+    "dryRun": {"name": "dryRun", "in": "query", "type": "boolean"}
+  },
+  "paths": {
+    "/orders": {
+      "parameters": [
+        {"$ref": "#/parameters/tenant"},
+        {"name": "dryRun", "in": "query", "type": "string"}
+      ],
+      "post": {
+        "operationId": "createOrder",
+        "parameters": [
+          {"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Order"}},
+          {"$ref": "#/parameters/dryRun"}
+        ],
+        "responses": {
+          "201": {"description": "created", "schema": {"$ref": "#/definitions/Order"}},
+          "default": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}
+        }
+      }
+    },
+    "/pets/{id}": {
+      "get": {"operationId": "getPet", "responses": {"default": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}}}
+    }
+  },
+  "definitions": {
+    "Order": {"type": "object", "properties": {"items": {"type": "array", "items": {"$ref": "#/definitions/Item"}}}},
+    "Item": {"type": "object", "properties": {"price": {"$ref": "#/definitions/Money"}}},
+    "Money": {"type": "string"},
+    "Error": {"type": "object"}
+  }
+}`
+
+func newTestIndex(t *testing.T) *Index {
+	s := &spec.Swagger{}
+	require.NoError(t, json.Unmarshal([]byte(testSpec), s))
+	ix, err := NewIndex(s)
+	require.NoError(t, err)
+	return ix
+}
+
+func TestIndex(t *testing.T) {
+	ix := newTestIndex(t)
+
+	op, ok := ix.Operation("getPet")
+	require.True(t, ok)
+	assert.Equal(t, "GET", op.Method)
+	assert.Equal(t, "/paths/~1pets~1{id}/get", op.Pointer())
+	_, ok = ix.Operation("missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{
+		"/paths/~1orders/post/responses/default/schema",
+		"/paths/~1pets~1{id}/get/responses/default/schema",
+	}, ix.ReferencedBy("#/definitions/Error"))
+	assert.Equal(t, []string{"#/definitions/Item", "#/definitions/Money"}, ix.Dependencies("#/definitions/Order"))
+	assert.Empty(t, ix.Dependencies("#/definitions/Money"))
+	assert.Equal(t, []string{"/paths/~1orders/post/parameters/1"}, ix.ReferencedBy("#/parameters/dryRun"))
+
+	// The operation parameters override the path item ones once their
+	// references are followed.
+	post, ok := ix.OperationAt("post", "/orders")
+	require.True(t, ok)
+	params, err := ix.Parameters(post)
+	require.NoError(t, err)
+	var names []string
+	for _, p := range params {
+		names = append(names, p.In+" "+p.Name+" "+p.Type)
+	}
+	assert.Equal(t, []string{"header tenant string", "body body ", "query dryRun boolean"}, names)
+	post.Operation = &spec.Operation{OperationProps: spec.OperationProps{Parameters: []spec.Parameter{*spec.ParamRef("#/parameters/missing")}}}
+	_, err = ix.Parameters(post)
+	assert.Error(t, err)
+
+	title, err := ix.Get("/info/title")
+	require.NoError(t, err)
+	assert.Equal(t, "Orders", title)
+	_, err = ix.Get("/info/missing")
+	assert.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	ix := newTestIndex(t)
+	for _, tc := range []struct {
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{query: "show operation getPet", want: "GET /pets/{id}\n{\n  \"operationId\": \"getPet\""},
+		{query: "show definition Money", want: "{\n  \"type\": \"string\"\n}"},
+		{query: "show /info/version", want: `"v1"`},
+		{query: "who references Money", want: "/definitions/Item/properties/price"},
+		{query: "deps #/definitions/Item", want: "#/definitions/Money"},
+		{query: "list definitions", want: "Error\nItem\nMoney\nOrder"},
+		{query: "list operations", want: "POST    /orders createOrder\nGET     /pets/{id} getPet"},
+		{query: "list required params of POST /orders", want: "tenant (in header) required\nbody (in body) required"},
+		{query: "list params of POST /orders", want: "tenant (in header) required\nbody (in body) required\ndryRun (in query)"},
+		{query: "list params of GET /orders", wantErr: true},
+		{query: "show operation missing", wantErr: true},
+		{query: "frobnicate", wantErr: true},
+		{query: "  ", want: ""},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			got, err := ix.Run(tc.query)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.want == "" {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Contains(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reflector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/builder"
+	"k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+type List[T any] struct {
+	Items []T `json:"items"`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K  `json:"key"`
+	Value *V `json:"value,omitempty"`
+}
+
+type Ptr[T any] *T
+
+type Catalog struct {
+	Pets   List[Pet]                 `json:"pets"`
+	Names  List[string]              `json:"names"`
+	Owners List[Pair[string, Owner]] `json:"owners"`
+}
+
+func TestGenericDefinitions(t *testing.T) {
+	r := NewReflector()
+	require.NoError(t, r.Add(Catalog{}))
+	defs := r.Definitions()
+
+	petList := pkg + ".List[" + pkg + ".Pet]"
+	stringList := pkg + ".List[string]"
+	ownerPairList := pkg + ".List[" + pkg + ".Pair[string," + pkg + ".Owner]]"
+	ownerPair := pkg + ".Pair[string," + pkg + ".Owner]"
+	for _, name := range []string{petList, stringList, ownerPairList, ownerPair} {
+		assert.Contains(t, defs, name)
+	}
+
+	pets := defs[pkg+".Catalog"].Properties["pets"]
+	assert.Equal(t, "#/definitions/"+common.EscapeJsonPointer(petList), pets.Ref.String())
+	assert.Equal(t, spec.StringOrArray{"string"}, defs[stringList].Properties["items"].Items.Schema.Type)
+	assert.Equal(t, "#/definitions/"+common.EscapeJsonPointer(pkg+".Pet"), defs[petList].Properties["items"].Items.Schema.Ref.String())
+	pair := defs[ownerPair]
+	value := pair.Properties["value"]
+	assert.Equal(t, []string{"key"}, pair.Required)
+	assert.Equal(t, "#/definitions/"+common.EscapeJsonPointer(pkg+".Owner"), value.Ref.String())
+
+	var p Ptr[Meta]
+	s, err := r.Schema(p, func(name string) spec.Ref { return spec.MustCreateRef(name) })
+	require.NoError(t, err)
+	assert.Equal(t, spec.RefSchema(pkg+".Meta"), s)
+}
+
+func TestGenericDefinitionNames(t *testing.T) {
+	r := NewReflector()
+	require.NoError(t, r.Add(Catalog{}))
+	sw, err := builder.BuildOpenAPIDefinitionsForResources(&common.Config{GetDefinitions: r.GetOpenAPIDefinitions}, pkg+".Catalog")
+	require.NoError(t, err)
+	assert.Contains(t, sw.Definitions, "reflector.List["+pkg+".Pet]")
+	assert.Contains(t, sw.Definitions, "reflector.Pair[string,"+pkg+".Owner]")
+	pets := sw.Definitions["reflector.Catalog"].Properties["pets"]
+	assert.Equal(t, "#/definitions/reflector.List["+common.EscapeJsonPointer(pkg)+".Pet]", pets.Ref.String())
+}
@@ -0,0 +1,386 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reflector builds OpenAPI definitions from Go types at runtime,
+// for services that publish a spec without running openapi-gen. The
+// definitions follow the conventions of the generator: named structs become
+// definitions named after their package path and type name, fields are
+// named after their json tags and types implementing
+// common.OpenAPIDefinitionGetter or the OpenAPISchemaType and
+// OpenAPISchemaFormat methods describe themselves. Validation constraints
+// are read from openapi struct tags holding the markers of
+// common.ValidationMarkers, e.g. `openapi:"minimum=0;maxLength=63"`.
+//
+// Each instantiation of a generic struct is a definition of its own, named
+// after the generic type and its type arguments, e.g.
+// "example.com/pets/api.List[example.com/pets/api.Pet]".
+package reflector
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/util"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var (
+	timeType                 = reflect.TypeOf(time.Time{})
+	definitionGetterType     = reflect.TypeOf((*common.OpenAPIDefinitionGetter)(nil)).Elem()
+	schemaTypeFormatTypeType = reflect.TypeOf((*schemaTypeFormat)(nil)).Elem()
+)
+
+// schemaTypeFormat is implemented by types describing themselves as a
+// simple type and format, e.g. int-or-string.
+type schemaTypeFormat interface {
+	OpenAPISchemaType() []string
+	OpenAPISchemaFormat() string
+}
+
+// Reflector collects Go types and builds their definitions.
+type Reflector struct {
+	// TypeHook, if set, is called for every type before reflecting it. A
+	// non-nil schema is used for the type instead of the reflected one,
+	// e.g. to describe a custom type of a third party package.
+	TypeHook func(t reflect.Type) *spec.Schema
+
+	types []reflect.Type
+}
+
+// NewReflector returns a Reflector without types.
+func NewReflector() *Reflector {
+	return &Reflector{}
+}
+
+// Add adds the types of the given values, e.g. Add(Pet{}, &Owner{}), and
+// the types they use. It returns an error if a type can't be described,
+// e.g. a channel or a function.
+func (r *Reflector) Add(values ...interface{}) error {
+	var types []reflect.Type
+	for _, v := range values {
+		t := reflect.TypeOf(v)
+		if t == nil {
+			return fmt.Errorf("can't reflect the type of nil")
+		}
+		types = append(types, t)
+	}
+	g := r.newGeneration(func(name string) spec.Ref { return spec.Ref{} })
+	for _, t := range types {
+		if _, err := g.schema(t, t.String(), map[string]bool{}); err != nil {
+			return err
+		}
+	}
+	r.types = append(r.types, types...)
+	return nil
+}
+
+// DefinitionName returns the name of the definition of a named struct or
+// self describing type: its canonical type name as used by the builder,
+// e.g. "example.com/pets/api.Pet". It returns an empty string for the
+// other types, which are inlined.
+func (r *Reflector) DefinitionName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() == "" || t == timeType || r.hooked(t) {
+		return ""
+	}
+	if t.Kind() == reflect.Struct || selfDescribing(t) {
+		return util.GetCanonicalTypeName(reflect.New(t).Interface())
+	}
+	return ""
+}
+
+// GetOpenAPIDefinitions returns the definitions of the added types, to be
+// used as the common.GetOpenAPIDefinitions of a builder configuration.
+func (r *Reflector) GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+	g := r.newGeneration(ref)
+	for _, t := range r.types {
+		// Types were checked when added.
+		g.schema(t, t.String(), map[string]bool{})
+	}
+	return g.definitions
+}
+
+// Definitions returns the definitions of the added types, referencing each
+// other by "#/definitions/<name>".
+func (r *Reflector) Definitions() spec.Definitions {
+	defs := r.GetOpenAPIDefinitions(func(name string) spec.Ref {
+		return spec.MustCreateRef("#/definitions/" + common.EscapeJsonPointer(name))
+	})
+	ret := make(spec.Definitions, len(defs))
+	for name, def := range defs {
+		ret[name] = def.Schema
+	}
+	return ret
+}
+
+// Schema returns the schema of the type of a value: a reference for the
+// types with a definition, an inline schema otherwise. Definitions can be
+// obtained by adding the value.
+func (r *Reflector) Schema(v interface{}, ref common.ReferenceCallback) (*spec.Schema, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("can't reflect the type of nil")
+	}
+	return r.newGeneration(ref).schema(t, t.String(), map[string]bool{})
+}
+
+func (r *Reflector) hooked(t reflect.Type) bool {
+	return r.TypeHook != nil && r.TypeHook(t) != nil
+}
+
+func selfDescribing(t reflect.Type) bool {
+	pt := reflect.PtrTo(t)
+	return pt.Implements(definitionGetterType) || pt.Implements(schemaTypeFormatTypeType)
+}
+
+type generation struct {
+	*Reflector
+	ref         common.ReferenceCallback
+	definitions map[string]common.OpenAPIDefinition
+}
+
+func (r *Reflector) newGeneration(ref common.ReferenceCallback) *generation {
+	return &generation{Reflector: r, ref: ref, definitions: map[string]common.OpenAPIDefinition{}}
+}
+
+// schema returns the schema of a type, adding the definitions it needs.
+// path locates the type for errors and the names of the referenced
+// definitions are added to deps.
+func (g *generation) schema(t reflect.Type, path string, deps map[string]bool) (*spec.Schema, error) {
+	if g.TypeHook != nil {
+		if s := g.TypeHook(t); s != nil {
+			return s, nil
+		}
+	}
+	if t == timeType {
+		return spec.DateTimeProperty(), nil
+	}
+	if name := g.DefinitionName(t); name != "" && t.Kind() != reflect.Ptr {
+		deps[name] = true
+		if err := g.define(name, t, path); err != nil {
+			return nil, err
+		}
+		return &spec.Schema{SchemaProps: spec.SchemaProps{Ref: g.ref(name)}}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem(), path, deps)
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		tpe, format := common.OpenAPITypeFormat(t.Kind().String())
+		return new(spec.Schema).Typed(tpe, format), nil
+	case reflect.Interface:
+		tpe, format := common.OpenAPITypeFormat("interface{}")
+		return new(spec.Schema).Typed(tpe, format), nil
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			tpe, format := common.OpenAPITypeFormat("[]byte")
+			return new(spec.Schema).Typed(tpe, format), nil
+		}
+		items, err := g.schema(t.Elem(), path+"[]", deps)
+		if err != nil {
+			return nil, err
+		}
+		return spec.ArrayProperty(items), nil
+	case reflect.Map:
+		if !mapKey(t.Key()) {
+			return nil, fmt.Errorf("%s: unsupported map key type %s", path, t.Key())
+		}
+		values, err := g.schema(t.Elem(), path+"[]", deps)
+		if err != nil {
+			return nil, err
+		}
+		return spec.MapProperty(values), nil
+	case reflect.Struct:
+		return g.structSchema(t, path, deps)
+	}
+	return nil, fmt.Errorf("%s: unsupported type %s", path, t)
+}
+
+// mapKey returns whether encoding/json supports a map key type.
+func mapKey(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func (g *generation) define(name string, t reflect.Type, path string) error {
+	if _, ok := g.definitions[name]; ok {
+		return nil
+	}
+	// Reserve the name first, so that recursive types terminate.
+	g.definitions[name] = common.OpenAPIDefinition{}
+
+	v := reflect.New(t).Interface()
+	if getter, ok := v.(common.OpenAPIDefinitionGetter); ok {
+		g.definitions[name] = *getter.OpenAPIDefinition()
+		return nil
+	}
+	if tf, ok := v.(schemaTypeFormat); ok {
+		g.definitions[name] = common.OpenAPIDefinition{
+			Schema: spec.Schema{SchemaProps: spec.SchemaProps{
+				Type:   tf.OpenAPISchemaType(),
+				Format: tf.OpenAPISchemaFormat(),
+			}},
+		}
+		return nil
+	}
+
+	deps := map[string]bool{}
+	s, err := g.structSchema(t, name, deps)
+	if err != nil {
+		delete(g.definitions, name)
+		return err
+	}
+	def := common.OpenAPIDefinition{Schema: *s}
+	for dep := range deps {
+		def.Dependencies = append(def.Dependencies, dep)
+	}
+	sort.Strings(def.Dependencies)
+	g.definitions[name] = def
+	return nil
+}
+
+func (g *generation) structSchema(t reflect.Type, path string, deps map[string]bool) (*spec.Schema, error) {
+	s := new(spec.Schema).Typed("object", "")
+	var required []string
+	if err := g.addFields(s, &required, t, path, deps); err != nil {
+		return nil, err
+	}
+	for _, name := range required {
+		if _, ok := s.Properties[name]; ok {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s, nil
+}
+
+// addFields adds the properties of the fields of a struct, inlining
+// embedded structs without json name like encoding/json does. Fields of the
+// outer struct win over the ones of embedded structs.
+func (g *generation) addFields(s *spec.Schema, required *[]string, t reflect.Type, path string, deps map[string]bool) error {
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts := parseTag(f.Tag.Get("json"))
+		if name == "-" && opts == "" {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			embedded = append(embedded, f)
+			continue
+		}
+		if f.PkgPath != "" {
+			// Unexported field.
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, ok := s.Properties[name]; ok {
+			continue
+		}
+		prop, err := g.schema(f.Type, path+"."+f.Name, deps)
+		if err != nil {
+			return err
+		}
+		if hasOption(opts, "string") && stringEncoded(ft) {
+			prop = spec.StringProperty()
+		}
+		if tag := f.Tag.Get("openapi"); tag != "" {
+			markers, err := common.ParseValidationStructTag(tag)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %v", path, f.Name, err)
+			}
+			if markers != nil {
+				// Don't modify schemas returned by the type hook.
+				constrained := *prop
+				markers.ApplyTo(&constrained)
+				prop = &constrained
+			}
+		}
+		s.SetProperty(name, *prop)
+		if !hasOption(opts, "omitempty") && f.Type.Kind() != reflect.Ptr {
+			*required = append(*required, name)
+		}
+	}
+	for _, f := range embedded {
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		inner := new(spec.Schema)
+		var innerRequired []string
+		if err := g.addFields(inner, &innerRequired, ft, path+"."+f.Name, deps); err != nil {
+			return err
+		}
+		for name, prop := range inner.Properties {
+			if _, ok := s.Properties[name]; !ok {
+				s.SetProperty(name, prop)
+			}
+		}
+		if f.Type.Kind() != reflect.Ptr {
+			*required = append(*required, innerRequired...)
+		}
+	}
+	return nil
+}
+
+// stringEncoded returns whether the ",string" json option applies to a
+// type.
+func stringEncoded(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	}
+	return false
+}
+
+func parseTag(tag string) (string, string) {
+	if i := strings.Index(tag, ","); i >= 0 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
+
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reflector
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/builder"
+	"k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const pkg = "k8s.io/kube-openapi/pkg/reflector"
+
+type Meta struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created,omitempty"`
+}
+
+type owned struct {
+	OwnerName string `json:"ownerName,omitempty"`
+}
+
+type Phase string
+
+type Pet struct {
+	Meta
+	*owned
+	ID       int64             `json:"id,string"`
+	Phase    Phase             `json:"phase"`
+	Owner    *Owner            `json:"owner"`
+	Tags     []string          `json:"tags,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Photo    []byte            `json:"photo,omitempty"`
+	Children []Pet             `json:"children,omitempty"`
+	Address  net.IP            `json:"address,omitempty"`
+	Port     IntOrString       `json:"port"`
+	Extra    interface{}       `json:"extra,omitempty"`
+	Ignored  string            `json:"-"`
+	NoTag    bool
+	secret   string
+}
+
+type Owner struct {
+	Name string `json:"name"`
+	Pets []*Pet `json:"pets"`
+}
+
+type IntOrString struct{}
+
+func (IntOrString) OpenAPISchemaType() []string { return []string{"string"} }
+func (IntOrString) OpenAPISchemaFormat() string { return "int-or-string" }
+
+type Custom struct{}
+
+func (*Custom) OpenAPIDefinition() *common.OpenAPIDefinition {
+	return &common.OpenAPIDefinition{Schema: *spec.StringProperty().WithDescription("custom")}
+}
+
+func newTestReflector(t *testing.T) *Reflector {
+	r := NewReflector()
+	r.TypeHook = func(t reflect.Type) *spec.Schema {
+		if t == reflect.TypeOf(net.IP{}) {
+			return new(spec.Schema).Typed("string", "ipv4")
+		}
+		return nil
+	}
+	require.NoError(t, r.Add(Pet{}, &Custom{}))
+	return r
+}
+
+func TestDefinitions(t *testing.T) {
+	defs := newTestReflector(t).Definitions()
+	assert.Len(t, defs, 4)
+
+	ref := func(name string) spec.Schema {
+		return *spec.RefSchema("#/definitions/" + common.EscapeJsonPointer(pkg+"."+name))
+	}
+	pet := defs[pkg+".Pet"]
+	assert.Equal(t, []string{"id", "phase", "port", "NoTag", "name"}, pet.Required)
+	assert.Equal(t, map[string]spec.Schema{
+		"name":      *spec.StringProperty(),
+		"created":   *spec.DateTimeProperty(),
+		"ownerName": *spec.StringProperty(),
+		"id":        *spec.StringProperty(),
+		"phase":     *spec.StringProperty(),
+		"owner":     ref("Owner"),
+		"tags":      *spec.ArrayProperty(spec.StringProperty()),
+		"labels":    *spec.MapProperty(spec.StringProperty()),
+		"photo":     *new(spec.Schema).Typed("string", "byte"),
+		"children":  *spec.ArrayProperty(spec.RefSchema("#/definitions/" + common.EscapeJsonPointer(pkg+".Pet"))),
+		"address":   *new(spec.Schema).Typed("string", "ipv4"),
+		"port":      ref("IntOrString"),
+		"extra":     *new(spec.Schema).Typed("object", ""),
+		"NoTag":     *new(spec.Schema).Typed("boolean", ""),
+	}, pet.Properties)
+
+	owner := defs[pkg+".Owner"]
+	assert.Equal(t, []string{"name", "pets"}, owner.Required)
+	assert.Equal(t, *spec.ArrayProperty(spec.RefSchema("#/definitions/" + common.EscapeJsonPointer(pkg+".Pet"))), owner.Properties["pets"])
+
+	assert.Equal(t, *new(spec.Schema).Typed("string", "int-or-string"), defs[pkg+".IntOrString"])
+	assert.Equal(t, *spec.StringProperty().WithDescription("custom"), defs[pkg+".Custom"])
+}
+
+func TestGetOpenAPIDefinitions(t *testing.T) {
+	r := newTestReflector(t)
+	defs := r.GetOpenAPIDefinitions(func(name string) spec.Ref { return spec.MustCreateRef(name) })
+	assert.Equal(t, []string{pkg + ".IntOrString", pkg + ".Owner", pkg + ".Pet"}, defs[pkg+".Pet"].Dependencies)
+	assert.Equal(t, []string{pkg + ".Pet"}, defs[pkg+".Owner"].Dependencies)
+
+	sw, err := builder.BuildOpenAPIDefinitionsForResources(&common.Config{GetDefinitions: r.GetOpenAPIDefinitions}, pkg+".Owner")
+	require.NoError(t, err)
+	assert.Len(t, sw.Definitions, 3)
+	assert.Equal(t, "#/definitions/reflector.Pet", sw.Definitions["reflector.Owner"].Properties["pets"].Items.Schema.Ref.String())
+}
+
+func TestSchema(t *testing.T) {
+	r := NewReflector()
+	s, err := r.Schema(map[string][]*Meta{}, func(name string) spec.Ref { return spec.MustCreateRef(name) })
+	require.NoError(t, err)
+	assert.Equal(t, spec.MapProperty(spec.ArrayProperty(spec.RefSchema(pkg+".Meta"))), s)
+	assert.Equal(t, "", r.DefinitionName(reflect.TypeOf(Phase(""))))
+	assert.Equal(t, pkg+".Meta", r.DefinitionName(reflect.TypeOf(&Meta{})))
+}
+
+func TestAddErrors(t *testing.T) {
+	type withChannel struct {
+		Events chan string
+	}
+	r := NewReflector()
+	assert.EqualError(t, r.Add(withChannel{}), pkg+".withChannel.Events: unsupported type chan string")
+	assert.EqualError(t, r.Add(map[bool]string{}), "map[bool]string: unsupported map key type bool")
+	assert.Error(t, r.Add(nil))
+	assert.Empty(t, r.Definitions())
+}
+
+func TestValidationStructTags(t *testing.T) {
+	type Server struct {
+		Name     string            `json:"name" openapi:"minLength=1;maxLength=63;pattern=^[a-z]([-a-z0-9]*[a-z0-9])?$"`
+		Port     int               `json:"port" openapi:"minimum=1;maximum=65536;exclusiveMaximum;default=8080"`
+		Protocol string            `json:"protocol,omitempty" openapi:"enum=TCP,UDP"`
+		Aliases  []string          `json:"aliases,omitempty" openapi:"maxItems=3;uniqueItems"`
+		Owner    *Owner            `json:"owner,omitempty" openapi:"nullable"`
+		Labels   map[string]string `json:"labels,omitempty" openapi:"keyPattern=^[a-z]+$"`
+	}
+	r := NewReflector()
+	require.NoError(t, r.Add(Server{}))
+	server := r.Definitions()[pkg+".Server"]
+	assert.Equal(t, *spec.StringProperty().WithMinLength(1).WithMaxLength(63).WithPattern("^[a-z]([-a-z0-9]*[a-z0-9])?$"), server.Properties["name"])
+	assert.Equal(t, *new(spec.Schema).Typed("integer", "int32").WithMinimum(1, false).WithMaximum(65536, true).WithDefault(float64(8080)), server.Properties["port"])
+	assert.Equal(t, *spec.StringProperty().WithEnum("TCP", "UDP"), server.Properties["protocol"])
+	assert.Equal(t, *spec.ArrayProperty(spec.StringProperty()).WithMaxItems(3).UniqueValues(), server.Properties["aliases"])
+	labels := server.Properties["labels"]
+	assert.Equal(t, spec.Extensions{"x-key-pattern": "^[a-z]+$"}, labels.Extensions)
+	owner := server.Properties["owner"]
+	assert.True(t, owner.Nullable)
+	assert.Equal(t, "#/definitions/"+common.EscapeJsonPointer(pkg+".Owner"), owner.Ref.String())
+
+	type invalid struct {
+		Name string `openapi:"maxLength=many"`
+	}
+	assert.EqualError(t, r.Add(invalid{}), pkg+`.invalid.Name: invalid maxLength marker "many": expected a non-negative integer`)
+	type unknown struct {
+		Name string `openapi:"maxlength=1"`
+	}
+	assert.EqualError(t, r.Add(unknown{}), pkg+`.unknown.Name: unknown validation marker "maxlength"`)
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package routes scaffolds a spec from the route table of a router, so
+// that the documented paths and methods follow the routes actually served.
+// Each route becomes an operation with its path parameters; parameters and
+// responses are supplied by handlers implementing OperationDescriber or by
+// Config.DescribeOperation.
+//
+// Routers are walked by their own API:
+//
+//	chi.Walk(router, b.WalkFunc())
+//
+//	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+//		return b.AddRoute(route)
+//	})
+//
+// and registrations on a net/http ServeMux are recorded by wrapping it with
+// Builder.ServeMux.
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// OperationDescriber is implemented by handlers documenting their
+// operation. DescribeOperation fills in the scaffolded operation, e.g. its
+// ID, parameters and responses.
+type OperationDescriber interface {
+	DescribeOperation(op *spec.Operation)
+}
+
+// Config configures a Builder.
+type Config struct {
+	// Info is the info of the spec.
+	Info *spec.Info
+	// DefaultMethods are the methods documented for routes that match any
+	// method. Defaults to GET.
+	DefaultMethods []string
+	// GetOperationID returns the ID of the operation of a route. Defaults to
+	// the lower case method followed by the path segments, e.g. "getPetsId"
+	// for GET /pets/{id}.
+	GetOperationID func(method, path string) string
+	// DescribeOperation, if set, fills in the operation of a route after the
+	// handler described it.
+	DescribeOperation func(method, path string, handler http.Handler, op *spec.Operation)
+}
+
+// Route is a route of a gorilla/mux style router. *mux.Route implements it.
+type Route interface {
+	GetPathTemplate() (string, error)
+	GetMethods() ([]string, error)
+	GetHandler() http.Handler
+}
+
+// Builder builds a spec from routes.
+type Builder struct {
+	config  Config
+	swagger *spec.Swagger
+	err     error
+}
+
+// NewBuilder returns a builder of a spec without any path.
+func NewBuilder(config Config) *Builder {
+	if len(config.DefaultMethods) == 0 {
+		config.DefaultMethods = []string{"GET"}
+	}
+	if config.GetOperationID == nil {
+		config.GetOperationID = operationID
+	}
+	return &Builder{
+		config: config,
+		swagger: &spec.Swagger{
+			SwaggerProps: spec.SwaggerProps{
+				Swagger: "2.0",
+				Info:    config.Info,
+				Paths:   &spec.Paths{Paths: map[string]spec.PathItem{}},
+			},
+		},
+	}
+}
+
+// Add documents a route. The path may use the templates of gorilla/mux and
+// chi, e.g. "/pets/{id:[0-9]+}" or "/static/*", or of net/http patterns,
+// e.g. "/files/{path...}". An empty method or "*" stands for the default
+// methods. Methods that can't be documented, e.g. CONNECT, are ignored, as
+// are routes already added.
+func (b *Builder) Add(method, path string, handler http.Handler) error {
+	template, params, err := pathTemplate(path)
+	if err != nil {
+		return err
+	}
+	methods := []string{strings.ToUpper(method)}
+	if method == "" || method == "*" {
+		methods = b.config.DefaultMethods
+	}
+
+	item := b.swagger.Paths.Paths[template]
+	if len(item.Parameters) == 0 {
+		item.Parameters = params
+	}
+	for _, m := range methods {
+		op := &spec.Operation{OperationProps: spec.OperationProps{ID: b.config.GetOperationID(m, template)}}
+		if item.Operation(m) != nil || !item.SetOperation(m, op) {
+			continue
+		}
+		if d, ok := handler.(OperationDescriber); ok {
+			d.DescribeOperation(op)
+		}
+		if b.config.DescribeOperation != nil {
+			b.config.DescribeOperation(m, template, handler, op)
+		}
+		if op.Responses == nil {
+			op.Responses = &spec.Responses{ResponsesProps: spec.ResponsesProps{
+				Default: &spec.Response{ResponseProps: spec.ResponseProps{Description: "Default response"}},
+			}}
+		}
+	}
+	b.swagger.Paths.Paths[template] = item
+	return nil
+}
+
+// AddRoute documents a gorilla/mux style route. Routes without a path
+// template or handler, e.g. subrouters, are ignored.
+func (b *Builder) AddRoute(route Route) error {
+	path, err := route.GetPathTemplate()
+	if err != nil || route.GetHandler() == nil {
+		return nil
+	}
+	methods, err := route.GetMethods()
+	if err != nil || len(methods) == 0 {
+		methods = []string{""}
+	}
+	for _, m := range methods {
+		if err := b.Add(m, path, route.GetHandler()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkFunc returns a function adding the routes it is called with, to be
+// passed to chi.Walk.
+func (b *Builder) WalkFunc() func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+	return func(method, route string, handler http.Handler, _ ...func(http.Handler) http.Handler) error {
+		return b.Add(method, route, handler)
+	}
+}
+
+// ServeMux wraps a ServeMux to document the patterns registered on it.
+func (b *Builder) ServeMux(mux *http.ServeMux) *ServeMux {
+	return &ServeMux{ServeMux: mux, builder: b}
+}
+
+// Build returns the spec, or the first error of the patterns registered on
+// a ServeMux returned by the builder.
+func (b *Builder) Build() (*spec.Swagger, error) {
+	return b.swagger, b.err
+}
+
+// ServeMux is a ServeMux documenting the patterns registered on it.
+type ServeMux struct {
+	*http.ServeMux
+	builder *Builder
+}
+
+// Handle registers the handler for the pattern and documents it.
+func (m *ServeMux) Handle(pattern string, handler http.Handler) {
+	m.ServeMux.Handle(pattern, handler)
+	m.add(pattern, handler)
+}
+
+// HandleFunc registers the handler function for the pattern and documents
+// it.
+func (m *ServeMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.ServeMux.HandleFunc(pattern, handler)
+	m.add(pattern, http.HandlerFunc(handler))
+}
+
+// add documents a pattern of the form "[METHOD ][HOST]/[PATH]".
+func (m *ServeMux) add(pattern string, handler http.Handler) {
+	method := ""
+	if i := strings.IndexAny(pattern, " \t"); i >= 0 {
+		method, pattern = pattern[:i], strings.TrimLeft(pattern[i:], " \t")
+	}
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+	if err := m.builder.Add(method, pattern, handler); err != nil && m.builder.err == nil {
+		m.builder.err = err
+	}
+}
+
+// pathTemplate converts a route path to an OpenAPI path template and its
+// path parameters. Variable patterns become parameter patterns.
+func pathTemplate(path string) (string, []spec.Parameter, error) {
+	var sb strings.Builder
+	var params []spec.Parameter
+	seen := map[string]bool{}
+	addParam := func(name, pattern string) error {
+		if name == "" {
+			return fmt.Errorf("%s: unnamed path variable", path)
+		}
+		if seen[name] {
+			return fmt.Errorf("%s: duplicate path variable %q", path, name)
+		}
+		seen[name] = true
+		p := spec.PathParam(name).Typed("string", "")
+		if pattern != "" {
+			p.Pattern = "^(?:" + pattern + ")$"
+		}
+		params = append(params, *p)
+		sb.WriteString("{" + name + "}")
+		return nil
+	}
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '{':
+			end, depth := -1, 0
+			for j := i; j < len(path) && end < 0; j++ {
+				switch path[j] {
+				case '{':
+					depth++
+				case '}':
+					if depth--; depth == 0 {
+						end = j
+					}
+				}
+			}
+			if end < 0 {
+				return "", nil, fmt.Errorf("%s: unbalanced braces", path)
+			}
+			variable := path[i+1 : end]
+			i = end
+			if variable == "$" {
+				// net/http: the pattern only matches the path up to here.
+				continue
+			}
+			name, pattern := variable, ""
+			if k := strings.Index(variable, ":"); k >= 0 {
+				name, pattern = variable[:k], variable[k+1:]
+			}
+			if err := addParam(strings.TrimSuffix(name, "..."), pattern); err != nil {
+				return "", nil, err
+			}
+		case '*':
+			// chi: a trailing wildcard matches the rest of the path.
+			if i != len(path)-1 {
+				return "", nil, fmt.Errorf("%s: wildcard not at the end", path)
+			}
+			if err := addParam("wildcard", ""); err != nil {
+				return "", nil, err
+			}
+		default:
+			sb.WriteByte(path[i])
+		}
+	}
+	return sb.String(), params, nil
+}
+
+// operationID returns the lower case method followed by the capitalized
+// path segments, without braces.
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '_' || r == '.'
+	}) {
+		id += strings.ToUpper(segment[:1]) + segment[1:]
+	}
+	return id
+}
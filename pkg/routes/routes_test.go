@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+type getPet struct{}
+
+func (getPet) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+func (getPet) DescribeOperation(op *spec.Operation) {
+	op.ID = "readPet"
+	op.Responses = &spec.Responses{ResponsesProps: spec.ResponsesProps{
+		StatusCodeResponses: map[int]spec.Response{200: *spec.NewResponse().WithDescription("the pet")},
+	}}
+}
+
+var noop = http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+func TestPathTemplate(t *testing.T) {
+	var tests = []struct {
+		path     string
+		template string
+		params   []string
+		pattern  string
+	}{
+		{"/pets", "/pets", nil, ""},
+		{"/pets/{id}", "/pets/{id}", []string{"id"}, ""},
+		{"/pets/{id:[0-9]{1,3}}/toys", "/pets/{id}/toys", []string{"id"}, "^(?:[0-9]{1,3})$"},
+		{"/static/*", "/static/{wildcard}", []string{"wildcard"}, ""},
+		{"/files/{path...}", "/files/{path}", []string{"path"}, ""},
+		{"/{$}", "/", nil, ""},
+	}
+	for _, test := range tests {
+		template, params, err := pathTemplate(test.path)
+		require.NoError(t, err, test.path)
+		assert.Equal(t, test.template, template, test.path)
+		var names []string
+		for _, p := range params {
+			assert.Equal(t, "path", p.In)
+			assert.True(t, p.Required)
+			names = append(names, p.Name)
+		}
+		assert.Equal(t, test.params, names, test.path)
+		if test.pattern != "" {
+			assert.Equal(t, test.pattern, params[0].Pattern, test.path)
+		}
+	}
+
+	for _, path := range []string{"/pets/{id", "/pets/{id}/{id}", "/*/pets", "/pets/{}"} {
+		_, _, err := pathTemplate(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestWalkFunc(t *testing.T) {
+	var described []string
+	b := NewBuilder(Config{
+		Info: &spec.Info{InfoProps: spec.InfoProps{Title: "pets", Version: "1.0"}},
+		DescribeOperation: func(method, path string, _ http.Handler, op *spec.Operation) {
+			described = append(described, method+" "+path)
+		},
+	})
+	walk := b.WalkFunc()
+	require.NoError(t, walk("GET", "/pets/{id}", getPet{}))
+	require.NoError(t, walk("DELETE", "/pets/{id}", noop))
+	require.NoError(t, walk("CONNECT", "/pets/{id}", noop))
+	require.NoError(t, walk("GET", "/pets/{id}", noop))
+	assert.Error(t, walk("GET", "/pets/{id", noop))
+
+	sw, err := b.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "pets", sw.Info.Title)
+	item := sw.Paths.Paths["/pets/{id}"]
+	require.Len(t, item.Parameters, 1)
+	assert.Equal(t, "id", item.Parameters[0].Name)
+	assert.Equal(t, "readPet", item.Get.ID)
+	assert.Equal(t, "the pet", item.Get.Responses.StatusCodeResponses[200].Description)
+	assert.Equal(t, "deletePetsId", item.Delete.ID)
+	assert.Equal(t, "Default response", item.Delete.Responses.Default.Description)
+	assert.Equal(t, []string{"GET /pets/{id}", "DELETE /pets/{id}"}, described)
+}
+
+type fakeRoute struct {
+	path    string
+	methods []string
+	handler http.Handler
+}
+
+func (r fakeRoute) GetPathTemplate() (string, error) {
+	if r.path == "" {
+		return "", errors.New("no path")
+	}
+	return r.path, nil
+}
+
+func (r fakeRoute) GetMethods() ([]string, error) {
+	if len(r.methods) == 0 {
+		return nil, errors.New("no methods")
+	}
+	return r.methods, nil
+}
+
+func (r fakeRoute) GetHandler() http.Handler { return r.handler }
+
+func TestAddRoute(t *testing.T) {
+	b := NewBuilder(Config{DefaultMethods: []string{"GET", "HEAD"}})
+	require.NoError(t, b.AddRoute(fakeRoute{path: "/pets", methods: []string{"GET", "POST"}, handler: noop}))
+	require.NoError(t, b.AddRoute(fakeRoute{path: "/healthz", handler: noop}))
+	require.NoError(t, b.AddRoute(fakeRoute{path: "/api", methods: []string{"GET"}}))
+	require.NoError(t, b.AddRoute(fakeRoute{methods: []string{"GET"}, handler: noop}))
+
+	sw, err := b.Build()
+	require.NoError(t, err)
+	assert.Len(t, sw.Paths.Paths, 2)
+	pets := sw.Paths.Paths["/pets"]
+	assert.NotNil(t, pets.Get)
+	assert.NotNil(t, pets.Post)
+	healthz := sw.Paths.Paths["/healthz"]
+	assert.Equal(t, "getHealthz", healthz.Get.ID)
+	assert.Equal(t, "headHealthz", healthz.Head.ID)
+	assert.Nil(t, healthz.Post)
+}
+
+func TestServeMux(t *testing.T) {
+	b := NewBuilder(Config{})
+	mux := b.ServeMux(http.NewServeMux())
+	mux.Handle("GET example.com/pets/{id}", getPet{})
+	mux.HandleFunc("/healthz", noop)
+
+	sw, err := b.Build()
+	require.NoError(t, err)
+	pet := sw.Paths.Paths["/pets/{id}"]
+	assert.Equal(t, "readPet", pet.Get.ID)
+	healthz := sw.Paths.Paths["/healthz"]
+	assert.Equal(t, "getHealthz", healthz.Get.ID)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemaconv
+
+import (
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+
+	"k8s.io/kube-openapi/pkg/util/proto"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// ToSchemaFromDefinitions converts a set of openapi definitions, e.g. the
+// definitions of a spec built by the builder, into a schema suitable for
+// structured merge. Types are named after their definitions and references
+// between definitions become named type references. List and map
+// semantics are taken from the x-kubernetes-list-type,
+// x-kubernetes-list-map-keys, x-kubernetes-map-type, and
+// x-kubernetes-patch-strategy/x-kubernetes-patch-merge-key extensions.
+func ToSchemaFromDefinitions(definitions spec.Definitions, preserveUnknownFields bool) (*schema.Schema, error) {
+	models, err := proto.NewOpenAPIDataFromSwagger(&spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{Definitions: definitions},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ToSchemaWithPreserveUnknownFields(models, preserveUnknownFields)
+}
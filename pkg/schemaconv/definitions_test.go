@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemaconv
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/structured-merge-diff/v4/schema"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const podDefinitions = `{
+  "io.example.Pod": {
+    "type": "object",
+    "properties": {
+      "containers": {
+        "type": "array",
+        "items": {"$ref": "#/definitions/io.example.Container"},
+        "x-kubernetes-patch-strategy": "merge",
+        "x-kubernetes-patch-merge-key": "name"
+      },
+      "labels": {
+        "type": "object",
+        "additionalProperties": {"type": "string"},
+        "x-kubernetes-map-type": "atomic"
+      }
+    }
+  },
+  "io.example.Container": {
+    "type": "object",
+    "properties": {
+      "name": {"type": "string"},
+      "ports": {
+        "type": "array",
+        "items": {"$ref": "#/definitions/io.example.Port"},
+        "x-kubernetes-list-type": "map",
+        "x-kubernetes-list-map-keys": ["port", "protocol"]
+      },
+      "args": {
+        "type": "array",
+        "items": {"type": "string"},
+        "x-kubernetes-list-type": "set"
+      }
+    }
+  },
+  "io.example.Port": {
+    "type": "object",
+    "properties": {
+      "port": {"type": "integer"},
+      "protocol": {"type": "string"}
+    }
+  }
+}`
+
+func TestToSchemaFromDefinitions(t *testing.T) {
+	var definitions spec.Definitions
+	if err := json.Unmarshal([]byte(podDefinitions), &definitions); err != nil {
+		t.Fatal(err)
+	}
+	s, err := ToSchemaFromDefinitions(definitions, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	field := func(typeName, fieldName string) schema.Atom {
+		t.Helper()
+		tr := schema.TypeRef{NamedType: &typeName}
+		atom, ok := s.Resolve(tr)
+		if !ok || atom.Map == nil {
+			t.Fatalf("type %q is not a map", typeName)
+		}
+		f, ok := atom.Map.FindField(fieldName)
+		if !ok {
+			t.Fatalf("type %q has no field %q", typeName, fieldName)
+		}
+		atom, ok = s.Resolve(f.Type)
+		if !ok {
+			t.Fatalf("cannot resolve %s.%s", typeName, fieldName)
+		}
+		return atom
+	}
+
+	containers := field("io.example.Pod", "containers")
+	if containers.List == nil || containers.List.ElementRelationship != schema.Associative || !reflect.DeepEqual(containers.List.Keys, []string{"name"}) {
+		t.Errorf("expected containers to be a list map keyed by name, got %#v", containers.List)
+	}
+	if name := containers.List.ElementType.NamedType; name == nil || *name != "io.example.Container" {
+		t.Errorf("expected containers to reference io.example.Container, got %v", name)
+	}
+	if labels := field("io.example.Pod", "labels"); labels.Map == nil || labels.Map.ElementRelationship != schema.Atomic {
+		t.Errorf("expected labels to be an atomic map, got %#v", labels.Map)
+	}
+	if ports := field("io.example.Container", "ports"); ports.List == nil || !reflect.DeepEqual(ports.List.Keys, []string{"port", "protocol"}) {
+		t.Errorf("expected ports to be keyed by port and protocol, got %#v", ports.List)
+	}
+	if args := field("io.example.Container", "args"); args.List == nil || args.List.ElementRelationship != schema.Associative || len(args.List.Keys) != 0 {
+		t.Errorf("expected args to be a set, got %#v", args.List)
+	}
+}
+
+func TestToSchemaFromDefinitionsErrors(t *testing.T) {
+	list := spec.ArrayProperty(spec.StringProperty())
+	list.AddExtension("x-kubernetes-list-type", "unordered")
+	definitions := spec.Definitions{
+		"io.example.Bad": *new(spec.Schema).Typed("object", "").SetProperty("items", *list),
+	}
+	if _, err := ToSchemaFromDefinitions(definitions, false); err == nil {
+		t.Error("expected an error for an unknown list type")
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"encoding/json"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Callback maps runtime expressions, e.g. "{$request.body#/callbackUrl}",
+// to the path items describing the requests the API may send to them.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#callback-object
+type Callback struct {
+	spec.Refable
+	Expressions map[string]*Path
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the callback to JSON.
+func (c Callback) MarshalJSON() ([]byte, error) {
+	if c.Ref.String() != "" {
+		return marshal(c.Refable, c.VendorExtensible)
+	}
+	return marshal(c.Expressions, c.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the callback from JSON.
+func (c *Callback) UnmarshalJSON(data []byte) error {
+	var res map[string]json.RawMessage
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+	for k, v := range res {
+		switch {
+		case k == "$ref":
+			if err := json.Unmarshal(data, &c.Refable); err != nil {
+				return err
+			}
+		case strings.HasPrefix(strings.ToLower(k), "x-"):
+			var ext interface{}
+			if err := json.Unmarshal(v, &ext); err != nil {
+				return err
+			}
+			c.AddExtension(k, ext)
+		default:
+			item := &Path{}
+			if err := json.Unmarshal(v, item); err != nil {
+				return err
+			}
+			if c.Expressions == nil {
+				c.Expressions = map[string]*Path{}
+			}
+			c.Expressions[k] = item
+		}
+	}
+	return nil
+}
+
+// ValidateCallbacks checks the callbacks of the operations of the document
+// and of its components: references must resolve and the runtime
+// expressions of their keys must parse. It returns nil or an
+// *errors.CompositeError.
+func (o *OpenAPI) ValidateCallbacks() error {
+	var errs specErrors
+	o.walkPaths(nil, func(path, method string, _ *Path, op *Operation) {
+		for _, name := range sortedKeys(op.Callbacks) {
+			if c := op.Callbacks[name]; c != nil {
+				o.validateCallback("paths."+path+"."+method+".callbacks."+name, c, &errs)
+			}
+		}
+	})
+	components := o.components()
+	for _, name := range sortedKeys(components.Callbacks) {
+		if c := components.Callbacks[name]; c != nil && c.Ref.String() == "" {
+			o.validateCallback("components.callbacks."+name, c, &errs)
+		}
+	}
+	return errs.err()
+}
+
+func (o *OpenAPI) validateCallback(name string, c *Callback, errs *specErrors) {
+	c, err := o.ResolveCallback(c)
+	if err != nil {
+		errs.add(name, "%v", err)
+		return
+	}
+	for _, key := range sortedKeys(c.Expressions) {
+		if strings.HasPrefix(key, "$") {
+			_, err = ParseExpression(key)
+		} else {
+			_, err = ParseTemplate(key)
+		}
+		if err != nil {
+			errs.add(name+"."+key, "%v", err)
+		}
+	}
+}
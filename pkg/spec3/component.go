@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// ComponentsProps are the properties of the components of a document.
+type ComponentsProps struct {
+	Schemas         map[string]*spec.Schema    `json:"schemas,omitempty"`
+	Responses       map[string]*Response       `json:"responses,omitempty"`
+	Parameters      map[string]*Parameter      `json:"parameters,omitempty"`
+	Examples        map[string]*Example        `json:"examples,omitempty"`
+	RequestBodies   map[string]*RequestBody    `json:"requestBodies,omitempty"`
+	Headers         map[string]*Header         `json:"headers,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+	Links           map[string]*Link           `json:"links,omitempty"`
+	Callbacks       map[string]*Callback       `json:"callbacks,omitempty"`
+	// PathItems are reusable path items, since 3.1.
+	PathItems map[string]*Path `json:"pathItems,omitempty"`
+}
+
+// Components holds the reusable objects of a document, referenced as
+// "#/components/<kind>/<name>".
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#components-object
+type Components struct {
+	ComponentsProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the components to JSON.
+func (c Components) MarshalJSON() ([]byte, error) {
+	return marshal(c.ComponentsProps, c.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the components from JSON.
+func (c *Components) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &c.ComponentsProps, &c.VendorExtensible)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Principal holds the credentials presented with a request for one security
+// scheme, normalized across scheme types. They are not verified.
+type Principal struct {
+	// Scheme is the name of the security scheme in the components.
+	Scheme string
+	// Type is the type of the security scheme, e.g. "http".
+	Type string
+	// Username and Password are set by the basic HTTP authentication.
+	// Username is also the common name of the client certificate with
+	// mutual TLS.
+	Username string
+	Password string
+	// Token is the bearer token, the API key or the credentials of other
+	// HTTP authentication schemes.
+	Token string
+}
+
+// ExtractCredentials returns the credentials a request presents for the
+// named security scheme, or nil if it presents none: the API key from its
+// header, query parameter or cookie, the Authorization header for HTTP
+// authentication, whose basic credentials are decoded, the bearer token
+// for oauth2 and openIdConnect, and the client certificate for mutual TLS.
+// It fails when the credentials are malformed.
+func ExtractCredentials(r *http.Request, name string, s *SecurityScheme) (*Principal, error) {
+	p := &Principal{Scheme: name, Type: s.Type}
+	switch s.Type {
+	case "apiKey":
+		switch s.In {
+		case "header":
+			p.Token = r.Header.Get(s.Name)
+		case "query":
+			p.Token = r.URL.Query().Get(s.Name)
+		case "cookie":
+			if c, err := r.Cookie(s.Name); err == nil {
+				p.Token = c.Value
+			}
+		}
+		if p.Token == "" {
+			return nil, nil
+		}
+	case "http":
+		credentials, ok := authorization(r, s.Scheme)
+		if !ok {
+			return nil, nil
+		}
+		if !strings.EqualFold(s.Scheme, "basic") {
+			p.Token = credentials
+			break
+		}
+		decoded, err := base64.StdEncoding.DecodeString(credentials)
+		if err != nil {
+			return nil, fmt.Errorf("security scheme %s: malformed basic credentials: %v", name, err)
+		}
+		i := strings.IndexByte(string(decoded), ':')
+		if i < 0 {
+			return nil, fmt.Errorf("security scheme %s: malformed basic credentials: no colon", name)
+		}
+		p.Username, p.Password = string(decoded[:i]), string(decoded[i+1:])
+	case "oauth2", "openIdConnect":
+		token, ok := authorization(r, "bearer")
+		if !ok {
+			return nil, nil
+		}
+		p.Token = token
+	case "mutualTLS":
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			return nil, nil
+		}
+		p.Username = r.TLS.PeerCertificates[0].Subject.CommonName
+	default:
+		return nil, fmt.Errorf("security scheme %s: unsupported type %q", name, s.Type)
+	}
+	return p, nil
+}
+
+// authorization returns the credentials of the Authorization header of a
+// request if it uses the given authentication scheme, which is case
+// insensitive.
+func authorization(r *http.Request, scheme string) (string, bool) {
+	parts := strings.SplitN(strings.TrimSpace(r.Header.Get("Authorization")), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], scheme) {
+		return "", false
+	}
+	credentials := strings.TrimSpace(parts[1])
+	return credentials, credentials != ""
+}
+
+// Credentials returns the credentials a request presents for the
+// security schemes of the document's components, keyed by scheme name.
+// Schemes the request presents no credentials for are omitted, and
+// references are followed.
+func (o *OpenAPI) Credentials(r *http.Request) (map[string]*Principal, error) {
+	ret := map[string]*Principal{}
+	schemes := o.components().SecuritySchemes
+	for _, name := range sortedKeys(schemes) {
+		s := schemes[name]
+		if s == nil {
+			continue
+		}
+		s, err := o.ResolveSecurityScheme(s)
+		if err != nil {
+			return nil, err
+		}
+		p, err := ExtractCredentials(r, name, s)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			ret[name] = p
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractCredentials(t *testing.T) {
+	scheme := func(props SecuritySchemeProps) *SecurityScheme {
+		return &SecurityScheme{SecuritySchemeProps: props}
+	}
+	basic := scheme(SecuritySchemeProps{Type: "http", Scheme: "basic"})
+
+	r := httptest.NewRequest("GET", "/pets?key=q", nil)
+	r.SetBasicAuth("rex", "pa:ss")
+	p, err := ExtractCredentials(r, "basic", basic)
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Scheme: "basic", Type: "http", Username: "rex", Password: "pa:ss"}, p)
+
+	p, err = ExtractCredentials(r, "bearer", scheme(SecuritySchemeProps{Type: "http", Scheme: "bearer"}))
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	r.Header.Set("Authorization", "Basic !!!")
+	_, err = ExtractCredentials(r, "basic", basic)
+	assert.Error(t, err)
+	r.Header.Set("Authorization", "Basic cmV4")
+	_, err = ExtractCredentials(r, "basic", basic)
+	assert.EqualError(t, err, "security scheme basic: malformed basic credentials: no colon")
+
+	r.Header.Set("Authorization", "bearer abc")
+	for _, s := range []*SecurityScheme{
+		scheme(SecuritySchemeProps{Type: "http", Scheme: "Bearer"}),
+		scheme(SecuritySchemeProps{Type: "oauth2"}),
+		scheme(SecuritySchemeProps{Type: "openIdConnect"}),
+	} {
+		p, err = ExtractCredentials(r, "token", s)
+		require.NoError(t, err)
+		require.NotNil(t, p, s.Type)
+		assert.Equal(t, "abc", p.Token)
+	}
+
+	r.Header.Set("X-API-Key", "h")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "c"})
+	for in, want := range map[string]string{"header": "h", "query": "q", "cookie": "c"} {
+		name := map[string]string{"header": "X-API-Key", "query": "key", "cookie": "session"}[in]
+		p, err = ExtractCredentials(r, "key", scheme(SecuritySchemeProps{Type: "apiKey", In: in, Name: name}))
+		require.NoError(t, err)
+		require.NotNil(t, p, in)
+		assert.Equal(t, want, p.Token, in)
+	}
+	p, err = ExtractCredentials(r, "key", scheme(SecuritySchemeProps{Type: "apiKey", In: "query", Name: "missing"}))
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	mtls := scheme(SecuritySchemeProps{Type: "mutualTLS"})
+	p, err = ExtractCredentials(r, "mtls", mtls)
+	require.NoError(t, err)
+	assert.Nil(t, p)
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "client"}}}}
+	p, err = ExtractCredentials(r, "mtls", mtls)
+	require.NoError(t, err)
+	assert.Equal(t, "client", p.Username)
+
+	_, err = ExtractCredentials(r, "x", scheme(SecuritySchemeProps{Type: "magic"}))
+	assert.Error(t, err)
+}
+
+func TestOpenAPICredentials(t *testing.T) {
+	o := loadSecurityDoc(t)
+	r := httptest.NewRequest("GET", "/pets", nil)
+	r.Header.Set("X-API-Key", "k")
+	r.Header.Set("Authorization", "Bearer t")
+
+	creds, err := o.Credentials(r)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*Principal{
+		"alias": {Scheme: "alias", Type: "apiKey", Token: "k"},
+		"key":   {Scheme: "key", Type: "apiKey", Token: "k"},
+		"oauth": {Scheme: "oauth", Type: "oauth2", Token: "t"},
+		"oidc":  {Scheme: "oidc", Type: "openIdConnect", Token: "t"},
+	}, creds)
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"strconv"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// ExampleProps are the properties of an example.
+type ExampleProps struct {
+	Summary       string      `json:"summary,omitempty"`
+	Description   string      `json:"description,omitempty"`
+	Value         interface{} `json:"value,omitempty"`
+	ExternalValue string      `json:"externalValue,omitempty"`
+}
+
+// Example is a named example of a parameter or media type.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#example-object
+type Example struct {
+	spec.Refable
+	ExampleProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the example to JSON.
+func (e Example) MarshalJSON() ([]byte, error) {
+	return marshal(e.Refable, e.ExampleProps, e.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the example from JSON.
+func (e *Example) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &e.Refable, &e.ExampleProps, &e.VendorExtensible)
+}
+
+// ValidateExamples checks that the examples of the document validate
+// against their schemas: the examples of the schema components and of the
+// schemas they nest, and the example and examples of parameters, headers
+// and JSON media types, in components, paths and webhooks. Referenced
+// components are checked where they are defined and external example
+// values are not checked. The errors are named after the path of the
+// non-conforming value, e.g.
+// "paths./pets.get.responses.200.content.application/json.example.name".
+// It returns nil or an *errors.CompositeError.
+func (o *OpenAPI) ValidateExamples() error {
+	v := &valueValidator{doc: o}
+	v.document()
+	return v.errs.err()
+}
+
+// ValidateDefaults checks that the defaults of the schemas of the document
+// validate against these schemas: the schema components, and the schemas of
+// parameters, headers and media types, in components, paths and webhooks.
+// The errors are named after the path of the non-conforming value, e.g.
+// "components.schemas.Pet.properties.age.default". It returns nil or an
+// *errors.CompositeError.
+func (o *OpenAPI) ValidateDefaults() error {
+	v := &valueValidator{doc: o, defaults: true}
+	v.document()
+	return v.errs.err()
+}
+
+// valueValidator checks either the examples or the defaults of a document.
+type valueValidator struct {
+	doc      *OpenAPI
+	defaults bool
+	errs     specErrors
+}
+
+func (v *valueValidator) document() {
+	o := v.doc
+	c := o.components()
+	for _, name := range sortedKeys(c.Schemas) {
+		v.schema("components.schemas."+name, c.Schemas[name])
+	}
+	for _, name := range sortedKeys(c.Parameters) {
+		v.parameter("components.parameters."+name, c.Parameters[name])
+	}
+	for _, name := range sortedKeys(c.Headers) {
+		v.header("components.headers."+name, c.Headers[name])
+	}
+	for _, name := range sortedKeys(c.RequestBodies) {
+		if rb := c.RequestBodies[name]; rb != nil && rb.Ref.String() == "" {
+			v.content("components.requestBodies."+name+".content", rb.Content)
+		}
+	}
+	for _, name := range sortedKeys(c.Responses) {
+		v.response("components.responses."+name, c.Responses[name])
+	}
+	o.walkPaths(func(path string, item *Path) {
+		v.path("paths."+path, item)
+	}, nil)
+	for _, name := range sortedKeys(o.Webhooks) {
+		if item := o.Webhooks[name]; item != nil {
+			v.path("webhooks."+name, item)
+		}
+	}
+}
+
+func (v *valueValidator) resolveSchema(ref string) *spec.Schema {
+	name, err := componentName(spec.MustCreateRef(ref), "schemas")
+	if err != nil {
+		return nil
+	}
+	return v.doc.components().Schemas[name]
+}
+
+func (v *valueValidator) check(name string, example interface{}, s *spec.Schema) {
+	if v.defaults || example == nil || s == nil {
+		return
+	}
+	v.errs = append(v.errs, validate.Example(name, example, s, v.resolveSchema).Errors...)
+}
+
+func (v *valueValidator) schema(name string, s *spec.Schema) {
+	switch {
+	case s == nil:
+	case v.defaults:
+		v.errs = append(v.errs, validate.SchemaDefaults(name, s, v.resolveSchema).Errors...)
+	default:
+		v.errs = append(v.errs, validate.SchemaExamples(name, s, v.resolveSchema).Errors...)
+	}
+}
+
+func (v *valueValidator) examples(name string, examples map[string]*Example, s *spec.Schema) {
+	if v.defaults {
+		return
+	}
+	for _, k := range sortedKeys(examples) {
+		e := examples[k]
+		if e == nil {
+			continue
+		}
+		e, err := v.doc.ResolveExample(e)
+		if err != nil {
+			v.errs.add(name+".examples."+k, "%v", err)
+			continue
+		}
+		v.check(name+".examples."+k+".value", e.Value, s)
+	}
+}
+
+func (v *valueValidator) path(name string, item *Path) {
+	for i, p := range item.Parameters {
+		v.parameter(name+".parameters."+strconv.Itoa(i), p)
+	}
+	ops := item.Operations()
+	for _, method := range sortedKeys(ops) {
+		op, opName := ops[method], name+"."+method
+		for i, p := range op.Parameters {
+			v.parameter(opName+".parameters."+strconv.Itoa(i), p)
+		}
+		if rb := op.RequestBody; rb != nil && rb.Ref.String() == "" {
+			v.content(opName+".requestBody.content", rb.Content)
+		}
+		if op.Responses == nil {
+			continue
+		}
+		for _, code := range sortedKeys(op.Responses.StatusCodeResponses) {
+			v.response(opName+".responses."+code, op.Responses.StatusCodeResponses[code])
+		}
+		if op.Responses.Default != nil {
+			v.response(opName+".responses.default", op.Responses.Default)
+		}
+	}
+}
+
+func (v *valueValidator) parameter(name string, p *Parameter) {
+	if p == nil || p.Ref.String() != "" {
+		return
+	}
+	v.schema(name+".schema", p.Schema)
+	v.check(name+".example", p.Example, p.Schema)
+	v.examples(name, p.Examples, p.Schema)
+	v.content(name+".content", p.Content)
+}
+
+func (v *valueValidator) header(name string, h *Header) {
+	if h == nil || h.Ref.String() != "" {
+		return
+	}
+	v.schema(name+".schema", h.Schema)
+	v.check(name+".example", h.Example, h.Schema)
+	v.examples(name, h.Examples, h.Schema)
+	v.content(name+".content", h.Content)
+}
+
+func (v *valueValidator) response(name string, r *Response) {
+	if r == nil || r.Ref.String() != "" {
+		return
+	}
+	for _, h := range sortedKeys(r.Headers) {
+		v.header(name+".headers."+h, r.Headers[h])
+	}
+	v.content(name+".content", r.Content)
+}
+
+func (v *valueValidator) content(name string, content map[string]*MediaType) {
+	for _, mt := range sortedKeys(content) {
+		m := content[mt]
+		if m == nil {
+			continue
+		}
+		v.schema(name+"."+mt+".schema", m.Schema)
+		// Other media types have examples in their own format.
+		if !strings.Contains(mt, "json") {
+			continue
+		}
+		v.check(name+"."+mt+".example", m.Example, m.Schema)
+		v.examples(name+"."+mt, m.Examples, m.Schema)
+	}
+}
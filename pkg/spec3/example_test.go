@@ -0,0 +1,156 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const examplesDoc = `{
+  "openapi": "3.0.3",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "maximum": 100}, "example": 500}],
+      "post": {
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {"$ref": "#/components/schemas/Pet"},
+              "examples": {"cat": {"$ref": "#/components/examples/Cat"}, "broken": {"$ref": "#/components/examples/Missing"}}
+            },
+            "application/xml": {"schema": {"$ref": "#/components/schemas/Pet"}, "example": "<pet/>"}
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "ok",
+            "headers": {"X-Rate": {"schema": {"type": "integer"}, "example": "fast"}},
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}, "example": {"name": 1}}}
+          }
+        }
+      }
+    }
+  },
+  "webhooks": {
+    "newPet": {"post": {"requestBody": {"content": {"application/json": {"schema": {"type": "string"}, "example": 2}}}, "responses": {"200": {"description": "ok"}}}}
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {"name": {"type": "string"}, "age": {"type": "integer", "example": "old"}},
+        "example": {"name": "rex"}
+      }
+    },
+    "examples": {"Cat": {"value": {"age": 3}}}
+  }
+}`
+
+func TestValidateExamples(t *testing.T) {
+	var o OpenAPI
+	require.NoError(t, json.Unmarshal([]byte(examplesDoc), &o))
+	err := o.ValidateExamples()
+	require.Error(t, err)
+
+	var msgs []string
+	for _, e := range err.(*errors.CompositeError).Errors {
+		msgs = append(msgs, e.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		`components.schemas.Pet.properties.age.example in body must be of type integer: "string"`,
+		`paths./pets.parameters.0.example in body should be less than or equal to 100`,
+		`paths./pets.post.requestBody.content.application/json.examples.cat.value.name in body is required`,
+		`paths./pets.post.requestBody.content.application/json.examples.broken is invalid: example #/components/examples/Missing can't be resolved`,
+		`paths./pets.post.responses.200.headers.X-Rate.example in body must be of type integer: "string"`,
+		`paths./pets.post.responses.200.content.application/json.example.name in body must be of type string: "number"`,
+		`webhooks.newPet.post.requestBody.content.application/json.example in body must be of type string: "number"`,
+	}, msgs)
+
+	valid := OpenAPI{OpenAPIProps: OpenAPIProps{Components: &Components{ComponentsProps: ComponentsProps{
+		Schemas: map[string]*spec.Schema{"Name": spec.StringProperty().WithExample("rex")},
+	}}}}
+	assert.NoError(t, valid.ValidateExamples())
+}
+
+const defaultsDoc = `{
+  "openapi": "3.1.0",
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "properties": {
+          "age": {"type": "integer", "minimum": 0, "default": -1, "example": "old"},
+          "name": {"type": "string", "default": "rex"}
+        }
+      }
+    }
+  },
+  "paths": {
+    "/pets": {
+      "parameters": [
+        {"name": "order", "in": "query", "schema": {"type": "string", "enum": ["asc", "desc"], "default": "up"}}
+      ],
+      "post": {
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {"type": "object", "properties": {"size": {"type": "integer", "default": "big"}}}
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "headers": {"X-Rate": {"schema": {"type": "integer", "default": 1.5}}}
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestValidateDefaults(t *testing.T) {
+	var o OpenAPI
+	require.NoError(t, json.Unmarshal([]byte(defaultsDoc), &o))
+	err := o.ValidateDefaults()
+	require.Error(t, err)
+
+	var msgs []string
+	for _, e := range err.(*errors.CompositeError).Errors {
+		msgs = append(msgs, e.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		`components.schemas.Pet.properties.age.default in body should be greater than or equal to 0`,
+		`paths./pets.parameters.0.schema.default in body should be one of [asc desc]`,
+		`paths./pets.post.requestBody.content.application/json.schema.properties.size.default in body must be of type integer: "string"`,
+		`paths./pets.post.responses.200.headers.X-Rate.schema.default in body must be of type integer: "number"`,
+	}, msgs)
+
+	valid := OpenAPI{OpenAPIProps: OpenAPIProps{Components: &Components{ComponentsProps: ComponentsProps{
+		Schemas: map[string]*spec.Schema{"Name": spec.StringProperty().WithDefault("rex")},
+	}}}}
+	assert.NoError(t, valid.ValidateDefaults())
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expression is a parsed runtime expression, used by links and callbacks to
+// refer to a value of the HTTP messages of an operation, e.g.
+// "$request.body#/id" or "$response.header.Location".
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#runtime-expressions
+type Expression struct {
+	// Source is one of "url", "method", "statusCode", "request" and
+	// "response".
+	Source string
+	// Location is one of "header", "query", "path" and "body" for request
+	// and response expressions, and empty otherwise.
+	Location string
+	// Name is the name of the header, query or path parameter.
+	Name string
+	// Pointer is the JSON pointer into the body, possibly empty for the
+	// whole body.
+	Pointer string
+}
+
+// String returns the expression as written in a document.
+func (e *Expression) String() string {
+	switch {
+	case e.Location == "":
+		return "$" + e.Source
+	case e.Location == "body" && e.Pointer != "":
+		return "$" + e.Source + ".body#" + e.Pointer
+	case e.Location == "body":
+		return "$" + e.Source + ".body"
+	}
+	return "$" + e.Source + "." + e.Location + "." + e.Name
+}
+
+// ParseExpression parses a runtime expression such as "$request.path.id".
+func ParseExpression(s string) (*Expression, error) {
+	switch s {
+	case "$url", "$method", "$statusCode":
+		return &Expression{Source: s[1:]}, nil
+	}
+	var e Expression
+	var rest string
+	switch {
+	case strings.HasPrefix(s, "$request."):
+		e.Source, rest = "request", s[len("$request."):]
+	case strings.HasPrefix(s, "$response."):
+		e.Source, rest = "response", s[len("$response."):]
+	default:
+		return nil, fmt.Errorf("invalid runtime expression %q", s)
+	}
+
+	if rest == "body" || strings.HasPrefix(rest, "body#") {
+		e.Location = "body"
+		e.Pointer = strings.TrimPrefix(strings.TrimPrefix(rest, "body"), "#")
+		if err := validatePointer(e.Pointer); err != nil {
+			return nil, fmt.Errorf("invalid runtime expression %q: %v", s, err)
+		}
+		return &e, nil
+	}
+	i := strings.Index(rest, ".")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid runtime expression %q", s)
+	}
+	e.Location, e.Name = rest[:i], rest[i+1:]
+	switch e.Location {
+	case "header":
+		if !isToken(e.Name) {
+			return nil, fmt.Errorf("invalid runtime expression %q: %q is not a valid header name", s, e.Name)
+		}
+	case "query", "path":
+		if e.Name == "" {
+			return nil, fmt.Errorf("invalid runtime expression %q: missing %s parameter name", s, e.Location)
+		}
+	default:
+		return nil, fmt.Errorf("invalid runtime expression %q: unknown location %q", s, e.Location)
+	}
+	return &e, nil
+}
+
+// ParseTemplate parses the runtime expressions embedded in braces in a
+// string, e.g. the callback expression
+// "{$request.body#/callbackUrl}?id={$request.query.id}".
+func ParseTemplate(s string) ([]*Expression, error) {
+	var ret []*Expression
+	for {
+		start := strings.Index(s, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated expression in %q", s)
+		}
+		e, err := ParseExpression(s[start+1 : start+end])
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, e)
+		s = s[start+end+1:]
+	}
+	return ret, nil
+}
+
+func validatePointer(p string) error {
+	if p != "" && !strings.HasPrefix(p, "/") {
+		return fmt.Errorf("JSON pointer %q must start with /", p)
+	}
+	for i := 0; i < len(p); i++ {
+		if p[i] == '~' && (i+1 == len(p) || (p[i+1] != '0' && p[i+1] != '1')) {
+			return fmt.Errorf("JSON pointer %q has an invalid escape", p)
+		}
+	}
+	return nil
+}
+
+// isToken reports whether s is a valid HTTP token, as used for header names.
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c > 127 || !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || strings.ContainsRune("!#$%&'*+-.^_`|~", c)) {
+			return false
+		}
+	}
+	return true
+}
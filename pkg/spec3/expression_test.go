@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpression(t *testing.T) {
+	for s, want := range map[string]Expression{
+		"$url":                         {Source: "url"},
+		"$statusCode":                  {Source: "statusCode"},
+		"$request.body":                {Source: "request", Location: "body"},
+		"$request.body#/id":            {Source: "request", Location: "body", Pointer: "/id"},
+		"$response.body#/a~1b/0":       {Source: "response", Location: "body", Pointer: "/a~1b/0"},
+		"$request.path.id":             {Source: "request", Location: "path", Name: "id"},
+		"$request.query.queryUrl":      {Source: "request", Location: "query", Name: "queryUrl"},
+		"$response.header.Location":    {Source: "response", Location: "header", Name: "Location"},
+		"$request.header.X-Rate-Limit": {Source: "request", Location: "header", Name: "X-Rate-Limit"},
+	} {
+		e, err := ParseExpression(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, *e, s)
+		assert.Equal(t, s, e.String())
+	}
+
+	for _, s := range []string{
+		"", "url", "$uri", "$request", "$request.", "$request.cookie.a", "$request.path.",
+		"$response.header.bad header", "$request.body#id", "$request.body#/a~2",
+	} {
+		_, err := ParseExpression(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	l, err := ParseTemplate("{$request.body#/callbackUrl}?id={$request.query.id}")
+	require.NoError(t, err)
+	require.Len(t, l, 2)
+	assert.Equal(t, "$request.body#/callbackUrl", l[0].String())
+	assert.Equal(t, "$request.query.id", l[1].String())
+
+	l, err = ParseTemplate("http://example.com/hook")
+	require.NoError(t, err)
+	assert.Empty(t, l)
+
+	_, err = ParseTemplate("{$request.body")
+	assert.Error(t, err)
+	_, err = ParseTemplate("{$request.nope.x}")
+	assert.Error(t, err)
+}
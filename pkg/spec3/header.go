@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// HeaderProps are the properties of a header. They are those of a
+// parameter, without name and location.
+type HeaderProps struct {
+	Description     string                `json:"description,omitempty"`
+	Required        bool                  `json:"required,omitempty"`
+	Deprecated      bool                  `json:"deprecated,omitempty"`
+	AllowEmptyValue bool                  `json:"allowEmptyValue,omitempty"`
+	Style           string                `json:"style,omitempty"`
+	Explode         *bool                 `json:"explode,omitempty"`
+	AllowReserved   bool                  `json:"allowReserved,omitempty"`
+	Schema          *spec.Schema          `json:"schema,omitempty"`
+	Content         map[string]*MediaType `json:"content,omitempty"`
+	Example         interface{}           `json:"example,omitempty"`
+	Examples        map[string]*Example   `json:"examples,omitempty"`
+}
+
+// Header describes a response or encoding header.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#header-object
+type Header struct {
+	spec.Refable
+	HeaderProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the header to JSON.
+func (h Header) MarshalJSON() ([]byte, error) {
+	return marshal(h.Refable, h.HeaderProps, h.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the header from JSON.
+func (h *Header) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &h.Refable, &h.HeaderProps, &h.VendorExtensible)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// LinkProps are the properties of a link.
+type LinkProps struct {
+	// OperationRef is a reference to the target operation, e.g.
+	// "#/paths/~1pets~1{id}/get". It is mutually exclusive with OperationID.
+	OperationRef string `json:"operationRef,omitempty"`
+	OperationID  string `json:"operationId,omitempty"`
+	// Parameters are the values passed to the parameters of the target
+	// operation, keyed by name, optionally qualified by location as in
+	// "path.id". String values starting with "$" are runtime expressions.
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	RequestBody interface{}            `json:"requestBody,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Server      *Server                `json:"server,omitempty"`
+}
+
+// Link describes how a value of a response can be used as input of
+// another operation.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#link-object
+type Link struct {
+	spec.Refable
+	LinkProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the link to JSON.
+func (l Link) MarshalJSON() ([]byte, error) {
+	return marshal(l.Refable, l.LinkProps, l.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the link from JSON.
+func (l *Link) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &l.Refable, &l.LinkProps, &l.VendorExtensible)
+}
+
+// ValidateLinks checks the links of the responses of the document and of
+// its components: references must resolve, each link must target an
+// operation of the document, its runtime expressions must parse, and its
+// parameters must be parameters of the target operation. Links to
+// operations of other documents are only checked for their expressions.
+// It returns nil or an *errors.CompositeError.
+func (o *OpenAPI) ValidateLinks() error {
+	var errs specErrors
+	o.walkPaths(nil, func(path, method string, _ *Path, op *Operation) {
+		if op.Responses == nil {
+			return
+		}
+		name := "paths." + path + "." + method + ".responses."
+		for _, code := range sortedKeys(op.Responses.StatusCodeResponses) {
+			o.validateResponseLinks(name+code, op.Responses.StatusCodeResponses[code], &errs)
+		}
+		if op.Responses.Default != nil {
+			o.validateResponseLinks(name+"default", op.Responses.Default, &errs)
+		}
+	})
+	components := o.components()
+	for _, name := range sortedKeys(components.Responses) {
+		o.validateResponseLinks("components.responses."+name, components.Responses[name], &errs)
+	}
+	for _, name := range sortedKeys(components.Links) {
+		if l := components.Links[name]; l != nil {
+			o.validateLink("components.links."+name, l, &errs)
+		}
+	}
+	return errs.err()
+}
+
+func (o *OpenAPI) validateResponseLinks(name string, r *Response, errs *specErrors) {
+	// Referenced responses are validated as components.
+	if r == nil || r.Ref.String() != "" {
+		return
+	}
+	for _, link := range sortedKeys(r.Links) {
+		if l := r.Links[link]; l != nil {
+			o.validateLink(name+".links."+link, l, errs)
+		}
+	}
+}
+
+func (o *OpenAPI) validateLink(name string, l *Link, errs *specErrors) {
+	l, err := o.ResolveLink(l)
+	if err != nil {
+		errs.add(name, "%v", err)
+		return
+	}
+
+	for _, p := range sortedKeys(l.Parameters) {
+		validateExpressionValue(name+".parameters."+p, l.Parameters[p], errs)
+	}
+	validateExpressionValue(name+".requestBody", l.RequestBody, errs)
+
+	var item *Path
+	var op *Operation
+	switch {
+	case l.OperationRef != "" && l.OperationID != "":
+		errs.add(name, "operationRef and operationId are mutually exclusive")
+		return
+	case l.OperationID != "":
+		if item, op = o.OperationByID(l.OperationID); op == nil {
+			errs.add(name, "no operation with operationId %q", l.OperationID)
+			return
+		}
+	case l.OperationRef != "":
+		if !strings.HasPrefix(l.OperationRef, "#") {
+			return
+		}
+		if item, op, err = o.OperationByRef(l.OperationRef); err != nil {
+			errs.add(name, "%v", err)
+			return
+		}
+	default:
+		errs.add(name, "operationRef or operationId is required")
+		return
+	}
+
+	for _, p := range sortedKeys(l.Parameters) {
+		if !o.hasParameter(item, op, p) {
+			errs.add(name+".parameters."+p, "the target operation has no such parameter")
+		}
+	}
+	if l.RequestBody != nil && op.RequestBody == nil {
+		errs.add(name+".requestBody", "the target operation has no request body")
+	}
+}
+
+// validateExpressionValue checks the runtime expressions of a link parameter
+// or request body. Strings starting with "$" are expressions, strings
+// holding "{$" embed expressions, and other values are constants.
+func validateExpressionValue(name string, v interface{}, errs *specErrors) {
+	s, ok := v.(string)
+	if !ok {
+		return
+	}
+	var err error
+	switch {
+	case strings.HasPrefix(s, "$"):
+		_, err = ParseExpression(s)
+	case strings.Contains(s, "{$"):
+		_, err = ParseTemplate(s)
+	}
+	if err != nil {
+		errs.add(name, "%v", err)
+	}
+}
+
+// hasParameter returns whether an operation, or its path item, declares the
+// parameter of a link. The name may be qualified by location, as in
+// "path.id".
+func (o *OpenAPI) hasParameter(item *Path, op *Operation, name string) bool {
+	in, unqualified := "", name
+	for _, loc := range []string{"path", "query", "header", "cookie"} {
+		if strings.HasPrefix(name, loc+".") {
+			in, unqualified = loc, name[len(loc)+1:]
+			break
+		}
+	}
+	for _, p := range append(append([]*Parameter{}, item.Parameters...), op.Parameters...) {
+		if p == nil {
+			continue
+		}
+		p, err := o.ResolveParameter(p)
+		if err != nil {
+			continue
+		}
+		if p.Name == name || (p.Name == unqualified && p.In == in) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const linkedDoc = `{
+  "openapi": "3.0.3",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "post": {
+        "operationId": "createPet",
+        "requestBody": {"content": {"application/json": {"schema": {"type": "object"}}}},
+        "responses": {
+          "201": {
+            "description": "created",
+            "links": {
+              "get": {"operationId": "getPet", "parameters": {"path.id": "$response.body#/id"}},
+              "update": {"$ref": "#/components/links/UpdatePet"}
+            }
+          }
+        },
+        "callbacks": {
+          "onCreated": {"{$request.body#/callbackUrl}?id={$response.body#/id}": {"post": {"responses": {"200": {"description": "ok"}}}}},
+          "shared": {"$ref": "#/components/callbacks/Event"}
+        }
+      }
+    },
+    "/pets/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "get": {"operationId": "getPet", "responses": {"200": {"description": "ok"}}},
+      "put": {
+        "operationId": "updatePet",
+        "requestBody": {"content": {"application/json": {"schema": {"type": "object"}}}},
+        "parameters": [{"name": "dryRun", "in": "query"}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  },
+  "components": {
+    "parameters": {"ID": {"name": "id", "in": "path", "required": true}},
+    "links": {
+      "UpdatePet": {
+        "operationRef": "#/paths/~1pets~1{id}/put",
+        "parameters": {"id": "$response.body#/id", "dryRun": true},
+        "requestBody": "$request.body"
+      }
+    },
+    "callbacks": {
+      "Event": {"$request.query.hook": {"post": {"responses": {"200": {"description": "ok"}}}}}
+    }
+  }
+}`
+
+func loadLinkedDoc(t *testing.T) *OpenAPI {
+	var o OpenAPI
+	require.NoError(t, json.Unmarshal([]byte(linkedDoc), &o))
+	return &o
+}
+
+func TestLinksAndCallbacksRoundTrip(t *testing.T) {
+	o := loadLinkedDoc(t)
+	post := o.Paths.Paths["/pets"].Post
+	links := post.Responses.StatusCodeResponses["201"].Links
+	assert.Equal(t, "getPet", links["get"].OperationID)
+	assert.Equal(t, "#/components/links/UpdatePet", links["update"].Ref.String())
+	assert.Contains(t, post.Callbacks["onCreated"].Expressions, "{$request.body#/callbackUrl}?id={$response.body#/id}")
+	assert.Equal(t, "#/components/callbacks/Event", post.Callbacks["shared"].Ref.String())
+	assert.Empty(t, post.Callbacks["shared"].Expressions)
+
+	data, err := json.Marshal(o)
+	require.NoError(t, err)
+	assert.JSONEq(t, linkedDoc, string(data))
+}
+
+func TestResolve(t *testing.T) {
+	o := loadLinkedDoc(t)
+	links := o.Paths.Paths["/pets"].Post.Responses.StatusCodeResponses["201"].Links
+	l, err := o.ResolveLink(links["update"])
+	require.NoError(t, err)
+	assert.Equal(t, "#/paths/~1pets~1{id}/put", l.OperationRef)
+
+	l, err = o.ResolveLink(links["get"])
+	require.NoError(t, err)
+	assert.Same(t, links["get"], l)
+
+	c, err := o.ResolveCallback(o.Paths.Paths["/pets"].Post.Callbacks["shared"])
+	require.NoError(t, err)
+	assert.Contains(t, c.Expressions, "$request.query.hook")
+
+	p, err := o.ResolveParameter(o.Paths.Paths["/pets/{id}"].Parameters[0])
+	require.NoError(t, err)
+	assert.Equal(t, "id", p.Name)
+
+	item, op, err := o.OperationByRef("#/paths/~1pets~1{id}/put")
+	require.NoError(t, err)
+	assert.Equal(t, "updatePet", op.OperationID)
+	assert.Same(t, o.Paths.Paths["/pets/{id}"], item)
+
+	_, op = o.OperationByID("getPet")
+	require.NotNil(t, op)
+
+	for _, ref := range []string{"#/components/links/Missing", "#/components/parameters/ID", "other.yaml#/components/links/UpdatePet"} {
+		_, err = o.ResolveLink(&Link{Refable: refable(ref)})
+		assert.Error(t, err, ref)
+	}
+
+	// Cycles fail.
+	o.Components.Links["A"] = &Link{Refable: refable("#/components/links/B")}
+	o.Components.Links["B"] = &Link{Refable: refable("#/components/links/A")}
+	_, err = o.ResolveLink(o.Components.Links["A"])
+	assert.Error(t, err)
+
+	_, _, err = o.OperationByRef("#/paths/~1pets/delete")
+	assert.Error(t, err)
+	_, _, err = o.OperationByRef("#/components/links/UpdatePet")
+	assert.Error(t, err)
+}
+
+func TestValidateLinks(t *testing.T) {
+	o := loadLinkedDoc(t)
+	assert.NoError(t, o.ValidateLinks())
+	assert.NoError(t, o.ValidateCallbacks())
+
+	links := o.Paths.Paths["/pets"].Post.Responses.StatusCodeResponses["201"].Links
+	links["get"].Parameters["petId"] = "$response.body#/id"
+	links["get"].RequestBody = "$request.bdy"
+	links["missing"] = &Link{LinkProps: LinkProps{OperationID: "deletePet"}}
+	links["both"] = &Link{LinkProps: LinkProps{OperationID: "getPet", OperationRef: "#/paths/~1pets/post"}}
+	links["external"] = &Link{LinkProps: LinkProps{OperationRef: "https://example.com/openapi.json#/paths/~1a/get", Parameters: map[string]interface{}{"a": "$nope"}}}
+	o.Components.Links["UpdatePet"].Parameters["query.id"] = "1"
+
+	err := o.ValidateLinks()
+	require.Error(t, err)
+	msg := err.Error()
+	prefix := "paths./pets.post.responses.201.links."
+	assert.Contains(t, msg, prefix+"get.parameters.petId")
+	assert.Contains(t, msg, prefix+"get.requestBody")
+	assert.Contains(t, msg, `invalid runtime expression "$request.bdy"`)
+	assert.Contains(t, msg, `no operation with operationId "deletePet"`)
+	assert.Contains(t, msg, "operationRef and operationId are mutually exclusive")
+	assert.Contains(t, msg, prefix+"external.parameters.a")
+	assert.Contains(t, msg, "components.links.UpdatePet.parameters.query.id")
+	assert.NotContains(t, msg, "parameters.path.id")
+	assert.NotContains(t, msg, "dryRun")
+}
+
+func TestValidateCallbacks(t *testing.T) {
+	o := loadLinkedDoc(t)
+	post := o.Paths.Paths["/pets"].Post
+	post.Callbacks["bad"] = &Callback{Expressions: map[string]*Path{"{$request.body#/url": {}}}
+	post.Callbacks["dangling"] = &Callback{Refable: refable("#/components/callbacks/Missing")}
+	o.Components.Callbacks["Event"].Expressions["$request.cookie.a"] = &Path{}
+
+	err := o.ValidateCallbacks()
+	require.Error(t, err)
+	msg := err.Error()
+	assert.Contains(t, msg, "paths./pets.post.callbacks.bad")
+	assert.Contains(t, msg, "paths./pets.post.callbacks.dangling")
+	assert.Contains(t, msg, "components.callbacks.Event.$request.cookie.a")
+	assert.NotContains(t, msg, "onCreated")
+}
+
+func refable(ref string) spec.Refable {
+	return spec.Refable{Ref: spec.MustCreateRef(ref)}
+}
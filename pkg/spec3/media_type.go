@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// MediaTypeProps are the properties of a media type.
+type MediaTypeProps struct {
+	Schema   *spec.Schema         `json:"schema,omitempty"`
+	Example  interface{}          `json:"example,omitempty"`
+	Examples map[string]*Example  `json:"examples,omitempty"`
+	Encoding map[string]*Encoding `json:"encoding,omitempty"`
+}
+
+// MediaType describes the schema and examples of a content type.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#media-type-object
+type MediaType struct {
+	MediaTypeProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the media type to JSON.
+func (m MediaType) MarshalJSON() ([]byte, error) {
+	return marshal(m.MediaTypeProps, m.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the media type from JSON.
+func (m *MediaType) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &m.MediaTypeProps, &m.VendorExtensible)
+}
+
+// EncodingProps are the properties of an encoding.
+type EncodingProps struct {
+	ContentType   string             `json:"contentType,omitempty"`
+	Headers       map[string]*Header `json:"headers,omitempty"`
+	Style         string             `json:"style,omitempty"`
+	Explode       *bool              `json:"explode,omitempty"`
+	AllowReserved bool               `json:"allowReserved,omitempty"`
+}
+
+// Encoding describes how a property of a multipart or form body is encoded.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#encoding-object
+type Encoding struct {
+	EncodingProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the encoding to JSON.
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	return marshal(e.EncodingProps, e.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the encoding from JSON.
+func (e *Encoding) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &e.EncodingProps, &e.VendorExtensible)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// OpenIDConfiguration is the OpenID Provider metadata an openIdConnect
+// security scheme points to with its openIdConnectUrl.
+//
+// For more information: https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type OpenIDConfiguration struct {
+	Issuer                 string   `json:"issuer"`
+	AuthorizationEndpoint  string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint          string   `json:"token_endpoint,omitempty"`
+	UserinfoEndpoint       string   `json:"userinfo_endpoint,omitempty"`
+	JWKSURI                string   `json:"jwks_uri,omitempty"`
+	RegistrationEndpoint   string   `json:"registration_endpoint,omitempty"`
+	ScopesSupported        []string `json:"scopes_supported,omitempty"`
+	ResponseTypesSupported []string `json:"response_types_supported,omitempty"`
+	// GrantTypesSupported defaults to authorization_code and implicit.
+	GrantTypesSupported []string `json:"grant_types_supported,omitempty"`
+}
+
+// maxOpenIDConfigurationSize bounds the size of a fetched discovery
+// document.
+const maxOpenIDConfigurationSize = 1 << 20
+
+// FetchOpenIDConfiguration fetches the OpenID Provider metadata of an
+// openIdConnect security scheme, with http.DefaultClient if client is nil.
+func FetchOpenIDConfiguration(ctx context.Context, client *http.Client, s *SecurityScheme) (*OpenIDConfiguration, error) {
+	if s.Type != "openIdConnect" || s.OpenIDConnectURL == "" {
+		return nil, fmt.Errorf("security scheme of type %q has no openIdConnectUrl", s.Type)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest(http.MethodGet, s.OpenIDConnectURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %s", s.OpenIDConnectURL, resp.Status)
+	}
+	c := &OpenIDConfiguration{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxOpenIDConfigurationSize)).Decode(c); err != nil {
+		return nil, fmt.Errorf("decoding %s: %v", s.OpenIDConnectURL, err)
+	}
+	if c.Issuer == "" {
+		return nil, fmt.Errorf("decoding %s: issuer is required", s.OpenIDConnectURL)
+	}
+	return c, nil
+}
+
+// OAuthFlows returns the OAuth flows the provider supports, with the
+// supported scopes and the refresh URL when refresh tokens are supported,
+// so that an openIdConnect scheme can be handled as an oauth2 one.
+func (c *OpenIDConfiguration) OAuthFlows() *OAuthFlows {
+	grants := c.GrantTypesSupported
+	if len(grants) == 0 {
+		grants = []string{"authorization_code", "implicit"}
+	}
+	newFlow := func() *OAuthFlow {
+		f := &OAuthFlow{Scopes: map[string]string{}}
+		for _, s := range c.ScopesSupported {
+			f.Scopes[s] = ""
+		}
+		return f
+	}
+	ret := &OAuthFlows{}
+	refresh := false
+	for _, g := range grants {
+		switch {
+		case g == "authorization_code" && c.AuthorizationEndpoint != "" && c.TokenEndpoint != "":
+			ret.AuthorizationCode = newFlow()
+			ret.AuthorizationCode.AuthorizationURL = c.AuthorizationEndpoint
+			ret.AuthorizationCode.TokenURL = c.TokenEndpoint
+		case g == "implicit" && c.AuthorizationEndpoint != "":
+			ret.Implicit = newFlow()
+			ret.Implicit.AuthorizationURL = c.AuthorizationEndpoint
+		case g == "password" && c.TokenEndpoint != "":
+			ret.Password = newFlow()
+			ret.Password.TokenURL = c.TokenEndpoint
+		case g == "client_credentials" && c.TokenEndpoint != "":
+			ret.ClientCredentials = newFlow()
+			ret.ClientCredentials.TokenURL = c.TokenEndpoint
+		case g == "refresh_token":
+			refresh = true
+		}
+	}
+	// Tokens are refreshed at the token endpoint.
+	if refresh {
+		for _, f := range []*OAuthFlow{ret.AuthorizationCode, ret.Password} {
+			if f != nil {
+				f.RefreshURL = c.TokenEndpoint
+			}
+		}
+	}
+	return ret
+}
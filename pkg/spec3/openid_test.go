@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchOpenIDConfiguration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			w.Write([]byte(`{
+			  "issuer": "https://auth.example.com",
+			  "authorization_endpoint": "https://auth.example.com/authorize",
+			  "token_endpoint": "https://auth.example.com/token",
+			  "scopes_supported": ["openid", "email"],
+			  "grant_types_supported": ["authorization_code", "client_credentials", "refresh_token"]
+			}`))
+		case "/empty":
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	scheme := &SecurityScheme{SecuritySchemeProps: SecuritySchemeProps{Type: "openIdConnect", OpenIDConnectURL: server.URL + "/.well-known/openid-configuration"}}
+	c, err := FetchOpenIDConfiguration(context.Background(), server.Client(), scheme)
+	require.NoError(t, err)
+	assert.Equal(t, "https://auth.example.com", c.Issuer)
+
+	flows := c.OAuthFlows()
+	assert.Nil(t, flows.Implicit)
+	assert.Nil(t, flows.Password)
+	require.NotNil(t, flows.AuthorizationCode)
+	assert.Equal(t, "https://auth.example.com/authorize", flows.AuthorizationCode.AuthorizationURL)
+	assert.Equal(t, "https://auth.example.com/token", flows.AuthorizationCode.RefreshURL)
+	require.NotNil(t, flows.ClientCredentials)
+	assert.Equal(t, "", flows.ClientCredentials.RefreshURL)
+	assert.Equal(t, map[string]string{"openid": "", "email": ""}, flows.Scopes())
+	assert.NoError(t, (&SecurityScheme{SecuritySchemeProps: SecuritySchemeProps{Type: "oauth2", Flows: flows}}).Validate())
+
+	// Without grant types, the defaults of the discovery specification apply.
+	c.GrantTypesSupported = nil
+	flows = c.OAuthFlows()
+	assert.NotNil(t, flows.AuthorizationCode)
+	assert.NotNil(t, flows.Implicit)
+	assert.Nil(t, flows.ClientCredentials)
+
+	scheme.OpenIDConnectURL = server.URL + "/empty"
+	_, err = FetchOpenIDConfiguration(context.Background(), server.Client(), scheme)
+	assert.EqualError(t, err, "decoding "+scheme.OpenIDConnectURL+": issuer is required")
+	scheme.OpenIDConnectURL = server.URL + "/missing"
+	_, err = FetchOpenIDConfiguration(context.Background(), server.Client(), scheme)
+	assert.Error(t, err)
+	_, err = FetchOpenIDConfiguration(context.Background(), nil, &SecurityScheme{SecuritySchemeProps: SecuritySchemeProps{Type: "oauth2"}})
+	assert.Error(t, err)
+}
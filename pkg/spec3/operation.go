@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// OperationProps are the properties of an operation.
+type OperationProps struct {
+	Tags         []string                    `json:"tags,omitempty"`
+	Summary      string                      `json:"summary,omitempty"`
+	Description  string                      `json:"description,omitempty"`
+	ExternalDocs *spec.ExternalDocumentation `json:"externalDocs,omitempty"`
+	OperationID  string                      `json:"operationId,omitempty"`
+	Parameters   []*Parameter                `json:"parameters,omitempty"`
+	RequestBody  *RequestBody                `json:"requestBody,omitempty"`
+	Responses    *Responses                  `json:"responses,omitempty"`
+	Callbacks    map[string]*Callback        `json:"callbacks,omitempty"`
+	Deprecated   bool                        `json:"deprecated,omitempty"`
+	Security     []map[string][]string       `json:"security,omitempty"`
+	Servers      []*Server                   `json:"servers,omitempty"`
+}
+
+// Operation describes a single API operation on a path.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#operation-object
+type Operation struct {
+	OperationProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the operation to JSON.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	return marshal(o.OperationProps, o.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the operation from JSON.
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &o.OperationProps, &o.VendorExtensible)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// ParameterProps are the properties of a parameter.
+type ParameterProps struct {
+	Name            string `json:"name,omitempty"`
+	In              string `json:"in,omitempty"`
+	Description     string `json:"description,omitempty"`
+	Required        bool   `json:"required,omitempty"`
+	Deprecated      bool   `json:"deprecated,omitempty"`
+	AllowEmptyValue bool   `json:"allowEmptyValue,omitempty"`
+	Style           string `json:"style,omitempty"`
+	// Explode is a pointer because its default depends on the style.
+	Explode       *bool                 `json:"explode,omitempty"`
+	AllowReserved bool                  `json:"allowReserved,omitempty"`
+	Schema        *spec.Schema          `json:"schema,omitempty"`
+	Content       map[string]*MediaType `json:"content,omitempty"`
+	Example       interface{}           `json:"example,omitempty"`
+	Examples      map[string]*Example   `json:"examples,omitempty"`
+}
+
+// Parameter describes a single operation parameter, in the path, query,
+// header or a cookie.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#parameter-object
+type Parameter struct {
+	spec.Refable
+	ParameterProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the parameter to JSON.
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	return marshal(p.Refable, p.ParameterProps, p.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the parameter from JSON.
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &p.Refable, &p.ParameterProps, &p.VendorExtensible)
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Paths holds the relative paths to the endpoints of an API, each starting
+// with "/".
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#paths-object
+type Paths struct {
+	Paths map[string]*Path
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the paths to JSON.
+func (p Paths) MarshalJSON() ([]byte, error) {
+	paths := make(map[string]*Path, len(p.Paths))
+	for k, v := range p.Paths {
+		if strings.HasPrefix(k, "/") {
+			paths[k] = v
+		}
+	}
+	return marshal(paths, p.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the paths from JSON. Keys that are neither paths nor
+// extensions are ignored.
+func (p *Paths) UnmarshalJSON(data []byte) error {
+	var res map[string]json.RawMessage
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+	for k, v := range res {
+		switch {
+		case strings.HasPrefix(strings.ToLower(k), "x-"):
+			var ext interface{}
+			if err := json.Unmarshal(v, &ext); err != nil {
+				return err
+			}
+			p.AddExtension(k, ext)
+		case strings.HasPrefix(k, "/"):
+			if p.Paths == nil {
+				p.Paths = map[string]*Path{}
+			}
+			path := &Path{}
+			if err := json.Unmarshal(v, path); err != nil {
+				return err
+			}
+			p.Paths[k] = path
+		}
+	}
+	return nil
+}
+
+// PathProps are the properties of a path item.
+type PathProps struct {
+	Summary     string       `json:"summary,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Get         *Operation   `json:"get,omitempty"`
+	Put         *Operation   `json:"put,omitempty"`
+	Post        *Operation   `json:"post,omitempty"`
+	Delete      *Operation   `json:"delete,omitempty"`
+	Options     *Operation   `json:"options,omitempty"`
+	Head        *Operation   `json:"head,omitempty"`
+	Patch       *Operation   `json:"patch,omitempty"`
+	Trace       *Operation   `json:"trace,omitempty"`
+	Servers     []*Server    `json:"servers,omitempty"`
+	Parameters  []*Parameter `json:"parameters,omitempty"`
+}
+
+// Path describes the operations available on a single path.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#path-item-object
+type Path struct {
+	spec.Refable
+	PathProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the path item to JSON.
+func (p Path) MarshalJSON() ([]byte, error) {
+	return marshal(p.Refable, p.PathProps, p.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the path item from JSON.
+func (p *Path) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &p.Refable, &p.PathProps, &p.VendorExtensible)
+}
+
+// Operations returns the operations of the path item keyed by their
+// lowercase method.
+func (p *Path) Operations() map[string]*Operation {
+	ret := map[string]*Operation{}
+	for method, op := range map[string]*Operation{
+		"get":     p.Get,
+		"put":     p.Put,
+		"post":    p.Post,
+		"delete":  p.Delete,
+		"options": p.Options,
+		"head":    p.Head,
+		"patch":   p.Patch,
+		"trace":   p.Trace,
+	} {
+		if op != nil {
+			ret[method] = op
+		}
+	}
+	return ret
+}
+
+// SetOperation sets the operation of an HTTP method, case insensitive. It
+// returns false if path items have no operation for the method.
+func (p *Path) SetOperation(method string, op *Operation) bool {
+	var field **Operation
+	switch strings.ToLower(method) {
+	case "get":
+		field = &p.Get
+	case "put":
+		field = &p.Put
+	case "post":
+		field = &p.Post
+	case "delete":
+		field = &p.Delete
+	case "options":
+		field = &p.Options
+	case "head":
+		field = &p.Head
+	case "patch":
+		field = &p.Patch
+	case "trace":
+		field = &p.Trace
+	default:
+		return false
+	}
+	*field = op
+	return true
+}
+
+// walkPaths calls onPath for each path item of the document, then onOperation
+// for each of its operations, in a stable order. Both may be nil.
+func (o *OpenAPI) walkPaths(onPath func(path string, item *Path), onOperation func(path, method string, item *Path, op *Operation)) {
+	if o.Paths == nil {
+		return
+	}
+	for _, p := range sortedKeys(o.Paths.Paths) {
+		item := o.Paths.Paths[p]
+		if item == nil {
+			continue
+		}
+		if onPath != nil {
+			onPath(p, item)
+		}
+		if onOperation == nil {
+			continue
+		}
+		ops := item.Operations()
+		for _, m := range sortedKeys(ops) {
+			onOperation(p, m, item, ops[m])
+		}
+	}
+}
+
+var pathTemplateRegexp = regexp.MustCompile(`{([^{}]+)}`)
+
+// ValidatePathParameters checks that each parameter of the path templates
+// is declared as a path parameter of every operation of the path item,
+// either at the path item level or at the operation level, and that every
+// declared path parameter is in the template. Referenced parameters are
+// resolved in the components first. Path items without operations are
+// checked against their own parameters. It returns nil or an
+// *errors.CompositeError.
+func (o *OpenAPI) ValidatePathParameters() error {
+	var errs specErrors
+	o.walkPaths(func(path string, item *Path) {
+		ops := item.Operations()
+		if len(ops) == 0 {
+			o.validatePathParameters("paths."+path, path, item.Parameters, nil, &errs)
+			return
+		}
+		for _, method := range sortedKeys(ops) {
+			o.validatePathParameters("paths."+path+"."+method, path, item.Parameters, ops[method].Parameters, &errs)
+		}
+	}, nil)
+	return errs.err()
+}
+
+// validatePathParameters checks the path parameters declared by an
+// operation and its path item against the path template.
+func (o *OpenAPI) validatePathParameters(name, path string, common, params []*Parameter, errs *specErrors) {
+	declared := map[string]bool{}
+	resolvable := true
+	for _, l := range [][]*Parameter{common, params} {
+		for _, p := range l {
+			if p == nil {
+				continue
+			}
+			p, err := o.ResolveParameter(p)
+			if err != nil {
+				errs.add(name, "%v", err)
+				resolvable = false
+				continue
+			}
+			if p.In == "path" {
+				declared[p.Name] = true
+			}
+		}
+	}
+	inTemplate := map[string]bool{}
+	for _, m := range pathTemplateRegexp.FindAllStringSubmatch(path, -1) {
+		inTemplate[m[1]] = true
+		if !declared[m[1]] && resolvable {
+			errs.add(name, "path parameter %q is not declared", m[1])
+		}
+	}
+	for _, p := range sortedKeys(declared) {
+		if !inTemplate[p] {
+			errs.add(name, "path parameter %q is not in the path", p)
+		}
+	}
+}
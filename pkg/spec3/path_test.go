@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+)
+
+const pathParametersDoc = `{
+  "openapi": "3.1.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "components": {
+    "parameters": {
+      "ID": {"name": "id", "in": "path", "required": true, "schema": {"type": "string"}},
+      "Alias": {"$ref": "#/components/parameters/ID"}
+    }
+  },
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/Alias"}],
+      "get": {"responses": {"200": {"description": "ok"}}},
+      "put": {
+        "parameters": [{"name": "owner", "in": "path", "required": true}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/owners/{owner}/pets/{id}": {
+      "get": {
+        "parameters": [{"$ref": "#/components/parameters/ID"}, {"name": "owner", "in": "query"}],
+        "responses": {"200": {"description": "ok"}}
+      },
+      "delete": {
+        "parameters": [{"$ref": "#/components/parameters/Missing"}],
+        "responses": {"204": {"description": "deleted"}}
+      }
+    },
+    "/health/{probe}": {
+      "parameters": [{"name": "check", "in": "path", "required": true}]
+    }
+  }
+}`
+
+func TestValidatePathParameters(t *testing.T) {
+	var o OpenAPI
+	require.NoError(t, json.Unmarshal([]byte(pathParametersDoc), &o))
+	err := o.ValidatePathParameters()
+	require.Error(t, err)
+
+	var msgs []string
+	for _, e := range err.(*errors.CompositeError).Errors {
+		msgs = append(msgs, e.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		`paths./health/{probe} is invalid: path parameter "probe" is not declared`,
+		`paths./health/{probe} is invalid: path parameter "check" is not in the path`,
+		`paths./owners/{owner}/pets/{id}.delete is invalid: parameter #/components/parameters/Missing can't be resolved`,
+		`paths./owners/{owner}/pets/{id}.get is invalid: path parameter "owner" is not declared`,
+		`paths./pets/{id}.put is invalid: path parameter "owner" is not in the path`,
+	}, msgs)
+
+	delete(o.Paths.Paths, "/health/{probe}")
+	delete(o.Paths.Paths, "/owners/{owner}/pets/{id}")
+	o.Paths.Paths["/pets/{id}"].Put.Parameters = nil
+	assert.NoError(t, o.ValidatePathParameters())
+}
+
+func TestPathSetOperation(t *testing.T) {
+	var p Path
+	get, trace := &Operation{}, &Operation{}
+	assert.True(t, p.SetOperation("GET", get))
+	assert.True(t, p.SetOperation("trace", trace))
+	assert.False(t, p.SetOperation("CONNECT", get))
+	assert.Equal(t, map[string]*Operation{"get": get, "trace": trace}, p.Operations())
+}
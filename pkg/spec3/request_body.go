@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// RequestBodyProps are the properties of a request body.
+type RequestBodyProps struct {
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+}
+
+// RequestBody describes the body of a request, keyed by media type.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#request-body-object
+type RequestBody struct {
+	spec.Refable
+	RequestBodyProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the request body to JSON.
+func (r RequestBody) MarshalJSON() ([]byte, error) {
+	return marshal(r.Refable, r.RequestBodyProps, r.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the request body from JSON.
+func (r *RequestBody) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &r.Refable, &r.RequestBodyProps, &r.VendorExtensible)
+}
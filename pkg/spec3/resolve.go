@@ -0,0 +1,191 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// maxRefDepth bounds chains of references between components, so that
+// cycles fail instead of looping.
+const maxRefDepth = 32
+
+// componentName returns the name of the component of the given kind a
+// local reference points to, e.g. "Next" for "#/components/links/Next".
+func componentName(ref spec.Ref, kind string) (string, error) {
+	prefix := "#/components/" + kind + "/"
+	s := ref.String()
+	if !strings.HasPrefix(s, prefix) || strings.Contains(s[len(prefix):], "/") {
+		return "", fmt.Errorf("reference %s is not to a %s component", s, kind)
+	}
+	return jsonpointer.Unescape(s[len(prefix):]), nil
+}
+
+func (o *OpenAPI) components() *ComponentsProps {
+	if o.Components == nil {
+		return &ComponentsProps{}
+	}
+	return &o.Components.ComponentsProps
+}
+
+// ResolveParameter follows the references of a parameter into the
+// components of the document. Parameters without references are returned
+// as is.
+func (o *OpenAPI) ResolveParameter(p *Parameter) (*Parameter, error) {
+	for i := 0; p.Ref.String() != ""; i++ {
+		name, err := componentName(p.Ref, "parameters")
+		if err != nil {
+			return nil, err
+		}
+		next, ok := o.components().Parameters[name]
+		if !ok || next == nil || i == maxRefDepth {
+			return nil, fmt.Errorf("parameter %s can't be resolved", p.Ref.String())
+		}
+		p = next
+	}
+	return p, nil
+}
+
+// ResolveResponse follows the references of a response into the components
+// of the document.
+func (o *OpenAPI) ResolveResponse(r *Response) (*Response, error) {
+	for i := 0; r.Ref.String() != ""; i++ {
+		name, err := componentName(r.Ref, "responses")
+		if err != nil {
+			return nil, err
+		}
+		next, ok := o.components().Responses[name]
+		if !ok || next == nil || i == maxRefDepth {
+			return nil, fmt.Errorf("response %s can't be resolved", r.Ref.String())
+		}
+		r = next
+	}
+	return r, nil
+}
+
+// ResolveLink follows the references of a link into the components of the
+// document.
+func (o *OpenAPI) ResolveLink(l *Link) (*Link, error) {
+	for i := 0; l.Ref.String() != ""; i++ {
+		name, err := componentName(l.Ref, "links")
+		if err != nil {
+			return nil, err
+		}
+		next, ok := o.components().Links[name]
+		if !ok || next == nil || i == maxRefDepth {
+			return nil, fmt.Errorf("link %s can't be resolved", l.Ref.String())
+		}
+		l = next
+	}
+	return l, nil
+}
+
+// ResolveExample follows the references of an example into the components
+// of the document.
+func (o *OpenAPI) ResolveExample(e *Example) (*Example, error) {
+	for i := 0; e.Ref.String() != ""; i++ {
+		name, err := componentName(e.Ref, "examples")
+		if err != nil {
+			return nil, err
+		}
+		next, ok := o.components().Examples[name]
+		if !ok || next == nil || i == maxRefDepth {
+			return nil, fmt.Errorf("example %s can't be resolved", e.Ref.String())
+		}
+		e = next
+	}
+	return e, nil
+}
+
+// ResolveCallback follows the references of a callback into the components
+// of the document.
+func (o *OpenAPI) ResolveCallback(c *Callback) (*Callback, error) {
+	for i := 0; c.Ref.String() != ""; i++ {
+		name, err := componentName(c.Ref, "callbacks")
+		if err != nil {
+			return nil, err
+		}
+		next, ok := o.components().Callbacks[name]
+		if !ok || next == nil || i == maxRefDepth {
+			return nil, fmt.Errorf("callback %s can't be resolved", c.Ref.String())
+		}
+		c = next
+	}
+	return c, nil
+}
+
+// ResolveSecurityScheme follows the references of a security scheme into
+// the components of the document.
+func (o *OpenAPI) ResolveSecurityScheme(s *SecurityScheme) (*SecurityScheme, error) {
+	for i := 0; s.Ref.String() != ""; i++ {
+		name, err := componentName(s.Ref, "securitySchemes")
+		if err != nil {
+			return nil, err
+		}
+		next, ok := o.components().SecuritySchemes[name]
+		if !ok || next == nil || i == maxRefDepth {
+			return nil, fmt.Errorf("security scheme %s can't be resolved", s.Ref.String())
+		}
+		s = next
+	}
+	return s, nil
+}
+
+// OperationByID returns the operation with the given operationId along
+// with its path item, or nil if there is none.
+func (o *OpenAPI) OperationByID(id string) (*Path, *Operation) {
+	if o.Paths == nil || id == "" {
+		return nil, nil
+	}
+	for _, item := range o.Paths.Paths {
+		if item == nil {
+			continue
+		}
+		for _, op := range item.Operations() {
+			if op.OperationID == id {
+				return item, op
+			}
+		}
+	}
+	return nil, nil
+}
+
+// OperationByRef returns the operation a local operation reference such as
+// "#/paths/~1pets~1{id}/get" points to, along with its path item.
+func (o *OpenAPI) OperationByRef(ref string) (*Path, *Operation, error) {
+	tokens := strings.Split(strings.TrimPrefix(ref, "#/"), "/")
+	if !strings.HasPrefix(ref, "#/paths/") || len(tokens) != 3 {
+		return nil, nil, fmt.Errorf("operation reference %s is not to a local operation", ref)
+	}
+	var item *Path
+	if o.Paths != nil {
+		item = o.Paths.Paths[jsonpointer.Unescape(tokens[1])]
+	}
+	if item == nil {
+		return nil, nil, fmt.Errorf("operation reference %s can't be resolved", ref)
+	}
+	op := item.Operations()[tokens[2]]
+	if op == nil {
+		return nil, nil, fmt.Errorf("operation reference %s can't be resolved", ref)
+	}
+	return item, op, nil
+}
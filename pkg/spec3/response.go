@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Responses holds the responses of an operation.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#responses-object
+type Responses struct {
+	Default *Response
+	// StatusCodeResponses are keyed by status code, e.g. "200", or status
+	// code range, e.g. "2XX".
+	StatusCodeResponses map[string]*Response
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the responses to JSON.
+func (r Responses) MarshalJSON() ([]byte, error) {
+	responses := make(map[string]*Response, len(r.StatusCodeResponses)+1)
+	for code, resp := range r.StatusCodeResponses {
+		responses[code] = resp
+	}
+	if r.Default != nil {
+		responses["default"] = r.Default
+	}
+	return marshal(responses, r.VendorExtensible)
+}
+
+// Match returns the response documenting a status code along with its
+// key: the response of the status code itself, else the response of its
+// range, e.g. "2XX", else the default response. It returns "" and nil if
+// none applies. Response references are not followed.
+func (r *Responses) Match(code int) (string, *Response) {
+	key := strconv.Itoa(code)
+	if resp, ok := r.StatusCodeResponses[key]; ok && resp != nil {
+		return key, resp
+	}
+	if code >= 100 && code <= 599 {
+		for _, k := range []string{key[:1] + "XX", key[:1] + "xx"} {
+			if resp, ok := r.StatusCodeResponses[k]; ok && resp != nil {
+				return k, resp
+			}
+		}
+	}
+	if r.Default != nil {
+		return "default", r.Default
+	}
+	return "", nil
+}
+
+// UnmarshalJSON hydrates the responses from JSON.
+func (r *Responses) UnmarshalJSON(data []byte) error {
+	var res map[string]json.RawMessage
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+	for k, v := range res {
+		if strings.HasPrefix(strings.ToLower(k), "x-") {
+			var ext interface{}
+			if err := json.Unmarshal(v, &ext); err != nil {
+				return err
+			}
+			r.AddExtension(k, ext)
+			continue
+		}
+		resp := &Response{}
+		if err := json.Unmarshal(v, resp); err != nil {
+			return err
+		}
+		if k == "default" {
+			r.Default = resp
+			continue
+		}
+		if r.StatusCodeResponses == nil {
+			r.StatusCodeResponses = map[string]*Response{}
+		}
+		r.StatusCodeResponses[k] = resp
+	}
+	return nil
+}
+
+// ResponseProps are the properties of a response.
+type ResponseProps struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+	Links       map[string]*Link      `json:"links,omitempty"`
+}
+
+// Response describes a single response of an operation.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#response-object
+type Response struct {
+	spec.Refable
+	ResponseProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the response to JSON. References are marshaled alone,
+// as description is required otherwise.
+func (r Response) MarshalJSON() ([]byte, error) {
+	if r.Ref.String() != "" {
+		return marshal(r.Refable, r.VendorExtensible)
+	}
+	return marshal(r.ResponseProps, r.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the response from JSON.
+func (r *Response) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &r.Refable, &r.ResponseProps, &r.VendorExtensible)
+}
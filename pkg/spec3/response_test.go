@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponsesMatch(t *testing.T) {
+	var r Responses
+	require.NoError(t, json.Unmarshal([]byte(`{
+	  "200": {"description": "ok"},
+	  "2XX": {"description": "success"},
+	  "4xx": {"description": "client error"},
+	  "default": {"description": "error"}
+	}`), &r))
+
+	for _, tc := range []struct {
+		code        int
+		key         string
+		description string
+	}{
+		{200, "200", "ok"},
+		{204, "2XX", "success"},
+		{404, "4xx", "client error"},
+		{500, "default", "error"},
+		{42, "default", "error"},
+	} {
+		key, resp := r.Match(tc.code)
+		assert.Equal(t, tc.key, key, "code %d", tc.code)
+		if assert.NotNil(t, resp, "code %d", tc.code) {
+			assert.Equal(t, tc.description, resp.Description, "code %d", tc.code)
+		}
+	}
+
+	r.Default = nil
+	key, resp := r.Match(500)
+	assert.Equal(t, "", key)
+	assert.Nil(t, resp)
+}
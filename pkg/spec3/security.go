@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ValidateSecurity validates the security schemes of the components, see
+// SecurityScheme.Validate, and the security requirements of the document
+// and of its operations: each requirement must name a declared security
+// scheme, and the scopes required from an oauth2 scheme must be declared
+// by one of its flows. It returns nil or an *errors.CompositeError.
+func (o *OpenAPI) ValidateSecurity() error {
+	var errs specErrors
+	schemes := o.components().SecuritySchemes
+	for _, name := range sortedKeys(schemes) {
+		if s := schemes[name]; s != nil && s.Ref.String() == "" {
+			s.validate("components.securitySchemes."+name, &errs)
+		}
+	}
+	o.validateRequirements("security", o.Security, &errs)
+	o.walkPaths(nil, func(path, method string, _ *Path, op *Operation) {
+		o.validateRequirements("paths."+path+"."+method+".security", op.Security, &errs)
+	})
+	return errs.err()
+}
+
+func (o *OpenAPI) validateRequirements(name string, requirements []map[string][]string, errs *specErrors) {
+	for i, req := range requirements {
+		rname := fmt.Sprintf("%s[%d]", name, i)
+		for _, scheme := range sortedKeys(req) {
+			s, ok := o.components().SecuritySchemes[scheme]
+			if !ok || s == nil {
+				errs.add(rname, "security scheme %q is not declared", scheme)
+				continue
+			}
+			s, err := o.ResolveSecurityScheme(s)
+			if err != nil {
+				errs.add(rname, "%v", err)
+				continue
+			}
+			if s.Type != "oauth2" || s.Flows == nil {
+				continue
+			}
+			declared := s.Flows.Scopes()
+			for _, scope := range req[scheme] {
+				if _, ok := declared[scope]; !ok {
+					errs.add(rname, "scope %q is not declared by security scheme %q", scope, scheme)
+				}
+			}
+		}
+	}
+}
+
+// EffectiveSecurity returns the security requirements of an operation, or
+// the ones of the document when the operation doesn't declare any. An
+// empty, non-nil result means the operation requires no security.
+func (o *OpenAPI) EffectiveSecurity(op *Operation) []map[string][]string {
+	if op.Security != nil {
+		return op.Security
+	}
+	return o.Security
+}
+
+// ScopeChecker evaluates security requirements against the credentials
+// presented with a request.
+type ScopeChecker struct {
+	granted map[string]map[string]bool
+}
+
+// NewScopeChecker returns a checker for credentials satisfying the given
+// security schemes, keyed by name, with the scopes granted by each of
+// them, e.g. the scopes of an OAuth2 token. Schemes without scopes, such
+// as API keys, map to no scopes.
+func NewScopeChecker(granted map[string][]string) *ScopeChecker {
+	c := &ScopeChecker{granted: map[string]map[string]bool{}}
+	for scheme, scopes := range granted {
+		c.granted[scheme] = map[string]bool{}
+		for _, s := range scopes {
+			c.granted[scheme][s] = true
+		}
+	}
+	return c
+}
+
+// Satisfies returns whether the credentials satisfy one of the security
+// requirements: all the schemes of a requirement must be satisfied, with
+// all the scopes they require. An empty list of requirements, or an empty
+// requirement, is always satisfied.
+func (c *ScopeChecker) Satisfies(requirements []map[string][]string) bool {
+	if len(requirements) == 0 {
+		return true
+	}
+	for _, req := range requirements {
+		if len(c.Missing(req)) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Missing returns the scopes of a security requirement the credentials
+// don't grant, keyed by scheme, or nil if the requirement is satisfied. A
+// scheme the credentials don't satisfy at all maps to all of its scopes.
+func (c *ScopeChecker) Missing(requirement map[string][]string) map[string][]string {
+	var ret map[string][]string
+	for scheme, scopes := range requirement {
+		granted, ok := c.granted[scheme]
+		var missing []string
+		for _, s := range scopes {
+			if !granted[s] {
+				missing = append(missing, s)
+			}
+		}
+		if ok && len(missing) == 0 {
+			continue
+		}
+		if ret == nil {
+			ret = map[string][]string{}
+		}
+		sort.Strings(missing)
+		ret[scheme] = append([]string{}, missing...)
+	}
+	return ret
+}
+
+// Allows returns whether the credentials satisfy the effective security
+// requirements of an operation of the document.
+func (c *ScopeChecker) Allows(o *OpenAPI, op *Operation) bool {
+	return c.Satisfies(o.EffectiveSecurity(op))
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"net/url"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// SecuritySchemeProps are the properties of a security scheme.
+type SecuritySchemeProps struct {
+	// Type is one of apiKey, http, mutualTLS (since 3.1), oauth2 or
+	// openIdConnect.
+	Type             string      `json:"type,omitempty"`
+	Description      string      `json:"description,omitempty"`
+	Name             string      `json:"name,omitempty"`
+	In               string      `json:"in,omitempty"`
+	Scheme           string      `json:"scheme,omitempty"`
+	BearerFormat     string      `json:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows `json:"flows,omitempty"`
+	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty"`
+}
+
+// SecurityScheme describes a way to authenticate to the API.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#security-scheme-object
+type SecurityScheme struct {
+	spec.Refable
+	SecuritySchemeProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the security scheme to JSON.
+func (s SecurityScheme) MarshalJSON() ([]byte, error) {
+	return marshal(s.Refable, s.SecuritySchemeProps, s.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the security scheme from JSON.
+func (s *SecurityScheme) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &s.Refable, &s.SecuritySchemeProps, &s.VendorExtensible)
+}
+
+// OAuthFlows are the OAuth flows supported by an oauth2 security scheme.
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+// OAuthFlow describes an OAuth flow.
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	RefreshURL       string            `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+// Validate checks that the security scheme declares what its type needs:
+// the name and location of API keys, the scheme of HTTP authentication,
+// the flows of OAuth2 with the URLs each flow needs, and the discovery URL
+// of OpenID Connect. It returns nil or an *errors.CompositeError of errors
+// built by errors.InvalidSpec.
+func (s *SecurityScheme) Validate() error {
+	var errs specErrors
+	s.validate("securityScheme", &errs)
+	return errs.err()
+}
+
+func (s *SecurityScheme) validate(name string, errs *specErrors) {
+	switch s.Type {
+	case "apiKey":
+		if s.Name == "" {
+			errs.add(name, "name is required for apiKey security schemes")
+		}
+		if s.In != "query" && s.In != "header" && s.In != "cookie" {
+			errs.add(name, "in must be one of query, header or cookie, got %q", s.In)
+		}
+	case "http":
+		if s.Scheme == "" {
+			errs.add(name, "scheme is required for http security schemes")
+		}
+	case "mutualTLS":
+	case "oauth2":
+		if s.Flows == nil {
+			errs.add(name, "flows is required for oauth2 security schemes")
+			return
+		}
+		// Each flow uses an authorization URL, a token URL or both.
+		flows := []struct {
+			name                 string
+			flow                 *OAuthFlow
+			authorization, token bool
+		}{
+			{"implicit", s.Flows.Implicit, true, false},
+			{"password", s.Flows.Password, false, true},
+			{"clientCredentials", s.Flows.ClientCredentials, false, true},
+			{"authorizationCode", s.Flows.AuthorizationCode, true, true},
+		}
+		declared := false
+		for _, f := range flows {
+			if f.flow == nil {
+				continue
+			}
+			declared = true
+			fname := name + ".flows." + f.name
+			validateURL(fname+".authorizationUrl", f.flow.AuthorizationURL, f.authorization, errs)
+			validateURL(fname+".tokenUrl", f.flow.TokenURL, f.token, errs)
+			validateURL(fname+".refreshUrl", f.flow.RefreshURL, false, errs)
+			if f.flow.Scopes == nil {
+				errs.add(fname, "scopes is required, even if empty")
+			}
+			if !f.authorization && f.flow.AuthorizationURL != "" {
+				errs.add(fname, "authorizationUrl is not used by the %s flow", f.name)
+			}
+			if !f.token && f.flow.TokenURL != "" {
+				errs.add(fname, "tokenUrl is not used by the %s flow", f.name)
+			}
+		}
+		if !declared {
+			errs.add(name+".flows", "at least one flow is required")
+		}
+	case "openIdConnect":
+		validateURL(name+".openIdConnectUrl", s.OpenIDConnectURL, true, errs)
+	case "":
+		errs.add(name, "type is required")
+	default:
+		errs.add(name, "type %q is not one of apiKey, http, mutualTLS, oauth2 or openIdConnect", s.Type)
+	}
+}
+
+// validateURL checks that u is a valid URL, and that it is set if required.
+func validateURL(name, u string, required bool, errs *specErrors) {
+	if u == "" {
+		if required {
+			errs.add(name, "url is required")
+		}
+		return
+	}
+	if _, err := url.Parse(u); err != nil {
+		errs.add(name, "url is invalid: %v", err)
+	}
+}
+
+// Scopes returns the scopes declared by the flows, keyed by name.
+func (f *OAuthFlows) Scopes() map[string]string {
+	ret := map[string]string{}
+	for _, flow := range []*OAuthFlow{f.Implicit, f.Password, f.ClientCredentials, f.AuthorizationCode} {
+		if flow == nil {
+			continue
+		}
+		for name, description := range flow.Scopes {
+			ret[name] = description
+		}
+	}
+	return ret
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+)
+
+const securityDoc = `{
+  "openapi": "3.1.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "security": [{"oauth": ["read"]}],
+  "components": {
+    "securitySchemes": {
+      "oauth": {
+        "type": "oauth2",
+        "flows": {
+          "authorizationCode": {
+            "authorizationUrl": "https://auth.example.com/authorize",
+            "tokenUrl": "https://auth.example.com/token",
+            "refreshUrl": "https://auth.example.com/token",
+            "scopes": {"read": "read pets", "write": "write pets"}
+          }
+        }
+      },
+      "key": {"type": "apiKey", "name": "X-API-Key", "in": "header"},
+      "alias": {"$ref": "#/components/securitySchemes/key"},
+      "oidc": {"type": "openIdConnect", "openIdConnectUrl": "https://auth.example.com/.well-known/openid-configuration"}
+    }
+  },
+  "paths": {
+    "/pets": {
+      "get": {"responses": {"200": {"description": "ok"}}},
+      "post": {
+        "security": [{"oauth": ["write"]}, {"key": [], "oidc": ["admin"]}],
+        "responses": {"201": {"description": "created"}}
+      },
+      "delete": {
+        "security": [{"oauth": ["delete"]}, {"missing": []}, {"alias": []}],
+        "responses": {"204": {"description": "deleted"}}
+      }
+    },
+    "/health": {
+      "get": {"security": [], "responses": {"200": {"description": "ok"}}}
+    }
+  }
+}`
+
+func loadSecurityDoc(t *testing.T) *OpenAPI {
+	var o OpenAPI
+	require.NoError(t, json.Unmarshal([]byte(securityDoc), &o))
+	return &o
+}
+
+func TestSecuritySchemeValidate(t *testing.T) {
+	o := loadSecurityDoc(t)
+	for name, s := range o.Components.SecuritySchemes {
+		if s.Ref.String() == "" {
+			assert.NoError(t, s.Validate(), name)
+		}
+	}
+
+	for _, tc := range []struct {
+		scheme string
+		want   []string
+	}{
+		{`{}`, []string{`securityScheme is invalid: type is required`}},
+		{`{"type": "basic"}`, []string{`securityScheme is invalid: type "basic" is not one of apiKey, http, mutualTLS, oauth2 or openIdConnect`}},
+		{`{"type": "apiKey", "in": "body"}`, []string{
+			`securityScheme is invalid: name is required for apiKey security schemes`,
+			`securityScheme is invalid: in must be one of query, header or cookie, got "body"`,
+		}},
+		{`{"type": "http"}`, []string{`securityScheme is invalid: scheme is required for http security schemes`}},
+		{`{"type": "oauth2"}`, []string{`securityScheme is invalid: flows is required for oauth2 security schemes`}},
+		{`{"type": "oauth2", "flows": {}}`, []string{`securityScheme.flows is invalid: at least one flow is required`}},
+		{`{"type": "oauth2", "flows": {
+		    "implicit": {"tokenUrl": "https://a/token", "scopes": {}},
+		    "clientCredentials": {"tokenUrl": "http://%zz"},
+		    "authorizationCode": {"tokenUrl": "https://a/token", "scopes": {}}
+		  }}`, []string{
+			`securityScheme.flows.implicit.authorizationUrl is invalid: url is required`,
+			`securityScheme.flows.implicit is invalid: tokenUrl is not used by the implicit flow`,
+			`securityScheme.flows.clientCredentials.tokenUrl is invalid: url is invalid: parse "http://%zz": invalid URL escape "%zz"`,
+			`securityScheme.flows.clientCredentials is invalid: scopes is required, even if empty`,
+			`securityScheme.flows.authorizationCode.authorizationUrl is invalid: url is required`,
+		}},
+		{`{"type": "openIdConnect"}`, []string{`securityScheme.openIdConnectUrl is invalid: url is required`}},
+		{`{"type": "mutualTLS"}`, nil},
+	} {
+		var s SecurityScheme
+		require.NoError(t, json.Unmarshal([]byte(tc.scheme), &s))
+		err := s.Validate()
+		if tc.want == nil {
+			assert.NoError(t, err, tc.scheme)
+			continue
+		}
+		require.Error(t, err, tc.scheme)
+		var msgs []string
+		for _, e := range err.(*errors.CompositeError).Errors {
+			msgs = append(msgs, e.Error())
+		}
+		assert.Equal(t, tc.want, msgs, tc.scheme)
+	}
+}
+
+func TestValidateSecurity(t *testing.T) {
+	o := loadSecurityDoc(t)
+	err := o.ValidateSecurity()
+	require.Error(t, err)
+	var msgs []string
+	for _, e := range err.(*errors.CompositeError).Errors {
+		msgs = append(msgs, e.Error())
+	}
+	assert.Equal(t, []string{
+		`paths./pets.delete.security[0] is invalid: scope "delete" is not declared by security scheme "oauth"`,
+		`paths./pets.delete.security[1] is invalid: security scheme "missing" is not declared`,
+	}, msgs)
+}
+
+func TestScopeChecker(t *testing.T) {
+	o := loadSecurityDoc(t)
+	pets := o.Paths.Paths["/pets"]
+	health := o.Paths.Paths["/health"]
+
+	reader := NewScopeChecker(map[string][]string{"oauth": {"read"}})
+	assert.True(t, reader.Allows(o, pets.Get))
+	assert.False(t, reader.Allows(o, pets.Post))
+	assert.True(t, reader.Allows(o, health.Get))
+	assert.Equal(t, map[string][]string{"oauth": {"write"}}, reader.Missing(pets.Post.Security[0]))
+	assert.Equal(t, map[string][]string{"key": {}, "oidc": {"admin"}}, reader.Missing(pets.Post.Security[1]))
+
+	// Alternatives are ORed, schemes within one are ANDed.
+	admin := NewScopeChecker(map[string][]string{"key": nil, "oidc": {"admin", "other"}})
+	assert.True(t, admin.Allows(o, pets.Post))
+	assert.False(t, admin.Allows(o, pets.Get))
+	assert.False(t, NewScopeChecker(map[string][]string{"key": nil}).Allows(o, pets.Post))
+
+	anonymous := NewScopeChecker(nil)
+	assert.True(t, anonymous.Satisfies(nil))
+	assert.True(t, anonymous.Satisfies([]map[string][]string{{}}))
+	assert.Nil(t, anonymous.Missing(map[string][]string{}))
+	assert.False(t, anonymous.Allows(o, pets.Get))
+}
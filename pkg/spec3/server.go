@@ -0,0 +1,202 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// ServerProps are the properties of a server.
+type ServerProps struct {
+	// URL may be relative to the document and contain {variables}.
+	URL         string                     `json:"url"`
+	Description string                     `json:"description,omitempty"`
+	Variables   map[string]*ServerVariable `json:"variables,omitempty"`
+}
+
+// Server is a base URL of the API.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#server-object
+type Server struct {
+	ServerProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the server to JSON.
+func (s Server) MarshalJSON() ([]byte, error) {
+	return marshal(s.ServerProps, s.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the server from JSON.
+func (s *Server) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &s.ServerProps, &s.VendorExtensible)
+}
+
+// ServerVariableProps are the properties of a server variable.
+type ServerVariableProps struct {
+	Enum        []string `json:"enum,omitempty"`
+	Default     string   `json:"default"`
+	Description string   `json:"description,omitempty"`
+}
+
+// ServerVariable is a variable of a server URL template.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#server-variable-object
+type ServerVariable struct {
+	ServerVariableProps
+	spec.VendorExtensible
+}
+
+// MarshalJSON converts the server variable to JSON.
+func (v ServerVariable) MarshalJSON() ([]byte, error) {
+	return marshal(v.ServerVariableProps, v.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the server variable from JSON.
+func (v *ServerVariable) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &v.ServerVariableProps, &v.VendorExtensible)
+}
+
+var serverVariableRegexp = regexp.MustCompile(`{([^{}]*)}`)
+
+// Validate checks that the URL of the server is valid once its variables are
+// substituted, that the variables it uses are declared, and that the
+// defaults of the variables are among their enum values. It returns nil or
+// an *errors.CompositeError of errors built by errors.InvalidSpec.
+func (s *Server) Validate() error {
+	var errs specErrors
+	s.validate("server", &errs)
+	return errs.err()
+}
+
+func (s *Server) validate(name string, errs *specErrors) {
+	if s.URL == "" {
+		errs.add(name, "url is required")
+		return
+	}
+	used := map[string]bool{}
+	for _, m := range serverVariableRegexp.FindAllStringSubmatch(s.URL, -1) {
+		used[m[1]] = true
+		if _, ok := s.Variables[m[1]]; !ok {
+			errs.add(name, "variable %q is not declared", m[1])
+		}
+	}
+	for _, n := range sortedKeys(s.Variables) {
+		v := s.Variables[n]
+		vname := name + ".variables." + n
+		if !used[n] {
+			errs.add(vname, "variable is not used in the url")
+		}
+		if v == nil {
+			continue
+		}
+		if v.Enum != nil && len(v.Enum) == 0 {
+			errs.add(vname, "enum must not be empty")
+		}
+		if len(v.Enum) > 0 && !containsString(v.Enum, v.Default) {
+			errs.add(vname, "default %q is not one of the enum values", v.Default)
+		}
+	}
+	if _, err := url.Parse(s.substitute(nil)); err != nil {
+		errs.add(name, "url is invalid: %v", err)
+	}
+}
+
+func (s *Server) substitute(values map[string]string) string {
+	return serverVariableRegexp.ReplaceAllStringFunc(s.URL, func(m string) string {
+		n := m[1 : len(m)-1]
+		if v, ok := values[n]; ok {
+			return v
+		}
+		if v := s.Variables[n]; v != nil {
+			return v.Default
+		}
+		return m
+	})
+}
+
+// Resolve returns the URL of the server with its variables replaced by the
+// given values, or by their defaults for those not given. Values must be
+// among the enum values of their variable, when there are some.
+func (s *Server) Resolve(values map[string]string) (string, error) {
+	for n, value := range values {
+		v, ok := s.Variables[n]
+		if !ok || v == nil {
+			return "", fmt.Errorf("server %s has no variable %q", s.URL, n)
+		}
+		if len(v.Enum) > 0 && !containsString(v.Enum, value) {
+			return "", fmt.Errorf("value %q of variable %q is not one of %s", value, n, strings.Join(v.Enum, ", "))
+		}
+	}
+	for _, m := range serverVariableRegexp.FindAllStringSubmatch(s.URL, -1) {
+		if _, ok := s.Variables[m[1]]; !ok {
+			return "", fmt.Errorf("variable %q of server %s is not declared", m[1], s.URL)
+		}
+	}
+	return s.substitute(values), nil
+}
+
+// defaultServer is the server of documents that don't declare any.
+var defaultServer = &Server{ServerProps: ServerProps{URL: "/"}}
+
+// Resolve returns the base URL of the server at serverIndex in the document,
+// see Server.Resolve. Documents without servers have a single server at "/".
+func (o *OpenAPI) Resolve(serverIndex int, values map[string]string) (string, error) {
+	servers := o.Servers
+	if len(servers) == 0 {
+		servers = []*Server{defaultServer}
+	}
+	if serverIndex < 0 || serverIndex >= len(servers) {
+		return "", fmt.Errorf("no server at index %d, there are %d", serverIndex, len(servers))
+	}
+	return servers[serverIndex].Resolve(values)
+}
+
+// ValidateServers validates the servers of the document, of its paths and
+// of its operations, see Server.Validate.
+func (o *OpenAPI) ValidateServers() error {
+	var errs specErrors
+	validate := func(name string, servers []*Server) {
+		for i, s := range servers {
+			if s == nil {
+				continue
+			}
+			s.validate(fmt.Sprintf("%s[%d]", name, i), &errs)
+		}
+	}
+	validate("servers", o.Servers)
+	o.walkPaths(func(path string, item *Path) {
+		validate("paths."+path+".servers", item.Servers)
+	}, func(path, method string, _ *Path, op *Operation) {
+		validate("paths."+path+"."+method+".servers", op.Servers)
+	})
+	return errs.err()
+}
+
+func containsString(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testServer() *Server {
+	return &Server{ServerProps: ServerProps{
+		URL: "https://{region}.example.com:{port}/v1",
+		Variables: map[string]*ServerVariable{
+			"region": {ServerVariableProps: ServerVariableProps{Enum: []string{"eu", "us"}, Default: "eu"}},
+			"port":   {ServerVariableProps: ServerVariableProps{Default: "443"}},
+		},
+	}}
+}
+
+func TestServerValidate(t *testing.T) {
+	assert.NoError(t, testServer().Validate())
+
+	s := testServer()
+	s.URL = "https://{region}.{zone}.example.com/v1"
+	s.Variables["region"].Default = "ap"
+	s.Variables["port"].Enum = []string{}
+	err := s.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `variable "zone" is not declared`)
+	assert.Contains(t, err.Error(), `default "ap" is not one of the enum values`)
+	assert.Contains(t, err.Error(), "server.variables.port")
+	assert.Contains(t, err.Error(), "enum must not be empty")
+
+	assert.Error(t, (&Server{}).Validate())
+	assert.Error(t, (&Server{ServerProps: ServerProps{URL: "http://%zz"}}).Validate())
+}
+
+func TestServerResolve(t *testing.T) {
+	s := testServer()
+	u, err := s.Resolve(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://eu.example.com:443/v1", u)
+
+	u, err = s.Resolve(map[string]string{"region": "us", "port": "8443"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://us.example.com:8443/v1", u)
+
+	_, err = s.Resolve(map[string]string{"region": "ap"})
+	assert.Error(t, err)
+	_, err = s.Resolve(map[string]string{"zone": "a"})
+	assert.Error(t, err)
+}
+
+func TestOpenAPIResolve(t *testing.T) {
+	o := &OpenAPI{}
+	u, err := o.Resolve(0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "/", u)
+
+	o.Servers = []*Server{{ServerProps: ServerProps{URL: "/api"}}, testServer()}
+	u, err = o.Resolve(1, map[string]string{"region": "us"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://us.example.com:443/v1", u)
+
+	_, err = o.Resolve(2, nil)
+	assert.Error(t, err)
+	_, err = o.Resolve(-1, nil)
+	assert.Error(t, err)
+}
+
+func TestOpenAPIValidateServers(t *testing.T) {
+	o := &OpenAPI{OpenAPIProps: OpenAPIProps{
+		Servers: []*Server{testServer()},
+		Paths: &Paths{Paths: map[string]*Path{
+			"/pets": {PathProps: PathProps{
+				Servers: []*Server{{ServerProps: ServerProps{URL: "/{version}"}}},
+				Get: &Operation{OperationProps: OperationProps{
+					Servers: []*Server{{}},
+				}},
+			}},
+		}},
+	}}
+	err := o.ValidateServers()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "paths./pets.servers[0]")
+	assert.Contains(t, err.Error(), "paths./pets.get.servers[0]")
+
+	o.Paths = nil
+	assert.NoError(t, o.ValidateServers())
+}
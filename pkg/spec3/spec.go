@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spec3 models OpenAPI 3.0 and 3.1 documents. Schemas are the
+// *spec.Schema of the validation/spec package, which keeps the JSON Schema
+// keywords it doesn't model, like the 3.1 $defs, const or examples, in
+// ExtraProps so that they survive a round trip.
+//
+// Every object marshals its Refable, its props and its VendorExtensible
+// side by side, like the Swagger 2.0 objects of the spec package.
+package spec3
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-openapi/swag"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// OpenAPIProps are the properties of the root object of a document.
+type OpenAPIProps struct {
+	// Version is the OpenAPI version of the document, e.g. "3.0.3" or "3.1.0".
+	Version string     `json:"openapi"`
+	Info    *spec.Info `json:"info"`
+	// JSONSchemaDialect is the default $schema of the schemas, since 3.1.
+	JSONSchemaDialect string    `json:"jsonSchemaDialect,omitempty"`
+	Servers           []*Server `json:"servers,omitempty"`
+	Paths             *Paths    `json:"paths,omitempty"`
+	// Webhooks are the requests the API may send to its consumers, since 3.1.
+	Webhooks     map[string]*Path            `json:"webhooks,omitempty"`
+	Components   *Components                 `json:"components,omitempty"`
+	Security     []map[string][]string       `json:"security,omitempty"`
+	Tags         []spec.Tag                  `json:"tags,omitempty"`
+	ExternalDocs *spec.ExternalDocumentation `json:"externalDocs,omitempty"`
+}
+
+// OpenAPI is the root object of an OpenAPI 3 document.
+//
+// For more information: https://spec.openapis.org/oas/v3.1.0#openapi-object
+type OpenAPI struct {
+	OpenAPIProps
+	spec.VendorExtensible
+}
+
+// Is31 returns whether the document is an OpenAPI 3.1 document.
+func (o *OpenAPI) Is31() bool {
+	return strings.HasPrefix(o.Version, "3.1")
+}
+
+// MarshalJSON converts the document to JSON.
+func (o OpenAPI) MarshalJSON() ([]byte, error) {
+	return marshal(o.OpenAPIProps, o.VendorExtensible)
+}
+
+// UnmarshalJSON hydrates the document from JSON.
+func (o *OpenAPI) UnmarshalJSON(data []byte) error {
+	return unmarshal(data, &o.OpenAPIProps, &o.VendorExtensible)
+}
+
+// marshal merges the JSON objects of the given values.
+func marshal(parts ...interface{}) ([]byte, error) {
+	objects := make([][]byte, 0, len(parts))
+	for _, p := range parts {
+		b, err := json.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, b)
+	}
+	return swag.ConcatJSON(objects...), nil
+}
+
+// unmarshal decodes the same JSON object into each of the given values.
+func unmarshal(data []byte, parts ...interface{}) error {
+	for _, p := range parts {
+		if err := json.Unmarshal(data, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
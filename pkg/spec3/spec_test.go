@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstore31 = `{
+  "openapi": "3.1.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "jsonSchemaDialect": "https://spec.openapis.org/oas/3.1/dialect/base",
+  "servers": [{"url": "https://{region}.example.com/v1", "variables": {"region": {"default": "eu", "enum": ["eu", "us"]}}}],
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+      "get": {
+        "operationId": "getPet",
+        "parameters": [{"name": "fields", "in": "query", "style": "form", "explode": false, "schema": {"type": "array", "items": {"type": "string"}}}],
+        "responses": {
+          "200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}, "examples": {"cat": {"value": {"name": "tom"}}}}}},
+          "4XX": {"$ref": "#/components/responses/Error"},
+          "default": {"description": "error"},
+          "x-codes": "documented"
+        }
+      },
+      "put": {
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+        "responses": {"204": {"description": "updated"}}
+      }
+    },
+    "x-paths": true
+  },
+  "webhooks": {
+    "newPet": {"post": {"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}, "responses": {"200": {"description": "ok"}}}}
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string", "examples": ["tom"]},
+          "nick": {"type": ["string", "null"]},
+          "kind": {"const": "pet"},
+          "tags": {"$ref": "#/components/schemas/Pet/$defs/tags"}
+        },
+        "$defs": {"tags": {"type": "array", "prefixItems": [{"type": "string"}]}}
+      }
+    },
+    "responses": {"Error": {"description": "error", "headers": {"X-Request-Id": {"schema": {"type": "string"}}}}},
+    "securitySchemes": {"oauth": {"type": "oauth2", "flows": {"clientCredentials": {"tokenUrl": "https://example.com/token", "scopes": {"read": "read pets"}}}}},
+    "pathItems": {"health": {"get": {"responses": {"200": {"description": "ok"}}}}}
+  },
+  "security": [{"oauth": ["read"]}],
+  "tags": [{"name": "pets"}],
+  "x-owner": "team"
+}`
+
+func TestOpenAPIRoundTrip(t *testing.T) {
+	var o OpenAPI
+	require.NoError(t, json.Unmarshal([]byte(petstore31), &o))
+	assert.True(t, o.Is31())
+	assert.Equal(t, "team", o.Extensions["x-owner"])
+	assert.Equal(t, []string{"eu", "us"}, o.Servers[0].Variables["region"].Enum)
+
+	path := o.Paths.Paths["/pets/{id}"]
+	require.NotNil(t, path)
+	assert.Equal(t, true, o.Paths.Extensions["x-paths"])
+	assert.Len(t, path.Operations(), 2)
+	get := path.Get
+	assert.Equal(t, "getPet", get.OperationID)
+	require.NotNil(t, get.Parameters[0].Explode)
+	assert.False(t, *get.Parameters[0].Explode)
+	assert.Equal(t, "error", get.Responses.Default.Description)
+	assert.Equal(t, "#/components/responses/Error", get.Responses.StatusCodeResponses["4XX"].Ref.String())
+	assert.Equal(t, "documented", get.Responses.Extensions["x-codes"])
+	assert.Equal(t, "#/components/schemas/Pet", get.Responses.StatusCodeResponses["200"].Content["application/json"].Schema.Ref.String())
+	assert.True(t, path.Put.RequestBody.Required)
+	assert.NotNil(t, o.Webhooks["newPet"].Post)
+	assert.NotNil(t, o.Components.PathItems["health"].Get)
+	assert.Equal(t, "https://example.com/token", o.Components.SecuritySchemes["oauth"].Flows.ClientCredentials.TokenURL)
+
+	pet := o.Components.Schemas["Pet"]
+	assert.Contains(t, pet.ExtraProps, "$defs")
+	assert.Equal(t, "pet", pet.Properties["kind"].ExtraProps["const"])
+
+	data, err := json.Marshal(o)
+	require.NoError(t, err)
+	assert.JSONEq(t, petstore31, string(data))
+}
+
+func TestIs31(t *testing.T) {
+	assert.False(t, (&OpenAPI{OpenAPIProps: OpenAPIProps{Version: "3.0.3"}}).Is31())
+	assert.True(t, (&OpenAPI{OpenAPIProps: OpenAPIProps{Version: "3.1.0"}}).Is31())
+}
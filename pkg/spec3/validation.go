@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec3
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+)
+
+// specErrors accumulates the errors found while validating a document.
+type specErrors []error
+
+func (e *specErrors) add(name, format string, args ...interface{}) {
+	*e = append(*e, errors.InvalidSpec(name, "", fmt.Sprintf(format, args...)))
+}
+
+// err returns nil or an *errors.CompositeError of the accumulated errors.
+func (e specErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return errors.CompositeValidationError(e...)
+}
+
+// sortedKeys returns the sorted keys of a map with string keys.
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	ret := make([]string, 0, len(keys))
+	for _, k := range keys {
+		ret = append(ret, k.String())
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,347 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package structural converts schemas to structural schemas, the form
+// Kubernetes requires for CustomResourceDefinitions: every node of the
+// schema specifies its type, and the value validations (allOf, anyOf,
+// oneOf, not) only constrain values without introducing new structure.
+// NewStructural splits a schema into its structure and its value
+// validations, and Validate performs the checks CRD-style governance
+// expects.
+package structural
+
+import (
+	"fmt"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Structural is a structural schema.
+type Structural struct {
+	Items      *Structural
+	Properties map[string]Structural
+
+	Generic
+	Extensions
+
+	ValueValidation *ValueValidation
+}
+
+// StructuralOrBool is either a structural schema or a boolean.
+type StructuralOrBool struct {
+	Structural *Structural
+	Bool       bool
+}
+
+// Generic are the keywords of a node that are not value validations.
+type Generic struct {
+	Description string
+	// Type is empty, "object", "array", "number", "integer", "boolean" or
+	// "string". It is only empty for x-kubernetes-int-or-string and
+	// x-kubernetes-preserve-unknown-fields nodes.
+	Type                 string
+	Title                string
+	Default              interface{}
+	AdditionalProperties *StructuralOrBool
+	Nullable             bool
+}
+
+// Extensions are the Kubernetes extensions of a node.
+type Extensions struct {
+	XPreserveUnknownFields bool
+	XEmbeddedResource      bool
+	XIntOrString           bool
+	XListMapKeys           []string
+	XListType              *string
+	XMapType               *string
+}
+
+// ValueValidation are the value validations of a node.
+type ValueValidation struct {
+	Format           string
+	Maximum          *float64
+	ExclusiveMaximum bool
+	Minimum          *float64
+	ExclusiveMinimum bool
+	MaxLength        *int64
+	MinLength        *int64
+	Pattern          string
+	MaxItems         *int64
+	MinItems         *int64
+	UniqueItems      bool
+	MultipleOf       *float64
+	Enum             []interface{}
+	MaxProperties    *int64
+	MinProperties    *int64
+	Required         []string
+	AllOf            []NestedValueValidation
+	OneOf            []NestedValueValidation
+	AnyOf            []NestedValueValidation
+	Not              *NestedValueValidation
+}
+
+// NestedValueValidation is a value validation in allOf, anyOf, oneOf or
+// not. It may refer to the properties and items of the structure, but the
+// generic keywords and extensions it specifies are forbidden, and kept to
+// be reported by Validate.
+type NestedValueValidation struct {
+	ValueValidation
+	Items      *NestedValueValidation
+	Properties map[string]NestedValueValidation
+
+	ForbiddenGenerics   Generic
+	ForbiddenExtensions Extensions
+}
+
+const (
+	extPreserveUnknownFields = "x-kubernetes-preserve-unknown-fields"
+	extEmbeddedResource      = "x-kubernetes-embedded-resource"
+	extIntOrString           = "x-kubernetes-int-or-string"
+	extListMapKeys           = "x-kubernetes-list-map-keys"
+	extListType              = "x-kubernetes-list-type"
+	extMapType               = "x-kubernetes-map-type"
+)
+
+// NewStructural converts a schema to a structural schema. It fails on the
+// keywords structural schemas don't support: $ref, id, $schema,
+// definitions, patternProperties, dependencies, additionalItems and tuple
+// items. Other constraints are checked by Validate.
+func NewStructural(s *spec.Schema) (*Structural, error) {
+	return newStructural(s, "")
+}
+
+func newStructural(s *spec.Schema, path string) (*Structural, error) {
+	if s == nil {
+		return nil, nil
+	}
+	if err := unsupported(s, path); err != nil {
+		return nil, err
+	}
+
+	ret := &Structural{}
+	var err error
+	if ret.Generic, err = newGeneric(s, path); err != nil {
+		return nil, err
+	}
+	if ret.Extensions, err = newExtensions(s, path); err != nil {
+		return nil, err
+	}
+	if s.Items != nil {
+		if ret.Items, err = newStructural(s.Items.Schema, path+".items"); err != nil {
+			return nil, err
+		}
+	}
+	for name, prop := range s.Properties {
+		prop := prop
+		p, err := newStructural(&prop, path+".properties["+name+"]")
+		if err != nil {
+			return nil, err
+		}
+		if ret.Properties == nil {
+			ret.Properties = map[string]Structural{}
+		}
+		ret.Properties[name] = *p
+	}
+	if ret.ValueValidation, err = newValueValidation(s, path); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func unsupported(s *spec.Schema, path string) error {
+	at := func(keyword string) error {
+		return fmt.Errorf("%s: not supported in structural schemas", orRoot(path+"."+keyword))
+	}
+	switch {
+	case s.Ref.String() != "":
+		return at("$ref")
+	case s.ID != "":
+		return at("id")
+	case s.Schema != "":
+		return at("$schema")
+	case len(s.Definitions) > 0:
+		return at("definitions")
+	case len(s.PatternProperties) > 0:
+		return at("patternProperties")
+	case len(s.Dependencies) > 0:
+		return at("dependencies")
+	case s.AdditionalItems != nil:
+		return at("additionalItems")
+	case s.Items != nil && len(s.Items.Schemas) > 0:
+		return fmt.Errorf("%s: tuple items are not supported in structural schemas", orRoot(path+".items"))
+	case len(s.Type) > 1:
+		return fmt.Errorf("%s: multiple types are not supported in structural schemas", orRoot(path+".type"))
+	}
+	return nil
+}
+
+func orRoot(path string) string {
+	if path == "" || path[0] != '.' {
+		return path
+	}
+	return path[1:]
+}
+
+func newGeneric(s *spec.Schema, path string) (Generic, error) {
+	g := Generic{
+		Description: s.Description,
+		Title:       s.Title,
+		Default:     s.Default,
+		Nullable:    s.Nullable,
+	}
+	if len(s.Type) == 1 {
+		g.Type = s.Type[0]
+	}
+	if s.AdditionalProperties != nil {
+		g.AdditionalProperties = &StructuralOrBool{Bool: s.AdditionalProperties.Allows}
+		if s.AdditionalProperties.Schema != nil {
+			additional, err := newStructural(s.AdditionalProperties.Schema, path+".additionalProperties")
+			if err != nil {
+				return Generic{}, err
+			}
+			g.AdditionalProperties = &StructuralOrBool{Structural: additional, Bool: true}
+		}
+	}
+	return g, nil
+}
+
+func newExtensions(s *spec.Schema, path string) (Extensions, error) {
+	var e Extensions
+	var ok bool
+	for _, b := range []struct {
+		key   string
+		field *bool
+	}{
+		{extPreserveUnknownFields, &e.XPreserveUnknownFields},
+		{extEmbeddedResource, &e.XEmbeddedResource},
+		{extIntOrString, &e.XIntOrString},
+	} {
+		if *b.field, ok = s.Extensions.GetBool(b.key); !ok && s.Extensions.Has(b.key) {
+			return Extensions{}, fmt.Errorf("%s: %s must be a boolean", orRoot(path+"."+b.key), b.key)
+		}
+	}
+	if s.Extensions.Has(extListMapKeys) {
+		if e.XListMapKeys, ok = s.Extensions.GetStringSlice(extListMapKeys); !ok {
+			return Extensions{}, fmt.Errorf("%s: %s must be a list of strings", orRoot(path+"."+extListMapKeys), extListMapKeys)
+		}
+	}
+	for _, str := range []struct {
+		key   string
+		field **string
+	}{
+		{extListType, &e.XListType},
+		{extMapType, &e.XMapType},
+	} {
+		if !s.Extensions.Has(str.key) {
+			continue
+		}
+		v, ok := s.Extensions.GetString(str.key)
+		if !ok {
+			return Extensions{}, fmt.Errorf("%s: %s must be a string", orRoot(path+"."+str.key), str.key)
+		}
+		*str.field = &v
+	}
+	return e, nil
+}
+
+func newValueValidation(s *spec.Schema, path string) (*ValueValidation, error) {
+	v := &ValueValidation{
+		Format:           s.Format,
+		Maximum:          s.Maximum,
+		ExclusiveMaximum: s.ExclusiveMaximum,
+		Minimum:          s.Minimum,
+		ExclusiveMinimum: s.ExclusiveMinimum,
+		MaxLength:        s.MaxLength,
+		MinLength:        s.MinLength,
+		Pattern:          s.Pattern,
+		MaxItems:         s.MaxItems,
+		MinItems:         s.MinItems,
+		UniqueItems:      s.UniqueItems,
+		MultipleOf:       s.MultipleOf,
+		Enum:             s.Enum,
+		MaxProperties:    s.MaxProperties,
+		MinProperties:    s.MinProperties,
+		Required:         s.Required,
+	}
+	for _, l := range []struct {
+		keyword string
+		in      []spec.Schema
+		out     *[]NestedValueValidation
+	}{{"allOf", s.AllOf, &v.AllOf}, {"oneOf", s.OneOf, &v.OneOf}, {"anyOf", s.AnyOf, &v.AnyOf}} {
+		for i := range l.in {
+			n, err := newNestedValueValidation(&l.in[i], fmt.Sprintf("%s.%s[%d]", path, l.keyword, i))
+			if err != nil {
+				return nil, err
+			}
+			*l.out = append(*l.out, *n)
+		}
+	}
+	if s.Not != nil {
+		n, err := newNestedValueValidation(s.Not, path+".not")
+		if err != nil {
+			return nil, err
+		}
+		v.Not = n
+	}
+	if isEmpty(v) {
+		return nil, nil
+	}
+	return v, nil
+}
+
+func newNestedValueValidation(s *spec.Schema, path string) (*NestedValueValidation, error) {
+	if err := unsupported(s, path); err != nil {
+		return nil, err
+	}
+	v, err := newValueValidation(s, path)
+	if err != nil {
+		return nil, err
+	}
+	ret := &NestedValueValidation{}
+	if v != nil {
+		ret.ValueValidation = *v
+	}
+	if ret.ForbiddenGenerics, err = newGeneric(s, path); err != nil {
+		return nil, err
+	}
+	if ret.ForbiddenExtensions, err = newExtensions(s, path); err != nil {
+		return nil, err
+	}
+	if s.Items != nil && s.Items.Schema != nil {
+		if ret.Items, err = newNestedValueValidation(s.Items.Schema, path+".items"); err != nil {
+			return nil, err
+		}
+	}
+	for name, prop := range s.Properties {
+		prop := prop
+		p, err := newNestedValueValidation(&prop, path+".properties["+name+"]")
+		if err != nil {
+			return nil, err
+		}
+		if ret.Properties == nil {
+			ret.Properties = map[string]NestedValueValidation{}
+		}
+		ret.Properties[name] = *p
+	}
+	return ret, nil
+}
+
+func isEmpty(v *ValueValidation) bool {
+	return v.Format == "" && v.Maximum == nil && !v.ExclusiveMaximum && v.Minimum == nil && !v.ExclusiveMinimum &&
+		v.MaxLength == nil && v.MinLength == nil && v.Pattern == "" && v.MaxItems == nil && v.MinItems == nil &&
+		!v.UniqueItems && v.MultipleOf == nil && len(v.Enum) == 0 && v.MaxProperties == nil && v.MinProperties == nil &&
+		len(v.Required) == 0 && len(v.AllOf) == 0 && len(v.OneOf) == 0 && len(v.AnyOf) == 0 && v.Not == nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package structural
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func mustSchema(t *testing.T, s string) *spec.Schema {
+	var ret spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(s), &ret))
+	return &ret
+}
+
+func TestNewStructural(t *testing.T) {
+	s, err := NewStructural(mustSchema(t, `{
+	  "type": "object",
+	  "description": "a pet",
+	  "properties": {
+	    "name": {"type": "string", "minLength": 1, "default": "rex"},
+	    "port": {"x-kubernetes-int-or-string": true, "anyOf": [{"type": "integer"}, {"type": "string"}]},
+	    "tags": {"type": "array", "x-kubernetes-list-type": "set", "items": {"type": "string"}},
+	    "labels": {"type": "object", "additionalProperties": {"type": "string"}, "x-kubernetes-map-type": "atomic"}
+	  },
+	  "required": ["name"],
+	  "oneOf": [{"required": ["tags"]}, {"properties": {"labels": {"minProperties": 1}}}]
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, Generic{Type: "object", Description: "a pet"}, s.Generic)
+	assert.Equal(t, []string{"name"}, s.ValueValidation.Required)
+	require.Len(t, s.ValueValidation.OneOf, 2)
+	assert.Equal(t, []string{"tags"}, s.ValueValidation.OneOf[0].Required)
+	minProps := int64(1)
+	assert.Equal(t, &minProps, s.ValueValidation.OneOf[1].Properties["labels"].MinProperties)
+
+	name := s.Properties["name"]
+	assert.Equal(t, "rex", name.Default)
+	minLength := int64(1)
+	assert.Equal(t, &ValueValidation{MinLength: &minLength}, name.ValueValidation)
+
+	port := s.Properties["port"]
+	assert.True(t, port.XIntOrString)
+	assert.Equal(t, "integer", port.ValueValidation.AnyOf[0].ForbiddenGenerics.Type)
+
+	tags := s.Properties["tags"]
+	assert.Equal(t, "set", *tags.XListType)
+	assert.Equal(t, "string", tags.Items.Type)
+	assert.Nil(t, tags.ValueValidation)
+
+	labels := s.Properties["labels"]
+	assert.Equal(t, "string", labels.AdditionalProperties.Structural.Type)
+	assert.Equal(t, "atomic", *labels.XMapType)
+}
+
+func TestNewStructuralUnsupported(t *testing.T) {
+	for schema, msg := range map[string]string{
+		`{"$ref": "#/definitions/A"}`:                                          "$ref: not supported",
+		`{"type": "object", "properties": {"a": {"$ref": "#/definitions/A"}}}`: "properties[a].$ref: not supported",
+		`{"definitions": {"A": {}}}`:                                           "definitions: not supported",
+		`{"patternProperties": {"^a": {}}}`:                                    "patternProperties: not supported",
+		`{"type": "array", "items": [{"type": "string"}]}`:                     "items: tuple items are not supported",
+		`{"type": ["string", "integer"]}`:                                      "multiple types are not supported",
+		`{"allOf": [{"additionalItems": false}]}`:                              "allOf[0].additionalItems: not supported",
+		`{"x-kubernetes-list-map-keys": "name"}`:                               "must be a list of strings",
+		`{"x-kubernetes-preserve-unknown-fields": "yes"}`:                      "must be a boolean",
+	} {
+		_, err := NewStructural(mustSchema(t, schema))
+		if assert.Error(t, err, schema) {
+			assert.Contains(t, err.Error(), msg, schema)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package structural
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+)
+
+// Validate checks that a structural schema is a valid root of a
+// CustomResourceDefinition:
+//
+//   - every node specifies its type, except below x-kubernetes-int-or-string
+//     and x-kubernetes-preserve-unknown-fields,
+//   - properties and additionalProperties are not both specified,
+//   - value validations only refer to properties and items of the structure
+//     and don't specify generic keywords or extensions,
+//   - metadata only specifies restrictions of name and generateName, at the
+//     root and in embedded resources,
+//   - the x-kubernetes extensions are consistent with the types.
+//
+// It returns nil or an *errors.CompositeError.
+func Validate(s *Structural) error {
+	v := &validator{}
+	if s.Type != "object" {
+		v.add("", "type must be object at the root, got %q", s.Type)
+	}
+	v.structural(s, "", true)
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return errors.CompositeValidationError(v.errs...)
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) add(path, format string, args ...interface{}) {
+	name := orRoot(path)
+	if name == "" {
+		name = "schema"
+	}
+	v.errs = append(v.errs, errors.InvalidSpec(name, "", fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) structural(s *Structural, path string, hasMetadata bool) {
+	switch s.Type {
+	case "":
+		if !s.XIntOrString && !s.XPreserveUnknownFields {
+			v.add(path+".type", "must not be empty, unless x-kubernetes-int-or-string or x-kubernetes-preserve-unknown-fields is true")
+		}
+	case "object", "array", "number", "integer", "boolean", "string":
+	default:
+		v.add(path+".type", "unsupported type %q", s.Type)
+	}
+	if s.XIntOrString && s.Type != "" {
+		v.add(path+".type", "must be empty when x-kubernetes-int-or-string is true")
+	}
+	if s.Type != "array" && s.Type != "" && s.Items != nil {
+		v.add(path+".items", "must only be specified for arrays")
+	}
+	if s.Type == "array" && s.Items == nil {
+		v.add(path+".items", "must be specified for arrays")
+	}
+	if s.Type != "object" && s.Type != "" && (len(s.Properties) > 0 || s.AdditionalProperties != nil) {
+		v.add(path+".properties", "must only be specified for objects")
+	}
+	if len(s.Properties) > 0 && s.AdditionalProperties != nil && (s.AdditionalProperties.Structural != nil || s.AdditionalProperties.Bool) {
+		v.add(path+".additionalProperties", "must not be specified along with properties")
+	}
+	v.extensions(s, path)
+
+	if hasMetadata || s.XEmbeddedResource {
+		if metadata, ok := s.Properties["metadata"]; ok {
+			v.metadata(&metadata, path+".properties[metadata]")
+		}
+	}
+	for _, name := range sortedKeys(s.Properties) {
+		prop := s.Properties[name]
+		v.structural(&prop, path+".properties["+name+"]", false)
+	}
+	if s.Items != nil {
+		v.structural(s.Items, path+".items", false)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Structural != nil {
+		v.structural(s.AdditionalProperties.Structural, path+".additionalProperties", false)
+	}
+	if s.ValueValidation != nil {
+		v.valueValidation(s.ValueValidation, s, path)
+	}
+}
+
+func (v *validator) extensions(s *Structural, path string) {
+	if s.XEmbeddedResource {
+		if s.Type != "object" {
+			v.add(path+".type", "must be object when x-kubernetes-embedded-resource is true")
+		}
+		if !s.XPreserveUnknownFields && len(s.Properties) == 0 {
+			v.add(path+".properties", "must not be empty when x-kubernetes-embedded-resource is true, unless x-kubernetes-preserve-unknown-fields is true")
+		}
+	}
+	if s.XMapType != nil {
+		if s.Type != "object" {
+			v.add(path+"."+extMapType, "must only be specified for objects")
+		}
+		if *s.XMapType != "granular" && *s.XMapType != "atomic" {
+			v.add(path+"."+extMapType, "must be granular or atomic, got %q", *s.XMapType)
+		}
+	}
+	if len(s.XListMapKeys) > 0 && (s.XListType == nil || *s.XListType != "map") {
+		v.add(path+"."+extListMapKeys, "must only be specified when x-kubernetes-list-type is map")
+	}
+	if s.XListType == nil {
+		return
+	}
+	if s.Type != "array" {
+		v.add(path+"."+extListType, "must only be specified for arrays")
+		return
+	}
+	switch *s.XListType {
+	case "atomic":
+	case "set":
+		if s.Items != nil && !isScalar(s.Items) && !isAtomic(s.Items) {
+			v.add(path+".items", "must be scalar or atomic when x-kubernetes-list-type is set")
+		}
+	case "map":
+		if s.Items == nil || s.Items.Type != "object" {
+			v.add(path+".items.type", "must be object when x-kubernetes-list-type is map")
+			return
+		}
+		if len(s.XListMapKeys) == 0 {
+			v.add(path+"."+extListMapKeys, "must not be empty when x-kubernetes-list-type is map")
+		}
+		for _, key := range s.XListMapKeys {
+			prop, ok := s.Items.Properties[key]
+			if !ok {
+				v.add(path+".items.properties["+key+"]", "must be specified for the list map key %q", key)
+				continue
+			}
+			if !isScalar(&prop) {
+				v.add(path+".items.properties["+key+"].type", "must be scalar for the list map key %q", key)
+			}
+		}
+	default:
+		v.add(path+"."+extListType, "must be atomic, set or map, got %q", *s.XListType)
+	}
+}
+
+func isScalar(s *Structural) bool {
+	switch s.Type {
+	case "number", "integer", "boolean", "string":
+		return true
+	}
+	return s.XIntOrString
+}
+
+func isAtomic(s *Structural) bool {
+	return s.XMapType != nil && *s.XMapType == "atomic" || s.XListType != nil && *s.XListType == "atomic"
+}
+
+// metadata checks that the metadata of a resource only restricts its name
+// and generateName, which the API server otherwise fully controls.
+func (v *validator) metadata(s *Structural, path string) {
+	if s.Type != "object" {
+		v.add(path+".type", "must be object")
+	}
+	if !reflect.DeepEqual(s.Generic, Generic{Type: s.Type}) || !reflect.DeepEqual(s.Extensions, Extensions{}) || s.ValueValidation != nil || s.Items != nil {
+		v.add(path, "must only specify type and the properties name and generateName")
+	}
+	for _, name := range sortedKeys(s.Properties) {
+		prop := s.Properties[name]
+		if name != "name" && name != "generateName" {
+			v.add(path+".properties["+name+"]", "must not be specified, only name and generateName can be restricted")
+			continue
+		}
+		if prop.Type != "string" {
+			v.add(path+".properties["+name+"].type", "must be string")
+		}
+		if !reflect.DeepEqual(prop.Generic, Generic{Type: prop.Type}) || !reflect.DeepEqual(prop.Extensions, Extensions{}) || prop.Items != nil || len(prop.Properties) > 0 {
+			v.add(path+".properties["+name+"]", "must only specify type and value validations")
+		}
+	}
+}
+
+func (v *validator) valueValidation(vv *ValueValidation, s *Structural, path string) {
+	for _, l := range []struct {
+		keyword string
+		nested  []NestedValueValidation
+	}{{"allOf", vv.AllOf}, {"oneOf", vv.OneOf}, {"anyOf", vv.AnyOf}} {
+		for i := range l.nested {
+			v.nested(&l.nested[i], s, fmt.Sprintf("%s.%s[%d]", path, l.keyword, i))
+		}
+	}
+	if vv.Not != nil {
+		v.nested(vv.Not, s, path+".not")
+	}
+}
+
+// nested checks a nested value validation against the structure s it
+// applies to, nil when the structure is unknown.
+func (v *validator) nested(n *NestedValueValidation, s *Structural, path string) {
+	g := n.ForbiddenGenerics
+	for _, f := range []struct {
+		keyword string
+		set     bool
+	}{
+		{"type", g.Type != ""},
+		{"description", g.Description != ""},
+		{"title", g.Title != ""},
+		{"default", g.Default != nil},
+		{"nullable", g.Nullable},
+		{"additionalProperties", g.AdditionalProperties != nil},
+	} {
+		if f.set {
+			v.add(path+"."+f.keyword, "must not be specified in a value validation")
+		}
+	}
+	if !reflect.DeepEqual(n.ForbiddenExtensions, Extensions{}) {
+		v.add(path, "x-kubernetes extensions must not be specified in a value validation")
+	}
+
+	open := s == nil || s.XPreserveUnknownFields || s.XIntOrString
+	for _, name := range sortedKeys(n.Properties) {
+		prop := n.Properties[name]
+		var structure *Structural
+		if s != nil {
+			if p, ok := s.Properties[name]; ok {
+				structure = &p
+			} else if s.AdditionalProperties != nil && s.AdditionalProperties.Structural != nil {
+				structure = s.AdditionalProperties.Structural
+			} else if !open {
+				v.add(path+".properties["+name+"]", "must have a corresponding property in the structure")
+			}
+		}
+		v.nested(&prop, structure, path+".properties["+name+"]")
+	}
+	if n.Items != nil {
+		var structure *Structural
+		if s != nil {
+			structure = s.Items
+			if structure == nil && !open {
+				v.add(path+".items", "must have corresponding items in the structure")
+			}
+		}
+		v.nested(n.Items, structure, path+".items")
+	}
+	v.valueValidation(&n.ValueValidation, s, path)
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	ret := make([]string, 0, len(keys))
+	for _, k := range keys {
+		ret = append(ret, k.String())
+	}
+	sort.Strings(ret)
+	return ret
+}
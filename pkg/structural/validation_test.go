@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package structural
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustStructural(t *testing.T, s string) *Structural {
+	ret, err := NewStructural(mustSchema(t, s))
+	require.NoError(t, err)
+	return ret
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(mustStructural(t, `{
+	  "type": "object",
+	  "properties": {
+	    "apiVersion": {"type": "string"},
+	    "metadata": {"type": "object", "properties": {"name": {"type": "string", "maxLength": 63}}},
+	    "spec": {
+	      "type": "object",
+	      "properties": {
+	        "port": {"x-kubernetes-int-or-string": true, "anyOf": [{"minimum": 1}, {"pattern": "^[a-z]+$"}]},
+	        "ports": {
+	          "type": "array",
+	          "x-kubernetes-list-type": "map",
+	          "x-kubernetes-list-map-keys": ["name"],
+	          "items": {"type": "object", "properties": {"name": {"type": "string"}}}
+	        },
+	        "template": {"type": "object", "x-kubernetes-embedded-resource": true, "x-kubernetes-preserve-unknown-fields": true},
+	        "extra": {"x-kubernetes-preserve-unknown-fields": true}
+	      },
+	      "oneOf": [{"required": ["port"]}, {"properties": {"ports": {"minItems": 1, "items": {"required": ["name"]}}}}]
+	    }
+	  }
+	}`)))
+}
+
+func TestValidateErrors(t *testing.T) {
+	for schema, msgs := range map[string][]string{
+		`{"type": "string"}`: {"type must be object at the root"},
+		`{"type": "object", "properties": {"a": {}}}`: {
+			"properties[a].type is invalid: must not be empty",
+		},
+		`{"type": "object", "properties": {"a": {"type": "object", "properties": {"b": {"type": "string"}}, "additionalProperties": {"type": "string"}}}}`: {
+			"properties[a].additionalProperties is invalid: must not be specified along with properties",
+		},
+		`{"type": "object", "properties": {"a": {"type": "string", "items": {"type": "string"}}, "b": {"type": "array"}}}`: {
+			"properties[a].items is invalid: must only be specified for arrays",
+			"properties[b].items is invalid: must be specified for arrays",
+		},
+		`{"type": "object", "properties": {"a": {"type": "string", "x-kubernetes-int-or-string": true}}}`: {
+			"properties[a].type is invalid: must be empty when x-kubernetes-int-or-string is true",
+		},
+		`{"type": "object", "properties": {"metadata": {"type": "object", "description": "d", "properties": {"labels": {"type": "object"}, "name": {"type": "integer"}}}}}`: {
+			"properties[metadata] is invalid: must only specify type and the properties name and generateName",
+			"properties[metadata].properties[labels] is invalid: must not be specified",
+			"properties[metadata].properties[name].type is invalid: must be string",
+		},
+		`{"type": "object", "properties": {"a": {"type": "object", "x-kubernetes-embedded-resource": true, "properties": {"metadata": {"type": "object", "properties": {"uid": {"type": "string"}}}}}}}`: {
+			"properties[a].properties[metadata].properties[uid] is invalid: must not be specified",
+		},
+		`{"type": "object", "properties": {"a": {"type": "string", "x-kubernetes-embedded-resource": true}}}`: {
+			"properties[a].type is invalid: must be object when x-kubernetes-embedded-resource is true",
+			"properties[a].properties is invalid: must not be empty when x-kubernetes-embedded-resource is true",
+		},
+		`{"type": "object", "properties": {
+		  "l": {"type": "array", "x-kubernetes-list-type": "map", "items": {"type": "object", "properties": {"o": {"type": "object"}}}, "x-kubernetes-list-map-keys": ["k", "o"]},
+		  "s": {"type": "array", "x-kubernetes-list-type": "set", "items": {"type": "object"}},
+		  "m": {"type": "object", "x-kubernetes-map-type": "deep"},
+		  "k": {"type": "array", "items": {"type": "string"}, "x-kubernetes-list-map-keys": ["a"]},
+		  "t": {"type": "string", "x-kubernetes-list-type": "atomic"}
+		}}`: {
+			"properties[l].items.properties[k] is invalid: must be specified for the list map key",
+			"properties[l].items.properties[o].type is invalid: must be scalar for the list map key",
+			"properties[s].items is invalid: must be scalar or atomic",
+			"properties[m].x-kubernetes-map-type is invalid: must be granular or atomic",
+			"properties[k].x-kubernetes-list-map-keys is invalid: must only be specified when x-kubernetes-list-type is map",
+			"properties[t].x-kubernetes-list-type is invalid: must only be specified for arrays",
+		},
+		`{"type": "object", "properties": {"a": {"type": "object", "properties": {"b": {"type": "string"}}}}, "anyOf": [
+		  {"type": "object", "x-kubernetes-preserve-unknown-fields": true},
+		  {"properties": {"a": {"properties": {"c": {"minLength": 1}}}, "z": {}}},
+		  {"not": {"properties": {"a": {"items": {"default": 1}}}}}
+		]}`: {
+			"anyOf[0].type is invalid: must not be specified in a value validation",
+			"anyOf[0] is invalid: x-kubernetes extensions must not be specified in a value validation",
+			"anyOf[1].properties[a].properties[c] is invalid: must have a corresponding property",
+			"anyOf[1].properties[z] is invalid: must have a corresponding property",
+			"anyOf[2].not.properties[a].items is invalid: must have corresponding items",
+			"anyOf[2].not.properties[a].items.default is invalid: must not be specified in a value validation",
+		},
+	} {
+		err := Validate(mustStructural(t, schema))
+		if !assert.Error(t, err, schema) {
+			continue
+		}
+		for _, msg := range msgs {
+			assert.Contains(t, err.Error(), msg, schema)
+		}
+	}
+}
@@ -17,12 +17,15 @@ limitations under the License.
 package proto
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
 
 	openapi_v2 "github.com/googleapis/gnostic/openapiv2"
 	"gopkg.in/yaml.v2"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
 func newSchemaError(path *Path, format string, a ...interface{}) error {
@@ -89,6 +92,31 @@ func NewOpenAPIData(doc *openapi_v2.Document) (Models, error) {
 	return &definitions, nil
 }
 
+// NewOpenAPIDataFromSwagger creates a new `Models` out of the definitions of
+// a spec. The swagger version, info and paths required by the openapi
+// document are filled in when the spec doesn't set them.
+func NewOpenAPIDataFromSwagger(s *spec.Swagger) (Models, error) {
+	props := s.SwaggerProps
+	if props.Swagger == "" {
+		props.Swagger = "2.0"
+	}
+	if props.Info == nil {
+		props.Info = &spec.Info{InfoProps: spec.InfoProps{Title: "definitions", Version: "unversioned"}}
+	}
+	if props.Paths == nil {
+		props.Paths = &spec.Paths{Paths: map[string]spec.PathItem{}}
+	}
+	specBytes, err := json.Marshal(&spec.Swagger{VendorExtensible: s.VendorExtensible, SwaggerProps: props})
+	if err != nil {
+		return nil, err
+	}
+	doc, err := openapi_v2.ParseDocument(specBytes)
+	if err != nil {
+		return nil, err
+	}
+	return NewOpenAPIData(doc)
+}
+
 // We believe the schema is a reference, verify that and returns a new
 // Schema
 func (d *Definitions) parseReference(s *openapi_v2.Schema, path *Path) (Schema, error) {
@@ -24,6 +24,7 @@ import (
 
 	"k8s.io/kube-openapi/pkg/util/proto"
 	"k8s.io/kube-openapi/pkg/util/proto/testing"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
 var fakeSchema = testing.Fake{Path: filepath.Join("testdata", "swagger.json")}
@@ -263,3 +264,54 @@ var _ = Describe("Path", func() {
 		Expect(field.Get()).To(Equal([]string{"key", "[12]", ".subKey"}))
 	})
 })
+
+var _ = Describe("Reading models from a spec", func() {
+	var models proto.Models
+	BeforeEach(func() {
+		s := &spec.Swagger{
+			SwaggerProps: spec.SwaggerProps{
+				Definitions: spec.Definitions{
+					"io.example.Pet": *new(spec.Schema).
+						Typed("object", "").
+						WithRequired("name").
+						SetProperty("name", *spec.StringProperty()).
+						SetProperty("tags", *spec.ArrayProperty(spec.StringProperty())).
+						SetProperty("labels", *spec.MapProperty(spec.StringProperty())).
+						SetProperty("owner", *spec.RefSchema("#/definitions/io.example.Owner")),
+					"io.example.Owner": *spec.StringProperty(),
+				},
+			},
+		}
+		var err error
+		models, err = proto.NewOpenAPIDataFromSwagger(s)
+		Expect(err).To(BeNil())
+	})
+
+	It("should list the definitions", func() {
+		Expect(models.ListModels()).To(Equal([]string{"io.example.Owner", "io.example.Pet"}))
+	})
+
+	It("should parse the definitions into schemas", func() {
+		pet := models.LookupModel("io.example.Pet").(*proto.Kind)
+		Expect(pet.IsRequired("name")).To(BeTrue())
+		Expect(pet.Keys()).To(Equal([]string{"labels", "name", "owner", "tags"}))
+		Expect(pet.Fields["name"].(*proto.Primitive).Type).To(Equal("string"))
+		Expect(pet.Fields["tags"].(*proto.Array).SubType.(*proto.Primitive).Type).To(Equal("string"))
+		Expect(pet.Fields["labels"].(*proto.Map).SubType.(*proto.Primitive).Type).To(Equal("string"))
+		owner := pet.Fields["owner"].(proto.Reference)
+		Expect(owner.Reference()).To(Equal("io.example.Owner"))
+		Expect(owner.SubSchema().(*proto.Primitive).Type).To(Equal("string"))
+	})
+
+	It("should reject dangling references", func() {
+		s := &spec.Swagger{
+			SwaggerProps: spec.SwaggerProps{
+				Definitions: spec.Definitions{
+					"io.example.Pet": *spec.RefSchema("#/definitions/io.example.Missing"),
+				},
+			},
+		}
+		_, err := proto.NewOpenAPIDataFromSwagger(s)
+		Expect(err).ToNot(BeNil())
+	})
+})
@@ -53,7 +53,18 @@ func ToCanonicalName(name string) string {
 //
 //	Input:  csi.storage.k8s.io/v1alpha1.CSINodeInfo
 //	Output: io.k8s.storage.csi.v1alpha1.CSINodeInfo
+//
+// The type arguments of an instantiated generic type are converted as well:
+//	Input:  k8s.io/api/core/v1.List[k8s.io/api/core/v1.Pod]
+//	Output: io.k8s.api.core.v1.List[io.k8s.api.core.v1.Pod]
 func ToRESTFriendlyName(name string) string {
+	if base, args := SplitTypeArguments(name); args != nil {
+		for i, arg := range args {
+			prefix, arg := splitTypePrefix(arg)
+			args[i] = prefix + ToRESTFriendlyName(arg)
+		}
+		return ToRESTFriendlyName(base) + "[" + strings.Join(args, ",") + "]"
+	}
 	nameParts := strings.Split(name, "/")
 	// Reverse first part. e.g., io.k8s... instead of k8s.io...
 	if len(nameParts) > 0 && strings.Contains(nameParts[0], ".") {
@@ -88,7 +99,9 @@ type OpenAPICanonicalTypeNamer interface {
 }
 
 // GetCanonicalTypeName will find the canonical type name of a sample object, removing
-// the "vendor" part of the path
+// the "vendor" part of the path. Instantiated generic types are named after the
+// generic type and the canonical names of their type arguments, e.g.
+//     k8s.io/api/core/v1.List[k8s.io/api/core/v1.Pod]
 func GetCanonicalTypeName(model interface{}) string {
 	if namer, ok := model.(OpenAPICanonicalTypeNamer); ok {
 		return namer.OpenAPICanonicalTypeName()
@@ -100,11 +113,64 @@ func GetCanonicalTypeName(model interface{}) string {
 	if t.PkgPath() == "" {
 		return t.Name()
 	}
-	path := t.PkgPath()
+	return stripVendor(t.PkgPath() + "." + t.Name())
+}
+
+// stripVendor removes the "vendor" part of the path of a type name and of
+// the names of its type arguments.
+func stripVendor(path string) string {
+	if base, args := SplitTypeArguments(path); args != nil {
+		for i, arg := range args {
+			prefix, arg := splitTypePrefix(arg)
+			args[i] = prefix + stripVendor(arg)
+		}
+		return stripVendor(base) + "[" + strings.Join(args, ",") + "]"
+	}
 	if strings.Contains(path, "/vendor/") {
-		path = path[strings.Index(path, "/vendor/")+len("/vendor/"):]
-	} else if strings.HasPrefix(path, "vendor/") {
-		path = strings.TrimPrefix(path, "vendor/")
+		return path[strings.Index(path, "/vendor/")+len("/vendor/"):]
+	}
+	return strings.TrimPrefix(path, "vendor/")
+}
+
+// SplitTypeArguments splits the name of an instantiated generic type into
+// the name of the generic type and its type arguments, e.g.
+// "k8s.io/api/core/v1.Pair[int,k8s.io/api/core/v1.Pod]" into
+// "k8s.io/api/core/v1.Pair" and ["int", "k8s.io/api/core/v1.Pod"]. The
+// arguments are nil for other names.
+func SplitTypeArguments(name string) (string, []string) {
+	start := strings.Index(name, "[")
+	if start <= 0 || !strings.HasSuffix(name, "]") {
+		return name, nil
+	}
+	var args []string
+	depth, last := 0, start+1
+	for i := start + 1; i < len(name)-1; i++ {
+		switch name[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, name[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return name[:start], append(args, name[last:len(name)-1])
+}
+
+// splitTypePrefix splits the pointer and slice markers off a type argument,
+// e.g. "[]*k8s.io/api/core/v1.Pod" into "[]*" and "k8s.io/api/core/v1.Pod".
+func splitTypePrefix(arg string) (string, string) {
+	i := 0
+	for {
+		if strings.HasPrefix(arg[i:], "*") {
+			i++
+		} else if strings.HasPrefix(arg[i:], "[]") {
+			i += 2
+		} else {
+			return arg[:i], arg[i:]
+		}
 	}
-	return path + "." + t.Name()
 }
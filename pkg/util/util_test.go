@@ -31,6 +31,8 @@ func TestCanonicalName(t *testing.T) {
 		{"k8s.io/api/networking/v1/NetworkPolicy", "io.k8s.api.networking.v1.NetworkPolicy"},
 		{"k8s.io/api/apps/v1beta2.Scale", "io.k8s.api.apps.v1beta2.Scale"},
 		{"servicecatalog.k8s.io/foo/bar/v1alpha1.Baz", "io.k8s.servicecatalog.foo.bar.v1alpha1.Baz"},
+		{"k8s.io/api/core/v1.List[k8s.io/api/core/v1.Pod]", "io.k8s.api.core.v1.List[io.k8s.api.core.v1.Pod]"},
+		{"k8s.io/api/core/v1.Pair[int,[]*example.com/pets.Pet]", "io.k8s.api.core.v1.Pair[int,[]*com.example.pets.Pet]"},
 	}
 	for _, test := range tests {
 		if got := ToRESTFriendlyName(test.input); got != test.expected {
@@ -56,3 +58,22 @@ func TestGetCanonicalTypeName(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitTypeArguments(t *testing.T) {
+	var tests = []struct {
+		input string
+		base  string
+		args  []string
+	}{
+		{"k8s.io/api/core/v1.Pod", "k8s.io/api/core/v1.Pod", nil},
+		{"[]int", "[]int", nil},
+		{"example.com/pets.List[int]", "example.com/pets.List", []string{"int"}},
+		{"example.com/pets.Pair[map[string]int,example.com/pets.List[[]string]]", "example.com/pets.Pair", []string{"map[string]int", "example.com/pets.List[[]string]"}},
+	}
+	for _, test := range tests {
+		base, args := SplitTypeArguments(test.input)
+		if base != test.base || !reflect.DeepEqual(args, test.args) {
+			t.Errorf("SplitTypeArguments(%q) = %q, %q", test.input, base, args)
+		}
+	}
+}
@@ -22,6 +22,16 @@ type Validation struct {
 	Value   interface{}
 	message string
 	Values  []interface{}
+	// Constraint is the value of the failed constraint, e.g. the maximum
+	// length or the pattern, if any.
+	Constraint interface{}
+	// Location is the path from the validated value to the failing one,
+	// as the property names and array indexes of the nested values. It is
+	// empty for errors of the validated value itself; missing properties
+	// are errors of their parent object. Unlike Name, it doesn't depend on
+	// the name the validator was created with and it is unambiguous when
+	// property names contain dots.
+	Location []string
 }
 
 func (e *Validation) Error() string {
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RedactedValue replaces the values of redacted errors.
+const RedactedValue = "[redacted]"
+
+// Redact returns err with the invalid value removed from its Value and from
+// its message, for values which must not be disclosed such as passwords.
+// Composite errors are redacted recursively, err itself is not modified.
+func Redact(err error) error {
+	switch e := err.(type) {
+	case *Validation:
+		if e.Value == nil {
+			return e
+		}
+		redacted := *e
+		redacted.Value = RedactedValue
+		switch v := e.Value.(type) {
+		case string:
+			if v != "" {
+				redacted.message = strings.Replace(e.message, fmt.Sprintf("%q", v), fmt.Sprintf("%q", RedactedValue), -1)
+			}
+		case error:
+			// Errors such as parsing errors may quote the value.
+			redacted.message = strings.Replace(e.message, v.Error(), RedactedValue, -1)
+		}
+		return &redacted
+	case *CompositeError:
+		redacted := *e
+		redacted.Errors = make([]error, len(e.Errors))
+		for i, child := range e.Errors {
+			redacted.Errors[i] = Redact(child)
+		}
+		return &redacted
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	err := Redact(InvalidType("password", "body", "integer", "hunter2"))
+	assert.Equal(t, `password in body must be of type integer: "[redacted]"`, err.Error())
+	assert.Equal(t, RedactedValue, err.(*Validation).Value)
+
+	err = Redact(InvalidType("pin", "header", "integer", fmt.Errorf(`parsing "hunter2": invalid syntax`)))
+	assert.Equal(t, "pin in header must be of type integer, because: [redacted]", err.Error())
+
+	original := TooShort("password", "body", 8, "hunter2")
+	composite := CompositeValidationError(original, Required("name", "body"))
+	redacted := Redact(composite).(*CompositeError)
+	assert.Equal(t, "password in body should be at least 8 chars long", redacted.Errors[0].Error())
+	assert.Equal(t, RedactedValue, redacted.Errors[0].(*Validation).Value)
+	assert.Equal(t, "hunter2", original.Value, "the original error is unchanged")
+	assert.Same(t, composite.Errors[1], redacted.Errors[1], "errors without a value are kept")
+
+	plain := fmt.Errorf("plain")
+	assert.Same(t, plain, Redact(plain))
+}
@@ -64,6 +64,18 @@ const (
 	failedAllPatternProps     = "%s.%s in %s failed all pattern properties"
 	failedAllPatternPropsNoIn = "%s.%s failed all pattern properties"
 	multipleOfMustBePositive  = "factor MultipleOf declared for %s must be positive: %v"
+	deprecatedProperty        = "%s.%s in %s is deprecated"
+	deprecatedPropertyNoIn    = "%s.%s is deprecated"
+	deprecatedParameter       = "%s in %s is deprecated"
+	willBeRemoved             = " and will be removed in %s"
+	invalidSpec               = "%s in %s is invalid: %s"
+	invalidSpecNoIn           = "%s is invalid: %s"
+	invalidContent            = "%s in %s should hold %s content: %s"
+	invalidContentNoIn        = "%s should hold %s content: %s"
+	invalidPropertyName       = "%s.%s in %s is an invalid property name: %s"
+	invalidPropertyNameNoIn   = "%s.%s is an invalid property name: %s"
+	immutable                 = "%s in %s is immutable"
+	immutableNoIn             = "%s is immutable"
 )
 
 // All code responses can be used to differentiate errors for different handling
@@ -91,6 +103,11 @@ const (
 	UnallowedPropertyCode
 	FailedAllPatternPropsCode
 	MultipleOfMustBePositiveCode
+	DeprecatedCode
+	InvalidSpecCode
+	InvalidContentCode
+	InvalidPropertyNameCode
+	ImmutableCode
 )
 
 // CompositeError is an error that groups several errors together
@@ -155,6 +172,105 @@ func PropertyNotAllowed(name, in, key string) *Validation {
 	}
 }
 
+// DeprecatedProperty a warning for when a deprecated property is set. The
+// removal version is optional.
+func DeprecatedProperty(name, in, key, removalVersion string) *Validation {
+	msg := fmt.Sprintf(deprecatedProperty, name, key, in)
+	if in == "" {
+		msg = fmt.Sprintf(deprecatedPropertyNoIn, name, key)
+	}
+	if removalVersion != "" {
+		msg += fmt.Sprintf(willBeRemoved, removalVersion)
+	}
+	return &Validation{
+		code:    DeprecatedCode,
+		Name:    name,
+		In:      in,
+		Value:   key,
+		message: msg,
+	}
+}
+
+// DeprecatedParameter a warning for when a deprecated parameter is set. The
+// removal version is optional.
+func DeprecatedParameter(name, in, removalVersion string) *Validation {
+	msg := fmt.Sprintf(deprecatedParameter, name, in)
+	if removalVersion != "" {
+		msg += fmt.Sprintf(willBeRemoved, removalVersion)
+	}
+	return &Validation{
+		code:    DeprecatedCode,
+		Name:    name,
+		In:      in,
+		message: msg,
+	}
+}
+
+// InvalidSpec an error for when a spec object itself is malformed, e.g. a
+// body parameter without schema. Name is the location of the object in the
+// spec and reason describes the problem.
+func InvalidSpec(name, in, reason string) *Validation {
+	msg := fmt.Sprintf(invalidSpec, name, in, reason)
+	if in == "" {
+		msg = fmt.Sprintf(invalidSpecNoIn, name, reason)
+	}
+	return &Validation{
+		code:    InvalidSpecCode,
+		Name:    name,
+		In:      in,
+		message: msg,
+	}
+}
+
+// InvalidContent an error for a string whose content can't be decoded with
+// its contentEncoding or parsed as its contentMediaType, e.g. "base64" or
+// "application/json". Reason describes the problem.
+func InvalidContent(name, in, content, reason string) *Validation {
+	msg := fmt.Sprintf(invalidContent, name, in, content, reason)
+	if in == "" {
+		msg = fmt.Sprintf(invalidContentNoIn, name, content, reason)
+	}
+	return &Validation{
+		code:       InvalidContentCode,
+		Name:       name,
+		In:         in,
+		Constraint: content,
+		message:    msg,
+	}
+}
+
+// InvalidPropertyName an error for an object key that doesn't validate
+// against the propertyNames or the key pattern of the object. Reason
+// describes the problem.
+func InvalidPropertyName(name, in, key, reason string) *Validation {
+	msg := fmt.Sprintf(invalidPropertyName, name, key, in, reason)
+	if in == "" {
+		msg = fmt.Sprintf(invalidPropertyNameNoIn, name, key, reason)
+	}
+	return &Validation{
+		code:    InvalidPropertyNameCode,
+		Name:    name,
+		In:      in,
+		Value:   key,
+		message: msg,
+	}
+}
+
+// Immutable an error for a value an update changed although its schema
+// doesn't allow it.
+func Immutable(name, in string) *Validation {
+	msg := fmt.Sprintf(immutable, name, in)
+	if in == "" {
+		msg = fmt.Sprintf(immutableNoIn, name)
+	}
+	return &Validation{
+		code:    ImmutableCode,
+		Name:    name,
+		In:      in,
+		message: msg,
+	}
+}
+
 // TooFewProperties an error for an object with too few properties
 func TooFewProperties(name, in string, n int64) *Validation {
 	msg := fmt.Sprintf(tooFewProperties, name, in, n)
@@ -162,11 +278,12 @@ func TooFewProperties(name, in string, n int64) *Validation {
 		msg = fmt.Sprintf(tooFewPropertiesNoIn, name, n)
 	}
 	return &Validation{
-		code:    TooFewPropertiesCode,
-		Name:    name,
-		In:      in,
-		Value:   n,
-		message: msg,
+		code:       TooFewPropertiesCode,
+		Name:       name,
+		In:         in,
+		Value:      n,
+		message:    msg,
+		Constraint: n,
 	}
 }
 
@@ -177,11 +294,12 @@ func TooManyProperties(name, in string, n int64) *Validation {
 		msg = fmt.Sprintf(tooManyPropertiesNoIn, name, n)
 	}
 	return &Validation{
-		code:    TooManyPropertiesCode,
-		Name:    name,
-		In:      in,
-		Value:   n,
-		message: msg,
+		code:       TooManyPropertiesCode,
+		Name:       name,
+		In:         in,
+		Value:      n,
+		message:    msg,
+		Constraint: n,
 	}
 }
 
@@ -202,11 +320,12 @@ func AdditionalItemsNotAllowed(name, in string) *Validation {
 // InvalidCollectionFormat another flavor of invalid type error
 func InvalidCollectionFormat(name, in, format string) *Validation {
 	return &Validation{
-		code:    InvalidTypeCode,
-		Name:    name,
-		In:      in,
-		Value:   format,
-		message: fmt.Sprintf("the collection format %q is not supported for the %s param %q", format, in, name),
+		code:       InvalidTypeCode,
+		Name:       name,
+		In:         in,
+		Value:      format,
+		message:    fmt.Sprintf("the collection format %q is not supported for the %s param %q", format, in, name),
+		Constraint: format,
 	}
 }
 
@@ -244,11 +363,12 @@ func InvalidType(name, in, typeName string, value interface{}) *Validation {
 	}
 
 	return &Validation{
-		code:    InvalidTypeCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: message,
+		code:       InvalidTypeCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    message,
+		Constraint: typeName,
 	}
 
 }
@@ -275,11 +395,12 @@ func TooManyItems(name, in string, max int64, value interface{}) *Validation {
 	}
 
 	return &Validation{
-		code:    MaxItemsFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: msg,
+		code:       MaxItemsFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    msg,
+		Constraint: max,
 	}
 }
 
@@ -290,11 +411,12 @@ func TooFewItems(name, in string, min int64, value interface{}) *Validation {
 		msg = fmt.Sprintf(minItemsFailNoIn, name, min)
 	}
 	return &Validation{
-		code:    MinItemsFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: msg,
+		code:       MinItemsFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    msg,
+		Constraint: min,
 	}
 }
 
@@ -315,11 +437,12 @@ func ExceedsMaximumInt(name, in string, max int64, exclusive bool, value interfa
 		message = fmt.Sprintf(m, name, in, max)
 	}
 	return &Validation{
-		code:    MaxFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: message,
+		code:       MaxFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    message,
+		Constraint: max,
 	}
 }
 
@@ -340,11 +463,12 @@ func ExceedsMaximumUint(name, in string, max uint64, exclusive bool, value inter
 		message = fmt.Sprintf(m, name, in, max)
 	}
 	return &Validation{
-		code:    MaxFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: message,
+		code:       MaxFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    message,
+		Constraint: max,
 	}
 }
 
@@ -365,11 +489,12 @@ func ExceedsMaximum(name, in string, max float64, exclusive bool, value interfac
 		message = fmt.Sprintf(m, name, in, max)
 	}
 	return &Validation{
-		code:    MaxFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: message,
+		code:       MaxFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    message,
+		Constraint: max,
 	}
 }
 
@@ -390,11 +515,12 @@ func ExceedsMinimumInt(name, in string, min int64, exclusive bool, value interfa
 		message = fmt.Sprintf(m, name, in, min)
 	}
 	return &Validation{
-		code:    MinFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: message,
+		code:       MinFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    message,
+		Constraint: min,
 	}
 }
 
@@ -415,11 +541,12 @@ func ExceedsMinimumUint(name, in string, min uint64, exclusive bool, value inter
 		message = fmt.Sprintf(m, name, in, min)
 	}
 	return &Validation{
-		code:    MinFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: message,
+		code:       MinFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    message,
+		Constraint: min,
 	}
 }
 
@@ -440,11 +567,12 @@ func ExceedsMinimum(name, in string, min float64, exclusive bool, value interfac
 		message = fmt.Sprintf(m, name, in, min)
 	}
 	return &Validation{
-		code:    MinFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: message,
+		code:       MinFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    message,
+		Constraint: min,
 	}
 }
 
@@ -457,11 +585,12 @@ func NotMultipleOf(name, in string, multiple, value interface{}) *Validation {
 		msg = fmt.Sprintf(multipleOfFail, name, in, multiple)
 	}
 	return &Validation{
-		code:    MultipleOfFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: msg,
+		code:       MultipleOfFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    msg,
+		Constraint: multiple,
 	}
 }
 
@@ -475,12 +604,13 @@ func EnumFail(name, in string, value interface{}, values []interface{}) *Validat
 	}
 
 	return &Validation{
-		code:    EnumFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		Values:  values,
-		message: msg,
+		code:       EnumFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		Values:     values,
+		message:    msg,
+		Constraint: values,
 	}
 }
 
@@ -509,11 +639,12 @@ func TooLong(name, in string, max int64, value interface{}) *Validation {
 		msg = fmt.Sprintf(tooLongMessage, name, in, max)
 	}
 	return &Validation{
-		code:    TooLongFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: msg,
+		code:       TooLongFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    msg,
+		Constraint: max,
 	}
 }
 
@@ -527,11 +658,12 @@ func TooShort(name, in string, min int64, value interface{}) *Validation {
 	}
 
 	return &Validation{
-		code:    TooShortFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: msg,
+		code:       TooShortFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    msg,
+		Constraint: min,
 	}
 }
 
@@ -546,11 +678,12 @@ func FailedPattern(name, in, pattern string, value interface{}) *Validation {
 	}
 
 	return &Validation{
-		code:    PatternFailCode,
-		Name:    name,
-		In:      in,
-		Value:   value,
-		message: msg,
+		code:       PatternFailCode,
+		Name:       name,
+		In:         in,
+		Value:      value,
+		message:    msg,
+		Constraint: pattern,
 	}
 }
 
@@ -558,10 +691,11 @@ func FailedPattern(name, in, pattern string, value interface{}) *Validation {
 // multipleOf factor is negative
 func MultipleOfMustBePositive(name, in string, factor interface{}) *Validation {
 	return &Validation{
-		code:    MultipleOfMustBePositiveCode,
-		Name:    name,
-		In:      in,
-		Value:   factor,
-		message: fmt.Sprintf(multipleOfMustBePositive, name, factor),
+		code:       MultipleOfMustBePositiveCode,
+		Name:       name,
+		In:         in,
+		Value:      factor,
+		message:    fmt.Sprintf(multipleOfMustBePositive, name, factor),
+		Constraint: factor,
 	}
 }
@@ -402,4 +402,45 @@ func TestSchemaErrors(t *testing.T) {
 	assert.EqualValues(t, FailedAllPatternPropsCode, err.Code())
 	//failedAllPatternPropsNoIn = "%s.%s failed all pattern properties"
 	assert.Equal(t, "path.key failed all pattern properties", err.Error())
+
+	err = DeprecatedProperty("path", "body", "key", "")
+	assert.Error(t, err)
+	assert.EqualValues(t, DeprecatedCode, err.Code())
+	assert.Equal(t, "path.key in body is deprecated", err.Error())
+
+	err = DeprecatedProperty("path", "", "key", "v2")
+	assert.EqualValues(t, DeprecatedCode, err.Code())
+	assert.Equal(t, "path.key is deprecated and will be removed in v2", err.Error())
+
+	err = DeprecatedParameter("limit", "query", "v2")
+	assert.EqualValues(t, DeprecatedCode, err.Code())
+	assert.Equal(t, "limit in query is deprecated and will be removed in v2", err.Error())
+
+	err = InvalidSpec("parameters[0]", "path", "path parameters must be required")
+	assert.EqualValues(t, InvalidSpecCode, err.Code())
+	assert.Equal(t, "parameters[0] in path is invalid: path parameters must be required", err.Error())
+
+	err = InvalidSpec("swagger", "", "must be 2.0")
+	assert.Equal(t, "swagger is invalid: must be 2.0", err.Error())
+
+	err = InvalidContent("data", "body", "base64", "illegal base64 data at input byte 4")
+	assert.EqualValues(t, InvalidContentCode, err.Code())
+	assert.Equal(t, "data in body should hold base64 content: illegal base64 data at input byte 4", err.Error())
+
+	err = InvalidContent("data", "", "application/json", "unexpected end of JSON input")
+	assert.Equal(t, "data should hold application/json content: unexpected end of JSON input", err.Error())
+
+	err = InvalidPropertyName("labels", "body", "Foo", "Foo should match '^[a-z]+$'")
+	assert.EqualValues(t, InvalidPropertyNameCode, err.Code())
+	assert.Equal(t, "labels.Foo in body is an invalid property name: Foo should match '^[a-z]+$'", err.Error())
+
+	err = InvalidPropertyName("labels", "", "Foo", "too long")
+	assert.Equal(t, "labels.Foo is an invalid property name: too long", err.Error())
+
+	err = Immutable("spec.selector", "body")
+	assert.EqualValues(t, ImmutableCode, err.Code())
+	assert.Equal(t, "spec.selector in body is immutable", err.Error())
+
+	err = Immutable("spec.selector", "")
+	assert.Equal(t, "spec.selector is immutable", err.Error())
 }
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MessageTemplates overrides the messages of validation errors by error
+// code, e.g. to translate them or to match an established error style.
+//
+// Templates refer to the error with named placeholders:
+//   {name}       the name of the invalid value
+//   {in}         where the value is, e.g. body or query
+//   {value}      the invalid value
+//   {constraint} the failed constraint, e.g. the maximum length
+//   {values}     the allowed values of an enum
+// Anything else is kept as is. Placeholders are substituted in a single
+// pass and the template is never interpreted as a format string, so values
+// containing % or braces are rendered verbatim.
+type MessageTemplates map[int32]string
+
+// Render returns the message of v according to its template, and false if
+// there is no template for its code.
+func (t MessageTemplates) Render(v *Validation) (string, bool) {
+	tpl, ok := t[v.code]
+	if !ok {
+		return "", false
+	}
+	values := ""
+	if v.Values != nil {
+		values = fmt.Sprintf("%v", v.Values)
+	}
+	r := strings.NewReplacer(
+		"{name}", v.Name,
+		"{in}", v.In,
+		"{value}", placeholderValue(v.Value),
+		"{constraint}", placeholderValue(v.Constraint),
+		"{values}", values,
+	)
+	return r.Replace(tpl), true
+}
+
+func placeholderValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// Apply returns err with its message rendered from the templates. Composite
+// errors are rendered recursively. Errors without a matching template are
+// returned as they are, err itself is not modified.
+func (t MessageTemplates) Apply(err error) error {
+	switch e := err.(type) {
+	case *Validation:
+		msg, ok := t.Render(e)
+		if !ok {
+			return e
+		}
+		rendered := *e
+		rendered.message = msg
+		return &rendered
+	case *CompositeError:
+		rendered := *e
+		rendered.Errors = make([]error, len(e.Errors))
+		for i, child := range e.Errors {
+			rendered.Errors[i] = t.Apply(child)
+		}
+		return &rendered
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageTemplates(t *testing.T) {
+	templates := MessageTemplates{
+		TooLongFailCode: "Le champ {name} ({in}) ne doit pas dépasser {constraint} caractères",
+		EnumFailCode:    "{name}: {value} n'est pas dans {values}",
+		PatternFailCode: "{name} must match {constraint} (100% {unknown})",
+	}
+
+	err := templates.Apply(TooLong("nom", "body", 10, "un nom trop long"))
+	assert.Equal(t, "Le champ nom (body) ne doit pas dépasser 10 caractères", err.Error())
+	assert.EqualValues(t, TooLongFailCode, err.(*Validation).Code())
+
+	err = templates.Apply(EnumFail("kind", "query", "fish", []interface{}{"cat", "dog"}))
+	assert.Equal(t, "kind: fish n'est pas dans [cat dog]", err.Error())
+
+	// Values are never interpreted as format strings or placeholders.
+	err = templates.Apply(FailedPattern("id", "path", "^%d{value}$", "x"))
+	assert.Equal(t, "id must match ^%d{value}$ (100% {unknown})", err.Error())
+
+	original := Required("name", "body")
+	assert.Same(t, original, templates.Apply(original))
+
+	composite := CompositeValidationError(TooShort("a", "", 3, "ab"), TooLong("b", "", 1, "bc"))
+	rendered := templates.Apply(composite).(*CompositeError)
+	assert.Equal(t, "a should be at least 3 chars long", rendered.Errors[0].Error())
+	assert.Equal(t, "Le champ b () ne doit pas dépasser 1 caractères", rendered.Errors[1].Error())
+	assert.Equal(t, "b should be at most 1 chars long", composite.Errors[1].Error(), "the original error is unchanged")
+
+	_, ok := templates.Render(Required("name", "body"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Names of the rules checking global invariants, along with
+// DuplicateOperationID.
+const (
+	AmbiguousPath      = "ambiguous-path"
+	DuplicateParameter = "duplicate-parameter"
+)
+
+// InvariantRules returns the built-in rules checking invariants that span
+// the whole spec rather than a single object: unique operation IDs, path
+// templates that can't match the same requests and parameters declared
+// once per operation. They are part of DefaultRules.
+func InvariantRules() []Rule {
+	var ret []Rule
+	for _, r := range DefaultRules() {
+		switch r.Name() {
+		case DuplicateOperationID, AmbiguousPath, DuplicateParameter:
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+func checkDuplicateOperationIDs(s *spec.Swagger, report Reporter) {
+	seen := map[string]string{}
+	for _, o := range operations(s) {
+		id := o.op.ID
+		if id == "" {
+			continue
+		}
+		if first, ok := seen[id]; ok {
+			report(o.pointer+"/operationId", "operationId %q is already used by %s", id, first)
+			continue
+		}
+		seen[id] = o.pointer
+	}
+}
+
+// checkAmbiguousPaths reports the paths matching the same requests as an
+// earlier path, e.g. /pets/{name} after /pets/{id}.
+func checkAmbiguousPaths(s *spec.Swagger, report Reporter) {
+	if s.Paths == nil {
+		return
+	}
+	paths := make([]string, 0, len(s.Paths.Paths))
+	for p := range s.Paths.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	seen := map[string]string{}
+	for _, p := range paths {
+		shape := pathTemplateRegexp.ReplaceAllString(p, "{}")
+		if first, ok := seen[shape]; ok {
+			report("/paths/"+jsonpointer.Escape(p), "path %q is ambiguous with %q", p, first)
+			continue
+		}
+		seen[shape] = p
+	}
+}
+
+// checkDuplicateParameters reports the parameters declared twice with the
+// same name and location in the parameters of a path item or of an
+// operation. Referenced parameters are resolved against the spec
+// parameters; the ones which can't be resolved are ignored.
+func checkDuplicateParameters(s *spec.Swagger, report Reporter) {
+	check := func(params []spec.Parameter, pointer string) {
+		seen := map[string]string{}
+		for i, p := range params {
+			if ref := p.Ref.String(); ref != "" {
+				resolved, ok := s.Parameters[strings.TrimPrefix(ref, "#/parameters/")]
+				if !ok || !strings.HasPrefix(ref, "#/parameters/") {
+					continue
+				}
+				p = resolved
+			}
+			pp := fmt.Sprintf("%s/parameters/%d", pointer, i)
+			key := p.In + "/" + p.Name
+			if first, ok := seen[key]; ok {
+				report(pp, "parameter %q in %s is already declared at %s", p.Name, p.In, first)
+				continue
+			}
+			seen[key] = pp
+		}
+	}
+	seenItems := map[string]bool{}
+	for _, o := range operations(s) {
+		itemPointer := "/paths/" + jsonpointer.Escape(o.path)
+		if !seenItems[itemPointer] {
+			seenItems[itemPointer] = true
+			check(o.item.Parameters, itemPointer)
+		}
+		check(o.op.Parameters, o.pointer)
+	}
+}
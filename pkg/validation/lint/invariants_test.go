@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const invariantsSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "t", "version": "v"},
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer"}
+  },
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [
+        {"name": "id", "in": "path", "required": true, "type": "string"},
+        {"name": "id", "in": "path", "required": true, "type": "integer"}
+      ],
+      "get": {
+        "operationId": "getPet",
+        "parameters": [
+          {"$ref": "#/parameters/limit"},
+          {"name": "id", "in": "query", "type": "string"},
+          {"name": "limit", "in": "query", "type": "integer"},
+          {"$ref": "#/parameters/missing"}
+        ],
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/pets/{name}": {
+      "parameters": [{"name": "name", "in": "path", "required": true, "type": "string"}],
+      "get": {"operationId": "getPet", "responses": {"200": {"description": "ok"}}}
+    },
+    "/pets/{id}.json": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "type": "string"}],
+      "get": {"operationId": "getPetJSON", "responses": {"200": {"description": "ok"}}}
+    }
+  }
+}`
+
+func TestInvariantRules(t *testing.T) {
+	s := &spec.Swagger{}
+	require.NoError(t, json.Unmarshal([]byte(invariantsSpec), s))
+
+	var names []string
+	for _, r := range InvariantRules() {
+		names = append(names, r.Name())
+	}
+	assert.Equal(t, []string{DuplicateOperationID, AmbiguousPath, DuplicateParameter}, names)
+
+	assert.Equal(t, []Finding{
+		{Rule: DuplicateParameter, Severity: Error, Path: "/paths/~1pets~1{id}/get/parameters/2", Message: `parameter "limit" in query is already declared at /paths/~1pets~1{id}/get/parameters/0`},
+		{Rule: DuplicateParameter, Severity: Error, Path: "/paths/~1pets~1{id}/parameters/1", Message: `parameter "id" in path is already declared at /paths/~1pets~1{id}/parameters/0`},
+		{Rule: AmbiguousPath, Severity: Error, Path: "/paths/~1pets~1{name}", Message: `path "/pets/{name}" is ambiguous with "/pets/{id}"`},
+		{Rule: DuplicateOperationID, Severity: Error, Path: "/paths/~1pets~1{name}/get/operationId", Message: `operationId "getPet" is already used by /paths/~1pets~1{id}/get`},
+	}, NewSpecLinter(InvariantRules()...).Lint(s))
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lint checks OpenAPI specs against a configurable set of rules
+// covering correctness and style, beyond what is needed for a spec to be
+// structurally valid. Each rule reports findings with a severity and the
+// JSON pointer of the offending object.
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Severity is how serious a finding is.
+type Severity int
+
+const (
+	// Info findings are suggestions.
+	Info Severity = iota
+	// Warning findings are likely problems.
+	Warning
+	// Error findings are problems that break clients or tools.
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Severity) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "info":
+		*s = Info
+	case "warning":
+		*s = Warning
+	case "error":
+		*s = Error
+	default:
+		return fmt.Errorf("unknown severity %q", text)
+	}
+	return nil
+}
+
+// Finding is a problem reported by a rule.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// Path is the JSON pointer of the offending object in the spec.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", f.Severity, f.Path, f.Message, f.Rule)
+}
+
+// Reporter records the findings of a rule. path is a JSON pointer.
+type Reporter func(path, format string, args ...interface{})
+
+// Rule is a check run by the linter.
+type Rule interface {
+	// Name identifies the rule in findings and configuration.
+	Name() string
+	// Description explains what the rule checks.
+	Description() string
+	// DefaultSeverity is the severity of the findings of the rule unless
+	// configured otherwise.
+	DefaultSeverity() Severity
+	// Check reports the problems found in the spec.
+	Check(s *spec.Swagger, report Reporter)
+}
+
+// SpecLinter runs a set of rules on specs. Rules can be disabled and their
+// severity overridden individually.
+type SpecLinter struct {
+	rules    []Rule
+	disabled map[string]bool
+	severity map[string]Severity
+}
+
+// NewSpecLinter returns a linter running the given rules, or DefaultRules if
+// none is given.
+func NewSpecLinter(rules ...Rule) *SpecLinter {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+	return &SpecLinter{
+		rules:    rules,
+		disabled: map[string]bool{},
+		severity: map[string]Severity{},
+	}
+}
+
+// Rules returns the rules of the linter, including the disabled ones.
+func (l *SpecLinter) Rules() []Rule {
+	return l.rules
+}
+
+// AddRule adds a rule to the linter.
+func (l *SpecLinter) AddRule(r Rule) *SpecLinter {
+	l.rules = append(l.rules, r)
+	return l
+}
+
+// Disable disables the rules with the given names.
+func (l *SpecLinter) Disable(names ...string) *SpecLinter {
+	for _, n := range names {
+		l.disabled[n] = true
+	}
+	return l
+}
+
+// Enable enables back the rules with the given names.
+func (l *SpecLinter) Enable(names ...string) *SpecLinter {
+	for _, n := range names {
+		delete(l.disabled, n)
+	}
+	return l
+}
+
+// Enabled returns whether the named rule is enabled.
+func (l *SpecLinter) Enabled(name string) bool {
+	return !l.disabled[name]
+}
+
+// SetSeverity overrides the severity of the findings of the named rule.
+func (l *SpecLinter) SetSeverity(name string, s Severity) *SpecLinter {
+	l.severity[name] = s
+	return l
+}
+
+// Lint runs the enabled rules on the spec and returns their findings sorted
+// by path, then rule.
+func (l *SpecLinter) Lint(s *spec.Swagger) []Finding {
+	var findings []Finding
+	for _, r := range l.rules {
+		name := r.Name()
+		if l.disabled[name] {
+			continue
+		}
+		severity, ok := l.severity[name]
+		if !ok {
+			severity = r.DefaultSeverity()
+		}
+		r.Check(s, func(path, format string, args ...interface{}) {
+			findings = append(findings, Finding{
+				Rule:     name,
+				Severity: severity,
+				Path:     path,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		})
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Path != findings[j].Path {
+			return findings[i].Path < findings[j].Path
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+	return findings
+}
+
+// MaxSeverity returns the highest severity among the findings, and false if
+// there is none.
+func MaxSeverity(findings []Finding) (Severity, bool) {
+	if len(findings) == 0 {
+		return Info, false
+	}
+	max := findings[0].Severity
+	for _, f := range findings[1:] {
+		if f.Severity > max {
+			max = f.Severity
+		}
+	}
+	return max, true
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const testSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "t", "version": "v"},
+  "parameters": {
+    "id": {"name": "id", "in": "path", "required": true, "type": "string"},
+    "order": {"name": "order", "in": "query", "type": "string", "enum": ["asc", "desc"], "default": "up"}
+  },
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"$ref": "#/parameters/id"}],
+      "get": {
+        "operationId": "getPet",
+        "summary": "Get a pet",
+        "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Pet"}}, "404": {"description": "not found"}}
+      },
+      "delete": {
+        "operationId": "getPet",
+        "parameters": [{"name": "other", "in": "path", "required": true, "type": "string"}],
+        "responses": {"204": {"description": "deleted"}}
+      }
+    },
+    "/owners/{name}": {
+      "get": {
+        "description": "List owners",
+        "parameters": [{"name": "limit", "in": "query", "type": "integer", "enum": [10, 20], "default": 10}],
+        "responses": {"default": {"description": "ok"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {"type": "object", "properties": {"kind": {"$ref": "#/definitions/Kind"}}},
+    "Kind": {"type": "string", "enum": ["cat", "dog"], "default": "cat"},
+    "Unused": {"type": "object", "properties": {"size": {"type": "string", "enum": ["s", "m"], "default": "xl"}}}
+  }
+}`
+
+func loadTestSpec(t *testing.T) *spec.Swagger {
+	s := &spec.Swagger{}
+	require.NoError(t, json.Unmarshal([]byte(testSpec), s))
+	return s
+}
+
+func TestLint(t *testing.T) {
+	findings := NewSpecLinter().Lint(loadTestSpec(t))
+	assert.Equal(t, []Finding{
+		{Rule: UnusedDefinition, Severity: Warning, Path: "/definitions/Unused", Message: `definition "Unused" is not referenced`},
+		{Rule: EnumDefaultMismatch, Severity: Error, Path: "/definitions/Unused/properties/size/default", Message: `default "xl" is not one of the enum values`},
+		{Rule: EnumDefaultMismatch, Severity: Error, Path: "/parameters/order/default", Message: `default "up" is not one of the enum values`},
+		{Rule: UndeclaredPathParameter, Severity: Error, Path: "/paths/~1owners~1{name}/get", Message: `path parameter "name" is not declared`},
+		{Rule: OperationDescription, Severity: Warning, Path: "/paths/~1pets~1{id}/delete", Message: "operation has neither a summary nor a description"},
+		{Rule: UndeclaredPathParameter, Severity: Error, Path: "/paths/~1pets~1{id}/delete", Message: `path parameter "other" is not in the path template`},
+		{Rule: DuplicateOperationID, Severity: Error, Path: "/paths/~1pets~1{id}/delete/operationId", Message: `operationId "getPet" is already used by /paths/~1pets~1{id}/get`},
+		{Rule: Missing4xxResponse, Severity: Warning, Path: "/paths/~1pets~1{id}/delete/responses", Message: "operation documents no 4xx or default response"},
+	}, findings)
+
+	max, ok := MaxSeverity(findings)
+	assert.True(t, ok)
+	assert.Equal(t, Error, max)
+}
+
+func TestLintConfiguration(t *testing.T) {
+	l := NewSpecLinter().
+		Disable(UnusedDefinition, EnumDefaultMismatch, UndeclaredPathParameter, DuplicateOperationID).
+		SetSeverity(OperationDescription, Info)
+	assert.False(t, l.Enabled(UnusedDefinition))
+	findings := l.Lint(loadTestSpec(t))
+	require.Len(t, findings, 2)
+	assert.Equal(t, OperationDescription, findings[0].Rule)
+	assert.Equal(t, Info, findings[0].Severity)
+	assert.Equal(t, Missing4xxResponse, findings[1].Rule)
+
+	l.Enable(UnusedDefinition)
+	assert.Len(t, l.Lint(loadTestSpec(t)), 3)
+
+	custom := NewSpecLinter(NewRule("has-host", "specs must declare a host", Error, func(s *spec.Swagger, report Reporter) {
+		if s.Host == "" {
+			report("/host", "host is not set")
+		}
+	}))
+	assert.Equal(t, []Finding{{Rule: "has-host", Severity: Error, Path: "/host", Message: "host is not set"}}, custom.Lint(loadTestSpec(t)))
+	_, ok := MaxSeverity(nil)
+	assert.False(t, ok)
+}
+
+func TestSeverityText(t *testing.T) {
+	data, err := json.Marshal(Finding{Rule: "r", Severity: Warning, Path: "/p", Message: "m"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"rule": "r", "severity": "warning", "path": "/p", "message": "m"}`, string(data))
+
+	var f Finding
+	require.NoError(t, json.Unmarshal(data, &f))
+	assert.Equal(t, Warning, f.Severity)
+	assert.Error(t, json.Unmarshal([]byte(`{"severity": "fatal"}`), &f))
+	assert.Equal(t, "warning: /p: m [r]", f.String())
+}
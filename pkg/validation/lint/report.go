@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"encoding/json"
+	"io"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// ValidationRule is the rule name of the findings converted from validation
+// results by FromResult.
+const ValidationRule = "validation"
+
+// ReportVersion is the version of the JSON report format written by
+// WriteJSON. It is bumped on incompatible changes.
+const ReportVersion = 1
+
+// FromResult converts the errors and warnings of a validation result, e.g.
+// of validate.Spec, to findings of the ValidationRule with the Error and
+// Warning severity. The path of the findings is the name of the invalid
+// location as reported by the validator, which is not a JSON pointer.
+func FromResult(res *validate.Result) []Finding {
+	var findings []Finding
+	add := func(severity Severity, errs []error) {
+		for _, err := range errs {
+			f := Finding{Rule: ValidationRule, Severity: severity, Message: err.Error()}
+			if v, ok := err.(*errors.Validation); ok {
+				f.Path = v.Name
+			}
+			findings = append(findings, f)
+		}
+	}
+	add(Error, res.Errors)
+	add(Warning, res.Warnings)
+	return findings
+}
+
+// Report is the JSON report format written by WriteJSON:
+//
+//	{
+//	  "version": 1,
+//	  "source": "api/swagger.json",
+//	  "summary": {"error": 1, "warning": 0, "info": 2},
+//	  "findings": [
+//	    {"rule": "duplicate-operation-id", "severity": "error",
+//	     "path": "/paths/~1pets/get/operationId", "message": "..."}
+//	  ]
+//	}
+//
+// Findings keep the order they are given in, and findings is always present,
+// possibly empty.
+type Report struct {
+	// Version is ReportVersion.
+	Version int `json:"version"`
+	// Source is the file the findings are about.
+	Source string `json:"source,omitempty"`
+	// Summary counts the findings of each severity.
+	Summary  map[Severity]int `json:"summary"`
+	Findings []Finding        `json:"findings"`
+}
+
+// NewReport returns the report of the given findings on source.
+func NewReport(source string, findings []Finding) *Report {
+	r := &Report{
+		Version:  ReportVersion,
+		Source:   source,
+		Summary:  map[Severity]int{Error: 0, Warning: 0, Info: 0},
+		Findings: findings,
+	}
+	if r.Findings == nil {
+		r.Findings = []Finding{}
+	}
+	for _, f := range findings {
+		r.Summary[f.Severity]++
+	}
+	return r
+}
+
+// WriteJSON writes the findings on source as an indented JSON Report.
+func WriteJSON(w io.Writer, source string, findings []Finding) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(NewReport(source, findings))
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+func TestFromResult(t *testing.T) {
+	res := new(validate.Result)
+	res.AddErrors(errors.InvalidSpec("paths./pets.get.parameters[0]", "path", "path parameters must be required"))
+	res.AddWarnings(fmt.Errorf("something odd"))
+	assert.Equal(t, []Finding{
+		{Rule: ValidationRule, Severity: Error, Path: "paths./pets.get.parameters[0]", Message: "paths./pets.get.parameters[0] in path is invalid: path parameters must be required"},
+		{Rule: ValidationRule, Severity: Warning, Message: "something odd"},
+	}, FromResult(res))
+	assert.Empty(t, FromResult(new(validate.Result)))
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, "swagger.json", []Finding{
+		{Rule: "r1", Severity: Error, Path: "/a", Message: "m1"},
+		{Rule: "r2", Severity: Info, Path: "/b", Message: "m2"},
+		{Rule: "r2", Severity: Info, Path: "/c", Message: "m3"},
+	}))
+	assert.JSONEq(t, `{
+	  "version": 1,
+	  "source": "swagger.json",
+	  "summary": {"error": 1, "warning": 0, "info": 2},
+	  "findings": [
+	    {"rule": "r1", "severity": "error", "path": "/a", "message": "m1"},
+	    {"rule": "r2", "severity": "info", "path": "/b", "message": "m2"},
+	    {"rule": "r2", "severity": "info", "path": "/c", "message": "m3"}
+	  ]
+	}`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteJSON(&buf, "", nil))
+	assert.JSONEq(t, `{"version": 1, "summary": {"error": 0, "warning": 0, "info": 0}, "findings": []}`, buf.String())
+
+	var r Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &r))
+	assert.Equal(t, ReportVersion, r.Version)
+}
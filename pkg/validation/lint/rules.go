@@ -0,0 +1,290 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Names of the built-in rules.
+const (
+	UndeclaredPathParameter = "undeclared-path-parameter"
+	DuplicateOperationID    = "duplicate-operation-id"
+	OperationDescription    = "operation-description"
+	Missing4xxResponse      = "missing-4xx-response"
+	UnusedDefinition        = "unused-definition"
+	EnumDefaultMismatch     = "enum-default-mismatch"
+)
+
+type rule struct {
+	name        string
+	description string
+	severity    Severity
+	check       func(s *spec.Swagger, report Reporter)
+}
+
+func (r *rule) Name() string                           { return r.name }
+func (r *rule) Description() string                    { return r.description }
+func (r *rule) DefaultSeverity() Severity              { return r.severity }
+func (r *rule) Check(s *spec.Swagger, report Reporter) { r.check(s, report) }
+
+// NewRule returns a rule running the given check function.
+func NewRule(name, description string, severity Severity, check func(s *spec.Swagger, report Reporter)) Rule {
+	return &rule{name: name, description: description, severity: severity, check: check}
+}
+
+// DefaultRules returns the built-in rules.
+func DefaultRules() []Rule {
+	return []Rule{
+		NewRule(UndeclaredPathParameter, "path template parameters must be declared as path parameters, and the other way around", Error, checkPathParameters),
+		NewRule(DuplicateOperationID, "operation IDs must be unique", Error, checkDuplicateOperationIDs),
+		NewRule(OperationDescription, "operations should have a summary or a description", Warning, checkOperationDescriptions),
+		NewRule(Missing4xxResponse, "operations should document at least one 4xx or default response", Warning, checkErrorResponses),
+		NewRule(UnusedDefinition, "definitions should be referenced", Warning, checkUnusedDefinitions),
+		NewRule(EnumDefaultMismatch, "defaults must be one of the enum values", Error, checkEnumDefaults),
+		NewRule(AmbiguousPath, "path templates must not differ only by the names of their parameters", Error, checkAmbiguousPaths),
+		NewRule(DuplicateParameter, "parameters must be unique by name and location within a path item or an operation", Error, checkDuplicateParameters),
+	}
+}
+
+type operation struct {
+	pointer string
+	path    string
+	item    *spec.PathItem
+	op      *spec.Operation
+}
+
+// operations returns the operations of the spec sorted by path and method.
+func operations(s *spec.Swagger) []operation {
+	if s.Paths == nil {
+		return nil
+	}
+	paths := make([]string, 0, len(s.Paths.Paths))
+	for p := range s.Paths.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	var ret []operation
+	for _, p := range paths {
+		item := s.Paths.Paths[p]
+		for _, m := range item.Operations() {
+			ret = append(ret, operation{
+				pointer: "/paths/" + jsonpointer.Escape(p) + "/" + strings.ToLower(m.Method),
+				path:    p,
+				item:    &item,
+				op:      m.Operation,
+			})
+		}
+	}
+	return ret
+}
+
+var pathTemplateRegexp = regexp.MustCompile(`{([^{}]+)}`)
+
+func checkPathParameters(s *spec.Swagger, report Reporter) {
+	for _, o := range operations(s) {
+		declared := map[string]bool{}
+		resolvable := true
+		for _, params := range [][]spec.Parameter{o.item.Parameters, o.op.Parameters} {
+			for _, p := range params {
+				if ref := p.Ref.String(); ref != "" {
+					resolved, ok := s.Parameters[strings.TrimPrefix(ref, "#/parameters/")]
+					if !ok || !strings.HasPrefix(ref, "#/parameters/") {
+						resolvable = false
+						continue
+					}
+					p = resolved
+				}
+				if p.In == "path" {
+					declared[p.Name] = true
+				}
+			}
+		}
+		inTemplate := map[string]bool{}
+		for _, m := range pathTemplateRegexp.FindAllStringSubmatch(o.path, -1) {
+			inTemplate[m[1]] = true
+			if !declared[m[1]] && resolvable {
+				report(o.pointer, "path parameter %q is not declared", m[1])
+			}
+		}
+		names := make([]string, 0, len(declared))
+		for n := range declared {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			if !inTemplate[n] {
+				report(o.pointer, "path parameter %q is not in the path template", n)
+			}
+		}
+	}
+}
+
+func checkOperationDescriptions(s *spec.Swagger, report Reporter) {
+	for _, o := range operations(s) {
+		if strings.TrimSpace(o.op.Summary) == "" && strings.TrimSpace(o.op.Description) == "" {
+			report(o.pointer, "operation has neither a summary nor a description")
+		}
+	}
+}
+
+func checkErrorResponses(s *spec.Swagger, report Reporter) {
+	for _, o := range operations(s) {
+		if o.op.Responses == nil {
+			report(o.pointer, "operation has no responses")
+			continue
+		}
+		if o.op.Responses.Default != nil {
+			continue
+		}
+		found := false
+		for code := range o.op.Responses.StatusCodeResponses {
+			if code >= 400 && code < 500 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report(o.pointer+"/responses", "operation documents no 4xx or default response")
+		}
+	}
+}
+
+const definitionsPrefix = "#/definitions/"
+
+func checkUnusedDefinitions(s *spec.Swagger, report Reporter) {
+	if len(s.Definitions) == 0 {
+		return
+	}
+	used := map[string]bool{}
+	var queue []string
+	visit := func(ref *spec.Ref) {
+		r := ref.String()
+		if !strings.HasPrefix(r, definitionsPrefix) {
+			return
+		}
+		name := strings.SplitN(strings.TrimPrefix(r, definitionsPrefix), "/", 2)[0]
+		if name, err := unescape(name); err == nil && !used[name] {
+			used[name] = true
+			queue = append(queue, name)
+		}
+	}
+	s.WalkRefs(func(ref *spec.Ref, pointer string) {
+		if !strings.HasPrefix(pointer, "/definitions/") {
+			visit(ref)
+		}
+	})
+	for len(queue) > 0 {
+		def, ok := s.Definitions[queue[0]]
+		queue = queue[1:]
+		if ok {
+			spec.WalkSchema(&def, "", func(sch *spec.Schema, _ string) {
+				visit(&sch.Ref)
+			})
+		}
+	}
+
+	names := make([]string, 0, len(s.Definitions))
+	for name := range s.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !used[name] {
+			report("/definitions/"+jsonpointer.Escape(name), "definition %q is not referenced", name)
+		}
+	}
+}
+
+func unescape(token string) (string, error) {
+	p, err := jsonpointer.New("/" + token)
+	if err != nil {
+		return "", err
+	}
+	return p.DecodedTokens()[0], nil
+}
+
+func checkEnumDefaults(s *spec.Swagger, report Reporter) {
+	names := make([]string, 0, len(s.Definitions))
+	for name := range s.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := s.Definitions[name]
+		spec.WalkSchema(&def, "/definitions/"+jsonpointer.Escape(name), func(sch *spec.Schema, pointer string) {
+			checkEnumDefault(sch.Default, sch.Enum, pointer, report)
+		})
+	}
+
+	checkParams := func(params []spec.Parameter, pointer string) {
+		for i := range params {
+			p := &params[i]
+			pp := fmt.Sprintf("%s/parameters/%d", pointer, i)
+			checkEnumDefault(p.Default, p.Enum, pp, report)
+			if p.Schema != nil {
+				spec.WalkSchema(p.Schema, pp+"/schema", func(sch *spec.Schema, pointer string) {
+					checkEnumDefault(sch.Default, sch.Enum, pointer, report)
+				})
+			}
+		}
+	}
+	seenItems := map[string]bool{}
+	for _, o := range operations(s) {
+		itemPointer := "/paths/" + jsonpointer.Escape(o.path)
+		if !seenItems[itemPointer] {
+			seenItems[itemPointer] = true
+			checkParams(o.item.Parameters, itemPointer)
+		}
+		checkParams(o.op.Parameters, o.pointer)
+	}
+	paramNames := make([]string, 0, len(s.Parameters))
+	for name := range s.Parameters {
+		paramNames = append(paramNames, name)
+	}
+	sort.Strings(paramNames)
+	for _, name := range paramNames {
+		p := s.Parameters[name]
+		checkEnumDefault(p.Default, p.Enum, "/parameters/"+jsonpointer.Escape(name), report)
+	}
+}
+
+func checkEnumDefault(def interface{}, enum []interface{}, pointer string, report Reporter) {
+	if def == nil || len(enum) == 0 {
+		return
+	}
+	d, err := json.Marshal(def)
+	if err != nil {
+		return
+	}
+	for _, e := range enum {
+		// Comparing the serialized values avoids false positives between
+		// numbers of different Go types.
+		if v, err := json.Marshal(e); err == nil && string(v) == string(d) {
+			return
+		}
+	}
+	report(pointer+"/default", "default %s is not one of the enum values", d)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// The subset of SARIF 2.1.0 needed to report findings, see
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifToolName is the driver name reported in SARIF logs.
+	sarifToolName = "kube-openapi-lint"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID                   string              `json:"id"`
+	ShortDescription     *sarifMessage       `json:"shortDescription,omitempty"`
+	DefaultConfiguration *sarifConfiguration `json:"defaultConfiguration,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// sarifLevel maps severities to SARIF result levels.
+func sarifLevel(s Severity) string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	}
+	return "note"
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log, which code review
+// tools of GitHub and GitLab show as annotations. uri is the location of the
+// spec relative to the repository root. rules describe the rules in the log,
+// usually the Rules of the linter; rules of the findings not among them, like
+// ValidationRule, are described by name only. Findings point to the spec
+// file, their path is reported as a logical location because the spec
+// objects have no line information.
+func WriteSARIF(w io.Writer, uri string, rules []Rule, findings []Finding) error {
+	driver := sarifDriver{Name: sarifToolName}
+	index := map[string]int{}
+	for _, r := range rules {
+		index[r.Name()] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   r.Name(),
+			ShortDescription:     &sarifMessage{Text: r.Description()},
+			DefaultConfiguration: &sarifConfiguration{Level: sarifLevel(r.DefaultSeverity())},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		i, ok := index[f.Rule]
+		if !ok {
+			i = len(driver.Rules)
+			index[f.Rule] = i
+			driver.Rules = append(driver.Rules, sarifRule{ID: f.Rule})
+		}
+		loc := sarifLocation{}
+		if uri != "" {
+			loc.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}}
+		}
+		if f.Path != "" {
+			loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: f.Path}}
+		}
+		result := sarifResult{
+			RuleID:    f.Rule,
+			RuleIndex: i,
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: f.Message},
+		}
+		if loc.PhysicalLocation != nil || loc.LogicalLocations != nil {
+			result.Locations = []sarifLocation{loc}
+		}
+		results = append(results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
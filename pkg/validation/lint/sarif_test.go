@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	l := NewSpecLinter().Disable(UnusedDefinition, EnumDefaultMismatch, UndeclaredPathParameter, DuplicateOperationID, OperationDescription)
+	findings := append(l.Lint(loadTestSpec(t)), Finding{Rule: ValidationRule, Severity: Info, Message: "no location"})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSARIF(&buf, "api/swagger.json", []Rule{NewRule("r", "checks r", Info, nil), l.Rules()[3]}, findings))
+	assert.JSONEq(t, `{
+	  "version": "2.1.0",
+	  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+	  "runs": [{
+	    "tool": {"driver": {
+	      "name": "kube-openapi-lint",
+	      "rules": [
+	        {"id": "r", "shortDescription": {"text": "checks r"}, "defaultConfiguration": {"level": "note"}},
+	        {"id": "missing-4xx-response", "shortDescription": {"text": "operations should document at least one 4xx or default response"}, "defaultConfiguration": {"level": "warning"}},
+	        {"id": "validation"}
+	      ]
+	    }},
+	    "results": [
+	      {
+	        "ruleId": "missing-4xx-response", "ruleIndex": 1, "level": "warning",
+	        "message": {"text": "operation documents no 4xx or default response"},
+	        "locations": [{
+	          "physicalLocation": {"artifactLocation": {"uri": "api/swagger.json"}},
+	          "logicalLocations": [{"fullyQualifiedName": "/paths/~1pets~1{id}/delete/responses"}]
+	        }]
+	      },
+	      {
+	        "ruleId": "validation", "ruleIndex": 2, "level": "note",
+	        "message": {"text": "no location"},
+	        "locations": [{"physicalLocation": {"artifactLocation": {"uri": "api/swagger.json"}}}]
+	      }
+	    ]
+	  }]
+	}`, buf.String())
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationBuilder(t *testing.T) {
+	op := NewOperation("getPet").
+		WithSummary("Get a pet").
+		WithTags("pets").
+		WithProduces("application/json").
+		AddParam(PathParam("id").Typed("string", "")).
+		AddParam(QueryParam("limit").Typed("integer", "int32").WithMinimum(1, false)).
+		AddParam(QueryParam("limit").Typed("integer", "int64")).
+		RespondsWith(200, NewResponse().
+			WithDescription("OK").
+			WithSchema(RefSchema("#/definitions/Pet")).
+			AddHeader("X-Rate-Limit", ResponseHeader().Typed("integer", "int32")).
+			AddExample("application/json", map[string]interface{}{"name": "rex"})).
+		WithDefaultResponse(ResponseRef("#/responses/Error")).
+		WithSecurity("api_key").
+		WithSecurity("oauth", "read:pets")
+
+	require.Len(t, op.Parameters, 2)
+	assert.Equal(t, "int64", op.Parameters[1].Format)
+	assert.True(t, op.Parameters[0].Required)
+
+	data, err := json.Marshal(op)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"operationId": "getPet",
+		"summary": "Get a pet",
+		"tags": ["pets"],
+		"produces": ["application/json"],
+		"parameters": [
+			{"name": "id", "in": "path", "required": true, "type": "string"},
+			{"name": "limit", "in": "query", "type": "integer", "format": "int64"}
+		],
+		"responses": {
+			"200": {
+				"description": "OK",
+				"schema": {"$ref": "#/definitions/Pet"},
+				"headers": {"X-Rate-Limit": {"type": "integer", "format": "int32"}},
+				"examples": {"application/json": {"name": "rex"}}
+			},
+			"default": {"$ref": "#/responses/Error"}
+		},
+		"security": [{"api_key": []}, {"oauth": ["read:pets"]}]
+	}`, string(data))
+
+	op.RemoveParam("limit", "query").RespondsWith(200, nil)
+	assert.Len(t, op.Parameters, 1)
+	assert.Empty(t, op.Responses.StatusCodeResponses)
+}
+
+func TestParameterBuilder(t *testing.T) {
+	p := QueryParam("tags").
+		CollectionOf(NewItems().Typed("string", "").WithEnum("a", "b"), "csv").
+		WithMaxItems(3).
+		UniqueValues().
+		WithDefault([]interface{}{"a"}).
+		AsRequired()
+	assert.False(t, p.Required, "a parameter with a default can't be required")
+	assert.Equal(t, "array", p.Type)
+	assert.Equal(t, []interface{}{"a", "b"}, p.Items.Enum)
+
+	body := BodyParam("pet", RefSchema("#/definitions/Pet")).AsRequired()
+	assert.Equal(t, "body", body.In)
+	assert.True(t, body.Required)
+	assert.Equal(t, "#/parameters/x", ParamRef("#/parameters/x").Ref.String())
+}
+
+func TestSecuritySchemeBuilders(t *testing.T) {
+	s := (&Swagger{}).
+		AddSecurityDefinition("basic", BasicAuth()).
+		AddSecurityDefinition("api_key", APIKeyAuth("X-API-Key", "header")).
+		AddSecurityDefinition("oauth", OAuth2AccessToken("https://example.com/auth", "https://example.com/token").
+			AddScope("read:pets", "read your pets").
+			WithDescription("OAuth2"))
+
+	data, err := json.Marshal(s.SecurityDefinitions)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"basic": {"type": "basic"},
+		"api_key": {"type": "apiKey", "name": "X-API-Key", "in": "header"},
+		"oauth": {
+			"type": "oauth2",
+			"description": "OAuth2",
+			"flow": "accessCode",
+			"authorizationUrl": "https://example.com/auth",
+			"tokenUrl": "https://example.com/token",
+			"scopes": {"read:pets": "read your pets"}
+		}
+	}`, string(data))
+	assert.Equal(t, "implicit", OAuth2Implicit("u").Flow)
+	assert.Equal(t, "password", OAuth2Password("u").Flow)
+	assert.Equal(t, "application", OAuth2Application("u").Flow)
+}
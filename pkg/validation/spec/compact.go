@@ -0,0 +1,199 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"crypto/sha512"
+	"encoding/json"
+)
+
+// Compact reduces the memory held by a spec which is only read from now on,
+// e.g. a large aggregated spec kept around to be served: repeated strings
+// like descriptions, formats and names are stored once, and identical
+// schemas held through pointers, e.g. the schemas of items, parameters and
+// responses, are replaced by a single shared instance.
+//
+// The spec is compacted in place, and so are the structures it shares with
+// other specs, without changing their content. Nobody may read them
+// meanwhile. Since its nodes may be shared afterwards, the spec must not be
+// modified anymore; use DeepCopy to get a copy that can be.
+func (s *Swagger) Compact() {
+	c := &compactor{strings: map[string]string{}, schemas: map[[sha512.Size256]byte]*Schema{}}
+	c.strs(s.Consumes)
+	c.strs(s.Produces)
+	c.strs(s.Schemes)
+	for k, def := range s.Definitions {
+		c.schema(&def)
+		s.Definitions[k] = def
+	}
+	for k, p := range s.Parameters {
+		c.parameter(&p)
+		s.Parameters[k] = p
+	}
+	for k, r := range s.Responses {
+		c.response(&r)
+		s.Responses[k] = r
+	}
+	for i := range s.Tags {
+		c.str(&s.Tags[i].Name)
+		c.str(&s.Tags[i].Description)
+	}
+	if s.Paths == nil {
+		return
+	}
+	for k, item := range s.Paths.Paths {
+		c.parameters(item.Parameters)
+		for _, m := range item.Operations() {
+			c.operation(m.Operation)
+		}
+		s.Paths.Paths[k] = item
+	}
+}
+
+// compactor interns the strings and the pointed-to schemas of a spec.
+type compactor struct {
+	strings map[string]string
+	// schemas are keyed by the hash of their serialization.
+	schemas map[[sha512.Size256]byte]*Schema
+}
+
+func (c *compactor) str(s *string) {
+	if *s == "" {
+		return
+	}
+	if interned, ok := c.strings[*s]; ok {
+		*s = interned
+		return
+	}
+	c.strings[*s] = *s
+}
+
+func (c *compactor) strs(l []string) {
+	for i := range l {
+		c.str(&l[i])
+	}
+}
+
+func (c *compactor) operation(op *Operation) {
+	c.str(&op.ID)
+	c.str(&op.Summary)
+	c.str(&op.Description)
+	c.strs(op.Tags)
+	c.strs(op.Consumes)
+	c.strs(op.Produces)
+	c.strs(op.Schemes)
+	c.parameters(op.Parameters)
+	if op.Responses == nil {
+		return
+	}
+	if op.Responses.Default != nil {
+		c.response(op.Responses.Default)
+	}
+	for code, r := range op.Responses.StatusCodeResponses {
+		c.response(&r)
+		op.Responses.StatusCodeResponses[code] = r
+	}
+}
+
+func (c *compactor) parameters(l []Parameter) {
+	for i := range l {
+		c.parameter(&l[i])
+	}
+}
+
+func (c *compactor) parameter(p *Parameter) {
+	c.str(&p.Name)
+	c.str(&p.In)
+	c.str(&p.Description)
+	c.simpleSchema(&p.SimpleSchema)
+	p.Schema = c.shared(p.Schema)
+}
+
+func (c *compactor) response(r *Response) {
+	c.str(&r.Description)
+	r.Schema = c.shared(r.Schema)
+	for k, h := range r.Headers {
+		c.str(&h.Description)
+		c.simpleSchema(&h.SimpleSchema)
+		r.Headers[k] = h
+	}
+}
+
+func (c *compactor) simpleSchema(s *SimpleSchema) {
+	c.str(&s.Type)
+	c.str(&s.Format)
+	c.str(&s.CollectionFormat)
+	for items := s.Items; items != nil; items = items.Items {
+		c.str(&items.Type)
+		c.str(&items.Format)
+		c.str(&items.CollectionFormat)
+	}
+}
+
+func (c *compactor) schema(s *Schema) {
+	RewriteSchema(s, "", func(s *Schema, _ string) {
+		c.str(&s.Description)
+		c.str(&s.Title)
+		c.str(&s.Format)
+		c.strs(s.Type)
+		c.strs(s.Required)
+		// The subschemas are compacted next, once shared.
+		s.Not = c.dedup(s.Not)
+		if s.Items != nil {
+			s.Items.Schema = c.dedup(s.Items.Schema)
+		}
+		if s.AdditionalProperties != nil {
+			s.AdditionalProperties.Schema = c.dedup(s.AdditionalProperties.Schema)
+		}
+		if s.AdditionalItems != nil {
+			s.AdditionalItems.Schema = c.dedup(s.AdditionalItems.Schema)
+		}
+		for k, dep := range s.Dependencies {
+			dep.Schema = c.dedup(dep.Schema)
+			c.strs(dep.Property)
+			s.Dependencies[k] = dep
+		}
+	})
+}
+
+// shared compacts s and returns the first schema seen with the same
+// content.
+func (c *compactor) shared(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	c.schema(s)
+	return c.dedup(s)
+}
+
+// dedup returns the first schema seen with the same content as s. Compacting
+// doesn't change the content of schemas, so s may be compacted afterwards.
+func (c *compactor) dedup(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return s
+	}
+	key := sha512.Sum512_256(data)
+	if shared, ok := c.schemas[key]; ok {
+		return shared
+	}
+	c.schemas[key] = s
+	return s
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const compactSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "description": "the pets",
+        "responses": {"200": {"description": "the pets", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}}
+      }
+    },
+    "/cats": {
+      "get": {
+        "description": "the cats",
+        "responses": {"200": {"description": "the pets", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string", "description": "the name"},
+        "nickname": {"type": "string", "description": "the name"},
+        "tags": {"type": "array", "items": {"type": "string", "format": "tag"}},
+        "aliases": {"type": "array", "items": {"type": "string", "format": "tag"}}
+      }
+    }
+  }
+}`
+
+func sameString(a, b string) bool {
+	return (*reflect.StringHeader)(unsafe.Pointer(&a)).Data == (*reflect.StringHeader)(unsafe.Pointer(&b)).Data
+}
+
+func TestCompact(t *testing.T) {
+	var s Swagger
+	require.NoError(t, json.Unmarshal([]byte(compactSpec), &s))
+	before, err := json.Marshal(&s)
+	require.NoError(t, err)
+
+	s.Compact()
+	after, err := json.Marshal(&s)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(before), string(after), "the content is unchanged")
+
+	pets := s.Paths.Paths["/pets"].Get.Responses.StatusCodeResponses[200]
+	cats := s.Paths.Paths["/cats"].Get.Responses.StatusCodeResponses[200]
+	assert.Same(t, pets.Schema, cats.Schema)
+	assert.True(t, sameString(pets.Description, cats.Description))
+	assert.True(t, sameString(pets.Description, s.Paths.Paths["/pets"].Get.Description))
+
+	pet := s.Definitions["Pet"]
+	assert.Same(t, pet.Properties["tags"].Items.Schema, pet.Properties["aliases"].Items.Schema)
+	assert.True(t, sameString(pet.Properties["name"].Description, pet.Properties["nickname"].Description))
+
+	var empty Swagger
+	empty.Compact()
+}
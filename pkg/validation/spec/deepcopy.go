@@ -0,0 +1,721 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import "encoding/json"
+
+// The DeepCopy methods below follow the deepcopy-gen conventions: DeepCopyInto
+// copies the receiver into out, which must be non-nil, and DeepCopy returns a
+// new copy. Untyped values (defaults, examples, enums and extensions) are
+// expected to hold decoded JSON and are copied recursively. A Ref is copied by
+// value: its parsed URL is never mutated after creation and is shared safely.
+
+// DeepCopyJSONValue returns a deep copy of a decoded JSON value, i.e. one made
+// of maps, slices, strings, numbers, booleans and nil. Values of other types
+// are returned as they are.
+func DeepCopyJSONValue(x interface{}) interface{} {
+	switch x := x.(type) {
+	case map[string]interface{}:
+		if x == nil {
+			return x
+		}
+		out := make(map[string]interface{}, len(x))
+		for k, v := range x {
+			out[k] = DeepCopyJSONValue(v)
+		}
+		return out
+	case []interface{}:
+		if x == nil {
+			return x
+		}
+		out := make([]interface{}, len(x))
+		for i, v := range x {
+			out[i] = DeepCopyJSONValue(v)
+		}
+		return out
+	case json.RawMessage:
+		if x == nil {
+			return x
+		}
+		return append(json.RawMessage(nil), x...)
+	default:
+		return x
+	}
+}
+
+func deepCopyJSONSlice(in []interface{}) []interface{} {
+	if in == nil {
+		return nil
+	}
+	out := make([]interface{}, len(in))
+	for i, v := range in {
+		out[i] = DeepCopyJSONValue(v)
+	}
+	return out
+}
+
+func deepCopyJSONMap(in map[string]interface{}) map[string]interface{} {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(in))
+	for k, v := range in {
+		out[k] = DeepCopyJSONValue(v)
+	}
+	return out
+}
+
+func deepCopyStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(in)), in...)
+}
+
+func deepCopySecurity(in []map[string][]string) []map[string][]string {
+	if in == nil {
+		return nil
+	}
+	out := make([]map[string][]string, len(in))
+	for i, req := range in {
+		if req == nil {
+			continue
+		}
+		out[i] = make(map[string][]string, len(req))
+		for k, scopes := range req {
+			out[i][k] = deepCopyStrings(scopes)
+		}
+	}
+	return out
+}
+
+func deepCopySchemas(in []Schema) []Schema {
+	if in == nil {
+		return nil
+	}
+	out := make([]Schema, len(in))
+	for i := range in {
+		in[i].DeepCopyInto(&out[i])
+	}
+	return out
+}
+
+func deepCopySchemaMap(in map[string]Schema) map[string]Schema {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]Schema, len(in))
+	for k, v := range in {
+		out[k] = *v.DeepCopy()
+	}
+	return out
+}
+
+func deepCopyParameters(in []Parameter) []Parameter {
+	if in == nil {
+		return nil
+	}
+	out := make([]Parameter, len(in))
+	for i := range in {
+		in[i].DeepCopyInto(&out[i])
+	}
+	return out
+}
+
+func deepCopyFloat64(in *float64) *float64 {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
+func deepCopyInt64(in *int64) *int64 {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in Extensions) DeepCopyInto(out *Extensions) {
+	*out = Extensions(deepCopyJSONMap(in))
+}
+
+// DeepCopy returns a deep copy of the extensions.
+func (in Extensions) DeepCopy() Extensions {
+	if in == nil {
+		return nil
+	}
+	var out Extensions
+	in.DeepCopyInto(&out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *VendorExtensible) DeepCopyInto(out *VendorExtensible) {
+	out.Extensions = in.Extensions.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *VendorExtensible) DeepCopy() *VendorExtensible {
+	if in == nil {
+		return nil
+	}
+	out := new(VendorExtensible)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ContactInfo) DeepCopyInto(out *ContactInfo) {
+	*out = *in
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *ContactInfo) DeepCopy() *ContactInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ContactInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *License) DeepCopyInto(out *License) {
+	*out = *in
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *License) DeepCopy() *License {
+	if in == nil {
+		return nil
+	}
+	out := new(License)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *ExternalDocumentation) DeepCopyInto(out *ExternalDocumentation) {
+	*out = *in
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *ExternalDocumentation) DeepCopy() *ExternalDocumentation {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDocumentation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Info) DeepCopyInto(out *Info) {
+	*out = *in
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+	out.Contact = in.Contact.DeepCopy()
+	out.License = in.License.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *Info) DeepCopy() *Info {
+	if in == nil {
+		return nil
+	}
+	out := new(Info)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Tag) DeepCopyInto(out *Tag) {
+	*out = *in
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+	out.ExternalDocs = in.ExternalDocs.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *Tag) DeepCopy() *Tag {
+	if in == nil {
+		return nil
+	}
+	out := new(Tag)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *SimpleSchema) DeepCopyInto(out *SimpleSchema) {
+	*out = *in
+	out.Items = in.Items.DeepCopy()
+	out.Default = DeepCopyJSONValue(in.Default)
+	out.Example = DeepCopyJSONValue(in.Example)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *SimpleSchema) DeepCopy() *SimpleSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(SimpleSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *CommonValidations) DeepCopyInto(out *CommonValidations) {
+	*out = *in
+	out.Maximum = deepCopyFloat64(in.Maximum)
+	out.Minimum = deepCopyFloat64(in.Minimum)
+	out.MaxLength = deepCopyInt64(in.MaxLength)
+	out.MinLength = deepCopyInt64(in.MinLength)
+	out.MaxItems = deepCopyInt64(in.MaxItems)
+	out.MinItems = deepCopyInt64(in.MinItems)
+	out.MultipleOf = deepCopyFloat64(in.MultipleOf)
+	out.Enum = deepCopyJSONSlice(in.Enum)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *CommonValidations) DeepCopy() *CommonValidations {
+	if in == nil {
+		return nil
+	}
+	out := new(CommonValidations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Items) DeepCopyInto(out *Items) {
+	*out = *in
+	in.CommonValidations.DeepCopyInto(&out.CommonValidations)
+	in.SimpleSchema.DeepCopyInto(&out.SimpleSchema)
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *Items) DeepCopy() *Items {
+	if in == nil {
+		return nil
+	}
+	out := new(Items)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Header) DeepCopyInto(out *Header) {
+	*out = *in
+	in.CommonValidations.DeepCopyInto(&out.CommonValidations)
+	in.SimpleSchema.DeepCopyInto(&out.SimpleSchema)
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *Header) DeepCopy() *Header {
+	if in == nil {
+		return nil
+	}
+	out := new(Header)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Parameter) DeepCopyInto(out *Parameter) {
+	*out = *in
+	in.CommonValidations.DeepCopyInto(&out.CommonValidations)
+	in.SimpleSchema.DeepCopyInto(&out.SimpleSchema)
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+	out.Schema = in.Schema.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *Parameter) DeepCopy() *Parameter {
+	if in == nil {
+		return nil
+	}
+	out := new(Parameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Response) DeepCopyInto(out *Response) {
+	*out = *in
+	out.Schema = in.Schema.DeepCopy()
+	if in.Headers != nil {
+		out.Headers = make(map[string]Header, len(in.Headers))
+		for k, v := range in.Headers {
+			out.Headers[k] = *v.DeepCopy()
+		}
+	}
+	out.Examples = deepCopyJSONMap(in.Examples)
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *Response) DeepCopy() *Response {
+	if in == nil {
+		return nil
+	}
+	out := new(Response)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Responses) DeepCopyInto(out *Responses) {
+	*out = *in
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+	out.Default = in.Default.DeepCopy()
+	if in.StatusCodeResponses != nil {
+		out.StatusCodeResponses = make(map[int]Response, len(in.StatusCodeResponses))
+		for k, v := range in.StatusCodeResponses {
+			out.StatusCodeResponses[k] = *v.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *Responses) DeepCopy() *Responses {
+	if in == nil {
+		return nil
+	}
+	out := new(Responses)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Operation) DeepCopyInto(out *Operation) {
+	*out = *in
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+	out.Consumes = deepCopyStrings(in.Consumes)
+	out.Produces = deepCopyStrings(in.Produces)
+	out.Schemes = deepCopyStrings(in.Schemes)
+	out.Tags = deepCopyStrings(in.Tags)
+	out.ExternalDocs = in.ExternalDocs.DeepCopy()
+	out.Security = deepCopySecurity(in.Security)
+	out.Parameters = deepCopyParameters(in.Parameters)
+	out.Responses = in.Responses.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *Operation) DeepCopy() *Operation {
+	if in == nil {
+		return nil
+	}
+	out := new(Operation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *PathItem) DeepCopyInto(out *PathItem) {
+	*out = *in
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+	out.Get = in.Get.DeepCopy()
+	out.Put = in.Put.DeepCopy()
+	out.Post = in.Post.DeepCopy()
+	out.Delete = in.Delete.DeepCopy()
+	out.Options = in.Options.DeepCopy()
+	out.Head = in.Head.DeepCopy()
+	out.Patch = in.Patch.DeepCopy()
+	out.Parameters = deepCopyParameters(in.Parameters)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *PathItem) DeepCopy() *PathItem {
+	if in == nil {
+		return nil
+	}
+	out := new(PathItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Paths) DeepCopyInto(out *Paths) {
+	*out = *in
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+	if in.Paths != nil {
+		out.Paths = make(map[string]PathItem, len(in.Paths))
+		for k, v := range in.Paths {
+			out.Paths[k] = *v.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *Paths) DeepCopy() *Paths {
+	if in == nil {
+		return nil
+	}
+	out := new(Paths)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *SecurityScheme) DeepCopyInto(out *SecurityScheme) {
+	*out = *in
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+	if in.Scopes != nil {
+		out.Scopes = make(map[string]string, len(in.Scopes))
+		for k, v := range in.Scopes {
+			out.Scopes[k] = v
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *SecurityScheme) DeepCopy() *SecurityScheme {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityScheme)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in StringOrArray) DeepCopyInto(out *StringOrArray) {
+	*out = StringOrArray(deepCopyStrings(in))
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in StringOrArray) DeepCopy() StringOrArray {
+	return StringOrArray(deepCopyStrings(in))
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *SchemaOrBool) DeepCopyInto(out *SchemaOrBool) {
+	*out = *in
+	out.Schema = in.Schema.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *SchemaOrBool) DeepCopy() *SchemaOrBool {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaOrBool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *SchemaOrStringArray) DeepCopyInto(out *SchemaOrStringArray) {
+	out.Schema = in.Schema.DeepCopy()
+	out.Property = deepCopyStrings(in.Property)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *SchemaOrStringArray) DeepCopy() *SchemaOrStringArray {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaOrStringArray)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *SchemaOrArray) DeepCopyInto(out *SchemaOrArray) {
+	out.Schema = in.Schema.DeepCopy()
+	out.Schemas = deepCopySchemas(in.Schemas)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *SchemaOrArray) DeepCopy() *SchemaOrArray {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaOrArray)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in Dependencies) DeepCopyInto(out *Dependencies) {
+	if in == nil {
+		*out = nil
+		return
+	}
+	*out = make(Dependencies, len(in))
+	for k, v := range in {
+		(*out)[k] = *v.DeepCopy()
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in Dependencies) DeepCopy() Dependencies {
+	var out Dependencies
+	in.DeepCopyInto(&out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in Definitions) DeepCopyInto(out *Definitions) {
+	*out = Definitions(deepCopySchemaMap(in))
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in Definitions) DeepCopy() Definitions {
+	return Definitions(deepCopySchemaMap(in))
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in SecurityDefinitions) DeepCopyInto(out *SecurityDefinitions) {
+	if in == nil {
+		*out = nil
+		return
+	}
+	*out = make(SecurityDefinitions, len(in))
+	for k, v := range in {
+		(*out)[k] = v.DeepCopy()
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in SecurityDefinitions) DeepCopy() SecurityDefinitions {
+	var out SecurityDefinitions
+	in.DeepCopyInto(&out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *SchemaProps) DeepCopyInto(out *SchemaProps) {
+	*out = *in
+	out.Type = in.Type.DeepCopy()
+	out.Default = DeepCopyJSONValue(in.Default)
+	out.Maximum = deepCopyFloat64(in.Maximum)
+	out.Minimum = deepCopyFloat64(in.Minimum)
+	out.MaxLength = deepCopyInt64(in.MaxLength)
+	out.MinLength = deepCopyInt64(in.MinLength)
+	out.MaxItems = deepCopyInt64(in.MaxItems)
+	out.MinItems = deepCopyInt64(in.MinItems)
+	out.MultipleOf = deepCopyFloat64(in.MultipleOf)
+	out.Enum = deepCopyJSONSlice(in.Enum)
+	out.MaxProperties = deepCopyInt64(in.MaxProperties)
+	out.MinProperties = deepCopyInt64(in.MinProperties)
+	out.Required = deepCopyStrings(in.Required)
+	out.Items = in.Items.DeepCopy()
+	out.AllOf = deepCopySchemas(in.AllOf)
+	out.OneOf = deepCopySchemas(in.OneOf)
+	out.AnyOf = deepCopySchemas(in.AnyOf)
+	out.Not = in.Not.DeepCopy()
+	out.Properties = deepCopySchemaMap(in.Properties)
+	out.AdditionalProperties = in.AdditionalProperties.DeepCopy()
+	out.PatternProperties = deepCopySchemaMap(in.PatternProperties)
+	out.Dependencies = in.Dependencies.DeepCopy()
+	out.AdditionalItems = in.AdditionalItems.DeepCopy()
+	out.Definitions = in.Definitions.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *SchemaProps) DeepCopy() *SchemaProps {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaProps)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *SwaggerSchemaProps) DeepCopyInto(out *SwaggerSchemaProps) {
+	*out = *in
+	out.ExternalDocs = in.ExternalDocs.DeepCopy()
+	out.Example = DeepCopyJSONValue(in.Example)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *SwaggerSchemaProps) DeepCopy() *SwaggerSchemaProps {
+	if in == nil {
+		return nil
+	}
+	out := new(SwaggerSchemaProps)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Schema) DeepCopyInto(out *Schema) {
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+	in.SchemaProps.DeepCopyInto(&out.SchemaProps)
+	in.SwaggerSchemaProps.DeepCopyInto(&out.SwaggerSchemaProps)
+	out.ExtraProps = deepCopyJSONMap(in.ExtraProps)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *Schema) DeepCopy() *Schema {
+	if in == nil {
+		return nil
+	}
+	out := new(Schema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Swagger) DeepCopyInto(out *Swagger) {
+	*out = *in
+	in.VendorExtensible.DeepCopyInto(&out.VendorExtensible)
+	out.Consumes = deepCopyStrings(in.Consumes)
+	out.Produces = deepCopyStrings(in.Produces)
+	out.Schemes = deepCopyStrings(in.Schemes)
+	out.Info = in.Info.DeepCopy()
+	out.Paths = in.Paths.DeepCopy()
+	out.Definitions = in.Definitions.DeepCopy()
+	if in.Parameters != nil {
+		out.Parameters = make(map[string]Parameter, len(in.Parameters))
+		for k, v := range in.Parameters {
+			out.Parameters[k] = *v.DeepCopy()
+		}
+	}
+	if in.Responses != nil {
+		out.Responses = make(map[string]Response, len(in.Responses))
+		for k, v := range in.Responses {
+			out.Responses[k] = *v.DeepCopy()
+		}
+	}
+	out.SecurityDefinitions = in.SecurityDefinitions.DeepCopy()
+	out.Security = deepCopySecurity(in.Security)
+	if in.Tags != nil {
+		out.Tags = make([]Tag, len(in.Tags))
+		for i := range in.Tags {
+			in.Tags[i].DeepCopyInto(&out.Tags[i])
+		}
+	}
+	out.ExternalDocs = in.ExternalDocs.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *Swagger) DeepCopy() *Swagger {
+	if in == nil {
+		return nil
+	}
+	out := new(Swagger)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadAggregatorSpec(t testing.TB) *Swagger {
+	data, err := ioutil.ReadFile("../../../test/integration/testdata/aggregator/openapi.json")
+	require.NoError(t, err)
+	s := &Swagger{}
+	require.NoError(t, json.Unmarshal(data, s))
+	return s
+}
+
+func TestSwaggerDeepCopy(t *testing.T) {
+	for name, s := range map[string]*Swagger{
+		"literal":    &spec,
+		"aggregator": loadAggregatorSpec(t),
+	} {
+		t.Run(name, func(t *testing.T) {
+			cp := s.DeepCopy()
+			assert.Equal(t, s, cp)
+
+			orig, err := json.Marshal(s)
+			require.NoError(t, err)
+			copied, err := json.Marshal(cp)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(orig), string(copied))
+		})
+	}
+	assert.Nil(t, (*Swagger)(nil).DeepCopy())
+}
+
+func TestSchemaDeepCopyIsIndependent(t *testing.T) {
+	cp := schema.DeepCopy()
+	require.Equal(t, &schema, cp)
+
+	*cp.Maximum = 1
+	cp.Enum[0] = "changed"
+	cp.Type[0] = "changed"
+	cp.Example.([]interface{})[0].(map[string]interface{})["name"] = "changed"
+	cp.Extensions["x-framework"] = "changed"
+	p := cp.Properties["id"]
+	p.Description = "changed"
+	cp.Properties["id"] = p
+	cp.Items.Schema.Description = "changed"
+	cp.AllOf[0].Description = "changed"
+
+	assert.NotEqual(t, float64(1), *schema.Maximum)
+	assert.NotEqual(t, "changed", schema.Enum[0])
+	assert.NotEqual(t, "changed", schema.Type[0])
+	assert.Equal(t, "a book", schema.Example.([]interface{})[0].(map[string]interface{})["name"])
+	assert.NotEqual(t, "changed", schema.Extensions["x-framework"])
+	assert.NotEqual(t, "changed", schema.Properties["id"].Description)
+	assert.NotEqual(t, "changed", schema.Items.Schema.Description)
+	assert.NotEqual(t, "changed", schema.AllOf[0].Description)
+}
+
+func TestDeepCopyJSONValue(t *testing.T) {
+	v := map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": 1.0}}, "c": "d"}
+	cp := DeepCopyJSONValue(v).(map[string]interface{})
+	assert.Equal(t, v, cp)
+	cp["a"].([]interface{})[0].(map[string]interface{})["b"] = 2.0
+	assert.Equal(t, 1.0, v["a"].([]interface{})[0].(map[string]interface{})["b"])
+}
+
+func BenchmarkSwaggerDeepCopy(b *testing.B) {
+	s := loadAggregatorSpec(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = s.DeepCopy()
+	}
+}
+
+func BenchmarkSwaggerJSONCopy(b *testing.B) {
+	s := loadAggregatorSpec(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, _ := json.Marshal(s)
+		_ = json.Unmarshal(data, &Swagger{})
+	}
+}
@@ -0,0 +1,65 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var fuzzSeeds = []string{
+	`{}`,
+	`{"swagger": "2.0", "info": {"title": "pets", "version": "1.0"}, "paths": {"/pets": {"get": {"responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/Pet"}}}}}}}`,
+	`{"type": "object", "properties": {"name": {"type": ["string", "null"]}}, "additionalProperties": false, "items": [{}, true]}`,
+	`{"name": "limit", "in": "query", "type": "array", "items": {"type": "integer", "items": {}}}`,
+	`{"a": [1, 2, {"b": null}], "a": true}`,
+}
+
+// FuzzLimitsCheck verifies that Check agrees with encoding/json on which
+// documents are valid.
+func FuzzLimitsCheck(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if valid, err := json.Valid(data), (Limits{}).Check(data); valid != (err == nil) {
+			t.Fatalf("json.Valid = %t but Check returned %v", valid, err)
+		}
+		DefaultLimits.Check(data)
+	})
+}
+
+// FuzzUnmarshal verifies that the spec types don't panic on any input and
+// that whatever they decode can be encoded again.
+func FuzzUnmarshal(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, v := range []interface{}{&Swagger{}, &Schema{}, &Parameter{}, &Response{}, &Responses{}, &PathItem{}} {
+			if err := DefaultLimits.Decode(data, v); err != nil {
+				continue
+			}
+			if _, err := json.Marshal(v); err != nil {
+				t.Fatalf("%T decoded %q but failed to encode: %v", v, data, err)
+			}
+		}
+	})
+}
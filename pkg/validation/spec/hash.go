@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// CanonicalJSON returns the canonical serialization of the spec: compact
+// JSON with object keys sorted at every level. Two specs with the same
+// content have the same canonical serialization, whatever the order their
+// source documents were written in. Normalize the spec first to also ignore
+// differences like the order of parameters.
+func (s *Swagger) CanonicalJSON() ([]byte, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	// Decoding into generic values and encoding again sorts the keys of
+	// extensions and extra properties as well. Numbers are kept verbatim.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// Hash returns the hex encoded SHA-512 of the canonical serialization of the
+// spec. It is stable across processes and suitable as an ETag, a cache key
+// or to detect changes.
+func (s *Swagger) Hash() (string, error) {
+	data, err := s.CanonicalJSON()
+	if err != nil {
+		return "", err
+	}
+	sum := sha512.Sum512(data)
+	return hex.EncodeToString(sum[:]), nil
+}
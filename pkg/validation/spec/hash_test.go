@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwaggerHash(t *testing.T) {
+	parse := func(s string) *Swagger {
+		sw := &Swagger{}
+		require.NoError(t, json.Unmarshal([]byte(s), sw))
+		return sw
+	}
+	a := parse(`{"swagger": "2.0", "x-b": {"d": 1, "c": 2}, "x-a": 1.50, "paths": {"/b": {}, "/a": {}},
+		"definitions": {"B": {"type": "string", "x-z": true, "x-y": false}, "A": {"type": "integer"}}}`)
+	b := parse(`{"definitions": {"A": {"type": "integer"}, "B": {"x-y": false, "x-z": true, "type": "string"}},
+		"paths": {"/a": {}, "/b": {}}, "x-a": 1.50, "x-b": {"c": 2, "d": 1}, "swagger": "2.0"}`)
+
+	ha, err := a.Hash()
+	require.NoError(t, err)
+	hb, err := b.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, ha, hb)
+	assert.Len(t, ha, 128)
+
+	again, err := a.DeepCopy().Hash()
+	require.NoError(t, err)
+	assert.Equal(t, ha, again)
+
+	b.Definitions["A"] = *new(Schema).Typed("integer", "int64")
+	hb, err = b.Hash()
+	require.NoError(t, err)
+	assert.NotEqual(t, ha, hb)
+}
+
+func TestSwaggerCanonicalJSON(t *testing.T) {
+	data, err := (&Swagger{
+		VendorExtensible: VendorExtensible{Extensions: Extensions{"x-b": 1, "x-a": 2}},
+		SwaggerProps:     SwaggerProps{Swagger: "2.0", Host: "example.com"},
+	}).CanonicalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"host":"example.com","paths":null,"swagger":"2.0","x-a":2,"x-b":1}`, string(data))
+}
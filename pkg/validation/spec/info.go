@@ -16,6 +16,8 @@ package spec
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"strings"
 
 	"github.com/go-openapi/swag"
@@ -24,33 +26,142 @@ import (
 // Extensions vendor specific extensions
 type Extensions map[string]interface{}
 
+// extensionKey normalizes an extension name: names are case insensitive and
+// the "x-" prefix is optional.
+func extensionKey(key string) string {
+	lk := strings.ToLower(key)
+	if !strings.HasPrefix(lk, "x-") {
+		lk = "x-" + lk
+	}
+	return lk
+}
+
+// get looks up an extension by its lowercased key, as Add stores it, then by
+// its normalized name. Keys decoded from JSON keep their original case, so a
+// case insensitive match is accepted as well.
+func (e Extensions) get(key string) (interface{}, bool) {
+	lk, realKey := strings.ToLower(key), extensionKey(key)
+	if v, ok := e[lk]; ok {
+		return v, true
+	}
+	if v, ok := e[realKey]; ok {
+		return v, true
+	}
+	for k, v := range e {
+		if l := strings.ToLower(k); l == lk || l == realKey {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
 // Add adds a value to these extensions
 func (e Extensions) Add(key string, value interface{}) {
 	realKey := strings.ToLower(key)
 	e[realKey] = value
 }
 
+// Set sets an extension under its normalized name: the key is lowercased,
+// prefixed with "x-" if missing, and replaces the keys differing from it only
+// in case. Use Add to store the key as is but for its case.
+func (e Extensions) Set(key string, value interface{}) {
+	realKey := extensionKey(key)
+	for k := range e {
+		if k != realKey && strings.ToLower(k) == realKey {
+			delete(e, k)
+		}
+	}
+	e[realKey] = value
+}
+
+// Has returns true if the extension is set.
+func (e Extensions) Has(key string) bool {
+	_, ok := e.get(key)
+	return ok
+}
+
+// Remove removes an extension.
+func (e Extensions) Remove(key string) {
+	realKey := extensionKey(key)
+	for k := range e {
+		if strings.ToLower(k) == realKey {
+			delete(e, k)
+		}
+	}
+}
+
 // GetString gets a string value from the extensions
 func (e Extensions) GetString(key string) (string, bool) {
-	if v, ok := e[strings.ToLower(key)]; ok {
+	if v, ok := e.get(key); ok {
 		str, ok := v.(string)
 		return str, ok
 	}
 	return "", false
 }
 
-// GetBool gets a string value from the extensions
+// GetBool gets a bool value from the extensions
 func (e Extensions) GetBool(key string) (bool, bool) {
-	if v, ok := e[strings.ToLower(key)]; ok {
-		str, ok := v.(bool)
-		return str, ok
+	if v, ok := e.get(key); ok {
+		b, ok := v.(bool)
+		return b, ok
 	}
 	return false, false
 }
 
-// GetStringSlice gets a string value from the extensions
+// GetInt gets an integer value from the extensions. Numbers decoded from
+// JSON are accepted as long as they have no fractional part.
+func (e Extensions) GetInt(key string) (int64, bool) {
+	v, ok := e.get(key)
+	if !ok {
+		return 0, false
+	}
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		if n != math.Trunc(n) || n > math.MaxInt64 || n < math.MinInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// GetFloat gets a number from the extensions.
+func (e Extensions) GetFloat(key string) (float64, bool) {
+	v, ok := e.get(key)
+	if !ok {
+		return 0, false
+	}
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// GetStringSlice gets a string slice value from the extensions
 func (e Extensions) GetStringSlice(key string) ([]string, bool) {
-	if v, ok := e[strings.ToLower(key)]; ok {
+	if v, ok := e.get(key); ok {
+		if strs, isStrings := v.([]string); isStrings {
+			return strs, true
+		}
 		arr, isSlice := v.([]interface{})
 		if !isSlice {
 			return nil, false
@@ -68,6 +179,60 @@ func (e Extensions) GetStringSlice(key string) ([]string, bool) {
 	return nil, false
 }
 
+// GetObject decodes an extension into out, typically a pointer to a struct
+// with json tags. It returns false if the extension is not set and an error
+// if it can't be decoded into out.
+func (e Extensions) GetObject(key string, out interface{}) (bool, error) {
+	v, ok := e.get(key)
+	if !ok {
+		return false, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return true, err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return true, fmt.Errorf("extension %s: %v", extensionKey(key), err)
+	}
+	return true, nil
+}
+
+// DeepMerge returns a copy of the extensions with other merged into it.
+// Object values present on both sides are merged recursively, any other value
+// from other replaces the existing one. Neither e nor other is modified.
+func (e Extensions) DeepMerge(other Extensions) Extensions {
+	if e == nil && other == nil {
+		return nil
+	}
+	ret := Extensions{}
+	for k, v := range e {
+		ret[k] = DeepCopyJSONValue(v)
+	}
+	for k, v := range other {
+		key := k
+		for existing := range ret {
+			if strings.ToLower(existing) == strings.ToLower(k) {
+				key = existing
+				break
+			}
+		}
+		ret[key] = mergeJSONValue(ret[key], v)
+	}
+	return ret
+}
+
+func mergeJSONValue(dst, src interface{}) interface{} {
+	dstMap, dstOK := dst.(map[string]interface{})
+	srcMap, srcOK := src.(map[string]interface{})
+	if !dstOK || !srcOK {
+		return DeepCopyJSONValue(src)
+	}
+	for k, v := range srcMap {
+		dstMap[k] = mergeJSONValue(dstMap[k], v)
+	}
+	return dstMap
+}
+
 // VendorExtensible composition block.
 type VendorExtensible struct {
 	Extensions Extensions
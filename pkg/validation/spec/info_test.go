@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const infoJSON = `{
@@ -68,3 +69,102 @@ func TestIntegrationInfo_Deserialize(t *testing.T) {
 		assert.EqualValues(t, info, actual)
 	}
 }
+
+func TestExtensionsAccessors(t *testing.T) {
+	var e Extensions
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"X-Str": "value",
+		"x-bool": true,
+		"x-int": 42,
+		"x-float": 1.5,
+		"x-strs": ["a", "b"],
+		"x-mixed": ["a", 1],
+		"x-obj": {"name": "n", "count": 3}
+	}`), &e))
+
+	s, ok := e.GetString("x-str")
+	assert.True(t, ok)
+	assert.Equal(t, "value", s)
+	s, ok = e.GetString("str")
+	assert.True(t, ok)
+	assert.Equal(t, "value", s)
+	_, ok = e.GetString("x-bool")
+	assert.False(t, ok)
+
+	b, ok := e.GetBool("bool")
+	assert.True(t, ok)
+	assert.True(t, b)
+
+	i, ok := e.GetInt("x-int")
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), i)
+	_, ok = e.GetInt("x-float")
+	assert.False(t, ok)
+	f, ok := e.GetFloat("x-float")
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, f)
+
+	strs, ok := e.GetStringSlice("x-strs")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a", "b"}, strs)
+	_, ok = e.GetStringSlice("x-mixed")
+	assert.False(t, ok)
+
+	var obj struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	found, err := e.GetObject("x-obj", &obj)
+	assert.True(t, found)
+	require.NoError(t, err)
+	assert.Equal(t, "n", obj.Name)
+	assert.Equal(t, 3, obj.Count)
+	found, err = e.GetObject("x-str", &obj)
+	assert.True(t, found)
+	assert.Error(t, err)
+	found, err = e.GetObject("x-missing", &obj)
+	assert.False(t, found)
+	assert.NoError(t, err)
+
+	e.Set("Str", "replaced")
+	assert.Equal(t, "replaced", e["x-str"])
+	assert.NotContains(t, e, "X-Str")
+	assert.True(t, e.Has("x-STR"))
+	e.Remove("str")
+	assert.False(t, e.Has("x-str"))
+
+	// Add only lowercases the key, and what it stores is found first.
+	e.Add("Plain", "p")
+	e.Add("X-Case", "c")
+	assert.Equal(t, "p", e["plain"])
+	assert.Equal(t, "c", e["x-case"])
+	assert.NotContains(t, e, "x-plain")
+	s, ok = e.GetString("PLAIN")
+	assert.True(t, ok)
+	assert.Equal(t, "p", s)
+}
+
+func TestExtensionsDeepMerge(t *testing.T) {
+	a := Extensions{
+		"x-a":   "a",
+		"x-obj": map[string]interface{}{"keep": 1, "nested": map[string]interface{}{"x": 1}},
+	}
+	b := Extensions{
+		"x-b":   "b",
+		"X-Obj": map[string]interface{}{"add": 2, "nested": map[string]interface{}{"y": 2}},
+		"x-a":   []interface{}{"replaced"},
+	}
+	merged := a.DeepMerge(b)
+	assert.Equal(t, Extensions{
+		"x-a": []interface{}{"replaced"},
+		"x-b": "b",
+		"x-obj": map[string]interface{}{
+			"keep":   1,
+			"add":    2,
+			"nested": map[string]interface{}{"x": 1, "y": 2},
+		},
+	}, merged)
+	assert.Equal(t, "a", a["x-a"])
+	assert.NotContains(t, a["x-obj"], "add")
+	assert.Nil(t, Extensions(nil).DeepMerge(nil))
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Limits bound the JSON documents accepted by Decode, so that specs and
+// instances from untrusted sources can be parsed safely. Zero values
+// disable the corresponding limit.
+type Limits struct {
+	// MaxSize is the maximum size of a document in bytes.
+	MaxSize int
+	// MaxDepth is the maximum nesting of objects and arrays.
+	MaxDepth int
+	// RejectDuplicateKeys rejects objects with the same key more than once,
+	// which encoding/json accepts by keeping the last value.
+	RejectDuplicateKeys bool
+}
+
+// DefaultLimits are suited to untrusted specs: even the largest real world
+// specs are far smaller and shallower.
+var DefaultLimits = Limits{MaxSize: 64 << 20, MaxDepth: 256, RejectDuplicateKeys: true}
+
+// Check returns an error if data is not a single valid JSON value or
+// exceeds the limits.
+func (l Limits) Check(data []byte) error {
+	if l.MaxSize > 0 && len(data) > l.MaxSize {
+		return fmt.Errorf("document of %d bytes exceeds the maximum size of %d bytes", len(data), l.MaxSize)
+	}
+
+	type frame struct {
+		object bool
+		// expectKey is set in objects when the next token is a key.
+		expectKey bool
+		keys      map[string]bool
+	}
+	var stack []frame
+	started := false
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	for {
+		offset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err == io.EOF {
+			if !started || len(stack) > 0 {
+				return fmt.Errorf("unexpected end of JSON input")
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if started && len(stack) == 0 {
+			return fmt.Errorf("unexpected data after the document at offset %d", offset)
+		}
+		started = true
+		if len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.object && top.expectKey {
+				if d, ok := tok.(json.Delim); ok && d == '}' {
+					stack = stack[:len(stack)-1]
+					continue
+				}
+				key := tok.(string)
+				if l.RejectDuplicateKeys {
+					if top.keys[key] {
+						return fmt.Errorf("duplicate key %q at offset %d", key, offset)
+					}
+					top.keys[key] = true
+				}
+				top.expectKey = false
+				continue
+			}
+			if top.object {
+				top.expectKey = true
+			}
+		}
+		d, ok := tok.(json.Delim)
+		if !ok {
+			continue
+		}
+		switch d {
+		case '{', '[':
+			if l.MaxDepth > 0 && len(stack) >= l.MaxDepth {
+				return fmt.Errorf("document exceeds the maximum depth of %d at offset %d", l.MaxDepth, offset)
+			}
+			f := frame{object: d == '{', expectKey: d == '{'}
+			if f.object && l.RejectDuplicateKeys {
+				f.keys = map[string]bool{}
+			}
+			stack = append(stack, f)
+		case ']':
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// Decode checks data against the limits, then unmarshals it into v, e.g. a
+// *Swagger or the *interface{} of an instance to validate.
+func (l Limits) Decode(data []byte, v interface{}) error {
+	if err := l.Check(data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitsCheck(t *testing.T) {
+	l := Limits{MaxSize: 100, MaxDepth: 3, RejectDuplicateKeys: true}
+	for _, doc := range []string{
+		`{}`,
+		`[]`,
+		`"x"`,
+		`{"a": {"b": [1, 2]}, "c": [{"a": 1}, {"a": 2}]}`,
+		` {"a": [[]]} `,
+	} {
+		assert.NoError(t, l.Check([]byte(doc)), doc)
+	}
+	for doc, msg := range map[string]string{
+		`{"a": [[[]]]}`:            "maximum depth of 3",
+		`{"a": 1, "b": 2, "a": 3}`: `duplicate key "a"`,
+		`{"a": {"b": 1, "b": 1}}`:  `duplicate key "b"`,
+		strings.Repeat(" ", 101):   "maximum size of 100 bytes",
+		``:                         "unexpected end",
+		`{"a": `:                   "unexpected end",
+		`{"a": 1`:                  "unexpected end",
+		`{} {}`:                    "unexpected data after the document",
+		`{"a" 1}`:                  "invalid character",
+	} {
+		err := l.Check([]byte(doc))
+		if assert.Error(t, err, doc) {
+			assert.Contains(t, err.Error(), msg, doc)
+		}
+	}
+
+	assert.NoError(t, Limits{}.Check([]byte(`{"a": 1, "a": [[[[[]]]]]}`)), "zero limits are not enforced")
+}
+
+func TestLimitsDecode(t *testing.T) {
+	var s Swagger
+	require.NoError(t, DefaultLimits.Decode([]byte(`{"swagger": "2.0", "info": {"title": "pets"}}`), &s))
+	assert.Equal(t, "pets", s.Info.Title)
+	assert.Error(t, DefaultLimits.Decode([]byte(`{"swagger": "2.0", "swagger": "3.0"}`), &s))
+
+	var instance interface{}
+	deep := strings.Repeat("[", 300) + strings.Repeat("]", 300)
+	assert.Error(t, DefaultLimits.Decode([]byte(deep), &instance))
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"sort"
+	"strings"
+)
+
+// Normalize rewrites the spec in place into a canonical form, so that specs
+// which only differ in ways the specification deems irrelevant serialize,
+// and thus hash, the same:
+//
+//   - parameters are sorted by location and name, references last;
+//   - the names of header parameters are lowercased, as header names are
+//     case insensitive;
+//   - required properties are sorted and deduplicated;
+//   - duplicated media types of consumes and produces are dropped, keeping
+//     the first occurrence since their order is a preference.
+//
+// Paths, methods, definitions and status codes are maps or fields, which
+// CanonicalJSON already serializes in a stable order. Use DeepCopy first to
+// keep the original spec.
+func (s *Swagger) Normalize() {
+	s.Consumes = dedupStrings(s.Consumes)
+	s.Produces = dedupStrings(s.Produces)
+	for k, def := range s.Definitions {
+		normalizeSchema(&def)
+		s.Definitions[k] = def
+	}
+	for k, p := range s.Parameters {
+		normalizeParameter(&p)
+		s.Parameters[k] = p
+	}
+	for k, r := range s.Responses {
+		normalizeResponse(&r)
+		s.Responses[k] = r
+	}
+	if s.Paths == nil {
+		return
+	}
+	for k, item := range s.Paths.Paths {
+		normalizeParameters(item.Parameters)
+		for _, m := range item.Operations() {
+			normalizeOperation(m.Operation)
+		}
+		s.Paths.Paths[k] = item
+	}
+}
+
+func normalizeOperation(op *Operation) {
+	op.Consumes = dedupStrings(op.Consumes)
+	op.Produces = dedupStrings(op.Produces)
+	normalizeParameters(op.Parameters)
+	if op.Responses == nil {
+		return
+	}
+	if op.Responses.Default != nil {
+		normalizeResponse(op.Responses.Default)
+	}
+	for code, r := range op.Responses.StatusCodeResponses {
+		normalizeResponse(&r)
+		op.Responses.StatusCodeResponses[code] = r
+	}
+}
+
+func normalizeParameters(l []Parameter) {
+	for i := range l {
+		normalizeParameter(&l[i])
+	}
+	sort.SliceStable(l, func(i, j int) bool {
+		return parameterKey(&l[i]) < parameterKey(&l[j])
+	})
+}
+
+// parameterKey orders parameters by location and name. References sort
+// after the other parameters since "~" is after the lowercase locations.
+func parameterKey(p *Parameter) string {
+	if ref := p.Ref.String(); ref != "" {
+		return "~" + ref
+	}
+	return p.In + "/" + p.Name
+}
+
+func normalizeParameter(p *Parameter) {
+	if p.In == "header" {
+		p.Name = strings.ToLower(p.Name)
+	}
+	normalizeSchema(p.Schema)
+}
+
+func normalizeResponse(r *Response) {
+	normalizeSchema(r.Schema)
+}
+
+func normalizeSchema(s *Schema) {
+	RewriteSchema(s, "", func(s *Schema, _ string) {
+		if len(s.Required) > 1 {
+			sort.Strings(s.Required)
+			s.Required = dedupSorted(s.Required)
+		}
+	})
+}
+
+// dedupStrings drops the repeated strings of l, keeping the first
+// occurrence of each.
+func dedupStrings(l []string) []string {
+	if len(l) < 2 {
+		return l
+	}
+	seen := make(map[string]bool, len(l))
+	ret := l[:0]
+	for _, s := range l {
+		if !seen[s] {
+			seen[s] = true
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// dedupSorted drops the repeated strings of a sorted slice.
+func dedupSorted(l []string) []string {
+	ret := l[:1]
+	for _, s := range l[1:] {
+		if s != ret[len(ret)-1] {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
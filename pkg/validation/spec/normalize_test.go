@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwaggerNormalize(t *testing.T) {
+	parse := func(s string) *Swagger {
+		sw := &Swagger{}
+		require.NoError(t, json.Unmarshal([]byte(s), sw))
+		return sw
+	}
+	a := parse(`{
+  "swagger": "2.0",
+  "produces": ["application/json", "application/yaml", "application/json"],
+  "paths": {
+    "/pets": {
+      "parameters": [{"$ref": "#/parameters/limit"}, {"name": "X-Request-ID", "in": "header", "type": "string"}],
+      "post": {
+        "consumes": ["application/json", "application/json"],
+        "parameters": [
+          {"name": "body", "in": "body", "schema": {"type": "object", "required": ["name", "age", "name"]}},
+          {"name": "dry", "in": "query", "type": "boolean"}
+        ],
+        "responses": {"200": {"description": "ok", "schema": {"items": {"required": ["b", "a"]}}}}
+      }
+    }
+  },
+  "definitions": {"Pet": {"required": ["z", "y"], "properties": {"tag": {"required": ["k", "j"]}}}}
+}`)
+	b := parse(`{
+  "swagger": "2.0",
+  "produces": ["application/json", "application/yaml"],
+  "paths": {
+    "/pets": {
+      "parameters": [{"name": "x-request-id", "in": "header", "type": "string"}, {"$ref": "#/parameters/limit"}],
+      "post": {
+        "consumes": ["application/json"],
+        "parameters": [
+          {"name": "dry", "in": "query", "type": "boolean"},
+          {"name": "body", "in": "body", "schema": {"type": "object", "required": ["age", "name"]}}
+        ],
+        "responses": {"200": {"description": "ok", "schema": {"items": {"required": ["a", "b"]}}}}
+      }
+    }
+  },
+  "definitions": {"Pet": {"required": ["y", "z"], "properties": {"tag": {"required": ["j", "k"]}}}}
+}`)
+	ha, err := a.Hash()
+	require.NoError(t, err)
+	hb, err := b.Hash()
+	require.NoError(t, err)
+	assert.NotEqual(t, ha, hb)
+
+	a.Normalize()
+	b.Normalize()
+	ha, err = a.Hash()
+	require.NoError(t, err)
+	hb, err = b.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, ha, hb)
+
+	assert.Equal(t, []string{"application/json", "application/yaml"}, a.Produces)
+	item := a.Paths.Paths["/pets"]
+	assert.Equal(t, "x-request-id", item.Parameters[0].Name)
+	assert.Equal(t, "#/parameters/limit", item.Parameters[1].Ref.String())
+	assert.Equal(t, "body", item.Post.Parameters[0].Name)
+	assert.Equal(t, []string{"age", "name"}, item.Post.Parameters[0].Schema.Required)
+	assert.Equal(t, []string{"y", "z"}, a.Definitions["Pet"].Required)
+
+	again := a.DeepCopy()
+	again.Normalize()
+	assert.Equal(t, a, again, "normalizing is idempotent")
+}
@@ -16,6 +16,7 @@ package spec
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/go-openapi/swag"
 )
@@ -32,6 +33,73 @@ type PathItemProps struct {
 	Parameters []Parameter `json:"parameters,omitempty"`
 }
 
+// MethodOperation is an operation of a path item with its uppercase HTTP
+// method, e.g. "GET".
+type MethodOperation struct {
+	Method    string
+	Operation *Operation
+}
+
+// Operations returns the operations of the path item in the order GET, PUT,
+// POST, DELETE, OPTIONS, HEAD, PATCH.
+func (p *PathItemProps) Operations() []MethodOperation {
+	var ret []MethodOperation
+	for _, m := range []MethodOperation{
+		{"GET", p.Get},
+		{"PUT", p.Put},
+		{"POST", p.Post},
+		{"DELETE", p.Delete},
+		{"OPTIONS", p.Options},
+		{"HEAD", p.Head},
+		{"PATCH", p.Patch},
+	} {
+		if m.Operation != nil {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+// Operation returns the operation of an HTTP method, case insensitive, or
+// nil.
+func (p *PathItemProps) Operation(method string) *Operation {
+	if field := p.operationField(method); field != nil {
+		return *field
+	}
+	return nil
+}
+
+// SetOperation sets the operation of an HTTP method, case insensitive. It
+// returns false if path items have no operation for the method.
+func (p *PathItemProps) SetOperation(method string, op *Operation) bool {
+	field := p.operationField(method)
+	if field == nil {
+		return false
+	}
+	*field = op
+	return true
+}
+
+func (p *PathItemProps) operationField(method string) **Operation {
+	switch strings.ToUpper(method) {
+	case "GET":
+		return &p.Get
+	case "PUT":
+		return &p.Put
+	case "POST":
+		return &p.Post
+	case "DELETE":
+		return &p.Delete
+	case "OPTIONS":
+		return &p.Options
+	case "HEAD":
+		return &p.Head
+	case "PATCH":
+		return &p.Patch
+	}
+	return nil
+}
+
 // PathItem describes the operations available on a single path.
 // A Path Item may be empty, due to [ACL constraints](http://goo.gl/8us55a#securityFiltering).
 // The path itself is still exposed to the documentation viewer but they will
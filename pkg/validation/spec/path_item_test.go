@@ -79,3 +79,18 @@ func TestIntegrationPathItem(t *testing.T) {
 
 	assertParsesJSON(t, pathItemJSON, pathItem)
 }
+
+func TestPathItemOperations(t *testing.T) {
+	get, patch := &Operation{}, &Operation{}
+	item := PathItem{PathItemProps: PathItemProps{Patch: patch, Get: get}}
+	assert.Equal(t, []MethodOperation{{"GET", get}, {"PATCH", patch}}, item.Operations())
+
+	assert.Equal(t, get, item.Operation("get"))
+	assert.Nil(t, item.Operation("POST"))
+	assert.Nil(t, item.Operation("TRACE"))
+
+	post := &Operation{}
+	assert.True(t, item.SetOperation("post", post))
+	assert.Equal(t, post, item.Post)
+	assert.False(t, item.SetOperation("TRACE", post))
+}
@@ -468,6 +468,12 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 	props := struct {
 		SchemaProps
 		SwaggerSchemaProps
+		// JSON Schema 2020-12, as used by OpenAPI 3.1, makes the exclusive
+		// bounds numbers instead of flags on maximum and minimum.
+		ExclusiveMaximum json.RawMessage `json:"exclusiveMaximum,omitempty"`
+		ExclusiveMinimum json.RawMessage `json:"exclusiveMinimum,omitempty"`
+		// OpenAPI 3 discriminators are objects, which are kept in ExtraProps.
+		Discriminator json.RawMessage `json:"discriminator,omitempty"`
 	}{}
 	if err := json.Unmarshal(data, &props); err != nil {
 		return err
@@ -477,6 +483,17 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 		SchemaProps:        props.SchemaProps,
 		SwaggerSchemaProps: props.SwaggerSchemaProps,
 	}
+	if err := exclusiveBound(props.ExclusiveMaximum, &sch.Maximum, &sch.ExclusiveMaximum, func(a, b float64) bool { return a < b }); err != nil {
+		return fmt.Errorf("exclusiveMaximum: %v", err)
+	}
+	if err := exclusiveBound(props.ExclusiveMinimum, &sch.Minimum, &sch.ExclusiveMinimum, func(a, b float64) bool { return a > b }); err != nil {
+		return fmt.Errorf("exclusiveMinimum: %v", err)
+	}
+	if len(props.Discriminator) > 0 && props.Discriminator[0] == '"' {
+		if err := json.Unmarshal(props.Discriminator, &sch.Discriminator); err != nil {
+			return err
+		}
+	}
 
 	var d map[string]interface{}
 	if err := json.Unmarshal(data, &d); err != nil {
@@ -486,11 +503,15 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 	_ = sch.Ref.fromMap(d)
 	_ = sch.Schema.fromMap(d)
 
+	discriminator, objectDiscriminator := d["discriminator"].(map[string]interface{})
 	delete(d, "$ref")
 	delete(d, "$schema")
 	for _, pn := range swag.DefaultJSONNameProvider.GetJSONNames(s) {
 		delete(d, pn)
 	}
+	if objectDiscriminator {
+		d["discriminator"] = discriminator
+	}
 
 	for k, vv := range d {
 		lk := strings.ToLower(k)
@@ -511,3 +532,25 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 
 	return nil
 }
+
+// exclusiveBound decodes an exclusiveMaximum or exclusiveMinimum keyword,
+// either a draft 4 flag or a JSON Schema 2020-12 number. A number becomes the
+// exclusive bound unless the inclusive bound is stricter, i.e. lower for a
+// maximum and higher for a minimum as told by stricter.
+func exclusiveBound(raw json.RawMessage, bound **float64, exclusive *bool, stricter func(a, b float64) bool) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if raw[0] == 't' || raw[0] == 'f' {
+		return json.Unmarshal(raw, exclusive)
+	}
+	var n float64
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return err
+	}
+	if *bound == nil || !stricter(**bound, n) {
+		*bound = &n
+		*exclusive = true
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var schema = Schema{
@@ -201,3 +202,57 @@ func BenchmarkSchemaUnmarshal(b *testing.B) {
 		_ = sch.UnmarshalJSON([]byte(schemaJSON))
 	}
 }
+
+func TestSchemaJSONSchema2020(t *testing.T) {
+	var s Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": ["string", "null"],
+		"maximum": 3,
+		"exclusiveMaximum": 10,
+		"exclusiveMinimum": 1,
+		"const": "a",
+		"examples": ["a"],
+		"$defs": {"name": {"type": "string"}}
+	}`), &s))
+	assert.Equal(t, StringOrArray{"string", "null"}, s.Type)
+	// The inclusive maximum is stricter than the exclusive one.
+	assert.Equal(t, 3.0, *s.Maximum)
+	assert.False(t, s.ExclusiveMaximum)
+	assert.Equal(t, 1.0, *s.Minimum)
+	assert.True(t, s.ExclusiveMinimum)
+	assert.Equal(t, map[string]interface{}{
+		"const":    "a",
+		"examples": []interface{}{"a"},
+		"$defs":    map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+	}, s.ExtraProps)
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": ["string", "null"],
+		"maximum": 3,
+		"minimum": 1,
+		"exclusiveMinimum": true,
+		"const": "a",
+		"examples": ["a"],
+		"$defs": {"name": {"type": "string"}}
+	}`, string(data))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"maximum": 5, "exclusiveMaximum": true}`), &s))
+	assert.True(t, s.ExclusiveMaximum)
+	assert.Error(t, json.Unmarshal([]byte(`{"exclusiveMaximum": "5"}`), &s))
+}
+
+func TestSchemaDiscriminatorObject(t *testing.T) {
+	var s Schema
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "object", "discriminator": {"propertyName": "kind"}}`), &s))
+	assert.Empty(t, s.Discriminator)
+	assert.Equal(t, map[string]interface{}{"propertyName": "kind"}, s.ExtraProps["discriminator"])
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "object", "discriminator": {"propertyName": "kind"}}`, string(data))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"discriminator": "kind"}`), &s))
+	assert.Equal(t, "kind", s.Discriminator)
+	assert.Nil(t, s.ExtraProps)
+}
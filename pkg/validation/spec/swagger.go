@@ -15,6 +15,7 @@
 package spec
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 
@@ -140,13 +141,21 @@ func (s SchemaOrStringArray) MarshalJSON() ([]byte, error) {
 	return []byte("null"), nil
 }
 
-// UnmarshalJSON converts this schema object or array from a JSON structure
+// UnmarshalJSON converts this schema object or array from a JSON structure.
+// The boolean schemas of draft 6 and later are converted to the equivalent
+// schemas: true accepts any value and false none.
 func (s *SchemaOrStringArray) UnmarshalJSON(data []byte) error {
 	var first byte
 	if len(data) > 1 {
 		first = data[0]
 	}
 	var nw SchemaOrStringArray
+	switch string(bytes.TrimSpace(data)) {
+	case "true":
+		nw.Schema = &Schema{}
+	case "false":
+		nw.Schema = &Schema{SchemaProps: SchemaProps{Not: &Schema{}}}
+	}
 	if first == '{' {
 		var sch Schema
 		if err := json.Unmarshal(data, &sch); err != nil {
@@ -177,6 +186,25 @@ type Definitions map[string]Schema
 // For more information: http://goo.gl/8us55a#securityDefinitionsObject
 type SecurityDefinitions map[string]*SecurityScheme
 
+// AddSecurityDefinition declares a security scheme under the given name, to
+// be referenced by security requirements, e.g. from Operation.WithSecurity.
+func (s *Swagger) AddSecurityDefinition(name string, scheme *SecurityScheme) *Swagger {
+	if s.SecurityDefinitions == nil {
+		s.SecurityDefinitions = SecurityDefinitions{}
+	}
+	s.SecurityDefinitions[name] = scheme
+	return s
+}
+
+// AddDefinition adds a schema to the definitions of the spec.
+func (s *Swagger) AddDefinition(name string, schema Schema) *Swagger {
+	if s.Definitions == nil {
+		s.Definitions = Definitions{}
+	}
+	s.Definitions[name] = schema
+	return s
+}
+
 // StringOrArray represents a value that can either be a string
 // or an array of strings. Mainly here for serialization purposes
 type StringOrArray []string
@@ -126,3 +126,14 @@ func TestSwaggerSpec_Deserialize(t *testing.T) {
 		assert.EqualValues(t, actual, spec)
 	}
 }
+
+func TestSchemaOrStringArray_Deserialize(t *testing.T) {
+	var deps Dependencies
+	err := json.Unmarshal([]byte(`{"a": ["b"], "c": {"required": ["d"]}, "e": true, "f": false}`), &deps)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"b"}, deps["a"].Property)
+		assert.Equal(t, []string{"d"}, deps["c"].Schema.Required)
+		assert.Equal(t, &Schema{}, deps["e"].Schema)
+		assert.Equal(t, &Schema{SchemaProps: SchemaProps{Not: &Schema{}}}, deps["f"].Schema)
+	}
+}
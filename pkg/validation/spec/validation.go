@@ -0,0 +1,446 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+)
+
+// The Validate methods check the structural correctness of spec objects
+// themselves, as opposed to the validate package which checks values against
+// a schema. They return nil when the object is valid, or an
+// *errors.CompositeError whose Errors are *errors.Validation values with the
+// InvalidSpecCode, named after the location of the offending object.
+
+var (
+	parameterLocations = map[string]bool{"query": true, "header": true, "path": true, "formData": true, "body": true}
+	simpleTypes        = map[string]bool{"string": true, "number": true, "integer": true, "boolean": true, "array": true, "file": true}
+	schemaTypes        = map[string]bool{"string": true, "number": true, "integer": true, "boolean": true, "array": true, "object": true, "null": true, "file": true}
+	collectionFormats  = map[string]bool{"": true, "csv": true, "ssv": true, "tsv": true, "pipes": true, "multi": true}
+	pathParamRegexp    = regexp.MustCompile(`{([^{}]+)}`)
+)
+
+type specErrors []error
+
+func (e *specErrors) add(name, in, format string, args ...interface{}) {
+	*e = append(*e, errors.InvalidSpec(name, in, fmt.Sprintf(format, args...)))
+}
+
+func (e specErrors) asError() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return errors.CompositeValidationError(e...)
+}
+
+// Validate checks the structure of the parameter, e.g. body parameters must
+// have a schema and path parameters must be required.
+func (p *Parameter) Validate() error {
+	var errs specErrors
+	p.validate(p.Name, &errs)
+	return errs.asError()
+}
+
+func (p *Parameter) validate(name string, errs *specErrors) {
+	if p.Ref.String() != "" {
+		return
+	}
+	if p.Name == "" {
+		errs.add(name, p.In, "name is required")
+	}
+	if !parameterLocations[p.In] {
+		errs.add(name, p.In, "in must be one of query, header, path, formData or body")
+		return
+	}
+	if p.In == "body" {
+		if p.Schema == nil {
+			errs.add(name, p.In, "body parameters must have a schema")
+		} else {
+			p.Schema.validate(name+".schema", errs)
+		}
+		if p.Type != "" {
+			errs.add(name, p.In, "body parameters can't have a type, use the schema")
+		}
+		return
+	}
+	if p.Schema != nil {
+		errs.add(name, p.In, "only body parameters can have a schema")
+	}
+	if p.In == "path" && !p.Required {
+		errs.add(name, p.In, "path parameters must be required")
+	}
+	if p.AllowEmptyValue && p.In != "query" && p.In != "formData" {
+		errs.add(name, p.In, "allowEmptyValue is only allowed in query and formData parameters")
+	}
+	if p.Type == "file" && p.In != "formData" {
+		errs.add(name, p.In, "file parameters must be in formData")
+	}
+	validateSimpleSchema(name, p.In, &p.SimpleSchema, &p.CommonValidations, p.In == "query" || p.In == "formData", errs)
+}
+
+// Validate checks the structure of the header.
+func (h *Header) Validate() error {
+	var errs specErrors
+	validateSimpleSchema("header", "", &h.SimpleSchema, &h.CommonValidations, false, &errs)
+	return errs.asError()
+}
+
+func validateSimpleSchema(name, in string, s *SimpleSchema, v *CommonValidations, allowMulti bool, errs *specErrors) {
+	if s.Type == "" {
+		errs.add(name, in, "type is required")
+	} else if !simpleTypes[s.Type] {
+		errs.add(name, in, "type %q is not one of string, number, integer, boolean, array or file", s.Type)
+	}
+	if !collectionFormats[s.CollectionFormat] {
+		errs.add(name, in, "collectionFormat %q is not one of csv, ssv, tsv, pipes or multi", s.CollectionFormat)
+	}
+	if s.CollectionFormat == "multi" && !allowMulti {
+		errs.add(name, in, "collectionFormat multi is only allowed in query and formData parameters")
+	}
+	if s.Type == "array" {
+		if s.Items == nil {
+			errs.add(name, in, "items is required for arrays")
+		} else {
+			validateItems(name+".items", s.Items, errs)
+		}
+	}
+	validateCommonValidations(name, in, v, errs)
+}
+
+func validateItems(name string, items *Items, errs *specErrors) {
+	if items.Ref.String() != "" {
+		return
+	}
+	// Items of nested arrays can't use multi, only the outer parameter can.
+	validateSimpleSchema(name, "", &items.SimpleSchema, &items.CommonValidations, false, errs)
+	if items.Type == "file" {
+		errs.add(name, "", "items can't be files")
+	}
+}
+
+func validateCommonValidations(name, in string, v *CommonValidations, errs *specErrors) {
+	validateBounds(name, in, v.Minimum, v.Maximum, v.MinLength, v.MaxLength, v.MinItems, v.MaxItems, v.MultipleOf, v.Pattern, errs)
+}
+
+func validateBounds(name, in string, min, max *float64, minLength, maxLength, minItems, maxItems *int64, multipleOf *float64, pattern string, errs *specErrors) {
+	if min != nil && max != nil && *min > *max {
+		errs.add(name, in, "minimum %v is greater than maximum %v", *min, *max)
+	}
+	if minLength != nil && maxLength != nil && *minLength > *maxLength {
+		errs.add(name, in, "minLength %d is greater than maxLength %d", *minLength, *maxLength)
+	}
+	if minItems != nil && maxItems != nil && *minItems > *maxItems {
+		errs.add(name, in, "minItems %d is greater than maxItems %d", *minItems, *maxItems)
+	}
+	for _, n := range []struct {
+		what  string
+		value *int64
+	}{{"minLength", minLength}, {"maxLength", maxLength}, {"minItems", minItems}, {"maxItems", maxItems}} {
+		if n.value != nil && *n.value < 0 {
+			errs.add(name, in, "%s can't be negative", n.what)
+		}
+	}
+	if multipleOf != nil && *multipleOf <= 0 {
+		errs.add(name, in, "multipleOf must be positive")
+	}
+	if pattern != "" {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs.add(name, in, "pattern %q doesn't compile: %v", pattern, err)
+		}
+	}
+}
+
+// Validate checks the structure of the schema and of its subschemas, e.g.
+// its types, patterns and bounds.
+func (s *Schema) Validate() error {
+	var errs specErrors
+	s.validate("schema", &errs)
+	return errs.asError()
+}
+
+func (s *Schema) validate(name string, errs *specErrors) {
+	WalkSchema(s, "", func(sub *Schema, pointer string) {
+		if sub.Ref.String() == "" {
+			sub.validateKeywords(name+strings.Replace(pointer, "/", ".", -1), errs)
+		}
+	})
+}
+
+// validateKeywords validates the keywords of a schema, not its subschemas.
+func (s *Schema) validateKeywords(name string, errs *specErrors) {
+	for _, t := range s.Type {
+		if !schemaTypes[t] {
+			errs.add(name, "", "type %q is not a valid type", t)
+		}
+	}
+	validateBounds(name, "", s.Minimum, s.Maximum, s.MinLength, s.MaxLength, s.MinItems, s.MaxItems, s.MultipleOf, s.Pattern, errs)
+	if s.MinProperties != nil && s.MaxProperties != nil && *s.MinProperties > *s.MaxProperties {
+		errs.add(name, "", "minProperties %d is greater than maxProperties %d", *s.MinProperties, *s.MaxProperties)
+	}
+	for _, k := range sortedSchemaKeys(s.PatternProperties) {
+		if _, err := regexp.Compile(k); err != nil {
+			errs.add(name+".patternProperties", "", "pattern %q doesn't compile: %v", k, err)
+		}
+	}
+}
+
+func sortedSchemaKeys(m map[string]Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Validate checks the structure of the operation: its parameters, which must
+// be unique, and its responses. Referenced parameters can't be resolved
+// without the spec and are left out of the checks between parameters.
+func (o *Operation) Validate() error {
+	var errs specErrors
+	name := o.ID
+	if name == "" {
+		name = "operation"
+	}
+	o.validate(name, nil, nil, &errs)
+	return errs.asError()
+}
+
+// validate checks the operation together with the parameters of its path
+// item, which it inherits. Referenced parameters are resolved against defs,
+// the spec parameters, when possible.
+func (o *Operation) validate(name string, common []Parameter, defs map[string]Parameter, errs *specErrors) {
+	seen := map[string]bool{}
+	bodies, forms := 0, 0
+	params, _ := resolveParameters(o.Parameters, defs)
+	common, _ = resolveParameters(common, defs)
+	for i := range o.Parameters {
+		pName := fmt.Sprintf("%s.parameters[%d]", name, i)
+		o.Parameters[i].validate(pName, errs)
+		p := &params[i]
+		if p.Ref.String() != "" {
+			continue
+		}
+		key := p.In + "/" + p.Name
+		if seen[key] {
+			errs.add(pName, p.In, "duplicate parameter %q", p.Name)
+		}
+		seen[key] = true
+	}
+	for _, p := range effectiveParameters(common, params) {
+		switch p.In {
+		case "body":
+			bodies++
+		case "formData":
+			forms++
+		}
+	}
+	if bodies > 1 {
+		errs.add(name, "", "there can be at most one body parameter")
+	}
+	if bodies > 0 && forms > 0 {
+		errs.add(name, "", "body and formData parameters can't be used together")
+	}
+
+	if o.Responses == nil || (o.Responses.Default == nil && len(o.Responses.StatusCodeResponses) == 0) {
+		errs.add(name, "", "at least one response is required")
+		return
+	}
+	if o.Responses.Default != nil {
+		o.Responses.Default.validate(name+".responses.default", errs)
+	}
+	codes := make([]int, 0, len(o.Responses.StatusCodeResponses))
+	for code := range o.Responses.StatusCodeResponses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		r := o.Responses.StatusCodeResponses[code]
+		rName := fmt.Sprintf("%s.responses.%d", name, code)
+		if code < 100 || code > 599 {
+			errs.add(rName, "", "%d is not a valid status code", code)
+		}
+		r.validate(rName, errs)
+	}
+}
+
+// effectiveParameters returns the path item parameters not overridden by the
+// operation, followed by the operation parameters.
+func effectiveParameters(common, params []Parameter) []Parameter {
+	overridden := map[string]bool{}
+	for _, p := range params {
+		overridden[p.In+"/"+p.Name] = true
+	}
+	var ret []Parameter
+	for _, p := range common {
+		if !overridden[p.In+"/"+p.Name] {
+			ret = append(ret, p)
+		}
+	}
+	return append(ret, params...)
+}
+
+// resolveParameters returns the parameters with their references to defs,
+// the spec parameters, followed, and whether all of them were. Parameters
+// that can't be resolved are returned as is.
+func resolveParameters(params []Parameter, defs map[string]Parameter) ([]Parameter, bool) {
+	const prefix = "#/parameters/"
+	ret := make([]Parameter, len(params))
+	all := true
+	for i, p := range params {
+		if ref := p.Ref.String(); ref != "" {
+			if resolved, ok := defs[strings.TrimPrefix(ref, prefix)]; ok && strings.HasPrefix(ref, prefix) {
+				p = resolved
+			} else {
+				all = false
+			}
+		}
+		ret[i] = p
+	}
+	return ret, all
+}
+
+func (r *Response) validate(name string, errs *specErrors) {
+	if r.Ref.String() != "" {
+		return
+	}
+	if r.Description == "" {
+		errs.add(name, "", "description is required")
+	}
+	if r.Schema != nil {
+		r.Schema.validate(name+".schema", errs)
+	}
+	for _, h := range sortedHeaderKeys(r.Headers) {
+		header := r.Headers[h]
+		validateSimpleSchema(name+".headers."+h, "", &header.SimpleSchema, &header.CommonValidations, false, errs)
+	}
+}
+
+func sortedHeaderKeys(m map[string]Header) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Validate checks the structure of the whole spec: its version, paths,
+// operations, which must have unique IDs and declare every path template
+// parameter, and definitions.
+func (s *Swagger) Validate() error {
+	var errs specErrors
+	if s.Swagger != "2.0" {
+		errs.add("swagger", "", "version must be \"2.0\", got %q", s.Swagger)
+	}
+	if s.Info == nil || s.Info.Title == "" || s.Info.Version == "" {
+		errs.add("info", "", "title and version are required")
+	}
+
+	ids := map[string]string{}
+	if s.Paths == nil {
+		errs.add("paths", "", "paths is required")
+	} else {
+		paths := make([]string, 0, len(s.Paths.Paths))
+		for p := range s.Paths.Paths {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			item := s.Paths.Paths[path]
+			name := "paths." + path
+			if !strings.HasPrefix(path, "/") {
+				errs.add(name, "", "paths must start with a slash")
+			}
+			for i := range item.Parameters {
+				item.Parameters[i].validate(fmt.Sprintf("%s.parameters[%d]", name, i), &errs)
+			}
+			for _, m := range item.Operations() {
+				opName := name + "." + strings.ToLower(m.Method)
+				m.Operation.validate(opName, item.Parameters, s.Parameters, &errs)
+				if id := m.Operation.ID; id != "" {
+					if other, ok := ids[id]; ok {
+						errs.add(opName, "", "operationId %q is already used by %s", id, other)
+					} else {
+						ids[id] = opName
+					}
+				}
+				s.validatePathParams(opName, path, item.Parameters, m.Operation.Parameters, &errs)
+			}
+		}
+	}
+
+	for _, k := range sortedSchemaKeys(s.Definitions) {
+		def := s.Definitions[k]
+		def.validate("definitions."+k, &errs)
+	}
+	params := make([]string, 0, len(s.Parameters))
+	for k := range s.Parameters {
+		params = append(params, k)
+	}
+	sort.Strings(params)
+	for _, k := range params {
+		p := s.Parameters[k]
+		p.validate("parameters."+k, &errs)
+	}
+	return errs.asError()
+}
+
+// validatePathParams checks that the parameters in the path template and the
+// path parameters of an operation, inheriting the common ones of its path
+// item, match. Referenced parameters are resolved against the spec
+// parameters.
+func (s *Swagger) validatePathParams(name, path string, common, params []Parameter, errs *specErrors) {
+	common, commonResolved := resolveParameters(common, s.Parameters)
+	params, resolved := resolveParameters(params, s.Parameters)
+	if !commonResolved || !resolved {
+		// unknown reference, the template can't be checked
+		return
+	}
+	declared := map[string]bool{}
+	for _, p := range effectiveParameters(common, params) {
+		if p.In == "path" {
+			declared[p.Name] = true
+		}
+	}
+	inTemplate := map[string]bool{}
+	for _, m := range pathParamRegexp.FindAllStringSubmatch(path, -1) {
+		inTemplate[m[1]] = true
+		if !declared[m[1]] {
+			errs.add(name, "", "path parameter %q is not declared", m[1])
+		}
+	}
+	for _, p := range sortedKeys(declared) {
+		if !inTemplate[p] {
+			errs.add(name, "", "path parameter %q is not in the path", p)
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+)
+
+func validationMessages(t *testing.T, err error) []string {
+	if err == nil {
+		return nil
+	}
+	c, ok := err.(*errors.CompositeError)
+	require.True(t, ok, "expected a composite error, got %T", err)
+	var ret []string
+	for _, e := range c.Errors {
+		v, ok := e.(*errors.Validation)
+		require.True(t, ok, "expected a validation error, got %T", e)
+		assert.EqualValues(t, errors.InvalidSpecCode, v.Code())
+		ret = append(ret, v.Error())
+	}
+	return ret
+}
+
+func TestParameterValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		param *Parameter
+		want  []string
+	}{
+		{"valid query", QueryParam("q").Typed("string", ""), nil},
+		{"ref", ParamRef("#/parameters/x"), nil},
+		{"body without schema", BodyParam("b", nil), []string{"b in body is invalid: body parameters must have a schema"}},
+		{"optional path", PathParam("id").Typed("string", "").AsOptional(), []string{"id in path is invalid: path parameters must be required"}},
+		{"multi in header", HeaderParam("h").CollectionOf(NewItems().Typed("string", ""), "multi"), []string{
+			"h in header is invalid: collectionFormat multi is only allowed in query and formData parameters",
+		}},
+		{"multi in query", QueryParam("q").CollectionOf(NewItems().Typed("string", ""), "multi"), nil},
+		{"array without items", QueryParam("q").Typed("array", ""), []string{"q in query is invalid: items is required for arrays"}},
+		{"missing type", QueryParam("q"), []string{"q in query is invalid: type is required"}},
+		{"file in query", QueryParam("f").Typed("file", ""), []string{"f in query is invalid: file parameters must be in formData"}},
+		{"bad location", (&Parameter{ParamProps: ParamProps{Name: "x", In: "cookie"}}), []string{
+			"x in cookie is invalid: in must be one of query, header, path, formData or body",
+		}},
+		{"bounds", QueryParam("n").Typed("integer", "").WithMinimum(5, false).WithMaximum(1, false).WithPattern("("), []string{
+			"n in query is invalid: minimum 5 is greater than maximum 1",
+			"n in query is invalid: pattern \"(\" doesn't compile: error parsing regexp: missing closing ): `(`",
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, validationMessages(t, tc.param.Validate()))
+		})
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	assert.NoError(t, schema.Validate())
+
+	s := new(Schema).Typed("object", "").
+		SetProperty("name", *new(Schema).Typed("strin", "")).
+		SetProperty("tags", *ArrayProperty(new(Schema).WithMinLength(3).WithMaxLength(1)))
+	s.MultipleOf = float64Ptr(0)
+	assert.Equal(t, []string{
+		"schema is invalid: multipleOf must be positive",
+		"schema.properties.name is invalid: type \"strin\" is not a valid type",
+		"schema.properties.tags.items is invalid: minLength 3 is greater than maxLength 1",
+	}, validationMessages(t, s.Validate()))
+}
+
+func TestOperationValidate(t *testing.T) {
+	op := NewOperation("op").
+		AddParam(BodyParam("a", RefSchema("#/definitions/A"))).
+		AddParam(FormDataParam("f").Typed("string", ""))
+	op.Parameters = append(op.Parameters, *BodyParam("a", StringProperty()))
+	assert.Equal(t, []string{
+		"op.parameters[2] in body is invalid: duplicate parameter \"a\"",
+		"op is invalid: there can be at most one body parameter",
+		"op is invalid: body and formData parameters can't be used together",
+		"op is invalid: at least one response is required",
+	}, validationMessages(t, op.Validate()))
+
+	op = NewOperation("op").RespondsWith(200, NewResponse()).WithDefaultResponse(ResponseRef("#/responses/E"))
+	assert.Equal(t, []string{"op.responses.200 is invalid: description is required"}, validationMessages(t, op.Validate()))
+}
+
+func TestSwaggerValidate(t *testing.T) {
+	var s Swagger
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "t", "version": "v"},
+		"parameters": {
+			"id": {"name": "id", "in": "path", "required": true, "type": "string"},
+			"body": {"name": "body", "in": "body", "schema": {"type": "object"}},
+			"other": {"name": "other", "in": "body", "schema": {"type": "object"}}
+		},
+		"paths": {
+			"/a/{id}": {
+				"get": {"operationId": "get", "parameters": [{"$ref": "#/parameters/id"}], "responses": {"200": {"description": "ok"}}},
+				"put": {"operationId": "get", "responses": {"200": {"description": "ok"}}}
+			},
+			"/b/{name}": {
+				"parameters": [{"name": "other", "in": "path", "required": true, "type": "string"}],
+				"post": {"responses": {"default": {"description": "ok"}}}
+			},
+			"/d": {
+				"parameters": [{"$ref": "#/parameters/body"}],
+				"post": {"parameters": [{"name": "f", "in": "formData", "type": "string"}], "responses": {"200": {"description": "ok"}}},
+				"put": {"parameters": [{"$ref": "#/parameters/other"}], "responses": {"200": {"description": "ok"}}}
+			}
+		},
+		"definitions": {"A": {"type": "objet"}}
+	}`), &s))
+	s.Paths.Paths["c"] = PathItem{}
+	assert.Equal(t, []string{
+		"paths./a/{id}.put is invalid: operationId \"get\" is already used by paths./a/{id}.get",
+		"paths./a/{id}.put is invalid: path parameter \"id\" is not declared",
+		"paths./b/{name}.post is invalid: path parameter \"name\" is not declared",
+		"paths./b/{name}.post is invalid: path parameter \"other\" is not in the path",
+		"paths./d.put is invalid: there can be at most one body parameter",
+		"paths./d.post is invalid: body and formData parameters can't be used together",
+		"paths.c is invalid: paths must start with a slash",
+		"definitions.A is invalid: type \"objet\" is not a valid type",
+	}, validationMessages(t, s.Validate()))
+
+	assert.Equal(t, []string{
+		"swagger is invalid: version must be \"2.0\", got \"\"",
+		"info is invalid: title and version are required",
+		"paths is invalid: paths is required",
+	}, validationMessages(t, (&Swagger{}).Validate()))
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+)
+
+// EachSubschema calls fn with the direct subschemas of s and their JSON
+// pointers relative to s, e.g. "/properties/name" or "/allOf/0", in a
+// stable order. References are not followed. The subschemas held in maps
+// are passed as copies, so changes fn makes to them are lost; see
+// RewriteSubschemas.
+func EachSubschema(s *Schema, fn func(sub *Schema, pointer string)) {
+	subschemas(s, false, fn)
+}
+
+// RewriteSubschemas is like EachSubschema, but stores the subschemas held
+// in maps back after fn returns, so that fn may change any of them. It
+// writes to s and must not be used while s is read elsewhere.
+func RewriteSubschemas(s *Schema, fn func(sub *Schema, pointer string)) {
+	subschemas(s, true, fn)
+}
+
+// WalkSchema calls fn on s and on all of its subschemas, depth first, along
+// with their JSON pointers, pointer being the one of s. References are not
+// followed. fn must not change the schemas; see RewriteSchema.
+func WalkSchema(s *Schema, pointer string, fn func(s *Schema, pointer string)) {
+	walkSchema(s, pointer, false, fn)
+}
+
+// RewriteSchema is like WalkSchema, but keeps the changes fn makes to the
+// schemas. fn is called on a schema before its subschemas are walked, so it
+// may also replace them.
+func RewriteSchema(s *Schema, pointer string, fn func(s *Schema, pointer string)) {
+	walkSchema(s, pointer, true, fn)
+}
+
+func walkSchema(s *Schema, pointer string, store bool, fn func(s *Schema, pointer string)) {
+	if s == nil {
+		return
+	}
+	fn(s, pointer)
+	subschemas(s, store, func(sub *Schema, subpointer string) {
+		walkSchema(sub, pointer+subpointer, store, fn)
+	})
+}
+
+func subschemas(s *Schema, store bool, fn func(sub *Schema, pointer string)) {
+	for _, m := range []struct {
+		keyword string
+		schemas map[string]Schema
+	}{{"properties", s.Properties}, {"patternProperties", s.PatternProperties}, {"definitions", s.Definitions}} {
+		for _, k := range sortedSchemaKeys(m.schemas) {
+			sub := m.schemas[k]
+			fn(&sub, "/"+m.keyword+"/"+jsonpointer.Escape(k))
+			if store {
+				m.schemas[k] = sub
+			}
+		}
+	}
+	if s.Items != nil {
+		if s.Items.Schema != nil {
+			fn(s.Items.Schema, "/items")
+		}
+		for i := range s.Items.Schemas {
+			fn(&s.Items.Schemas[i], "/items/"+strconv.Itoa(i))
+		}
+	}
+	for _, l := range []struct {
+		keyword string
+		schemas []Schema
+	}{{"allOf", s.AllOf}, {"anyOf", s.AnyOf}, {"oneOf", s.OneOf}} {
+		for i := range l.schemas {
+			fn(&l.schemas[i], "/"+l.keyword+"/"+strconv.Itoa(i))
+		}
+	}
+	if s.Not != nil {
+		fn(s.Not, "/not")
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		fn(s.AdditionalProperties.Schema, "/additionalProperties")
+	}
+	if s.AdditionalItems != nil && s.AdditionalItems.Schema != nil {
+		fn(s.AdditionalItems.Schema, "/additionalItems")
+	}
+	keys := make([]string, 0, len(s.Dependencies))
+	for k := range s.Dependencies {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if dep := s.Dependencies[k]; dep.Schema != nil {
+			fn(dep.Schema, "/dependencies/"+jsonpointer.Escape(k))
+		}
+	}
+}
+
+// WalkRefs calls fn with each non-empty reference of the spec and the JSON
+// pointer of the object holding it, in a stable order: the references of
+// path items, parameters, their items, responses and of all schemas of the
+// spec. References are not followed, and fn must not change them.
+func (s *Swagger) WalkRefs(fn func(ref *Ref, pointer string)) {
+	visit := func(ref *Ref, pointer string) {
+		if ref.String() != "" {
+			fn(ref, pointer)
+		}
+	}
+	schema := func(sch *Schema, pointer string) {
+		WalkSchema(sch, pointer, func(sch *Schema, pointer string) {
+			visit(&sch.Ref, pointer)
+		})
+	}
+	var items func(it *Items, pointer string)
+	items = func(it *Items, pointer string) {
+		if it != nil {
+			visit(&it.Ref, pointer)
+			items(it.Items, pointer+"/items")
+		}
+	}
+	parameter := func(p *Parameter, pointer string) {
+		visit(&p.Ref, pointer)
+		schema(p.Schema, pointer+"/schema")
+		items(p.Items, pointer+"/items")
+	}
+	parameters := func(l []Parameter, pointer string) {
+		for i := range l {
+			parameter(&l[i], fmt.Sprintf("%s/parameters/%d", pointer, i))
+		}
+	}
+	response := func(r *Response, pointer string) {
+		visit(&r.Ref, pointer)
+		schema(r.Schema, pointer+"/schema")
+		keys := make([]string, 0, len(r.Headers))
+		for k := range r.Headers {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			h := r.Headers[k]
+			items(h.Items, pointer+"/headers/"+jsonpointer.Escape(k)+"/items")
+		}
+	}
+
+	if s.Paths != nil {
+		paths := make([]string, 0, len(s.Paths.Paths))
+		for p := range s.Paths.Paths {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			item := s.Paths.Paths[p]
+			pointer := "/paths/" + jsonpointer.Escape(p)
+			visit(&item.Ref, pointer)
+			parameters(item.Parameters, pointer)
+			for _, m := range item.Operations() {
+				opPointer := pointer + "/" + strings.ToLower(m.Method)
+				parameters(m.Operation.Parameters, opPointer)
+				if rs := m.Operation.Responses; rs != nil {
+					if rs.Default != nil {
+						response(rs.Default, opPointer+"/responses/default")
+					}
+					codes := make([]int, 0, len(rs.StatusCodeResponses))
+					for code := range rs.StatusCodeResponses {
+						codes = append(codes, code)
+					}
+					sort.Ints(codes)
+					for _, code := range codes {
+						r := rs.StatusCodeResponses[code]
+						response(&r, opPointer+"/responses/"+strconv.Itoa(code))
+					}
+				}
+			}
+		}
+	}
+	for _, k := range sortedSchemaKeys(s.Definitions) {
+		def := s.Definitions[k]
+		schema(&def, "/definitions/"+jsonpointer.Escape(k))
+	}
+	names := make([]string, 0, len(s.Parameters))
+	for k := range s.Parameters {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		p := s.Parameters[k]
+		parameter(&p, "/parameters/"+jsonpointer.Escape(k))
+	}
+	names = names[:0]
+	for k := range s.Responses {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		r := s.Responses[k]
+		response(&r, "/responses/"+jsonpointer.Escape(k))
+	}
+}
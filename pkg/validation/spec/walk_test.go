@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkSchema(t *testing.T) {
+	var s Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"properties": {"b": {"type": "string"}, "a/x": {"items": {"type": "integer"}}},
+		"allOf": [{"$ref": "#/definitions/A"}],
+		"not": {"type": "null"},
+		"additionalProperties": {"type": "boolean"},
+		"dependencies": {"b": {"required": ["a"]}, "c": ["b"]}
+	}`), &s))
+
+	var pointers []string
+	EachSubschema(&s, func(sub *Schema, pointer string) {
+		pointers = append(pointers, pointer)
+	})
+	assert.Equal(t, []string{"/properties/a~1x", "/properties/b", "/allOf/0", "/not", "/additionalProperties", "/dependencies/b"}, pointers)
+
+	pointers = nil
+	WalkSchema(&s, "#", func(sub *Schema, pointer string) {
+		pointers = append(pointers, pointer)
+	})
+	assert.Equal(t, []string{"#", "#/properties/a~1x", "#/properties/a~1x/items", "#/properties/b", "#/allOf/0", "#/not", "#/additionalProperties", "#/dependencies/b"}, pointers)
+}
+
+func TestRewriteSchema(t *testing.T) {
+	s := Schema{SchemaProps: SchemaProps{
+		Properties: map[string]Schema{"a": {SchemaProps: SchemaProps{Properties: map[string]Schema{"b": {}}}}},
+	}}
+	WalkSchema(&s, "", func(sub *Schema, pointer string) {
+		sub.Description = pointer
+	})
+	assert.Empty(t, s.Properties["a"].Description)
+
+	RewriteSchema(&s, "", func(sub *Schema, pointer string) {
+		sub.Description = pointer
+	})
+	assert.Equal(t, "/properties/a", s.Properties["a"].Description)
+	assert.Equal(t, "/properties/a/properties/b", s.Properties["a"].Properties["b"].Description)
+
+	RewriteSubschemas(&s, func(sub *Schema, pointer string) {
+		sub.Title = "t"
+	})
+	assert.Equal(t, "t", s.Properties["a"].Title)
+	assert.Empty(t, s.Properties["a"].Properties["b"].Title)
+}
+
+func TestWalkRefs(t *testing.T) {
+	var s Swagger
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"swagger": "2.0",
+		"paths": {
+			"/pets": {
+				"parameters": [{"$ref": "#/parameters/limit"}],
+				"get": {
+					"parameters": [{"name": "body", "in": "body", "schema": {"$ref": "#/definitions/Pet"}}],
+					"responses": {
+						"200": {"$ref": "#/responses/ok"},
+						"default": {"description": "error", "schema": {"items": {"$ref": "#/definitions/Error"}}}
+					}
+				}
+			}
+		},
+		"definitions": {"Pet": {"properties": {"owner": {"$ref": "#/definitions/Owner"}}}},
+		"parameters": {"limit": {"name": "limit", "in": "query", "type": "integer"}},
+		"responses": {"ok": {"description": "OK", "schema": {"$ref": "#/definitions/Pet"}}}
+	}`), &s))
+
+	var refs []string
+	s.WalkRefs(func(ref *Ref, pointer string) {
+		refs = append(refs, pointer+" "+ref.String())
+	})
+	assert.Equal(t, []string{
+		"/paths/~1pets/parameters/0 #/parameters/limit",
+		"/paths/~1pets/get/parameters/0/schema #/definitions/Pet",
+		"/paths/~1pets/get/responses/default/schema/items #/definitions/Error",
+		"/paths/~1pets/get/responses/200 #/responses/ok",
+		"/definitions/Pet/properties/owner #/definitions/Owner",
+		"/responses/ok/schema #/definitions/Pet",
+	}, refs)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"reflect"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+// contentEncodings decode the encodings of contentEncoding. The other
+// encodings are left unchecked.
+var contentEncodings = map[string]func(string) ([]byte, error){
+	"base64":    base64.StdEncoding.DecodeString,
+	"base64url": base64.URLEncoding.DecodeString,
+}
+
+// contentValidator validates the contentEncoding and contentMediaType of a
+// string schema: the string must decode with the encoding and, for JSON
+// media types, hold a JSON document, which must validate against the
+// contentSchema of the schema, if any.
+type contentValidator struct {
+	Path      string
+	In        string
+	Encoding  string
+	MediaType string
+	Schema    *spec.Schema
+	Root      interface{}
+	Formats   strfmt.Registry
+	Options   SchemaValidatorOptions
+}
+
+func (c *contentValidator) SetPath(path string) {
+	c.Path = path
+}
+
+func (c *contentValidator) Applies(source interface{}, kind reflect.Kind) bool {
+	_, ok := source.(*spec.Schema)
+	r := ok && kind == reflect.String && !c.Options.SkipContentDecoding && (c.Encoding != "" || c.MediaType != "")
+	debugLog("content validator for %q applies %t for %T (kind: %v)\n", c.Path, r, source, kind)
+	return r
+}
+
+func (c *contentValidator) Validate(data interface{}) *Result {
+	result := newResult()
+	content := []byte(reflect.ValueOf(data).String())
+	if decode, ok := contentEncodings[strings.ToLower(c.Encoding)]; ok {
+		decoded, err := decode(string(content))
+		if err != nil {
+			result.AddErrors(errors.InvalidContent(c.Path, c.In, c.Encoding, err.Error()))
+			return result
+		}
+		content = decoded
+	}
+	if !isJSONMediaType(c.MediaType) {
+		return result
+	}
+	var doc interface{}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		result.AddErrors(errors.InvalidContent(c.Path, c.In, c.MediaType, err.Error()))
+		return result
+	}
+	if c.Schema != nil {
+		result.mergeAndRelease(NewSchemaValidator(c.Schema, c.Root, c.Path, c.Formats, c.Options.Options()...).Validate(doc))
+	}
+	return result
+}
+
+// isJSONMediaType tells whether a media type is application/json or one
+// with the +json suffix, e.g. application/merge-patch+json.
+func isJSONMediaType(mediaType string) bool {
+	if mediaType == "" {
+		return false
+	}
+	t, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return false
+	}
+	return t == "application/json" || strings.HasSuffix(t, "+json")
+}
+
+// extraSchema decodes a schema kept in the extra properties of a schema,
+// e.g. contentSchema.
+func extraSchema(s *spec.Schema, name string) (*spec.Schema, error) {
+	raw, ok := s.ExtraProps[name]
+	if !ok {
+		return nil, nil
+	}
+	if sch, ok := raw.(*spec.Schema); ok {
+		return sch, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	sch := &spec.Schema{}
+	if err := json.Unmarshal(data, sch); err != nil {
+		return nil, err
+	}
+	return sch, nil
+}
+
+func (s *SchemaValidator) contentValidator() valueValidator {
+	encoding, _ := s.Schema.ExtraProps["contentEncoding"].(string)
+	mediaType, _ := s.Schema.ExtraProps["contentMediaType"].(string)
+	sch, err := extraSchema(s.Schema, "contentSchema")
+	if err != nil {
+		panic(fmt.Sprintf("invalid contentSchema: %v", err))
+	}
+	return &contentValidator{
+		Path:      s.Path,
+		In:        s.in,
+		Encoding:  encoding,
+		MediaType: mediaType,
+		Schema:    sch,
+		Root:      s.Root,
+		Formats:   s.KnownFormats,
+		Options:   s.Options,
+	}
+}
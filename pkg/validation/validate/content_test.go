@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+func TestContentValidator(t *testing.T) {
+	var s spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "string",
+		"contentEncoding": "base64",
+		"contentMediaType": "application/json",
+		"contentSchema": {"type": "object", "required": ["kind"]}
+	}`), &s))
+	v := NewSchemaValidator(&s, nil, "payload", strfmt.Default)
+	encode := func(doc string) string {
+		return base64.StdEncoding.EncodeToString([]byte(doc))
+	}
+
+	assert.True(t, v.Validate(encode(`{"kind": "Pod"}`)).IsValid())
+
+	res := v.Validate("not base64!")
+	if assert.Len(t, res.Errors, 1) {
+		assert.EqualValues(t, errors.InvalidContentCode, res.Errors[0].(*errors.Validation).Code())
+		assert.Contains(t, res.Errors[0].Error(), "payload in body should hold base64 content")
+	}
+
+	res = v.Validate(encode(`{"kind":`))
+	if assert.Len(t, res.Errors, 1) {
+		assert.Contains(t, res.Errors[0].Error(), "payload in body should hold application/json content")
+	}
+
+	res = v.Validate(encode(`{}`))
+	if assert.Len(t, res.Errors, 1) {
+		assert.EqualValues(t, errors.RequiredFailCode, res.Errors[0].(*errors.Validation).Code())
+	}
+
+	skipping := NewSchemaValidator(&s, nil, "payload", strfmt.Default, SkipContentDecoding(true))
+	assert.True(t, skipping.Validate("not base64!").IsValid())
+}
+
+func TestContentValidator_MediaTypes(t *testing.T) {
+	for _, tc := range []struct {
+		mediaType string
+		content   string
+		valid     bool
+	}{
+		{"application/json", `[1, 2]`, true},
+		{"application/json; charset=utf-8", `{`, false},
+		{"application/merge-patch+json", `{`, false},
+		{"text/plain", `{`, true},
+		{"", `{`, true},
+	} {
+		s := spec.StringProperty()
+		s.ExtraProps = map[string]interface{}{"contentMediaType": tc.mediaType}
+		res := NewSchemaValidator(s, nil, "", strfmt.Default).Validate(tc.content)
+		assert.Equal(t, tc.valid, res.IsValid(), tc.mediaType)
+	}
+}
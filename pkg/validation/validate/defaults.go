@@ -0,0 +1,42 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Defaults checks that the defaults of a spec validate against their
+// schemas: the defaults of the definitions and of the schemas they nest,
+// of the parameters and their items and of the response headers. A
+// default of the wrong type, out of its enum or range would otherwise
+// only be reported when it is applied. The errors are named after the
+// path of the non-conforming value, e.g.
+// "parameters.limit.default". Defaults of schemas holding a reference are
+// ignored, as the referenced schemas are checked where they are defined.
+func Defaults(s *spec.Swagger) *Result {
+	return newAnnotationValidator(jsonDefault, s).swagger(s)
+}
+
+// SchemaDefaults checks the defaults of a schema and of the schemas it
+// nests, whose references are resolved with resolve. The schema is named
+// name in the errors.
+func SchemaDefaults(name string, s *spec.Schema, resolve func(ref string) *spec.Schema) *Result {
+	e := &annotationValidator{keyword: jsonDefault, result: new(Result), resolve: resolve}
+	e.schema(name, s)
+	return e.result
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const defaultsSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer", "maximum": 100, "default": 500}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"$ref": "#/parameters/limit"},
+          {"name": "order", "in": "query", "type": "string", "enum": ["asc", "desc"], "default": "up"},
+          {"name": "tags", "in": "query", "type": "array", "items": {"type": "string", "default": 1}}
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "schema": {"$ref": "#/definitions/Pet"},
+            "headers": {"X-Rate": {"type": "integer", "default": "fast"}},
+            "examples": {"application/json": {"id": 1}}
+          }
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string", "default": "rex"},
+        "age": {"type": "integer", "minimum": 0, "default": -1},
+        "kind": {"type": "string", "default": 3, "example": 3},
+        "owner": {"$ref": "#/definitions/Owner", "default": 1}
+      },
+      "default": {"name": "rex", "owner": {"name": 3}}
+    },
+    "Owner": {
+      "type": "object",
+      "properties": {"name": {"type": "string"}}
+    }
+  }
+}`
+
+func TestDefaults(t *testing.T) {
+	var s spec.Swagger
+	require.NoError(t, json.Unmarshal([]byte(defaultsSpec), &s))
+	res := Defaults(&s)
+
+	var names []string
+	for _, err := range res.Errors {
+		names = append(names, err.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		"definitions.Pet.default.owner.name in body must be of type string: \"number\"",
+		"definitions.Pet.properties.age.default in body should be greater than or equal to 0",
+		"definitions.Pet.properties.kind.default in body must be of type string: \"number\"",
+		"parameters.limit.default in body should be less than or equal to 100",
+		"paths./pets.get.parameters.1.default in body should be one of [asc desc]",
+		"paths./pets.get.parameters.2.items.default in body must be of type string: \"number\"",
+		"paths./pets.get.responses.200.headers.X-Rate.default in body must be of type integer: \"string\"",
+	}, names)
+}
+
+func TestSchemaDefaults(t *testing.T) {
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: spec.StringOrArray{"object"},
+		Properties: map[string]spec.Schema{
+			"size": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"integer"}, Default: "big"}},
+		},
+	}}
+	res := SchemaDefaults("Box", s, nil)
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, "Box.properties.size.default in body must be of type integer: \"string\"", res.Errors[0].Error())
+
+	s.Properties["size"] = spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"integer"}, Default: 2}}
+	assert.True(t, SchemaDefaults("Box", s, nil).IsValid())
+}
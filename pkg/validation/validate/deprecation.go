@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	// extDeprecated marks a schema or a parameter as deprecated. Swagger 2.0
+	// only allows operations to be deprecated.
+	extDeprecated = "x-deprecated"
+	// extRemovalVersion is the version a deprecated schema or parameter is
+	// going to be removed in. It implies x-deprecated.
+	extRemovalVersion = "x-removal-version"
+)
+
+// IsDeprecated returns whether a schema is deprecated, either through the
+// x-deprecated or x-removal-version extensions or through the OpenAPI v3
+// deprecated keyword, and the version it will be removed in, if known.
+func IsDeprecated(s *spec.Schema) (bool, string) {
+	deprecated, removal := deprecation(s.Extensions)
+	if d, ok := s.ExtraProps["deprecated"].(bool); ok && d {
+		deprecated = true
+	}
+	return deprecated, removal
+}
+
+// IsDeprecatedParameter is like IsDeprecated for parameters.
+func IsDeprecatedParameter(p *spec.Parameter) (bool, string) {
+	return deprecation(p.Extensions)
+}
+
+func deprecation(ext spec.Extensions) (bool, string) {
+	removal, _ := ext.GetString(extRemovalVersion)
+	deprecated, _ := ext.GetBool(extDeprecated)
+	return deprecated || removal != "", removal
+}
+
+// DeprecatedParameters returns a result with a warning for each deprecated
+// parameter a request has set. isSet tells whether the request carries a
+// value for a parameter.
+func DeprecatedParameters(params []spec.Parameter, isSet func(p *spec.Parameter) bool) *Result {
+	res := new(Result)
+	for i := range params {
+		p := &params[i]
+		if deprecated, removal := IsDeprecatedParameter(p); deprecated && isSet(p) {
+			res.AddWarnings(errors.DeprecatedParameter(p.Name, p.In, removal))
+		}
+	}
+	return res
+}
+
+// WarningHeaders formats the deprecation warnings of a result as values of
+// the HTTP Warning header, with the miscellaneous persistent warning code
+// 299, so they can be surfaced to clients. Other warnings are left out.
+func WarningHeaders(r *Result) []string {
+	if r == nil {
+		return nil
+	}
+	var ret []string
+	for _, w := range r.Warnings {
+		if v, ok := w.(*errors.Validation); ok && v.Code() == errors.DeprecatedCode {
+			ret = append(ret, fmt.Sprintf("299 - %s", strconv.Quote(strings.Replace(v.Error(), "\n", " ", -1))))
+		}
+	}
+	return ret
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+func TestDeprecationWarnings(t *testing.T) {
+	var schema spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"old": {"type": "string", "x-deprecated": true},
+			"older": {"type": "string", "x-removal-version": "v2"},
+			"nested": {
+				"type": "object",
+				"properties": {"legacy": {"type": "integer", "deprecated": true}}
+			}
+		}
+	}`), &schema))
+	data := map[string]interface{}{
+		"name":   "n",
+		"old":    "o",
+		"older":  "o",
+		"nested": map[string]interface{}{"legacy": 1},
+	}
+
+	res := NewSchemaValidator(&schema, nil, "", strfmt.Default).Validate(data)
+	assert.True(t, res.IsValid())
+	assert.Empty(t, res.Warnings, "deprecation warnings are opt-in")
+
+	res = NewSchemaValidator(&schema, nil, "", strfmt.Default, EnableDeprecationWarnings(true)).Validate(data)
+	assert.True(t, res.IsValid())
+	assert.ElementsMatch(t, []string{
+		"299 - \".old in body is deprecated\"",
+		"299 - \".older in body is deprecated and will be removed in v2\"",
+		"299 - \"nested.legacy in body is deprecated\"",
+	}, WarningHeaders(res))
+
+	res = NewSchemaValidator(&schema, nil, "", strfmt.Default, EnableDeprecationWarnings(true)).Validate(map[string]interface{}{"name": "n"})
+	assert.Empty(t, res.Warnings)
+}
+
+func TestDeprecatedParameters(t *testing.T) {
+	params := []spec.Parameter{
+		*spec.QueryParam("limit"),
+		*spec.QueryParam("max"),
+		*spec.QueryParam("unused"),
+	}
+	params[1].AddExtension("x-removal-version", "v2")
+	params[2].AddExtension("x-deprecated", true)
+
+	res := DeprecatedParameters(params, func(p *spec.Parameter) bool { return p.Name != "unused" })
+	assert.Equal(t, []string{"299 - \"max in query is deprecated and will be removed in v2\""}, WarningHeaders(res))
+	assert.Nil(t, WarningHeaders(nil))
+}
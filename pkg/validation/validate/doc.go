@@ -17,6 +17,16 @@ Package validate provides methods to validate a swagger schema.
 
 This package follows Swagger 2.0. specification (aka OpenAPI 2.0). Reference
 can be found here: https://github.com/OAI/OpenAPI-Specification/blob/master/versions/2.0.md.
+
+Schema validators are created with NewFor and configured with options, e.g.
+
+	v := validate.NewFor(schema,
+		validate.WithFormats(formats),
+		validate.WithLimits(spec.DefaultLimits),
+		validate.WithMessageTemplates(templates))
+	result := v.ValidateJSON(body)
+
+Once created, they may be shared between goroutines.
 */
 
 package validate
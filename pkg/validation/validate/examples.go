@@ -0,0 +1,219 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+const definitionPrefix = "#/definitions/"
+
+// Examples checks that the examples of a spec validate against their
+// schemas: the examples of the definitions and of the schemas they nest,
+// of the parameters and their items, of the response headers and of the
+// responses for JSON media types. The errors are named after the path of
+// the non-conforming value, e.g.
+// "paths./pets.get.responses.200.examples.application/json.name".
+// Examples of schemas holding a reference are ignored, as the referenced
+// schemas are checked where they are defined.
+func Examples(s *spec.Swagger) *Result {
+	return newAnnotationValidator(jsonExample, s).swagger(s)
+}
+
+// newAnnotationValidator returns a validator of the values found under keyword,
+// "example" or "default", resolving references to the definitions of s.
+func newAnnotationValidator(keyword string, s *spec.Swagger) *annotationValidator {
+	return &annotationValidator{keyword: keyword, result: new(Result), resolve: definitionResolver(s)}
+}
+
+func (e *annotationValidator) swagger(s *spec.Swagger) *Result {
+	for _, name := range sortedKeys(s.Definitions) {
+		def := s.Definitions[name]
+		e.schema("definitions."+name, &def)
+	}
+	for _, name := range sortedKeys(s.Parameters) {
+		p := s.Parameters[name]
+		e.parameter("parameters."+name, &p)
+	}
+	for _, name := range sortedKeys(s.Responses) {
+		r := s.Responses[name]
+		e.response("responses."+name, &r)
+	}
+	if s.Paths != nil {
+		for _, path := range sortedKeys(s.Paths.Paths) {
+			item := s.Paths.Paths[path]
+			name := "paths." + path
+			for i := range item.Parameters {
+				e.parameter(name+".parameters."+strconv.Itoa(i), &item.Parameters[i])
+			}
+			for _, m := range item.Operations() {
+				e.operation(name+"."+strings.ToLower(m.Method), m.Operation)
+			}
+		}
+	}
+	return e.result
+}
+
+// Example validates an example against a schema whose references are
+// resolved with resolve, see ExpandRefs. The errors are named after the
+// path of the example, name.
+func Example(name string, example interface{}, s *spec.Schema, resolve func(ref string) *spec.Schema) *Result {
+	return NewSchemaValidator(ExpandRefs(s, resolve), nil, name, strfmt.Default).Validate(example)
+}
+
+// SchemaExamples checks the examples of a schema and of the schemas it
+// nests, whose references are resolved with resolve. The schema is named
+// name in the errors.
+func SchemaExamples(name string, s *spec.Schema, resolve func(ref string) *spec.Schema) *Result {
+	e := &annotationValidator{keyword: jsonExample, result: new(Result), resolve: resolve}
+	e.schema(name, s)
+	return e.result
+}
+
+// annotationValidator walks a spec checking the values under keyword against
+// the schemas holding them.
+type annotationValidator struct {
+	keyword string
+	resolve func(ref string) *spec.Schema
+	result  *Result
+}
+
+func (e *annotationValidator) check(name string, value interface{}, s *spec.Schema) {
+	e.result.Merge(Example(name, value, s, e.resolve))
+}
+
+func (e *annotationValidator) schema(name string, s *spec.Schema) {
+	spec.WalkSchema(s, "", func(sub *spec.Schema, pointer string) {
+		value := sub.Example
+		if e.keyword == jsonDefault {
+			value = sub.Default
+		}
+		if value != nil && sub.Ref.String() == "" {
+			e.check(name+strings.Replace(pointer, "/", ".", -1)+"."+e.keyword, value, sub)
+		}
+	})
+}
+
+func (e *annotationValidator) parameter(name string, p *spec.Parameter) {
+	if p.Ref.String() != "" {
+		return
+	}
+	if p.In == "body" {
+		e.schema(name+".schema", p.Schema)
+		return
+	}
+	e.simpleSchema(name, &p.SimpleSchema, &p.CommonValidations)
+}
+
+func (e *annotationValidator) simpleSchema(name string, s *spec.SimpleSchema, v *spec.CommonValidations) {
+	value := s.Example
+	if e.keyword == jsonDefault {
+		value = s.Default
+	}
+	if value != nil {
+		e.check(name+"."+e.keyword, value, simpleSchemaToSchema(s, v))
+	}
+	if s.Items != nil {
+		e.simpleSchema(name+".items", &s.Items.SimpleSchema, &s.Items.CommonValidations)
+	}
+}
+
+func (e *annotationValidator) operation(name string, op *spec.Operation) {
+	for i := range op.Parameters {
+		e.parameter(name+".parameters."+strconv.Itoa(i), &op.Parameters[i])
+	}
+	if op.Responses == nil {
+		return
+	}
+	codes := make([]int, 0, len(op.Responses.StatusCodeResponses))
+	for code := range op.Responses.StatusCodeResponses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		r := op.Responses.StatusCodeResponses[code]
+		e.response(name+".responses."+strconv.Itoa(code), &r)
+	}
+	if op.Responses.Default != nil {
+		e.response(name+".responses.default", op.Responses.Default)
+	}
+}
+
+func (e *annotationValidator) response(name string, r *spec.Response) {
+	if r.Ref.String() != "" {
+		return
+	}
+	e.schema(name+".schema", r.Schema)
+	for _, h := range sortedKeys(r.Headers) {
+		header := r.Headers[h]
+		e.simpleSchema(name+".headers."+h, &header.SimpleSchema, &header.CommonValidations)
+	}
+	if r.Schema == nil || e.keyword != jsonExample {
+		return
+	}
+	for _, mt := range sortedKeys(r.Examples) {
+		// Other media types have examples in their own format.
+		if strings.Contains(mt, "json") {
+			e.check(name+".examples."+mt, r.Examples[mt], r.Schema)
+		}
+	}
+}
+
+// simpleSchemaToSchema returns the schema of a non-body parameter, header
+// or items.
+func simpleSchemaToSchema(s *spec.SimpleSchema, v *spec.CommonValidations) *spec.Schema {
+	ret := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Format:           s.Format,
+		Nullable:         s.Nullable,
+		Maximum:          v.Maximum,
+		ExclusiveMaximum: v.ExclusiveMaximum,
+		Minimum:          v.Minimum,
+		ExclusiveMinimum: v.ExclusiveMinimum,
+		MaxLength:        v.MaxLength,
+		MinLength:        v.MinLength,
+		Pattern:          v.Pattern,
+		MaxItems:         v.MaxItems,
+		MinItems:         v.MinItems,
+		UniqueItems:      v.UniqueItems,
+		MultipleOf:       v.MultipleOf,
+		Enum:             v.Enum,
+	}}
+	if s.Type != "" {
+		ret.Type = spec.StringOrArray{s.Type}
+	}
+	if s.Items != nil {
+		ret.Items = &spec.SchemaOrArray{Schema: simpleSchemaToSchema(&s.Items.SimpleSchema, &s.Items.CommonValidations)}
+	}
+	return ret
+}
+
+// sortedKeys returns the sorted keys of a map with string keys.
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	ret := make([]string, 0, len(keys))
+	for _, k := range keys {
+		ret = append(ret, k.String())
+	}
+	sort.Strings(ret)
+	return ret
+}
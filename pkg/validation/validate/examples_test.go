@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const examplesSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer", "maximum": 100, "example": 500}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"$ref": "#/parameters/limit"},
+          {"name": "tags", "in": "query", "type": "array", "items": {"type": "string", "example": 1}},
+          {"name": "body", "in": "body", "schema": {"type": "object", "example": {"name": 4}, "properties": {"name": {"type": "string"}}}}
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "schema": {"$ref": "#/definitions/Pet"},
+            "headers": {"X-Rate": {"type": "integer", "example": "fast"}},
+            "examples": {
+              "application/json": {"id": 1},
+              "text/plain": "anything"
+            }
+          },
+          "default": {
+            "description": "error",
+            "schema": {"type": "string"},
+            "examples": {"application/json": "oops"}
+          }
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string", "example": "rex"},
+        "age": {"type": "integer", "minimum": 0, "example": -1},
+        "owner": {"$ref": "#/definitions/Owner"}
+      },
+      "example": {"name": "rex", "owner": {"name": 3}}
+    },
+    "Owner": {
+      "type": "object",
+      "properties": {"name": {"type": "string"}, "pets": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}
+    }
+  }
+}`
+
+func TestExamples(t *testing.T) {
+	var s spec.Swagger
+	require.NoError(t, json.Unmarshal([]byte(examplesSpec), &s))
+	res := Examples(&s)
+
+	var names []string
+	for _, err := range res.Errors {
+		names = append(names, err.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		"definitions.Pet.example.owner.name in body must be of type string: \"number\"",
+		"definitions.Pet.properties.age.example in body should be greater than or equal to 0",
+		"parameters.limit.example in body should be less than or equal to 100",
+		"paths./pets.get.parameters.1.items.example in body must be of type string: \"number\"",
+		"paths./pets.get.parameters.2.schema.example.name in body must be of type string: \"number\"",
+		"paths./pets.get.responses.200.headers.X-Rate.example in body must be of type integer: \"string\"",
+		"paths./pets.get.responses.200.examples.application/json.name in body is required",
+	}, names)
+}
+
+func TestExamplesValid(t *testing.T) {
+	s := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Definitions: spec.Definitions{
+			"Pet": *new(spec.Schema).Typed("object", "").
+				SetProperty("name", *spec.StringProperty().WithExample("rex")).
+				WithExample(map[string]interface{}{"name": "rex"}),
+		},
+	}}
+	assert.True(t, Examples(s).IsValid())
+}
+
+func TestExpandRefs(t *testing.T) {
+	defs := spec.Definitions{
+		"Node": *new(spec.Schema).Typed("object", "").
+			SetProperty("next", *spec.RefSchema("#/definitions/Node")).
+			SetProperty("value", *spec.RefSchema("#/definitions/Value")),
+		"Value": *spec.StringProperty(),
+	}
+	resolve := func(ref string) *spec.Schema {
+		def, ok := defs[ref[len(definitionPrefix):]]
+		if !ok {
+			return nil
+		}
+		return &def
+	}
+	expanded := ExpandRefs(spec.RefSchema("#/definitions/Node"), resolve)
+	assert.Equal(t, spec.StringOrArray{"object"}, expanded.Type)
+	assert.Equal(t, spec.Schema{}, expanded.Properties["next"], "recursive references are cut")
+	assert.Equal(t, *spec.StringProperty(), expanded.Properties["value"])
+	assert.Equal(t, &spec.Schema{}, ExpandRefs(spec.RefSchema("#/definitions/Missing"), resolve))
+	next := defs["Node"].Properties["next"]
+	assert.Equal(t, "#/definitions/Node", next.Ref.String(), "the input must not be modified")
+
+	// Subtrees without references are shared rather than copied.
+	plain := spec.ArrayProperty(spec.StringProperty())
+	assert.Same(t, plain, ExpandRefs(plain, resolve))
+	mixed := new(spec.Schema).Typed("object", "").
+		SetProperty("plain", *plain).
+		SetProperty("value", *spec.RefSchema("#/definitions/Value"))
+	expanded = ExpandRefs(mixed, resolve)
+	assert.NotSame(t, mixed, expanded)
+	assert.Same(t, mixed.Properties["plain"].Items.Schema, expanded.Properties["plain"].Items.Schema)
+	assert.Equal(t, *spec.StringProperty(), expanded.Properties["value"])
+	value := mixed.Properties["value"]
+	assert.Equal(t, "#/definitions/Value", value.Ref.String(), "the input must not be modified")
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// ExpandRefs returns a copy of a schema with its references replaced by the
+// schemas resolve returns for them, since schema validators don't resolve
+// references. Recursive references and references resolve returns nil for
+// are replaced by an empty schema, which accepts any value.
+//
+// Only the schemas holding references, directly or in their subtrees, are
+// copied: the result shares the others with s and the resolved schemas, so
+// none of them must be modified afterwards.
+func ExpandRefs(s *spec.Schema, resolve func(ref string) *spec.Schema) *spec.Schema {
+	return expandRefs(s, resolve, nil)
+}
+
+// expandRefs returns s itself if nothing in it needs expanding.
+func expandRefs(s *spec.Schema, resolve func(ref string) *spec.Schema, expanding map[string]bool) *spec.Schema {
+	if s == nil {
+		return nil
+	}
+	if ref := s.Ref.String(); ref != "" {
+		if expanding[ref] {
+			return &spec.Schema{}
+		}
+		resolved := resolve(ref)
+		if resolved == nil {
+			return &spec.Schema{}
+		}
+		nested := map[string]bool{ref: true}
+		for k := range expanding {
+			nested[k] = true
+		}
+		return expandRefs(resolved, resolve, nested)
+	}
+
+	orig := s
+	clone := func() {
+		if s == orig {
+			s = &spec.Schema{}
+			*s = *orig
+		}
+	}
+	expandMap := func(m map[string]spec.Schema) (map[string]spec.Schema, bool) {
+		var ret map[string]spec.Schema
+		for k, v := range m {
+			if e := expandRefs(&v, resolve, expanding); e != &v {
+				if ret == nil {
+					ret = make(map[string]spec.Schema, len(m))
+					for k2, v2 := range m {
+						ret[k2] = v2
+					}
+				}
+				ret[k] = *e
+			}
+		}
+		return ret, ret != nil
+	}
+	expandList := func(l []spec.Schema) ([]spec.Schema, bool) {
+		var ret []spec.Schema
+		for i := range l {
+			if e := expandRefs(&l[i], resolve, expanding); e != &l[i] {
+				if ret == nil {
+					ret = append([]spec.Schema(nil), l...)
+				}
+				ret[i] = *e
+			}
+		}
+		return ret, ret != nil
+	}
+
+	if s.Definitions != nil {
+		clone()
+		s.Definitions = nil
+	}
+	if m, changed := expandMap(s.Properties); changed {
+		clone()
+		s.Properties = m
+	}
+	if m, changed := expandMap(s.PatternProperties); changed {
+		clone()
+		s.PatternProperties = m
+	}
+	if l, changed := expandList(s.AllOf); changed {
+		clone()
+		s.AllOf = l
+	}
+	if l, changed := expandList(s.AnyOf); changed {
+		clone()
+		s.AnyOf = l
+	}
+	if l, changed := expandList(s.OneOf); changed {
+		clone()
+		s.OneOf = l
+	}
+	if e := expandRefs(s.Not, resolve, expanding); e != s.Not {
+		clone()
+		s.Not = e
+	}
+	if s.AdditionalProperties != nil {
+		if e := expandRefs(s.AdditionalProperties.Schema, resolve, expanding); e != s.AdditionalProperties.Schema {
+			clone()
+			s.AdditionalProperties = &spec.SchemaOrBool{Allows: s.AdditionalProperties.Allows, Schema: e}
+		}
+	}
+	if s.AdditionalItems != nil {
+		if e := expandRefs(s.AdditionalItems.Schema, resolve, expanding); e != s.AdditionalItems.Schema {
+			clone()
+			s.AdditionalItems = &spec.SchemaOrBool{Allows: s.AdditionalItems.Allows, Schema: e}
+		}
+	}
+	if s.Items != nil {
+		e := expandRefs(s.Items.Schema, resolve, expanding)
+		l, changed := expandList(s.Items.Schemas)
+		if e != s.Items.Schema || changed {
+			clone()
+			items := *s.Items
+			items.Schema = e
+			if changed {
+				items.Schemas = l
+			}
+			s.Items = &items
+		}
+	}
+	var deps spec.Dependencies
+	for k, dep := range s.Dependencies {
+		if e := expandRefs(dep.Schema, resolve, expanding); e != dep.Schema {
+			if deps == nil {
+				deps = make(spec.Dependencies, len(s.Dependencies))
+				for k2, v2 := range s.Dependencies {
+					deps[k2] = v2
+				}
+			}
+			dep.Schema = e
+			deps[k] = dep
+		}
+	}
+	if deps != nil {
+		clone()
+		s.Dependencies = deps
+	}
+	return s
+}
+
+// definitionResolver resolves the references to the definitions of root, a
+// schema or a spec, as ExpandRefs expects.
+func definitionResolver(root interface{}) func(ref string) *spec.Schema {
+	var defs spec.Definitions
+	switch root := root.(type) {
+	case *spec.Schema:
+		defs = root.Definitions
+	case *spec.Swagger:
+		defs = root.Definitions
+	}
+	return func(ref string) *spec.Schema {
+		if !strings.HasPrefix(ref, definitionPrefix) {
+			return nil
+		}
+		def, ok := defs[ref[len(definitionPrefix):]]
+		if !ok {
+			return nil
+		}
+		return &def
+	}
+}
@@ -17,6 +17,7 @@ package validate
 import (
 	"reflect"
 
+	"k8s.io/kube-openapi/pkg/validation/errors"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 	"k8s.io/kube-openapi/pkg/validation/strfmt"
 )
@@ -25,6 +26,7 @@ type formatValidator struct {
 	Format       string
 	Path         string
 	In           string
+	Sensitive    bool
 	KnownFormats strfmt.Registry
 }
 
@@ -49,8 +51,12 @@ func (f *formatValidator) Applies(source interface{}, kind reflect.Kind) bool {
 }
 
 func (f *formatValidator) Validate(val interface{}) *Result {
-	result := new(Result)
-	debugLog("validating \"%v\" against format: %s", val, f.Format)
+	result := newResult()
+	if f.Sensitive {
+		debugLog("validating %s against format: %s", errors.RedactedValue, f.Format)
+	} else {
+		debugLog("validating \"%v\" against format: %s", val, f.Format)
+	}
 
 	if err := FormatOf(f.Path, f.In, f.Format, val.(string), f.KnownFormats); err != nil {
 		result.AddErrors(err)
@@ -59,5 +65,6 @@ func (f *formatValidator) Validate(val interface{}) *Result {
 	if result.HasErrors() {
 		return result
 	}
+	result.Release()
 	return nil
 }
@@ -0,0 +1,206 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+const headerLocation = "header"
+
+// collectionSeparators are the separators of the items of arrays by
+// collection format. multi isn't supported by headers.
+var collectionSeparators = map[string]string{"": ",", "csv": ",", "ssv": " ", "tsv": "\t", "pipes": "|"}
+
+// Header validates the value of a header against its declaration, e.g. one
+// of the headers of a response. The value is parsed with ParseHeader
+// first. The errors are named name. The values of sensitive headers, see
+// IsSensitive, are redacted from them.
+func Header(name string, h *spec.Header, value string) *Result {
+	var res *Result
+	if v, err := ParseHeader(h, value); err != nil {
+		res = new(Result)
+		res.AddErrors(errors.InvalidType(name, headerLocation, h.Type, value))
+	} else {
+		res = validateSimpleValue(name, headerLocation, &h.SimpleSchema, &h.CommonValidations, v)
+	}
+	if sensitive(h.Format, h.Extensions) {
+		for i, err := range res.Errors {
+			res.Errors[i] = errors.Redact(err)
+		}
+	}
+	return res
+}
+
+// validateSimpleValue validates a parsed header or parameter value. The
+// items of arrays are validated one by one, so that their errors are named
+// after their index and located in the header or parameter too.
+func validateSimpleValue(name, in string, s *spec.SimpleSchema, v *spec.CommonValidations, value interface{}) *Result {
+	sch := simpleSchemaToSchema(s, v)
+	sch.Items = nil
+	res := newSchemaValidator(sch, nil, name, in, strfmt.Default).Validate(value)
+	if items, ok := value.([]interface{}); ok && s.Items != nil {
+		for i, item := range items {
+			res.Merge(validateSimpleValue(name+"."+strconv.Itoa(i), in, &s.Items.SimpleSchema, &s.Items.CommonValidations, item).locate(strconv.Itoa(i)))
+		}
+	}
+	return res
+}
+
+// Headers validates the headers of an HTTP message against their
+// declarations, keyed by header name. Declared headers missing from the
+// message are ignored, as Swagger 2.0 headers can't be required. Arrays
+// sent over several header lines are joined as if they were sent on one.
+func Headers(declared map[string]spec.Header, header http.Header) *Result {
+	res := new(Result)
+	for _, name := range sortedKeys(declared) {
+		values := header[textproto.CanonicalMIMEHeaderKey(name)]
+		if len(values) == 0 {
+			continue
+		}
+		h := declared[name]
+		if h.Type == "array" && len(values) > 1 {
+			values = []string{strings.Join(values, collectionSeparators[h.CollectionFormat])}
+		}
+		for _, v := range values {
+			res.Merge(Header(name, &h, v))
+		}
+	}
+	return res
+}
+
+// ParseHeader converts the value of a header to the Go value of its type:
+// an int64, a float64, a bool, a string or, for arrays, a []interface{} of
+// items split according to the collection format.
+func ParseHeader(h *spec.Header, value string) (interface{}, error) {
+	return parseSimpleValue(&h.SimpleSchema, value)
+}
+
+func parseSimpleValue(s *spec.SimpleSchema, value string) (interface{}, error) {
+	switch s.Type {
+	case "array":
+		sep, ok := collectionSeparators[s.CollectionFormat]
+		if !ok {
+			return nil, fmt.Errorf("collection format %q is not supported in headers", s.CollectionFormat)
+		}
+		if value == "" {
+			return []interface{}{}, nil
+		}
+		// The items are sliced out of value rather than split into a
+		// []string first, to allocate nothing but ret and their boxes.
+		ret := make([]interface{}, 0, strings.Count(value, sep)+1)
+		for rest, more := value, true; more; {
+			part := rest
+			if i := strings.Index(rest, sep); i >= 0 {
+				part, rest = rest[:i], rest[i+len(sep):]
+			} else {
+				more = false
+			}
+			if s.Items == nil {
+				ret = append(ret, part)
+				continue
+			}
+			item, err := parseSimpleValue(&s.Items.SimpleSchema, part)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, item)
+		}
+		return ret, nil
+	case "integer":
+		return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	case "number":
+		return strconv.ParseFloat(strings.TrimSpace(value), 64)
+	case "boolean":
+		return strconv.ParseBool(strings.TrimSpace(value))
+	}
+	return value, nil
+}
+
+// FormatHeader serializes a Go value into the value of a header. Slices and
+// arrays are joined according to the collection format, times are
+// formatted according to the date or date-time format and byte slices are
+// base64 encoded for the byte format.
+func FormatHeader(h *spec.Header, value interface{}) (string, error) {
+	return formatSimpleValue(&h.SimpleSchema, value)
+}
+
+func formatSimpleValue(s *spec.SimpleSchema, value interface{}) (string, error) {
+	if s.Type == "array" {
+		sep, ok := collectionSeparators[s.CollectionFormat]
+		if !ok {
+			return "", fmt.Errorf("collection format %q is not supported in headers", s.CollectionFormat)
+		}
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return "", fmt.Errorf("can't format %T as an array", value)
+		}
+		items := &spec.SimpleSchema{}
+		if s.Items != nil {
+			items = &s.Items.SimpleSchema
+		}
+		parts := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			part, err := formatSimpleValue(items, v.Index(i).Interface())
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, sep), nil
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		if s.Format == "byte" {
+			return base64.StdEncoding.EncodeToString(v), nil
+		}
+		return string(v), nil
+	case time.Time:
+		if s.Format == stringFormatDate {
+			return v.Format("2006-01-02"), nil
+		}
+		return v.Format(time.RFC3339Nano), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.String:
+		return fmt.Sprint(value), nil
+	}
+	return "", fmt.Errorf("can't format %T as a header value", value)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestParseHeader(t *testing.T) {
+	rate := spec.Header{SimpleSchema: spec.SimpleSchema{Type: "integer"}}
+	v, err := ParseHeader(&rate, " 42")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), v)
+	_, err = ParseHeader(&rate, "fast")
+	assert.Error(t, err)
+
+	tags := spec.Header{SimpleSchema: spec.SimpleSchema{Type: "array", CollectionFormat: "pipes", Items: spec.NewItems().Typed("number", "")}}
+	v, err = ParseHeader(&tags, "1.5|2")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{1.5, 2.0}, v)
+	v, err = ParseHeader(&tags, "")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{}, v)
+
+	tags.CollectionFormat = "multi"
+	_, err = ParseHeader(&tags, "1")
+	assert.Error(t, err)
+}
+
+func TestHeaders(t *testing.T) {
+	declared := map[string]spec.Header{
+		"X-Rate-Limit": *spec.ResponseHeader().Typed("integer", "int32").WithMaximum(100, false),
+		"X-Tags":       *spec.ResponseHeader().CollectionOf(spec.NewItems().Typed("string", "").WithEnum("a", "b"), "csv"),
+		"X-Date":       *spec.ResponseHeader().Typed("string", "date"),
+		"X-Missing":    *spec.ResponseHeader().Typed("string", ""),
+	}
+	header := http.Header{}
+	header.Set("x-rate-limit", "500")
+	header.Add("X-Tags", "a,b")
+	header.Add("X-Tags", "c")
+	header.Set("X-Date", "yesterday")
+
+	var msgs []string
+	for _, err := range Headers(declared, header).Errors {
+		msgs = append(msgs, err.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		"X-Date in header must be of type date: \"yesterday\"",
+		"X-Rate-Limit in header should be less than or equal to 100",
+		"X-Tags.2 in header should be one of [a b]",
+	}, msgs)
+
+	res := Header("X-Rate-Limit", &spec.Header{SimpleSchema: spec.SimpleSchema{Type: "integer"}}, "fast")
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, `X-Rate-Limit in header must be of type integer: "fast"`, res.Errors[0].Error())
+
+	header = http.Header{}
+	header.Set("X-Rate-Limit", "10")
+	header.Set("X-Tags", "b")
+	assert.True(t, Headers(declared, header).IsValid())
+}
+
+func TestFormatHeader(t *testing.T) {
+	for _, tc := range []struct {
+		header *spec.Header
+		value  interface{}
+		want   string
+	}{
+		{spec.ResponseHeader().Typed("integer", ""), 42, "42"},
+		{spec.ResponseHeader().Typed("number", ""), 0.5, "0.5"},
+		{spec.ResponseHeader().Typed("boolean", ""), true, "true"},
+		{spec.ResponseHeader().Typed("string", ""), "rex", "rex"},
+		{spec.ResponseHeader().Typed("string", ""), nil, ""},
+		{spec.ResponseHeader().Typed("string", "byte"), []byte("hi"), "aGk="},
+		{spec.ResponseHeader().Typed("string", "date"), time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), "2020-01-02"},
+		{spec.ResponseHeader().Typed("string", "date-time"), time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), "2020-01-02T03:04:05Z"},
+		{spec.ResponseHeader().CollectionOf(spec.NewItems().Typed("integer", ""), "ssv"), []int{1, 2}, "1 2"},
+		{spec.ResponseHeader().CollectionOf(spec.NewItems().Typed("string", ""), ""), [2]string{"a", "b"}, "a,b"},
+		{spec.ResponseHeader().CollectionOf(nil, "tsv"), []interface{}{"a", 1}, "a\t1"},
+	} {
+		got, err := FormatHeader(tc.header, tc.value)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got)
+	}
+
+	_, err := FormatHeader(spec.ResponseHeader().CollectionOf(nil, "csv"), 1)
+	assert.Error(t, err)
+	_, err = FormatHeader(spec.ResponseHeader().CollectionOf(nil, "multi"), []int{1})
+	assert.Error(t, err)
+	_, err = FormatHeader(spec.ResponseHeader().Typed("string", ""), struct{}{})
+	assert.Error(t, err)
+}
@@ -41,6 +41,7 @@ const (
 const (
 	jsonProperties = "properties"
 	jsonDefault    = "default"
+	jsonExample    = "example"
 )
 
 const (
@@ -90,7 +91,9 @@ type errorHelper struct {
 
 func (h *errorHelper) sErr(err errors.Error) *Result {
 	// Builds a Result from standard errors.Error
-	return &Result{Errors: []error{err}}
+	r := newResult()
+	r.Errors = append(r.Errors, err)
+	return r
 }
 
 type valueHelper struct {
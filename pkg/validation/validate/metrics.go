@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import "time"
+
+// Recorder receives the outcome of validations, e.g. to export them as
+// metrics. To count failures by operation, give each operation's validator
+// its own Recorder.
+type Recorder interface {
+	// ObserveValidation is called after each validation with its duration
+	// and the reported errors, if any. Most of them implement errors.Error
+	// and can be counted by their code, e.g. errors.RequiredFailCode.
+	//
+	// errs is the Errors slice of the result, which is reset when the
+	// caller releases it: it must not be kept after the call returns, but
+	// the errors themselves may be.
+	ObserveValidation(duration time.Duration, errs []error)
+}
+
+// RecorderFunc adapts a function to a Recorder.
+type RecorderFunc func(duration time.Duration, errs []error)
+
+// ObserveValidation implements Recorder.
+func (f RecorderFunc) ObserveValidation(duration time.Duration, errs []error) {
+	f(duration, errs)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+func TestWithRecorder(t *testing.T) {
+	schema := new(spec.Schema).Typed("object", "").
+		WithRequired("name").
+		SetProperty("name", *spec.StringProperty().WithMaxLength(3)).
+		SetProperty("tags", *spec.ArrayProperty(spec.StringProperty().WithEnum("a", "b")))
+	var validations int
+	failures := map[int32]int{}
+	recorder := RecorderFunc(func(d time.Duration, errs []error) {
+		validations++
+		assert.True(t, d >= 0)
+		for _, err := range errs {
+			if e, ok := err.(errors.Error); ok {
+				failures[e.Code()]++
+			}
+		}
+	})
+	v := NewSchemaValidator(schema, nil, "", strfmt.Default, WithRecorder(recorder))
+
+	v.Validate(map[string]interface{}{"name": "abc"})
+	v.Validate(map[string]interface{}{"name": "long", "tags": []interface{}{"c"}})
+	v.Validate(map[string]interface{}{})
+	assert.Equal(t, 3, validations, "nested validators don't report")
+	assert.Equal(t, map[int32]int{
+		errors.TooLongFailCode:  1,
+		errors.EnumFailCode:     1,
+		errors.RequiredFailCode: 1,
+	}, failures)
+}
@@ -24,6 +24,11 @@ import (
 	"k8s.io/kube-openapi/pkg/validation/strfmt"
 )
 
+// extKeyPattern is the extension holding a pattern every key of an object
+// must match, e.g. for schemas of Go maps, as a shorthand for propertyNames
+// with a pattern.
+const extKeyPattern = "x-key-pattern"
+
 type objectValidator struct {
 	Path                 string
 	In                   string
@@ -33,6 +38,8 @@ type objectValidator struct {
 	Properties           map[string]spec.Schema
 	AdditionalProperties *spec.SchemaOrBool
 	PatternProperties    map[string]spec.Schema
+	PropertyNames        *spec.Schema
+	KeyPattern           string
 	Root                 interface{}
 	KnownFormats         strfmt.Registry
 	Options              SchemaValidatorOptions
@@ -78,7 +85,7 @@ func (o *objectValidator) Validate(data interface{}) *Result {
 		return errorHelp.sErr(errors.TooManyProperties(o.Path, o.In, *o.MaxProperties))
 	}
 
-	res := new(Result)
+	res := newResult()
 
 	// check validity of field names
 	if o.AdditionalProperties != nil && !o.AdditionalProperties.Allows {
@@ -115,7 +122,7 @@ func (o *objectValidator) Validate(data interface{}) *Result {
 				// Cases: properties which are not regular properties and have not been matched by the PatternProperties validator
 				if o.AdditionalProperties != nil && o.AdditionalProperties.Schema != nil {
 					// AdditionalProperties as Schema
-					res.Merge(NewSchemaValidator(o.AdditionalProperties.Schema, o.Root, o.Path+"."+key, o.KnownFormats, o.Options.Options()...).Validate(value))
+					res.mergeAndRelease(NewSchemaValidator(o.AdditionalProperties.Schema, o.Root, o.Path+"."+key, o.KnownFormats, o.Options.Options()...).Validate(value).locate(key))
 				} else if regularProperty && !(matched || succeededOnce) {
 					// TODO: this is dead code since regularProperty=false here
 					res.AddErrors(errors.FailedAllPatternProperties(o.Path, o.In, key))
@@ -125,11 +132,16 @@ func (o *objectValidator) Validate(data interface{}) *Result {
 		// Valid cases: additionalProperties: true or undefined
 	}
 
+	o.validatePropertyNames(val, res)
+
 	createdFromDefaults := map[string]bool{}
 
 	// Property types:
 	// - regular Property
 	for pName, pSchema := range o.Properties {
+		if o.Options.contextErr() != nil {
+			break
+		}
 		rName := pName
 		if o.Path != "" {
 			rName = o.Path + "." + pName
@@ -137,8 +149,13 @@ func (o *objectValidator) Validate(data interface{}) *Result {
 
 		// Recursively validates each property against its schema
 		if v, ok := val[pName]; ok {
-			r := NewSchemaValidator(&pSchema, o.Root, rName, o.KnownFormats, o.Options.Options()...).Validate(v)
-			res.Merge(r)
+			r := NewSchemaValidator(&pSchema, o.Root, rName, o.KnownFormats, o.Options.Options()...).Validate(v).locate(pName)
+			res.mergeAndRelease(r)
+			if o.Options.DeprecationWarnings {
+				if deprecated, removal := IsDeprecated(&pSchema); deprecated {
+					res.AddWarnings(errors.DeprecatedProperty(o.Path, o.In, pName, removal))
+				}
+			}
 		}
 	}
 
@@ -160,7 +177,7 @@ func (o *objectValidator) Validate(data interface{}) *Result {
 		if !regularProperty && (matched /*|| succeededOnce*/) {
 			for _, pName := range patterns {
 				if v, ok := o.PatternProperties[pName]; ok {
-					res.Merge(NewSchemaValidator(&v, o.Root, o.Path+"."+key, o.KnownFormats, o.Options.Options()...).Validate(value))
+					res.mergeAndRelease(NewSchemaValidator(&v, o.Root, o.Path+"."+key, o.KnownFormats, o.Options.Options()...).Validate(value).locate(key))
 				}
 			}
 		}
@@ -168,6 +185,31 @@ func (o *objectValidator) Validate(data interface{}) *Result {
 	return res
 }
 
+// validatePropertyNames validates the keys of an object against the key
+// pattern and the propertyNames schema.
+func (o *objectValidator) validatePropertyNames(val map[string]interface{}, result *Result) {
+	if o.KeyPattern == "" && o.PropertyNames == nil {
+		return
+	}
+	for key := range val {
+		if o.KeyPattern != "" {
+			if err := Pattern(key, "", key, o.KeyPattern); err != nil {
+				result.AddErrors(errors.InvalidPropertyName(o.Path, o.In, key, err.Error()))
+			}
+		}
+		if o.PropertyNames == nil {
+			continue
+		}
+		// Keys are validated as values named after themselves, without
+		// location, so that the reasons read e.g. "Foo should match ...".
+		r := newSchemaValidator(o.PropertyNames, o.Root, key, "", o.KnownFormats, o.Options.Options()...).Validate(key)
+		for _, err := range r.Errors {
+			result.AddErrors(errors.InvalidPropertyName(o.Path, o.In, key, err.Error()))
+		}
+		r.Release()
+	}
+}
+
 // TODO: succeededOnce is not used anywhere
 func (o *objectValidator) validatePatternProperty(key string, value interface{}, result *Result) (bool, bool, []string) {
 	matched := false
@@ -181,8 +223,7 @@ func (o *objectValidator) validatePatternProperty(key string, value interface{},
 			matched = true
 			validator := NewSchemaValidator(&sch, o.Root, o.Path+"."+key, o.KnownFormats, o.Options.Options()...)
 
-			res := validator.Validate(value)
-			result.Merge(res)
+			result.mergeAndRelease(validator.Validate(value).locate(key))
 		}
 	}
 
@@ -15,9 +15,15 @@
 package validate
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
 )
 
 func itemsFixture() map[string]interface{} {
@@ -80,3 +86,30 @@ func TestObjectValidator_EdgeCases(t *testing.T) {
 	s.SetPath("path")
 	assert.Equal(t, "path", s.Path)
 }
+
+func TestObjectValidator_PropertyNames(t *testing.T) {
+	var s spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"additionalProperties": {"type": "string"},
+		"propertyNames": {"maxLength": 5},
+		"x-key-pattern": "^[a-z]+$"
+	}`), &s))
+	v := NewSchemaValidator(&s, nil, "labels", strfmt.Default)
+
+	assert.True(t, v.Validate(map[string]interface{}{"app": "web", "tier": "db"}).IsValid())
+
+	res := v.Validate(map[string]interface{}{"App": "web"})
+	if assert.Len(t, res.Errors, 1) {
+		assert.EqualValues(t, errors.InvalidPropertyNameCode, res.Errors[0].(*errors.Validation).Code())
+		assert.Equal(t, "labels.App in body is an invalid property name: App should match '^[a-z]+$'", res.Errors[0].Error())
+	}
+
+	res = v.Validate(map[string]interface{}{"component": "web"})
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, "labels.component in body is an invalid property name: component should be at most 5 chars long", res.Errors[0].Error())
+	}
+
+	// Values are still validated against additionalProperties.
+	assert.False(t, v.Validate(map[string]interface{}{"app": 1}).IsValid())
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+)
+
+// OutputFormat is one of the standard output formats of JSON Schema
+// validation results.
+type OutputFormat int
+
+const (
+	// FlagOutput only tells whether the validation succeeded.
+	FlagOutput OutputFormat = iota
+	// BasicOutput lists the errors.
+	BasicOutput
+	// DetailedOutput nests the errors following the structure of the
+	// validated value.
+	DetailedOutput
+	// VerboseOutput nests the errors like DetailedOutput without
+	// condensing the nodes with a single error below them.
+	VerboseOutput
+)
+
+// OutputUnit is a node of a validation result in one of the standard
+// output formats of JSON Schema.
+type OutputUnit struct {
+	Valid            bool         `json:"valid"`
+	KeywordLocation  string       `json:"keywordLocation,omitempty"`
+	InstanceLocation string       `json:"instanceLocation,omitempty"`
+	Error            string       `json:"error,omitempty"`
+	Errors           []OutputUnit `json:"errors,omitempty"`
+}
+
+// errorKeywords maps the codes of validation errors to the keyword failing
+// them.
+var errorKeywords = map[int32]string{
+	errors.InvalidTypeCode:           "type",
+	errors.RequiredFailCode:          "required",
+	errors.TooLongFailCode:           "maxLength",
+	errors.TooShortFailCode:          "minLength",
+	errors.PatternFailCode:           "pattern",
+	errors.EnumFailCode:              "enum",
+	errors.MultipleOfFailCode:        "multipleOf",
+	errors.MaxFailCode:               "maximum",
+	errors.MinFailCode:               "minimum",
+	errors.UniqueFailCode:            "uniqueItems",
+	errors.MaxItemsFailCode:          "maxItems",
+	errors.MinItemsFailCode:          "minItems",
+	errors.NoAdditionalItemsCode:     "additionalItems",
+	errors.TooFewPropertiesCode:      "minProperties",
+	errors.TooManyPropertiesCode:     "maxProperties",
+	errors.UnallowedPropertyCode:     "additionalProperties",
+	errors.FailedAllPatternPropsCode: "patternProperties",
+}
+
+// Output renders the result in one of the standard output formats of JSON
+// Schema, ready to be marshaled to JSON.
+//
+// The instance locations are those of the errors, see
+// errors.Validation.Location, relative to the validated value whatever the
+// name of the validator. Keyword locations are derived from them through
+// properties and items, so they are approximate for schemas composed with
+// allOf, anyOf, oneOf or using patternProperties and additionalProperties,
+// and properties named after integers are taken for array items. Results
+// only keep the failures, so the verbose format holds no unit for the
+// checks that passed.
+func (r *Result) Output(format OutputFormat) OutputUnit {
+	ret := OutputUnit{Valid: r.IsValid()}
+	if ret.Valid || format == FlagOutput {
+		return ret
+	}
+	units := make([]locatedUnit, 0, len(r.Errors))
+	for _, err := range r.Errors {
+		units = append(units, errorUnit(err))
+	}
+	if format == DetailedOutput || format == VerboseOutput {
+		ret.Errors = nest(units, 0, format == VerboseOutput)
+		return ret
+	}
+	for _, u := range units {
+		ret.Errors = append(ret.Errors, u.OutputUnit)
+	}
+	return ret
+}
+
+// locatedUnit is an output unit with the segments of its instance location.
+type locatedUnit struct {
+	OutputUnit
+	location []string
+}
+
+func errorUnit(err error) locatedUnit {
+	u := locatedUnit{OutputUnit: OutputUnit{Error: err.Error()}}
+	e, ok := err.(*errors.Validation)
+	if !ok {
+		return u
+	}
+	u.location = e.Location
+	u.InstanceLocation = pointer(e.Location)
+	u.KeywordLocation = keywordLocation(e.Location)
+	if keyword, ok := errorKeywords[e.Code()]; ok {
+		u.KeywordLocation += "/" + keyword
+	}
+	return u
+}
+
+func pointer(segments []string) string {
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteString("/")
+		b.WriteString(jsonpointer.Escape(s))
+	}
+	return b.String()
+}
+
+func keywordLocation(segments []string) string {
+	var b strings.Builder
+	for _, s := range segments {
+		if _, err := strconv.Atoi(s); err == nil {
+			b.WriteString("/items")
+			continue
+		}
+		b.WriteString("/properties/")
+		b.WriteString(jsonpointer.Escape(s))
+	}
+	return b.String()
+}
+
+// nest groups the units by the segment of their instance location at the
+// given depth. Unless verbose, groups with a single unit are not nested
+// further.
+func nest(units []locatedUnit, depth int, verbose bool) []OutputUnit {
+	var ret []OutputUnit
+	groups := map[string]int{}
+	var members [][]locatedUnit
+	for _, u := range units {
+		if len(u.location) <= depth {
+			ret = append(ret, u.OutputUnit)
+			continue
+		}
+		i, ok := groups[u.location[depth]]
+		if !ok {
+			i = len(members)
+			groups[u.location[depth]] = i
+			members = append(members, nil)
+		}
+		members[i] = append(members[i], u)
+	}
+	for _, group := range members {
+		if len(group) == 1 && !verbose {
+			ret = append(ret, group[0].OutputUnit)
+			continue
+		}
+		location := group[0].location[:depth+1]
+		ret = append(ret, OutputUnit{
+			InstanceLocation: pointer(location),
+			KeywordLocation:  keywordLocation(location),
+			Errors:           nest(group, depth+1, verbose),
+		})
+	}
+	return ret
+}
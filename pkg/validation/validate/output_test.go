@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+func outputSchema() *spec.Schema {
+	name := spec.StringProperty()
+	name.MaxLength = int64Ptr(3)
+	return &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:     spec.StringOrArray{"object"},
+		Required: []string{"id"},
+		Properties: map[string]spec.Schema{
+			"id": *spec.Int64Property(),
+			"owner": {SchemaProps: spec.SchemaProps{
+				Type: spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{
+					"name": *name,
+					"age":  *spec.Int64Property(),
+				},
+			}},
+		},
+	}}
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestResultOutput(t *testing.T) {
+	v := NewSchemaValidator(outputSchema(), nil, "", strfmt.Default)
+
+	valid := v.Validate(map[string]interface{}{"id": 1})
+	assert.Equal(t, OutputUnit{Valid: true}, valid.Output(BasicOutput))
+
+	res := v.Validate(map[string]interface{}{
+		"owner": map[string]interface{}{"name": "abcd", "age": "old"},
+	})
+	assert.Equal(t, OutputUnit{Valid: false}, res.Output(FlagOutput))
+
+	basic := res.Output(BasicOutput)
+	assert.False(t, basic.Valid)
+	byLocation := map[string]OutputUnit{}
+	for _, u := range basic.Errors {
+		assert.NotEmpty(t, u.Error)
+		byLocation[u.KeywordLocation] = u
+	}
+	assert.Len(t, byLocation, 3)
+	assert.Equal(t, "", byLocation["/required"].InstanceLocation)
+	assert.Equal(t, "/owner/name", byLocation["/properties/owner/properties/name/maxLength"].InstanceLocation)
+	assert.Equal(t, "/owner/age", byLocation["/properties/owner/properties/age/type"].InstanceLocation)
+
+	detailed := res.Output(DetailedOutput)
+	assert.False(t, detailed.Valid)
+	require.Len(t, detailed.Errors, 2)
+	var owner *OutputUnit
+	for i := range detailed.Errors {
+		if detailed.Errors[i].InstanceLocation == "/owner" {
+			owner = &detailed.Errors[i]
+		}
+	}
+	require.NotNil(t, owner)
+	assert.Equal(t, "/properties/owner", owner.KeywordLocation)
+	assert.Empty(t, owner.Error)
+	assert.Len(t, owner.Errors, 2)
+
+	data, err := json.Marshal(res.Output(FlagOutput))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"valid": false}`, string(data))
+}
+
+func TestErrorUnitEscapes(t *testing.T) {
+	v := NewSchemaValidator(&spec.Schema{SchemaProps: spec.SchemaProps{
+		Properties: map[string]spec.Schema{"a/b": *spec.StringProperty()},
+	}}, nil, "", strfmt.Default)
+	out := v.Validate(map[string]interface{}{"a/b": 1}).Output(BasicOutput)
+	require.Len(t, out.Errors, 1)
+	assert.Equal(t, "/a~1b", out.Errors[0].InstanceLocation)
+	assert.Equal(t, "/properties/a~1b/type", out.Errors[0].KeywordLocation)
+}
+
+func TestResultOutputVerbose(t *testing.T) {
+	v := NewSchemaValidator(outputSchema(), nil, "", strfmt.Default)
+	res := v.Validate(map[string]interface{}{
+		"id":    1,
+		"owner": map[string]interface{}{"name": "abcd"},
+	})
+
+	// The detailed format condenses the single error of owner.
+	detailed := res.Output(DetailedOutput)
+	require.Len(t, detailed.Errors, 1)
+	assert.Equal(t, "/owner/name", detailed.Errors[0].InstanceLocation)
+	assert.NotEmpty(t, detailed.Errors[0].Error)
+
+	verbose := res.Output(VerboseOutput)
+	assert.False(t, verbose.Valid)
+	require.Len(t, verbose.Errors, 1)
+	owner := verbose.Errors[0]
+	assert.Equal(t, "/owner", owner.InstanceLocation)
+	assert.Equal(t, "/properties/owner", owner.KeywordLocation)
+	assert.Empty(t, owner.Error)
+	require.Len(t, owner.Errors, 1)
+	name := owner.Errors[0]
+	assert.Equal(t, "/owner/name", name.InstanceLocation)
+	assert.Equal(t, "/properties/owner/properties/name", name.KeywordLocation)
+	require.Len(t, name.Errors, 1)
+	assert.Equal(t, "/properties/owner/properties/name/maxLength", name.Errors[0].KeywordLocation)
+	assert.NotEmpty(t, name.Errors[0].Error)
+}
+
+func TestErrorUnitLocations(t *testing.T) {
+	tags := spec.ArrayProperty(spec.StringProperty())
+	tags.Items.Schema.MaxLength = int64Ptr(1)
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Properties: map[string]spec.Schema{
+			"example.com/owner": {SchemaProps: spec.SchemaProps{
+				Required:   []string{"first.name"},
+				Properties: map[string]spec.Schema{"first.name": *spec.StringProperty()},
+			}},
+			"tags": *tags,
+		},
+	}}
+	// The locations don't depend on the name of the validator.
+	v := NewSchemaValidator(s, nil, "body", strfmt.Default)
+
+	out := v.Validate(map[string]interface{}{
+		"example.com/owner": map[string]interface{}{"first.name": 1},
+		"tags":              []interface{}{"a", "bc"},
+	}).Output(BasicOutput)
+	locations := map[string]string{}
+	for _, u := range out.Errors {
+		locations[u.InstanceLocation] = u.KeywordLocation
+	}
+	assert.Equal(t, map[string]string{
+		"/example.com~1owner/first.name": "/properties/example.com~1owner/properties/first.name/type",
+		"/tags/1":                        "/properties/tags/items/maxLength",
+	}, locations)
+
+	out = v.Validate(map[string]interface{}{
+		"example.com/owner": map[string]interface{}{},
+	}).Output(BasicOutput)
+	require.Len(t, out.Errors, 1)
+	assert.Equal(t, "/example.com~1owner", out.Errors[0].InstanceLocation)
+	assert.Equal(t, "/properties/example.com~1owner/required", out.Errors[0].KeywordLocation)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Parameter validates the values a request carries for a parameter other
+// than the body, e.g. the values of a query parameter, against its
+// declaration. The values are parsed like header values, see ParseHeader;
+// several values are only expected for arrays in the multi collection
+// format, each of them being an item, and the first one is used otherwise.
+// A required parameter without values is reported as missing; file
+// parameters are only checked for that. The errors are named after the
+// parameter and located where it is. The values of sensitive parameters,
+// see IsSensitiveParameter, are redacted from them.
+func Parameter(p *spec.Parameter, values []string) *Result {
+	res := new(Result)
+	if len(values) == 0 {
+		if p.Required {
+			res.AddErrors(errors.Required(p.Name, p.In))
+		}
+		return res
+	}
+	if p.Type == "file" {
+		return res
+	}
+	value, err := ParseParameter(p, values)
+	if err != nil {
+		res.AddErrors(errors.InvalidType(p.Name, p.In, p.Type, values[0]))
+	} else {
+		res = validateSimpleValue(p.Name, p.In, &p.SimpleSchema, &p.CommonValidations, value)
+	}
+	if IsSensitiveParameter(p) {
+		for i, err := range res.Errors {
+			res.Errors[i] = errors.Redact(err)
+		}
+	}
+	return res
+}
+
+// ParseParameter converts the values of a parameter other than the body to
+// the Go value of its type, as ParseHeader does for headers. The values of
+// arrays in the multi collection format are their items.
+func ParseParameter(p *spec.Parameter, values []string) (interface{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	if p.Type != "array" || p.CollectionFormat != "multi" {
+		return parseSimpleValue(&p.SimpleSchema, values[0])
+	}
+	ret := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		if p.Items == nil {
+			ret = append(ret, v)
+			continue
+		}
+		item, err := parseSimpleValue(&p.Items.SimpleSchema, v)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, item)
+	}
+	return ret, nil
+}
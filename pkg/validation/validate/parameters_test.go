@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestParseParameter(t *testing.T) {
+	ids := spec.QueryParam("id").CollectionOf(spec.NewItems().Typed("integer", ""), "multi")
+	v, err := ParseParameter(ids, []string{"1", "2"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, v)
+	_, err = ParseParameter(ids, []string{"1", "x"})
+	assert.Error(t, err)
+
+	// Other parameters only use their first value.
+	tags := spec.QueryParam("tags").CollectionOf(spec.NewItems().Typed("string", ""), "ssv")
+	v, err = ParseParameter(tags, []string{"a b", "c"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b"}, v)
+	v, err = ParseParameter(spec.PathParam("n").Typed("boolean", ""), []string{"true"})
+	require.NoError(t, err)
+	assert.Equal(t, true, v)
+	v, err = ParseParameter(tags, nil)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestParameter(t *testing.T) {
+	messages := func(res *Result) []string {
+		var ret []string
+		for _, err := range res.Errors {
+			ret = append(ret, err.Error())
+		}
+		return ret
+	}
+	limit := spec.QueryParam("limit").Typed("integer", "int32").WithMaximum(100, false).AsRequired()
+	assert.Empty(t, messages(Parameter(limit, []string{"10"})))
+	assert.Equal(t, []string{"limit in query should be less than or equal to 100"}, messages(Parameter(limit, []string{"500"})))
+	assert.Equal(t, []string{`limit in query must be of type integer: "many"`}, messages(Parameter(limit, []string{"many"})))
+	assert.Equal(t, []string{"limit in query is required"}, messages(Parameter(limit, nil)))
+	assert.Empty(t, messages(Parameter(spec.QueryParam("offset").Typed("integer", ""), nil)))
+
+	ids := spec.QueryParam("id").CollectionOf(spec.NewItems().Typed("string", "").WithEnum("a", "b"), "multi")
+	assert.Equal(t, []string{"id.1 in query should be one of [a b]"}, messages(Parameter(ids, []string{"a", "c"})))
+
+	// Sensitive values are redacted.
+	pin := spec.HeaderParam("X-Pin").Typed("integer", "")
+	assert.Equal(t, []string{`X-Pin in header must be of type integer: "secret"`}, messages(Parameter(pin, []string{"secret"})))
+	pin.AddExtension("x-sensitive", true)
+	res := Parameter(pin, []string{"secret"})
+	require.Len(t, res.Errors, 1)
+	assert.NotContains(t, res.Errors[0].Error(), "secret")
+
+	assert.Empty(t, messages(Parameter(spec.FileParam("upload"), []string{"upload.txt"})))
+}
+
+var benchmarkParameters = []struct {
+	name  string
+	param *spec.Parameter
+	value string
+}{
+	{"integer", spec.QueryParam("limit").Typed("integer", "int64"), "1000"},
+	{"number", spec.QueryParam("ratio").Typed("number", "double"), "0.25"},
+	{"boolean", spec.QueryParam("verbose").Typed("boolean", ""), "true"},
+	{"date", spec.QueryParam("since").Typed("string", "date"), "2020-01-02"},
+	{"csv", spec.QueryParam("ids").CollectionOf(spec.NewItems().Typed("integer", ""), "csv"), "1000,2000,3000,4000"},
+}
+
+func BenchmarkParseParameter(b *testing.B) {
+	for _, bm := range benchmarkParameters {
+		values := []string{bm.value}
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ParseParameter(bm.param, values); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParameter(b *testing.B) {
+	for _, bm := range benchmarkParameters {
+		values := []string{bm.value}
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Parameter(bm.param, values)
+			}
+		})
+	}
+}
+
+func TestParseParameterAllocations(t *testing.T) {
+	// Only the boxes of the values are allocated, and for arrays the slice
+	// of their items.
+	for _, bm := range benchmarkParameters {
+		values := []string{bm.value}
+		want := 1.0
+		switch bm.name {
+		case "boolean":
+			want = 0
+		case "csv":
+			want = 6
+		}
+		allocs := testing.AllocsPerRun(100, func() {
+			ParseParameter(bm.param, values)
+		})
+		assert.Equal(t, want, allocs, bm.name)
+	}
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-openapi/jsonpointer"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Registry indexes schema documents by URI, so that references between
+// documents sharing components can be resolved without loading them, e.g.
+// to expand references with ExpandRefs and Registry.Resolver.
+//
+// Schemas are indexed under the URI they are added with, or their $id
+// (the draft 4 id as fallback), and so are embedded schemas with their own
+// $id. Every schema is also indexed by its JSON pointer within its
+// document, by its $anchor and by its $dynamicAnchor. The draft 2020-12
+// keywords are read from the extra properties of the schemas.
+//
+// A Registry is not safe for concurrent use while schemas are added.
+type Registry struct {
+	// schemas maps absolute URIs, possibly with a JSON pointer or anchor
+	// fragment, to schemas.
+	schemas map[string]*spec.Schema
+	// dynamicAnchors maps the URIs of documents to their dynamic anchors.
+	dynamicAnchors map[string]map[string]bool
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		schemas:        map[string]*spec.Schema{},
+		dynamicAnchors: map[string]map[string]bool{},
+	}
+}
+
+// Add registers a copy of a schema document. The $id of the schema, if any,
+// is resolved against uri, and the result must be an absolute URI. The
+// references of the copy are made absolute, so they resolve through the
+// registry wherever the schemas they are in get expanded. A schema that
+// fails to be added leaves the registry unchanged.
+func (r *Registry) Add(uri string, s *spec.Schema) error {
+	base, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("invalid schema URI %q: %v", uri, err)
+	}
+	// Index into a new registry first, so that failures leave r unchanged.
+	n := NewRegistry()
+	if err := n.index(s.DeepCopy(), base, ""); err != nil {
+		return err
+	}
+	for key := range n.schemas {
+		if _, ok := r.schemas[key]; ok {
+			return fmt.Errorf("schema %s is already registered", key)
+		}
+	}
+	for key, schema := range n.schemas {
+		r.schemas[key] = schema
+	}
+	for doc, anchors := range n.dynamicAnchors {
+		r.dynamicAnchors[doc] = anchors
+	}
+	return nil
+}
+
+func (r *Registry) index(s *spec.Schema, base *url.URL, pointer string) error {
+	if id := schemaID(s); id != "" {
+		u, err := base.Parse(id)
+		if err != nil {
+			return fmt.Errorf("invalid schema id %q: %v", id, err)
+		}
+		base, pointer = u, ""
+	}
+	if pointer == "" {
+		if !base.IsAbs() {
+			return fmt.Errorf("schema URI %q is not absolute", base.String())
+		}
+		if base.Fragment != "" {
+			return fmt.Errorf("schema URI %q has a fragment", base.String())
+		}
+	}
+	doc := documentURI(base)
+	if err := r.register(doc, pointer, s); err != nil {
+		return err
+	}
+	if anchor, ok := s.ExtraProps["$anchor"].(string); ok {
+		if err := r.register(doc, anchor, s); err != nil {
+			return err
+		}
+	}
+	if anchor, ok := s.ExtraProps["$dynamicAnchor"].(string); ok {
+		if r.dynamicAnchors[doc] == nil {
+			r.dynamicAnchors[doc] = map[string]bool{}
+		}
+		r.dynamicAnchors[doc][anchor] = true
+		if s.ExtraProps["$anchor"] != anchor {
+			if err := r.register(doc, anchor, s); err != nil {
+				return err
+			}
+		}
+	}
+	if ref := s.Ref.String(); ref != "" {
+		u, err := base.Parse(ref)
+		if err != nil {
+			return fmt.Errorf("invalid reference %q: %v", ref, err)
+		}
+		if s.Ref, err = spec.NewRef(u.String()); err != nil {
+			return err
+		}
+	}
+	if raw, ok := s.ExtraProps["$defs"]; ok {
+		defs, err := decodeDefs(raw)
+		if err != nil {
+			return fmt.Errorf("invalid $defs at %s#%s: %v", doc, pointer, err)
+		}
+		s.ExtraProps["$defs"] = defs
+		if err := r.indexMap(defs, base, pointer+"/$defs"); err != nil {
+			return err
+		}
+	}
+	return r.indexSubschemas(s, base, pointer)
+}
+
+func (r *Registry) register(doc, fragment string, s *spec.Schema) error {
+	key := doc
+	if fragment != "" {
+		key += "#" + fragment
+	}
+	if _, ok := r.schemas[key]; ok {
+		return fmt.Errorf("schema %s is already registered", key)
+	}
+	r.schemas[key] = s
+	return nil
+}
+
+func (r *Registry) indexSubschemas(s *spec.Schema, base *url.URL, pointer string) error {
+	var err error
+	spec.RewriteSubschemas(s, func(sub *spec.Schema, subpointer string) {
+		if err == nil {
+			err = r.index(sub, base, pointer+subpointer)
+		}
+	})
+	return err
+}
+
+// indexMap indexes the schemas of a map, storing them back since map
+// values aren't addressable.
+func (r *Registry) indexMap(m map[string]spec.Schema, base *url.URL, pointer string) error {
+	for k, v := range m {
+		v := v
+		if err := r.index(&v, base, pointer+"/"+jsonpointer.Escape(k)); err != nil {
+			return err
+		}
+		m[k] = v
+	}
+	return nil
+}
+
+// Resolve returns the schema a reference points to, resolved against base.
+// The fragment of the reference is either a JSON pointer within the
+// document or one of its anchors.
+func (r *Registry) Resolve(ref, base string) (*spec.Schema, error) {
+	u, err := resolveURI(ref, base)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := r.schemas[schemaKey(u)]
+	if !ok {
+		return nil, fmt.Errorf("reference %q can't be resolved", ref)
+	}
+	return s, nil
+}
+
+// Resolver returns a function resolving references against base, as
+// ExpandRefs expects. It returns nil for the references it can't resolve.
+func (r *Registry) Resolver(base string) func(ref string) *spec.Schema {
+	return func(ref string) *spec.Schema {
+		s, _ := r.Resolve(ref, base)
+		return s
+	}
+}
+
+// ResolveDynamic resolves a draft 2020-12 $dynamicRef. scope holds the URIs
+// of the documents the evaluation went through to reach the reference,
+// outermost first, and the reference is resolved against the last of them.
+//
+// A reference to a dynamic anchor of the document it resolves to is
+// resolved to the outermost document of the scope declaring the same
+// dynamic anchor instead. Other references resolve like $ref.
+func (r *Registry) ResolveDynamic(ref string, scope []string) (*spec.Schema, error) {
+	if len(scope) == 0 {
+		return nil, fmt.Errorf("dynamic reference %q needs a scope", ref)
+	}
+	u, err := resolveURI(ref, scope[len(scope)-1])
+	if err != nil {
+		return nil, err
+	}
+	s, ok := r.schemas[schemaKey(u)]
+	if !ok {
+		return nil, fmt.Errorf("dynamic reference %q can't be resolved", ref)
+	}
+	anchor := u.Fragment
+	if anchor == "" || strings.HasPrefix(anchor, "/") || !r.dynamicAnchors[documentURI(u)][anchor] {
+		return s, nil
+	}
+	for _, outer := range scope {
+		o, err := url.Parse(outer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scope URI %q: %v", outer, err)
+		}
+		doc := documentURI(o)
+		if r.dynamicAnchors[doc][anchor] {
+			return r.schemas[doc+"#"+anchor], nil
+		}
+	}
+	return s, nil
+}
+
+func resolveURI(ref, base string) (*url.URL, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URI %q: %v", base, err)
+	}
+	u, err := b.Parse(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %q: %v", ref, err)
+	}
+	return u, nil
+}
+
+// documentURI returns u without its fragment.
+func documentURI(u *url.URL) string {
+	doc := *u
+	doc.Fragment = ""
+	doc.RawFragment = ""
+	return doc.String()
+}
+
+func schemaKey(u *url.URL) string {
+	if u.Fragment == "" {
+		return documentURI(u)
+	}
+	return documentURI(u) + "#" + u.Fragment
+}
+
+func schemaID(s *spec.Schema) string {
+	if id, ok := s.ExtraProps["$id"].(string); ok {
+		return id
+	}
+	return s.ID
+}
+
+func decodeDefs(raw interface{}) (map[string]spec.Schema, error) {
+	if defs, ok := raw.(map[string]spec.Schema); ok {
+		return defs, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var defs map[string]spec.Schema
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+func mustSchema(t *testing.T, data string) *spec.Schema {
+	s := &spec.Schema{}
+	require.NoError(t, json.Unmarshal([]byte(data), s))
+	return s
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Add("https://example.com/schemas/common.json", mustSchema(t, `{
+		"$defs": {
+			"name": {"$anchor": "name", "type": "string", "maxLength": 3},
+			"owner": {
+				"$id": "owner.json",
+				"type": "object",
+				"properties": {"name": {"$ref": "common.json#name"}}
+			}
+		}
+	}`)))
+	require.NoError(t, r.Add("https://example.com/schemas/pet.json", mustSchema(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"$ref": "common.json#/$defs/name"},
+			"owner": {"$ref": "owner.json"}
+		}
+	}`)))
+
+	name, err := r.Resolve("common.json#name", "https://example.com/schemas/pet.json")
+	require.NoError(t, err)
+	byPointer, err := r.Resolve("https://example.com/schemas/common.json#/$defs/name", "")
+	require.NoError(t, err)
+	assert.Same(t, name, byPointer)
+	owner, err := r.Resolve("https://example.com/schemas/owner.json#/properties/name", "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/schemas/common.json#name", owner.Ref.String())
+
+	_, err = r.Resolve("missing.json", "https://example.com/schemas/pet.json")
+	assert.Error(t, err)
+	assert.Error(t, r.Add("https://example.com/schemas/owner.json", &spec.Schema{}))
+	assert.Error(t, r.Add("relative.json", &spec.Schema{}))
+
+	pet, err := r.Resolve("https://example.com/schemas/pet.json", "")
+	require.NoError(t, err)
+	expanded := ExpandRefs(pet, r.Resolver("https://example.com/schemas/pet.json"))
+	v := NewSchemaValidator(expanded, nil, "", strfmt.Default)
+	assert.True(t, v.Validate(map[string]interface{}{
+		"name":  "rex",
+		"owner": map[string]interface{}{"name": "bob"},
+	}).IsValid())
+	assert.False(t, v.Validate(map[string]interface{}{
+		"owner": map[string]interface{}{"name": "alice"},
+	}).IsValid())
+}
+
+func TestRegistryResolveDynamic(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Add("https://example.com/tree", mustSchema(t, `{
+		"$dynamicAnchor": "node",
+		"type": "object",
+		"properties": {"children": {"type": "array", "items": {"$dynamicRef": "#node"}}}
+	}`)))
+	require.NoError(t, r.Add("https://example.com/strict-tree", mustSchema(t, `{
+		"$dynamicAnchor": "node",
+		"$ref": "tree",
+		"additionalProperties": false
+	}`)))
+	require.NoError(t, r.Add("https://example.com/plain", mustSchema(t, `{"$anchor": "node"}`)))
+
+	tree, err := r.Resolve("https://example.com/tree", "")
+	require.NoError(t, err)
+	strict, err := r.Resolve("https://example.com/strict-tree", "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/tree", strict.Ref.String())
+
+	s, err := r.ResolveDynamic("#node", []string{"https://example.com/tree"})
+	require.NoError(t, err)
+	assert.Same(t, tree, s)
+	s, err = r.ResolveDynamic("#node", []string{"https://example.com/strict-tree", "https://example.com/tree"})
+	require.NoError(t, err)
+	assert.Same(t, strict, s)
+
+	// Without a dynamic anchor at the target, dynamic references are static.
+	plain, err := r.Resolve("https://example.com/plain#node", "")
+	require.NoError(t, err)
+	s, err = r.ResolveDynamic("#node", []string{"https://example.com/strict-tree", "https://example.com/plain"})
+	require.NoError(t, err)
+	assert.Same(t, plain, s)
+
+	_, err = r.ResolveDynamic("#node", nil)
+	assert.Error(t, err)
+}
@@ -203,6 +203,31 @@ func TestResult_AsError(t *testing.T) {
 	}
 }
 
+func TestResult_Release(t *testing.T) {
+	r := newResult()
+	r.AddErrors(fmt.Errorf("one Error"))
+	r.AddWarnings(fmt.Errorf("one Warning"))
+	r.Inc()
+	errs := r.Errors
+	r.Release()
+	assert.Empty(t, r.Errors)
+	assert.Empty(t, r.Warnings)
+	assert.Equal(t, 0, r.MatchCount)
+	assert.Nil(t, errs[:1][0], "released errors are dropped")
+
+	var nilResult *Result
+	nilResult.Release()
+
+	merged := newResult()
+	other := newResult()
+	other.AddErrors(fmt.Errorf("merged Error"))
+	merged.mergeAndRelease(other, nil)
+	if assert.Len(t, merged.Errors, 1) {
+		assert.Equal(t, "merged Error", merged.Errors[0].Error())
+	}
+	assert.Empty(t, other.Errors)
+}
+
 // Test methods which suppport a call on a nil instance
 func TestResult_NilInstance(t *testing.T) {
 	var r *Result
@@ -17,9 +17,10 @@ package validate
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"reflect"
+	"time"
 
-	"github.com/go-openapi/swag"
 	"k8s.io/kube-openapi/pkg/validation/errors"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 	"k8s.io/kube-openapi/pkg/validation/strfmt"
@@ -30,7 +31,13 @@ var (
 	//specItemsType     = reflect.TypeOf(&spec.Items{})
 )
 
-// SchemaValidator validates data against a JSON schema
+// SchemaValidator validates data against a JSON schema.
+//
+// Once constructed, a SchemaValidator is safe for concurrent use by
+// multiple goroutines, as long as neither it, its schema nor its options
+// are modified meanwhile, e.g. with SetPath. Validate keeps no state
+// between calls. The Logger and Recorder of the options are called from
+// the goroutines calling Validate and must be safe for concurrent use too.
 type SchemaValidator struct {
 	Path         string
 	in           string
@@ -56,6 +63,21 @@ func AgainstSchema(schema *spec.Schema, data interface{}, formats strfmt.Registr
 //
 // Panics if the provided schema is invalid.
 func NewSchemaValidator(schema *spec.Schema, rootSchema interface{}, root string, formats strfmt.Registry, options ...Option) *SchemaValidator {
+	return newSchemaValidator(schema, rootSchema, root, "body", formats, options...)
+}
+
+// NewFor creates a validator for a schema configured with options, e.g.
+// WithFormats or WithLimits. It is NewSchemaValidator for a root schema,
+// validating strfmt.Default formats unless configured otherwise.
+//
+// Panics if the provided schema is invalid.
+func NewFor(schema *spec.Schema, options ...Option) *SchemaValidator {
+	return NewSchemaValidator(schema, nil, "", strfmt.Default, options...)
+}
+
+// newSchemaValidator creates a schema validator for a value found in the
+// given location, e.g. "header".
+func newSchemaValidator(schema *spec.Schema, rootSchema interface{}, root, in string, formats strfmt.Registry, options ...Option) *SchemaValidator {
 	if schema == nil {
 		return nil
 	}
@@ -70,7 +92,7 @@ func NewSchemaValidator(schema *spec.Schema, rootSchema interface{}, root string
 
 	s := SchemaValidator{
 		Path:         root,
-		in:           "body",
+		in:           in,
 		Schema:       schema,
 		Root:         rootSchema,
 		KnownFormats: formats,
@@ -78,6 +100,9 @@ func NewSchemaValidator(schema *spec.Schema, rootSchema interface{}, root string
 	for _, o := range options {
 		o(&s.Options)
 	}
+	if s.Options.Formats != nil {
+		s.KnownFormats = s.Options.Formats
+	}
 	s.validators = []valueValidator{
 		s.typeValidator(),
 		s.schemaPropsValidator(),
@@ -87,11 +112,13 @@ func NewSchemaValidator(schema *spec.Schema, rootSchema interface{}, root string
 		s.sliceValidator(),
 		s.commonValidator(),
 		s.objectValidator(),
+		s.contentValidator(),
 	}
 	return &s
 }
 
-// SetPath sets the path for this schema valdiator
+// SetPath sets the path for this schema valdiator. It must not be called
+// while the validator is in use by other goroutines.
 func (s *SchemaValidator) SetPath(path string) {
 	s.Path = path
 }
@@ -102,35 +129,91 @@ func (s *SchemaValidator) Applies(source interface{}, kind reflect.Kind) bool {
 	return ok
 }
 
-// Validate validates the data against the schema
+// Validate validates the data against the schema. The result can be given
+// back with Release once it is no longer needed.
+//
+// Data may be decoded JSON or Go values: structs, typed maps and pointers
+// are converted by reflection following their json tags, without marshaling
+// them, and values implementing json.Marshaler, e.g. strfmt.DateTime, are
+// validated in their JSON form.
 func (s *SchemaValidator) Validate(data interface{}) *Result {
-	result := new(Result)
 	if s == nil {
+		return new(Result)
+	}
+	start := time.Now()
+	result := s.validate(data)
+	if err := s.Options.contextErr(); err != nil {
+		result.Release()
+		result = newResult()
+		result.AddErrors(err)
+	}
+	if !s.Options.RevealSensitiveValues && IsSensitive(s.Schema) {
+		for i, err := range result.Errors {
+			result.Errors[i] = errors.Redact(err)
+		}
+	}
+	if s.Options.MessageTemplates != nil {
+		for i, err := range result.Errors {
+			result.Errors[i] = s.Options.MessageTemplates.Apply(err)
+		}
+	}
+	if l := s.Options.Logger; l != nil {
+		for _, err := range result.Errors {
+			l.Debugf("validation failed", "path", s.Path, "error", err.Error())
+		}
+		for _, w := range result.Warnings {
+			l.Warnf("validation warning", "path", s.Path, "warning", w.Error())
+		}
+	}
+	if s.Options.Recorder != nil {
+		s.Options.Recorder.ObserveValidation(time.Since(start), result.Errors)
+	}
+	return result
+}
+
+// ValidateJSON decodes a JSON document, bounded by the limits set with
+// WithLimits, and validates it. Documents which can't be decoded are
+// reported as a single error with code 400.
+func (s *SchemaValidator) ValidateJSON(data []byte) *Result {
+	limits := spec.Limits{}
+	if s.Options.Limits != nil {
+		limits = *s.Options.Limits
+	}
+	var v interface{}
+	if err := limits.Decode(data, &v); err != nil {
+		result := newResult()
+		result.AddErrors(errors.New(http.StatusBadRequest, "invalid JSON document: %v", err))
+		return result
+	}
+	return s.Validate(v)
+}
+
+func (s *SchemaValidator) validate(data interface{}) *Result {
+	result := newResult()
+	if s.Options.contextErr() != nil {
 		return result
 	}
 
+	if needsConversion(data) {
+		converted, err := toUnstructured(reflect.ValueOf(data))
+		if err != nil {
+			result.AddErrors(invalidTypeConversionMsg(s.Path, err))
+			result.Inc()
+			return result
+		}
+		data = converted
+	}
+
 	if data == nil {
-		result.Merge(s.validators[0].Validate(data)) // type validator
-		result.Merge(s.validators[6].Validate(data)) // common validator
+		result.mergeAndRelease(s.validators[0].Validate(data)) // type validator
+		result.mergeAndRelease(s.validators[6].Validate(data)) // common validator
 		return result
 	}
 
 	tpe := reflect.TypeOf(data)
 	kind := tpe.Kind()
-	for kind == reflect.Ptr {
-		tpe = tpe.Elem()
-		kind = tpe.Kind()
-	}
 	d := data
 
-	if kind == reflect.Struct {
-		// NOTE: since reflect retrieves the true nature of types
-		// this means that all strfmt types passed here (e.g. strfmt.Datetime, etc..)
-		// are converted here to strings, and structs are systematically converted
-		// to map[string]interface{}.
-		d = swag.ToDynamicJSON(data)
-	}
-
 	// TODO: this part should be handed over to type validator
 	// Handle special case of json.Number data (number marshalled as string)
 	isnumber := s.Schema.Type.Contains(numberType) || s.Schema.Type.Contains(integerType)
@@ -164,7 +247,7 @@ func (s *SchemaValidator) Validate(data interface{}) *Result {
 		}
 
 		err := v.Validate(d)
-		result.Merge(err)
+		result.mergeAndRelease(err)
 		result.Inc()
 	}
 	result.Inc()
@@ -176,10 +259,13 @@ func (s *SchemaValidator) typeValidator() valueValidator {
 }
 
 func (s *SchemaValidator) commonValidator() valueValidator {
+	c, hasConst := s.Schema.ExtraProps["const"]
 	return &basicCommonValidator{
-		Path: s.Path,
-		In:   s.in,
-		Enum: s.Schema.Enum,
+		Path:     s.Path,
+		In:       s.in,
+		Enum:     s.Schema.Enum,
+		Const:    c,
+		HasConst: hasConst,
 	}
 }
 
@@ -226,6 +312,7 @@ func (s *SchemaValidator) formatValidator() valueValidator {
 		Path:         s.Path,
 		In:           s.in,
 		Format:       s.Schema.Format,
+		Sensitive:    IsSensitive(s.Schema),
 		KnownFormats: s.KnownFormats,
 	}
 }
@@ -236,6 +323,11 @@ func (s *SchemaValidator) schemaPropsValidator() valueValidator {
 }
 
 func (s *SchemaValidator) objectValidator() valueValidator {
+	propertyNames, err := extraSchema(s.Schema, "propertyNames")
+	if err != nil {
+		panic(fmt.Sprintf("invalid propertyNames: %v", err))
+	}
+	keyPattern, _ := s.Schema.Extensions.GetString(extKeyPattern)
 	return &objectValidator{
 		Path:                 s.Path,
 		In:                   s.in,
@@ -245,6 +337,8 @@ func (s *SchemaValidator) objectValidator() valueValidator {
 		Properties:           s.Schema.Properties,
 		AdditionalProperties: s.Schema.AdditionalProperties,
 		PatternProperties:    s.Schema.PatternProperties,
+		PropertyNames:        propertyNames,
+		KeyPattern:           keyPattern,
 		Root:                 s.Root,
 		KnownFormats:         s.KnownFormats,
 		Options:              s.Options,
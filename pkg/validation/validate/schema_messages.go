@@ -22,7 +22,7 @@ import (
 const (
 	// ArrayDoesNotAllowAdditionalItemsError when an additionalItems construct is not verified by the array values provided.
 	//
-	// TODO: should move to package go-openapi/errors
+	// Deprecated: additional items are reported with errors.AdditionalItemsNotAllowed.
 	ArrayDoesNotAllowAdditionalItemsError = "array doesn't allow for additional items"
 
 	// HasDependencyError indicates that a dependencies construct was not verified
@@ -67,6 +67,3 @@ func mustNotValidatechemaMsg(path string) errors.Error {
 func hasADependencyMsg(path, depkey string) errors.Error {
 	return errors.New(errors.CompositeErrorCode, HasDependencyError, path, depkey)
 }
-func arrayDoesNotAllowAdditionalItemsMsg() errors.Error {
-	return errors.New(errors.CompositeErrorCode, ArrayDoesNotAllowAdditionalItemsError)
-}
@@ -14,14 +14,139 @@
 
 package validate
 
+import (
+	"context"
+
+	"k8s.io/kube-openapi/pkg/logging"
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
 // SchemaValidatorOptions defines optional rules for schema validation
 type SchemaValidatorOptions struct {
+	// DeprecationWarnings reports a warning for each deprecated property
+	// present in the validated value.
+	DeprecationWarnings bool
+	// MessageTemplates overrides the messages of the reported errors.
+	MessageTemplates errors.MessageTemplates
+	// RevealSensitiveValues keeps the values of sensitive schemas in the
+	// reported errors, see IsSensitive.
+	RevealSensitiveValues bool
+	// Context aborts the validation when it is done, see WithContext.
+	Context context.Context
+	// SkipContentDecoding skips checking the contentEncoding and
+	// contentMediaType of strings.
+	SkipContentDecoding bool
+	// Formats, if set, replaces the registry of formats the validator was
+	// created with.
+	Formats strfmt.Registry
+	// Limits bounds the documents decoded by ValidateJSON. Nested schemas
+	// don't decode anything, so it isn't part of Options.
+	Limits *spec.Limits
+	// Logger receives the errors and warnings of the validation. Only the
+	// validator it is given to reports, not the validators of nested
+	// schemas, so it isn't part of Options.
+	Logger logging.Logger
+	// Recorder receives the outcome of the validation. Like Logger, it
+	// isn't passed on to the validators of nested schemas.
+	Recorder Recorder
 }
 
 // Option sets optional rules for schema validation
 type Option func(*SchemaValidatorOptions)
 
+// EnableDeprecationWarnings adds a warning to the result for each property
+// set in the validated value whose schema is deprecated, see IsDeprecated.
+func EnableDeprecationWarnings(enable bool) Option {
+	return func(svo *SchemaValidatorOptions) {
+		svo.DeprecationWarnings = enable
+	}
+}
+
+// WithMessageTemplates renders the messages of the reported errors from the
+// given templates, keyed by error code. See errors.MessageTemplates.
+func WithMessageTemplates(templates errors.MessageTemplates) Option {
+	return func(svo *SchemaValidatorOptions) {
+		svo.MessageTemplates = templates
+	}
+}
+
+// RevealSensitiveValues keeps the values of sensitive schemas, e.g. with
+// the password format, in the reported errors. They are redacted by default.
+func RevealSensitiveValues(reveal bool) Option {
+	return func(svo *SchemaValidatorOptions) {
+		svo.RevealSensitiveValues = reveal
+	}
+}
+
+// WithContext aborts the validation once ctx is done, e.g. when the client
+// of the request being validated went away. The validation then only
+// reports ctx.Err(). ctx is checked before each nested schema and array
+// item is validated, so huge payloads are abandoned early.
+func WithContext(ctx context.Context) Option {
+	return func(svo *SchemaValidatorOptions) {
+		svo.Context = ctx
+	}
+}
+
+// SkipContentDecoding leaves the contentEncoding and contentMediaType of
+// strings unchecked, e.g. for large base64 payloads. Otherwise base64 and
+// base64url contents must decode, JSON media types must hold a JSON document
+// and that document must validate against the contentSchema, if any.
+func SkipContentDecoding(skip bool) Option {
+	return func(svo *SchemaValidatorOptions) {
+		svo.SkipContentDecoding = skip
+	}
+}
+
+// WithFormats validates the formats of strings with the given registry
+// instead of the one the validator is created with.
+func WithFormats(formats strfmt.Registry) Option {
+	return func(svo *SchemaValidatorOptions) {
+		svo.Formats = formats
+	}
+}
+
+// WithLimits bounds the size, the nesting and the duplicate keys of the
+// documents given to ValidateJSON, e.g. spec.DefaultLimits for untrusted
+// input.
+func WithLimits(limits spec.Limits) Option {
+	return func(svo *SchemaValidatorOptions) {
+		svo.Limits = &limits
+	}
+}
+
+// WithLogger reports the errors and warnings of each validation to l.
+func WithLogger(l logging.Logger) Option {
+	return func(svo *SchemaValidatorOptions) {
+		svo.Logger = l
+	}
+}
+
+// WithRecorder reports the duration and the errors of each validation to r.
+func WithRecorder(r Recorder) Option {
+	return func(svo *SchemaValidatorOptions) {
+		svo.Recorder = r
+	}
+}
+
+// contextErr returns the error of the context of the validation, if any.
+func (svo SchemaValidatorOptions) contextErr() error {
+	if svo.Context == nil {
+		return nil
+	}
+	return svo.Context.Err()
+}
+
 // Options returns current options
 func (svo SchemaValidatorOptions) Options() []Option {
-	return []Option{}
+	return []Option{
+		EnableDeprecationWarnings(svo.DeprecationWarnings),
+		WithMessageTemplates(svo.MessageTemplates),
+		RevealSensitiveValues(svo.RevealSensitiveValues),
+		WithContext(svo.Context),
+		SkipContentDecoding(svo.SkipContentDecoding),
+		WithFormats(svo.Formats),
+	}
 }
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kube-openapi/pkg/logging"
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+func TestWithMessageTemplates(t *testing.T) {
+	schema := new(spec.Schema).Typed("object", "").
+		WithRequired("name").
+		SetProperty("name", *spec.StringProperty().WithMaxLength(3)).
+		SetProperty("tags", *spec.ArrayProperty(spec.StringProperty().WithEnum("a", "b")))
+	templates := errors.MessageTemplates{
+		errors.TooLongFailCode: "{name} is too long, {constraint} characters at most",
+		errors.EnumFailCode:    "{name}: pick one of {values}",
+	}
+	v := NewSchemaValidator(schema, nil, "", strfmt.Default, WithMessageTemplates(templates))
+
+	res := v.Validate(map[string]interface{}{"name": "long", "tags": []interface{}{"c"}})
+	var msgs []string
+	for _, err := range res.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		"name is too long, 3 characters at most",
+		"tags: pick one of [a b]",
+	}, msgs)
+
+	res = v.Validate(map[string]interface{}{})
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, ".name in body is required", res.Errors[0].Error(), "codes without a template keep their message")
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	schema := new(spec.Schema).Typed("object", "").
+		SetProperty("name", *spec.StringProperty().WithMaxLength(3))
+	var lines []string
+	logger := logging.Func(func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})
+	v := NewSchemaValidator(schema, nil, "", strfmt.Default, WithLogger(logger))
+
+	v.Validate(map[string]interface{}{"name": "long"})
+	assert.Equal(t, []string{`DEBUG validation failed path="" error="name in body should be at most 3 chars long"`}, lines, "nested validators don't report")
+
+	lines = nil
+	v.Validate(map[string]interface{}{"name": "abc"})
+	assert.Empty(t, lines)
+}
+
+// countdownContext is cancelled once Err has been called n times.
+type countdownContext struct {
+	context.Context
+	n int
+}
+
+func (c *countdownContext) Err() error {
+	if c.n--; c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestWithContext(t *testing.T) {
+	schema := spec.ArrayProperty(spec.StringProperty().WithMaxLength(1))
+	data := make([]interface{}, 1000)
+	for i := range data {
+		data[i] = "long"
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	res := NewSchemaValidator(schema, nil, "", strfmt.Default, WithContext(ctx)).Validate(data)
+	assert.NotEmpty(t, res.Errors)
+	cancel()
+	res = NewSchemaValidator(schema, nil, "", strfmt.Default, WithContext(ctx)).Validate(data)
+	assert.Equal(t, []error{context.Canceled}, res.Errors)
+
+	// The items are checked for cancellation while the array is validated.
+	countdown := &countdownContext{Context: context.Background(), n: 10}
+	res = NewSchemaValidator(schema, nil, "", strfmt.Default, WithContext(countdown)).Validate(data)
+	assert.Equal(t, []error{context.Canceled}, res.Errors)
+	assert.True(t, countdown.n > -100, "validation stops checking the context once aborted")
+}
+
+func TestNewFor(t *testing.T) {
+	schema := new(spec.Schema).Typed("object", "").
+		SetProperty("code", *spec.StrFmtProperty("upper"))
+
+	// Without the format in the registry, any string is accepted.
+	assert.True(t, NewFor(schema).Validate(map[string]interface{}{"code": "abc"}).IsValid())
+
+	formats := strfmt.NewSeededFormats(nil, nil)
+	upper := strfmt.Base64{}
+	formats.Add("upper", &upper, func(s string) bool { return strings.ToUpper(s) == s })
+	v := NewFor(schema, WithFormats(formats))
+	assert.True(t, v.Validate(map[string]interface{}{"code": "ABC"}).IsValid())
+	res := v.Validate(map[string]interface{}{"code": "abc"})
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, `code in body must be of type upper: "abc"`, res.Errors[0].Error())
+	}
+}
+
+func TestWithLimits(t *testing.T) {
+	schema := new(spec.Schema).Typed("object", "").
+		SetProperty("name", *spec.StringProperty().WithMaxLength(3))
+	v := NewFor(schema, WithLimits(spec.Limits{MaxDepth: 2, RejectDuplicateKeys: true}))
+
+	assert.True(t, v.ValidateJSON([]byte(`{"name": "abc"}`)).IsValid())
+	assert.Len(t, v.ValidateJSON([]byte(`{"name": "long"}`)).Errors, 1)
+	for _, doc := range []string{`{"name": "abc", "name": "long"}`, `{"a": [[1]]}`, `{`} {
+		res := v.ValidateJSON([]byte(doc))
+		if assert.Len(t, res.Errors, 1, doc) {
+			assert.Equal(t, int32(400), res.Errors[0].(errors.Error).Code())
+		}
+	}
+}
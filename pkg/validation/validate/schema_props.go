@@ -212,20 +212,23 @@ func (s *schemaPropsValidator) Validate(data interface{}) *Result {
 		}
 	}
 
-	if s.Dependencies != nil && len(s.Dependencies) > 0 && reflect.TypeOf(data).Kind() == reflect.Map {
-		val := data.(map[string]interface{})
+	if val, ok := data.(map[string]interface{}); ok && len(s.Dependencies) > 0 {
 		for key := range val {
 			if dep, ok := s.Dependencies[key]; ok {
+				name := key
+				if s.Path != "" {
+					name = s.Path + "." + key
+				}
 
 				if dep.Schema != nil {
-					mainResult.Merge(NewSchemaValidator(dep.Schema, s.Root, s.Path+"."+key, s.KnownFormats, s.Options.Options()...).Validate(data))
+					mainResult.Merge(NewSchemaValidator(dep.Schema, s.Root, name, s.KnownFormats, s.Options.Options()...).Validate(data))
 					continue
 				}
 
 				if len(dep.Property) > 0 {
 					for _, depKey := range dep.Property {
 						if _, ok := val[depKey]; !ok {
-							mainResult.AddErrors(hasADependencyMsg(s.Path, depKey))
+							mainResult.AddErrors(hasADependencyMsg(name, depKey))
 						}
 					}
 				}
@@ -15,9 +15,14 @@
 package validate
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
 )
 
 // Test edge cases in schema_props_validator which are difficult
@@ -28,3 +33,37 @@ func TestSchemaPropsValidator_EdgeCases(t *testing.T) {
 	s.SetPath("path")
 	assert.Equal(t, "path", s.Path)
 }
+
+func TestSchemaPropsValidator_Dependencies(t *testing.T) {
+	var s spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"dependencies": {
+			"card": ["billing"],
+			"shipping": {"required": ["address"]},
+			"legacy": false
+		}
+	}`), &s))
+	v := NewSchemaValidator(&s, nil, "order", strfmt.Default)
+
+	assert.True(t, v.Validate(map[string]interface{}{"card": 1, "billing": 2}).IsValid())
+	assert.True(t, v.Validate(map[string]interface{}{"shipping": 1, "address": 2}).IsValid())
+
+	res := v.Validate(map[string]interface{}{"card": 1})
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, `"order.card" has a dependency on billing`, res.Errors[0].Error())
+	}
+	assert.False(t, v.Validate(map[string]interface{}{"shipping": 1}).IsValid())
+	assert.False(t, v.Validate(map[string]interface{}{"legacy": 1}).IsValid())
+	// Dependencies only apply to objects.
+	assert.True(t, v.Validate([]interface{}{"card"}).IsValid())
+
+	// At the root, the property is named without a leading dot.
+	res = NewSchemaValidator(&s, nil, "", strfmt.Default).Validate(map[string]interface{}{"card": 1})
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, `"card" has a dependency on billing`, res.Errors[0].Error())
+	}
+	res = NewSchemaValidator(&s, nil, "", strfmt.Default).Validate(map[string]interface{}{"shipping": 1})
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, "shipping.address in body is required", res.Errors[0].Error())
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"math"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -183,3 +184,59 @@ func TestSchemaValidator_EdgeCases(t *testing.T) {
 	r = s.Validate(j)
 	assert.False(t, r.IsValid())
 }
+
+func TestSchemaValidator_JSONSchema2020(t *testing.T) {
+	var schema spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"kind": {"const": "Pet"},
+			"nick": {"type": ["string", "null"]},
+			"age": {"type": "integer", "exclusiveMinimum": 0}
+		}
+	}`), &schema))
+	v := NewSchemaValidator(&schema, nil, "", strfmt.Default)
+
+	assert.True(t, v.Validate(map[string]interface{}{"kind": "Pet", "nick": nil, "age": 1}).IsValid())
+	res := v.Validate(map[string]interface{}{"kind": "Owner", "nick": 3, "age": 0})
+	assert.Len(t, res.Errors, 3)
+
+	null := &spec.Schema{}
+	null.ExtraProps = map[string]interface{}{"const": nil}
+	assert.True(t, NewSchemaValidator(null, nil, "", strfmt.Default).Validate(nil).IsValid())
+	assert.False(t, NewSchemaValidator(null, nil, "", strfmt.Default).Validate("a").IsValid())
+}
+
+// TestSchemaValidator_ConcurrentUse shares a validator between goroutines,
+// to be run with the race detector.
+func TestSchemaValidator_ConcurrentUse(t *testing.T) {
+	schema := new(spec.Schema).Typed("object", "").
+		WithRequired("name").
+		SetProperty("name", *spec.StringProperty().WithMaxLength(3).WithPattern("^[a-z]+$")).
+		SetProperty("age", *spec.Int64Property().WithMinimum(0, false)).
+		SetProperty("mail", *spec.StrFmtProperty("email")).
+		SetProperty("tags", *spec.ArrayProperty(spec.StringProperty().WithEnum("a", "b")).UniqueValues())
+	schema.AnyOf = []spec.Schema{*new(spec.Schema).WithRequired("age"), *new(spec.Schema).WithRequired("mail")}
+	schema.OneOf = []spec.Schema{*new(spec.Schema).WithRequired("age"), *new(spec.Schema).WithRequired("mail")}
+	schema.PatternProperties = map[string]spec.Schema{"^x-": *spec.StringProperty()}
+	schema.Not = new(spec.Schema).WithRequired("forbidden")
+	validator := NewSchemaValidator(schema, nil, "", strfmt.Default, EnableDeprecationWarnings(true))
+
+	invalid := map[string]interface{}{"name": "TooLong", "age": -1, "mail": "x", "tags": []interface{}{"a", "a", "c"}, "x-y": 1, "forbidden": true}
+	valid := map[string]interface{}{"name": "abc", "age": 1}
+	want := validator.Validate(invalid).Errors
+	require.NotEmpty(t, want)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				assert.ElementsMatch(t, want, validator.Validate(invalid).Errors)
+				assert.Empty(t, validator.Validate(valid).Errors)
+			}
+		}()
+	}
+	wg.Wait()
+}
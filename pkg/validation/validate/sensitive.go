@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import "k8s.io/kube-openapi/pkg/validation/spec"
+
+const (
+	// extSensitive marks a schema, a parameter or a header as holding a
+	// secret, like the password format does.
+	extSensitive = "x-sensitive"
+	// passwordFormat is the format of sensitive strings.
+	passwordFormat = "password"
+)
+
+// IsSensitive returns whether the values of a schema must not be shown,
+// either because of the password format or the x-sensitive extension. The
+// validators redact them from errors unless RevealSensitiveValues is set.
+func IsSensitive(s *spec.Schema) bool {
+	return sensitive(s.Format, s.Extensions)
+}
+
+// IsSensitiveParameter is like IsSensitive for parameters.
+func IsSensitiveParameter(p *spec.Parameter) bool {
+	return sensitive(p.Format, p.Extensions)
+}
+
+func sensitive(format string, ext spec.Extensions) bool {
+	s, _ := ext.GetBool(extSensitive)
+	return s || format == passwordFormat
+}
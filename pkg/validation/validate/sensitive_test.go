@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+func TestIsSensitive(t *testing.T) {
+	assert.True(t, IsSensitive(spec.StrFmtProperty("password")))
+	assert.False(t, IsSensitive(spec.StringProperty()))
+	marked := spec.StringProperty()
+	marked.AddExtension("x-sensitive", true)
+	assert.True(t, IsSensitive(marked))
+	assert.True(t, IsSensitiveParameter(spec.QueryParam("token").Typed("string", "password")))
+}
+
+func TestSensitiveValuesAreRedacted(t *testing.T) {
+	schema := new(spec.Schema).Typed("object", "").
+		SetProperty("password", *spec.StrFmtProperty("password").WithMinLength(8)).
+		SetProperty("pin", *spec.Int64Property().WithMaximum(100, false))
+	pin := schema.Properties["pin"]
+	pin.AddExtension("x-sensitive", true)
+	schema.Properties["pin"] = pin
+	data := map[string]interface{}{"password": "HUNTER2", "pin": "1234"}
+
+	res := NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(data)
+	if assert.Len(t, res.Errors, 2) {
+		for _, err := range res.Errors {
+			assert.NotContains(t, err.Error(), "HUNTER2")
+			assert.NotContains(t, err.Error(), "1234")
+			assert.Equal(t, errors.RedactedValue, err.(*errors.Validation).Value)
+		}
+	}
+
+	res = NewSchemaValidator(schema, nil, "", strfmt.Default, RevealSensitiveValues(true)).Validate(data)
+	if assert.Len(t, res.Errors, 2) {
+		for _, err := range res.Errors {
+			assert.NotEqual(t, errors.RedactedValue, err.(*errors.Validation).Value)
+		}
+	}
+}
+
+func TestSensitiveHeadersAreRedacted(t *testing.T) {
+	h := spec.ResponseHeader().Typed("integer", "")
+	h.AddExtension("x-sensitive", true)
+	res := Header("X-Pin", h, "secret")
+	if assert.Len(t, res.Errors, 1) {
+		assert.NotContains(t, res.Errors[0].Error(), "secret")
+	}
+}
@@ -17,7 +17,9 @@ package validate
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 
+	"k8s.io/kube-openapi/pkg/validation/errors"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 	"k8s.io/kube-openapi/pkg/validation/strfmt"
 )
@@ -46,7 +48,7 @@ func (s *schemaSliceValidator) Applies(source interface{}, kind reflect.Kind) bo
 }
 
 func (s *schemaSliceValidator) Validate(data interface{}) *Result {
-	result := new(Result)
+	result := newResult()
 	if data == nil {
 		return result
 	}
@@ -55,10 +57,10 @@ func (s *schemaSliceValidator) Validate(data interface{}) *Result {
 
 	if s.Items != nil && s.Items.Schema != nil {
 		validator := NewSchemaValidator(s.Items.Schema, s.Root, s.Path, s.KnownFormats, s.Options.Options()...)
-		for i := 0; i < size; i++ {
+		for i := 0; i < size && s.Options.contextErr() == nil; i++ {
 			validator.SetPath(fmt.Sprintf("%s.%d", s.Path, i))
 			value := val.Index(i)
-			result.Merge(validator.Validate(value.Interface()))
+			result.mergeAndRelease(validator.Validate(value.Interface()).locate(strconv.Itoa(i)))
 		}
 	}
 
@@ -70,17 +72,19 @@ func (s *schemaSliceValidator) Validate(data interface{}) *Result {
 			if val.Len() <= i {
 				break
 			}
-			result.Merge(validator.Validate(val.Index(i).Interface()))
+			result.mergeAndRelease(validator.Validate(val.Index(i).Interface()).locate(strconv.Itoa(i)))
 		}
 	}
-	if s.AdditionalItems != nil && itemsSize < size {
-		if s.Items != nil && len(s.Items.Schemas) > 0 && !s.AdditionalItems.Allows {
-			result.AddErrors(arrayDoesNotAllowAdditionalItemsMsg())
+	// additionalItems only applies to the items past a tuple of items
+	// schemas, it is ignored otherwise.
+	if s.AdditionalItems != nil && itemsSize > 0 && itemsSize < size {
+		if !s.AdditionalItems.Allows {
+			result.AddErrors(errors.AdditionalItemsNotAllowed(s.Path, s.In))
 		}
 		if s.AdditionalItems.Schema != nil {
-			for i := itemsSize; i < size-itemsSize+1; i++ {
+			for i := itemsSize; i < size && s.Options.contextErr() == nil; i++ {
 				validator := NewSchemaValidator(s.AdditionalItems.Schema, s.Root, fmt.Sprintf("%s.%d", s.Path, i), s.KnownFormats, s.Options.Options()...)
-				result.Merge(validator.Validate(val.Index(i).Interface()))
+				result.mergeAndRelease(validator.Validate(val.Index(i).Interface()).locate(strconv.Itoa(i)))
 			}
 		}
 	}
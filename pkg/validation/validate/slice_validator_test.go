@@ -15,9 +15,15 @@
 package validate
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
 )
 
 // Test edge cases in slice_validator which are difficult
@@ -32,3 +38,38 @@ func TestSliceValidator_EdgeCases(t *testing.T) {
 	assert.NotNil(t, r)
 	assert.True(t, r.IsValid())
 }
+
+func TestSliceValidator_Tuples(t *testing.T) {
+	schema := func(data string) *SchemaValidator {
+		var s spec.Schema
+		require.NoError(t, json.Unmarshal([]byte(data), &s))
+		return NewSchemaValidator(&s, nil, "row", strfmt.Default)
+	}
+
+	point := schema(`{"items": [{"type": "number"}, {"type": "number"}], "additionalItems": false}`)
+	assert.True(t, point.Validate([]interface{}{1.0, 2.0}).IsValid())
+	assert.True(t, point.Validate([]interface{}{1.0}).IsValid())
+	res := point.Validate([]interface{}{1.0, 2.0, 3.0})
+	if assert.Len(t, res.Errors, 1) {
+		assert.EqualValues(t, errors.NoAdditionalItemsCode, res.Errors[0].(*errors.Validation).Code())
+		assert.Equal(t, "row in body can't have additional items", res.Errors[0].Error())
+	}
+	res = point.Validate([]interface{}{1.0, "2"})
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, "row.1", res.Errors[0].(*errors.Validation).Name)
+	}
+
+	// Every additional item is validated, not only the first ones.
+	csv := schema(`{"items": [{"type": "string"}, {"type": "string"}], "additionalItems": {"type": "integer"}}`)
+	assert.True(t, csv.Validate([]interface{}{"a", "b", 1, 2, 3}).IsValid())
+	res = csv.Validate([]interface{}{"a", "b", 1, 2, "c"})
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, "row.4", res.Errors[0].(*errors.Validation).Name)
+	}
+
+	// additionalItems is ignored without a tuple of items schemas.
+	list := schema(`{"items": {"type": "string"}, "additionalItems": false}`)
+	assert.True(t, list.Validate([]interface{}{"a", "b", "c"}).IsValid())
+	bare := schema(`{"additionalItems": {"type": "integer"}}`)
+	assert.True(t, bare.Validate([]interface{}{"a"}).IsValid())
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Spec checks the structural correctness of a spec, see spec.Swagger's
+// Validate, and reports the problems found as errors of a Result.
+func Spec(s *spec.Swagger) *Result {
+	return specResult(s.Validate())
+}
+
+func specResult(err error) *Result {
+	res := new(Result)
+	if err == nil {
+		return res
+	}
+	if c, ok := err.(*errors.CompositeError); ok {
+		res.AddErrors(c.Errors...)
+		return res
+	}
+	res.AddErrors(err)
+	return res
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestSpec(t *testing.T) {
+	s := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Swagger: "2.0",
+		Info:    &spec.Info{InfoProps: spec.InfoProps{Title: "t", Version: "v"}},
+		Paths:   &spec.Paths{Paths: map[string]spec.PathItem{}},
+	}}
+	assert.True(t, Spec(s).IsValid())
+
+	s.Swagger = "3.0"
+	res := Spec(s)
+	if assert.Len(t, res.Errors, 1) {
+		assert.EqualValues(t, errors.InvalidSpecCode, res.Errors[0].(*errors.Validation).Code())
+	}
+}
@@ -130,7 +130,7 @@ func (t *typeValidator) Applies(source interface{}, kind reflect.Kind) bool {
 }
 
 func (t *typeValidator) Validate(data interface{}) *Result {
-	result := new(Result)
+	result := newResult()
 	result.Inc()
 	if data == nil {
 		// nil or zero value for the passed structure require Type: null
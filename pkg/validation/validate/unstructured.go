@@ -0,0 +1,407 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// needsConversion tells whether validators can't handle a value as is:
+// structs, typed maps and pointers are converted with toUnstructured.
+func needsConversion(data interface{}) bool {
+	if _, ok := data.(map[string]interface{}); ok || data == nil {
+		return false
+	}
+	switch reflect.TypeOf(data).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Ptr:
+		return true
+	}
+	return false
+}
+
+// toUnstructured converts a Go value to the form encoding/json decodes JSON
+// to: maps of strings, slices of interfaces, float64 numbers, strings,
+// booleans and nil. Structs are walked by reflection following their json
+// tags, which is much cheaper than marshaling the value and decoding it
+// back. Values implementing json.Marshaler are still marshaled, since only
+// they know their JSON form.
+//
+// Ambiguous fields of embedded structs are resolved like encoding/json as
+// far as depth goes: shallower fields win, and between fields at the same
+// depth the first one wins rather than none.
+//
+// Cyclic values are reported as errors rather than overflowing the stack.
+func toUnstructured(v reflect.Value) (interface{}, error) {
+	return (&converter{}).convert(v)
+}
+
+// startDetectingCyclesAfter is the depth of pointers, maps and slices after
+// which converter tracks them, as encoding/json does: most values are not
+// that deep, and don't pay for the tracking.
+const startDetectingCyclesAfter = 1000
+
+// converter holds the state of toUnstructured.
+type converter struct {
+	ptrLevel uint
+	ptrSeen  map[interface{}]struct{}
+}
+
+// enter records that v, a non nil pointer, map or slice, is being
+// converted, failing if it already is. Each successful call must be matched
+// by one to leave.
+func (c *converter) enter(v reflect.Value) error {
+	if c.ptrLevel++; c.ptrLevel <= startDetectingCyclesAfter {
+		return nil
+	}
+	key := cycleKey(v)
+	if c.ptrSeen == nil {
+		c.ptrSeen = map[interface{}]struct{}{}
+	}
+	if _, ok := c.ptrSeen[key]; ok {
+		c.ptrLevel--
+		return fmt.Errorf("encountered a cycle via %s", v.Type())
+	}
+	c.ptrSeen[key] = struct{}{}
+	return nil
+}
+
+func (c *converter) leave(v reflect.Value) {
+	if c.ptrLevel > startDetectingCyclesAfter {
+		delete(c.ptrSeen, cycleKey(v))
+	}
+	c.ptrLevel--
+}
+
+// cycleKey identifies a pointer, map or slice. Slices are identified by
+// their length too, since a slice and its first element share a pointer.
+func cycleKey(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Slice {
+		return struct {
+			ptr uintptr
+			len int
+		}{v.Pointer(), v.Len()}
+	}
+	return v.Pointer()
+}
+
+func (c *converter) convert(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	t := v.Type()
+	if t.Implements(jsonMarshalerType) || (v.CanAddr() && reflect.PtrTo(t).Implements(jsonMarshalerType)) {
+		if t.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, nil
+		}
+		if !t.Implements(jsonMarshalerType) {
+			v = v.Addr()
+		}
+		data, err := v.Interface().(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		var ret interface{}
+		if err := json.Unmarshal(data, &ret); err != nil {
+			return nil, err
+		}
+		return ret, nil
+	}
+	if t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface && t.Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return string(text), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if err := c.enter(v); err != nil {
+			return nil, err
+		}
+		defer c.leave(v)
+		return c.convert(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return c.convert(v.Elem())
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("unsupported value: %v", f)
+		}
+		return f, nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 && !reflect.PtrTo(t.Elem()).Implements(jsonMarshalerType) && !reflect.PtrTo(t.Elem()).Implements(textMarshalerType) {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		if err := c.enter(v); err != nil {
+			return nil, err
+		}
+		defer c.leave(v)
+		return c.slice(v)
+	case reflect.Array:
+		return c.slice(v)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if err := c.enter(v); err != nil {
+			return nil, err
+		}
+		defer c.leave(v)
+		ret := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := mapKeyString(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			if ret[key], err = c.convert(iter.Value()); err != nil {
+				return nil, err
+			}
+		}
+		return ret, nil
+	case reflect.Struct:
+		ret := map[string]interface{}{}
+		for _, f := range cachedFields(t) {
+			fv, ok := fieldByIndex(v, f.index)
+			if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+				continue
+			}
+			value, err := c.convert(fv)
+			if err != nil {
+				return nil, err
+			}
+			if f.quoted {
+				value = quote(value)
+			}
+			ret[f.name] = value
+		}
+		return ret, nil
+	}
+	return nil, fmt.Errorf("unsupported type: %v", t)
+}
+
+func (c *converter) slice(v reflect.Value) (interface{}, error) {
+	ret := make([]interface{}, v.Len())
+	for i := range ret {
+		var err error
+		if ret[i], err = c.convert(v.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func mapKeyString(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return "", nil
+		}
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type: %v", k.Type())
+}
+
+// quote applies the string option of json tags, which only affects
+// scalars.
+func quote(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return v
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex, reporting nil embedded
+// pointers instead of panicking.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// field is a struct field serialized by encoding/json.
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	quoted    bool
+}
+
+// fieldCache maps struct types to their []field.
+var fieldCache sync.Map
+
+func cachedFields(t reflect.Type) []field {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+	f, _ := fieldCache.LoadOrStore(t, structFields(t))
+	return f.([]field)
+}
+
+// structFields lists the fields of a struct, breadth first through the
+// embedded structs, keeping the shallowest field of each name.
+func structFields(t reflect.Type) []field {
+	var ret []field
+	seen := map[string]bool{}
+	visited := map[reflect.Type]bool{}
+	type embedded struct {
+		t     reflect.Type
+		index []int
+	}
+	level := []embedded{{t, nil}}
+	for len(level) > 0 {
+		var next []embedded
+		var current []field
+		for _, e := range level {
+			if visited[e.t] {
+				continue
+			}
+			visited[e.t] = true
+			for i := 0; i < e.t.NumField(); i++ {
+				sf := e.t.Field(i)
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := tag, ""
+				if j := strings.Index(tag, ","); j >= 0 {
+					name, opts = tag[:j], tag[j+1:]
+				}
+				index := append(append([]int(nil), e.index...), i)
+				ft := sf.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+					next = append(next, embedded{ft, index})
+					continue
+				}
+				if sf.PkgPath != "" {
+					// Unexported field.
+					continue
+				}
+				if name == "" {
+					name = sf.Name
+				}
+				current = append(current, field{
+					name:      name,
+					index:     index,
+					omitEmpty: hasTagOption(opts, "omitempty"),
+					quoted:    hasTagOption(opts, "string") && quotable(ft),
+				})
+			}
+		}
+		for _, f := range current {
+			if !seen[f.name] {
+				seen[f.name] = true
+				ret = append(ret, f)
+			}
+		}
+		level = next
+	}
+	return ret
+}
+
+func hasTagOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+func quotable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
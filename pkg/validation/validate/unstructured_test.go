@@ -0,0 +1,228 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+type unstructuredBase struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+}
+
+type unstructuredMeta struct {
+	Kind string
+	Note string `json:"note,omitempty"`
+}
+
+type unstructuredLevel int
+
+func (l unstructuredLevel) MarshalText() ([]byte, error) {
+	return []byte([]string{"low", "high"}[l]), nil
+}
+
+type unstructuredSample struct {
+	unstructuredBase
+	*unstructuredMeta
+	Kind      string                     `json:"kind"`
+	Name      string                     `json:"name,omitempty"`
+	Count     int                        `json:"count,string"`
+	Ratio     float32                    `json:"ratio"`
+	Tags      []string                   `json:"tags"`
+	Data      []byte                     `json:"data"`
+	Grid      [2]uint8                   `json:"grid"`
+	Labels    map[string]string          `json:"labels,omitempty"`
+	Levels    map[unstructuredLevel]bool `json:"levels"`
+	Ports     map[int]*unstructuredBase  `json:"ports"`
+	Created   time.Time                  `json:"created"`
+	Level     unstructuredLevel          `json:"level"`
+	Any       interface{}                `json:"any"`
+	Nested    *unstructuredSample        `json:"nested,omitempty"`
+	Ignored   string                     `json:"-"`
+	Dash      string                     `json:"-,"`
+	unexposed string
+	Extra     map[string]interface{}       `json:"extra"`
+	Nil       *unstructuredBase            `json:"nil"`
+	Objects   []map[string]json.RawMessage `json:"objects"`
+}
+
+func TestToUnstructured(t *testing.T) {
+	for _, v := range []interface{}{
+		nil,
+		true,
+		"s",
+		int8(-3),
+		uint64(7),
+		1.5,
+		[]int{1, 2},
+		[]byte("bytes"),
+		map[string]int{"a": 1},
+		&unstructuredSample{},
+		unstructuredSample{
+			unstructuredBase: unstructuredBase{ID: "id", Kind: "hidden"},
+			unstructuredMeta: &unstructuredMeta{Kind: "hidden too", Note: "note"},
+			Kind:             "Sample",
+			Count:            3,
+			Ratio:            0.5,
+			Tags:             []string{"a"},
+			Data:             []byte{1, 2, 3},
+			Grid:             [2]uint8{4, 5},
+			Labels:           map[string]string{"app": "web"},
+			Levels:           map[unstructuredLevel]bool{1: true},
+			Ports:            map[int]*unstructuredBase{80: {ID: "http"}, 443: nil},
+			Created:          time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			Level:            1,
+			Any:              []interface{}{1, "x", map[string]interface{}{"y": nil}},
+			Nested:           &unstructuredSample{Name: "nested"},
+			Ignored:          "ignored",
+			Dash:             "dash",
+			unexposed:        "unexposed",
+			Extra:            map[string]interface{}{"k": []string{"v"}},
+			Objects:          []map[string]json.RawMessage{{"raw": json.RawMessage(`{"a": [1]}`)}},
+		},
+	} {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		var expected interface{}
+		require.NoError(t, json.Unmarshal(data, &expected))
+		actual, err := toUnstructured(reflect.ValueOf(v))
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual, "%T", v)
+	}
+
+	_, err := toUnstructured(reflect.ValueOf(math.NaN()))
+	assert.Error(t, err)
+	_, err = toUnstructured(reflect.ValueOf(map[float64]int{1: 1}))
+	assert.Error(t, err)
+}
+
+type cyclic struct {
+	Name string   `json:"name"`
+	Next *cyclic  `json:"next,omitempty"`
+	List []cyclic `json:"list,omitempty"`
+}
+
+func TestToUnstructuredCycles(t *testing.T) {
+	// A deep value without cycles is converted.
+	deep := &cyclic{Name: "0"}
+	for i := 0; i < 2*startDetectingCyclesAfter; i++ {
+		deep = &cyclic{Next: deep}
+	}
+	_, err := toUnstructured(reflect.ValueOf(deep))
+	assert.NoError(t, err)
+
+	ptr := &cyclic{Name: "ptr"}
+	ptr.Next = ptr
+	m := map[string]interface{}{}
+	m["self"] = m
+	s := []interface{}{nil}
+	s[0] = s
+	list := &cyclic{List: make([]cyclic, 1)}
+	list.List[0].Next = list
+	for _, v := range []interface{}{ptr, m, s, list} {
+		_, err := toUnstructured(reflect.ValueOf(v))
+		if assert.Error(t, err, "%T", v) {
+			assert.Contains(t, err.Error(), "encountered a cycle", "%T", v)
+		}
+	}
+}
+
+func TestValidateGoValues(t *testing.T) {
+	type port struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+	}
+	type service struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels,omitempty"`
+		Ports  []port            `json:"ports"`
+	}
+	var s spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"labels": {"type": "object", "additionalProperties": {"type": "string", "maxLength": 3}},
+			"ports": {"type": "array", "items": {
+				"type": "object",
+				"properties": {"port": {"type": "integer", "maximum": 65535}}
+			}}
+		}
+	}`), &s))
+	v := NewSchemaValidator(&s, nil, "", strfmt.Default)
+
+	valid := service{Name: "web", Labels: map[string]string{"app": "web"}, Ports: []port{{"http", 80}}}
+	assert.True(t, v.Validate(valid).IsValid())
+	assert.True(t, v.Validate(&valid).IsValid())
+
+	res := v.Validate(&service{Labels: map[string]string{"app": "frontend"}, Ports: []port{{"http", 80000}}})
+	assert.Len(t, res.Errors, 3)
+
+	// Typed maps are validated like objects.
+	labels := s.Properties["labels"]
+	assert.False(t, NewSchemaValidator(&labels, nil, "", strfmt.Default).Validate(map[string]string{"app": "frontend"}).IsValid())
+
+	res = v.Validate(struct {
+		Name float64 `json:"name"`
+	}{math.Inf(1)})
+	assert.Len(t, res.Errors, 1)
+
+	self := &cyclic{Name: "self"}
+	self.Next = self
+	res = NewSchemaValidator(spec.MapProperty(nil), nil, "", strfmt.Default).Validate(self)
+	if assert.Len(t, res.Errors, 1) {
+		assert.Contains(t, res.Errors[0].Error(), "encountered a cycle")
+	}
+}
+
+func BenchmarkValidateGoValue(b *testing.B) {
+	type item struct {
+		Name  string   `json:"name"`
+		Count int      `json:"count"`
+		Tags  []string `json:"tags"`
+	}
+	items := make([]item, 100)
+	for i := range items {
+		items[i] = item{Name: "item", Count: i, Tags: []string{"a", "b"}}
+	}
+	s := spec.ArrayProperty(&spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:     spec.StringOrArray{"object"},
+		Required: []string{"name"},
+		Properties: map[string]spec.Schema{
+			"name":  *spec.StringProperty(),
+			"count": *spec.Int64Property(),
+			"tags":  *spec.ArrayProperty(spec.StringProperty()),
+		},
+	}})
+	v := NewSchemaValidator(s, nil, "", strfmt.Default)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.Validate(items).Release()
+	}
+}
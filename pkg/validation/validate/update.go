@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"reflect"
+	"strconv"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// extImmutable marks a schema whose values must not change once set.
+const extImmutable = "x-immutable"
+
+// IsImmutable returns whether a schema is marked with the x-immutable
+// extension.
+func IsImmutable(s *spec.Schema) bool {
+	immutable, _ := s.Extensions.GetBool(extImmutable)
+	return immutable
+}
+
+// ValidateUpdate validates the new version of an object like Validate and
+// reports an errors.Immutable error for each value of an immutable schema,
+// see IsImmutable, that differs between the old and the new version. Values
+// may be set if the old version doesn't have them, but not unset.
+//
+// The values are matched through properties, patternProperties,
+// additionalProperties and allOf, and array items by position. Values only
+// matched through anyOf, oneOf or not aren't checked. References to the
+// definitions of the root schema are followed, see ExpandRefs.
+func (s *SchemaValidator) ValidateUpdate(old, updated interface{}) *Result {
+	if s != nil {
+		if expanded := ExpandRefs(s.Schema, definitionResolver(s.Root)); expanded != s.Schema {
+			opts := s.Options
+			s = newSchemaValidator(expanded, s.Root, s.Path, s.in, s.KnownFormats, func(o *SchemaValidatorOptions) { *o = opts })
+		}
+	}
+	result := s.Validate(updated)
+	if s == nil {
+		return result
+	}
+	oldValue, err := comparableValue(old)
+	if err != nil {
+		result.AddErrors(invalidTypeConversionMsg(s.Path, err))
+		return result
+	}
+	newValue, err := comparableValue(updated)
+	if err != nil {
+		result.AddErrors(invalidTypeConversionMsg(s.Path, err))
+		return result
+	}
+	checkImmutable(s.Schema, s.Path, nil, s.in, oldValue, newValue, result)
+	return result
+}
+
+// comparableValue converts values to their JSON form throughout, typed
+// slices and Go values nested in decoded JSON included, so that old and new
+// versions compare equal when their JSON forms do.
+func comparableValue(v interface{}) (interface{}, error) {
+	return toUnstructured(reflect.ValueOf(v))
+}
+
+// checkImmutable compares the values at path, whose location is that of
+// the errors, see errors.Validation.Location.
+func checkImmutable(s *spec.Schema, path string, location []string, in string, old, updated interface{}, result *Result) {
+	if s == nil || old == nil {
+		return
+	}
+	if IsImmutable(s) {
+		if !reflect.DeepEqual(old, updated) {
+			err := errors.Immutable(path, in)
+			err.Location = location
+			result.AddErrors(err)
+		}
+		// Nothing below changed if this value didn't.
+		return
+	}
+	for i := range s.AllOf {
+		checkImmutable(&s.AllOf[i], path, location, in, old, updated, result)
+	}
+
+	switch oldValue := old.(type) {
+	case map[string]interface{}:
+		newValue, ok := updated.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for k, v := range oldValue {
+			name := k
+			if path != "" {
+				name = path + "." + k
+			}
+			// Sliced to its length so that appending never shares the
+			// array with the locations of the siblings.
+			loc := append(location[:len(location):len(location)], k)
+			matched := false
+			if prop, ok := s.Properties[k]; ok {
+				matched = true
+				checkImmutable(&prop, name, loc, in, v, newValue[k], result)
+			}
+			for pattern, prop := range s.PatternProperties {
+				if re, err := compileRegexp(pattern); err == nil && re.MatchString(k) {
+					matched = true
+					prop := prop
+					checkImmutable(&prop, name, loc, in, v, newValue[k], result)
+				}
+			}
+			if !matched && s.AdditionalProperties != nil {
+				checkImmutable(s.AdditionalProperties.Schema, name, loc, in, v, newValue[k], result)
+			}
+		}
+	case []interface{}:
+		newValue, _ := updated.([]interface{})
+		if s.Items == nil {
+			return
+		}
+		for i, v := range oldValue {
+			var n interface{}
+			if i < len(newValue) {
+				n = newValue[i]
+			}
+			name := path + "." + strconv.Itoa(i)
+			loc := append(location[:len(location):len(location)], strconv.Itoa(i))
+			switch {
+			case s.Items.Schema != nil:
+				checkImmutable(s.Items.Schema, name, loc, in, v, n, result)
+			case i < len(s.Items.Schemas):
+				checkImmutable(&s.Items.Schemas[i], name, loc, in, v, n, result)
+			case s.AdditionalItems != nil:
+				checkImmutable(s.AdditionalItems.Schema, name, loc, in, v, n, result)
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+)
+
+func TestValidateUpdate(t *testing.T) {
+	var s spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"spec": {
+				"type": "object",
+				"properties": {
+					"selector": {"type": "object", "x-immutable": true},
+					"replicas": {"type": "integer"},
+					"volumes": {"type": "array", "items": {
+						"type": "object",
+						"properties": {"name": {"type": "string", "x-immutable": true}}
+					}}
+				}
+			},
+			"labels": {"type": "object", "additionalProperties": {"type": "string", "x-immutable": true}}
+		}
+	}`), &s))
+	selector := s.Properties["spec"].Properties["selector"]
+	assert.True(t, IsImmutable(&selector))
+	v := NewSchemaValidator(&s, nil, "", strfmt.Default)
+
+	doc := func(data string) interface{} {
+		var v interface{}
+		require.NoError(t, json.Unmarshal([]byte(data), &v))
+		return v
+	}
+	old := doc(`{
+		"spec": {"selector": {"app": "web"}, "replicas": 1, "volumes": [{"name": "data"}]},
+		"labels": {"team": "a"}
+	}`)
+
+	assert.True(t, v.ValidateUpdate(old, doc(`{
+		"spec": {"selector": {"app": "web"}, "replicas": 3, "volumes": [{"name": "data"}, {"name": "new"}]},
+		"labels": {"team": "a", "added": "b"}
+	}`)).IsValid())
+
+	res := v.ValidateUpdate(old, doc(`{
+		"spec": {"selector": {"app": "api"}, "replicas": 1, "volumes": [{"name": "logs"}]},
+		"labels": {}
+	}`))
+	var names []string
+	for _, err := range res.Errors {
+		e := err.(*errors.Validation)
+		assert.EqualValues(t, errors.ImmutableCode, e.Code())
+		names = append(names, e.Name)
+	}
+	assert.ElementsMatch(t, []string{"spec.selector", "spec.volumes.0.name", "labels.team"}, names)
+
+	// Immutable values can be set when the old version doesn't have them.
+	assert.True(t, v.ValidateUpdate(doc(`{}`), old).IsValid())
+
+	// Go values are compared in their JSON form.
+	type objectSpec struct {
+		Selector map[string]string `json:"selector"`
+	}
+	type object struct {
+		Spec objectSpec `json:"spec"`
+	}
+	assert.True(t, v.ValidateUpdate(old, object{objectSpec{map[string]string{"app": "web"}}}).IsValid())
+	assert.False(t, v.ValidateUpdate(old, &object{objectSpec{map[string]string{"app": "api"}}}).IsValid())
+
+	// The new version is validated too.
+	assert.False(t, v.ValidateUpdate(old, doc(`{"spec": {"selector": {"app": "web"}, "replicas": "3"}}`)).IsValid())
+}
+
+func TestValidateUpdateConvertsNestedValues(t *testing.T) {
+	var s spec.Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"volumes": {"type": "array", "items": {"$ref": "#/definitions/Volume"}},
+			"owner": {"$ref": "#/definitions/Owner"}
+		},
+		"definitions": {
+			"Volume": {"type": "object", "properties": {"name": {"type": "string", "x-immutable": true}}},
+			"Owner": {"type": "object", "properties": {"spec": {"type": "object", "properties": {"id": {"type": "string", "x-immutable": true}}}}}
+		}
+	}`), &s))
+	v := NewSchemaValidator(&s, nil, "", strfmt.Default)
+	names := func(res *Result) []string {
+		var ret []string
+		for _, err := range res.Errors {
+			ret = append(ret, err.(*errors.Validation).Name)
+		}
+		return ret
+	}
+
+	type volume struct {
+		Name string `json:"name"`
+	}
+	type ownerSpec struct {
+		ID string `json:"id"`
+	}
+	object := func(volumes []volume, id string) map[string]interface{} {
+		return map[string]interface{}{"volumes": volumes, "owner": map[string]interface{}{"spec": ownerSpec{id}}}
+	}
+
+	// Typed slices and Go values nested in maps are compared like decoded
+	// JSON, through the referenced definitions.
+	old := object([]volume{{"data"}}, "a")
+	assert.Empty(t, names(v.ValidateUpdate(old, object([]volume{{"data"}, {"logs"}}, "a"))))
+	assert.ElementsMatch(t, []string{"volumes.0.name", "owner.spec.id"}, names(v.ValidateUpdate(old, object([]volume{{"logs"}}, "b"))))
+
+	// Typed slices at the root are compared too, their items named like
+	// the errors of Validate.
+	items := NewSchemaValidator(spec.ArrayProperty(spec.RefProperty("#/definitions/Volume")), &s, "", strfmt.Default)
+	res := items.ValidateUpdate([]volume{{"data"}}, []volume{{"logs"}})
+	assert.Equal(t, []string{".0.name"}, names(res))
+	assert.Equal(t, "/0/name", res.Output(BasicOutput).Errors[0].InstanceLocation)
+}
@@ -37,6 +37,11 @@ type basicCommonValidator struct {
 	In      string
 	Default interface{}
 	Enum    []interface{}
+	// Const is the value of the JSON Schema const keyword, which
+	// spec.Schema keeps in its ExtraProps. HasConst tells a null const
+	// from no const.
+	Const    interface{}
+	HasConst bool
 }
 
 func (b *basicCommonValidator) SetPath(path string) {
@@ -52,21 +57,29 @@ func (b *basicCommonValidator) Applies(source interface{}, kind reflect.Kind) bo
 }
 
 func (b *basicCommonValidator) Validate(data interface{}) (res *Result) {
-	if len(b.Enum) > 0 {
-		for _, enumValue := range b.Enum {
-			actualType := reflect.TypeOf(enumValue)
-			if actualType != nil { // Safeguard
-				expectedValue := reflect.ValueOf(data)
-				if expectedValue.IsValid() && expectedValue.Type().ConvertibleTo(actualType) {
-					if reflect.DeepEqual(expectedValue.Convert(actualType).Interface(), enumValue) {
-						return nil
-					}
+	if len(b.Enum) > 0 && !enumContains(b.Enum, data) {
+		return errorHelp.sErr(errors.EnumFail(b.Path, b.In, data, b.Enum))
+	}
+	// A const is an enum of a single value, which may be null.
+	if b.HasConst && !(b.Const == nil && data == nil) && !enumContains([]interface{}{b.Const}, data) {
+		return errorHelp.sErr(errors.EnumFail(b.Path, b.In, data, []interface{}{b.Const}))
+	}
+	return nil
+}
+
+func enumContains(enum []interface{}, data interface{}) bool {
+	for _, enumValue := range enum {
+		actualType := reflect.TypeOf(enumValue)
+		if actualType != nil { // Safeguard
+			expectedValue := reflect.ValueOf(data)
+			if expectedValue.IsValid() && expectedValue.Type().ConvertibleTo(actualType) {
+				if reflect.DeepEqual(expectedValue.Convert(actualType).Interface(), enumValue) {
+					return true
 				}
 			}
 		}
-		return errorHelp.sErr(errors.EnumFail(b.Path, b.In, data, b.Enum))
 	}
-	return nil
+	return false
 }
 
 type numberValidator struct {
@@ -122,11 +135,11 @@ func (n *numberValidator) Applies(source interface{}, kind reflect.Kind) bool {
 //
 // TODO: default boundaries with MAX_SAFE_INTEGER are not checked (specific to json.Number?)
 func (n *numberValidator) Validate(val interface{}) *Result {
-	res := new(Result)
+	res := newResult()
 
-	resMultiple := new(Result)
-	resMinimum := new(Result)
-	resMaximum := new(Result)
+	resMultiple := newResult()
+	resMinimum := newResult()
+	resMaximum := newResult()
 
 	// Used only to attempt to validate constraint on value,
 	// even though value or constraint specified do not match type and format
@@ -142,12 +155,12 @@ func (n *numberValidator) Validate(val interface{}) *Result {
 		if resMultiple.IsValid() {
 			// Constraint validated with compatible types
 			if err := MultipleOfNativeType(n.Path, n.In, val, *n.MultipleOf); err != nil {
-				resMultiple.Merge(errorHelp.sErr(err))
+				resMultiple.mergeAndRelease(errorHelp.sErr(err))
 			}
 		} else {
 			// Constraint nevertheless validated, converted as general number
 			if err := MultipleOf(n.Path, n.In, data, *n.MultipleOf); err != nil {
-				resMultiple.Merge(errorHelp.sErr(err))
+				resMultiple.mergeAndRelease(errorHelp.sErr(err))
 			}
 		}
 	}
@@ -159,12 +172,12 @@ func (n *numberValidator) Validate(val interface{}) *Result {
 		if resMaximum.IsValid() {
 			// Constraint validated with compatible types
 			if err := MaximumNativeType(n.Path, n.In, val, *n.Maximum, n.ExclusiveMaximum); err != nil {
-				resMaximum.Merge(errorHelp.sErr(err))
+				resMaximum.mergeAndRelease(errorHelp.sErr(err))
 			}
 		} else {
 			// Constraint nevertheless validated, converted as general number
 			if err := Maximum(n.Path, n.In, data, *n.Maximum, n.ExclusiveMaximum); err != nil {
-				resMaximum.Merge(errorHelp.sErr(err))
+				resMaximum.mergeAndRelease(errorHelp.sErr(err))
 			}
 		}
 	}
@@ -176,16 +189,16 @@ func (n *numberValidator) Validate(val interface{}) *Result {
 		if resMinimum.IsValid() {
 			// Constraint validated with compatible types
 			if err := MinimumNativeType(n.Path, n.In, val, *n.Minimum, n.ExclusiveMinimum); err != nil {
-				resMinimum.Merge(errorHelp.sErr(err))
+				resMinimum.mergeAndRelease(errorHelp.sErr(err))
 			}
 		} else {
 			// Constraint nevertheless validated, converted as general number
 			if err := Minimum(n.Path, n.In, data, *n.Minimum, n.ExclusiveMinimum); err != nil {
-				resMinimum.Merge(errorHelp.sErr(err))
+				resMinimum.mergeAndRelease(errorHelp.sErr(err))
 			}
 		}
 	}
-	res.Merge(resMultiple, resMinimum, resMaximum)
+	res.mergeAndRelease(resMultiple, resMinimum, resMaximum)
 	res.Inc()
 	return res
 }